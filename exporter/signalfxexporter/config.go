@@ -38,6 +38,11 @@ type Config struct {
 	// If a path is specified it will use the one set by the config.
 	URL string `mapstructure:"url"`
 
+	// APIURL is the destination to where dimension properties, received as
+	// metadata of resources, are sent. The value of Realm is ignored if the
+	// APIURL is specified.
+	APIURL string `mapstructure:"api_url"`
+
 	// Timeout is the maximum timeout for HTTP request sending trace data. The
 	// default value is 5 seconds.
 	Timeout time.Duration `mapstructure:"timeout"`
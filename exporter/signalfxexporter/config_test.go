@@ -54,6 +54,7 @@ func TestLoadConfig(t *testing.T) {
 		},
 		AccessToken: "testToken",
 		Realm:       "us1",
+		APIURL:      "https://api.us1.signalfx.com/",
 		Headers: map[string]string{
 			"added-entry": "added value",
 			"dot.test":    "test",
@@ -0,0 +1,248 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signalfxexporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/resourcemetadata"
+)
+
+const (
+	// dimensionQueueSize is the maximum number of dimension updates waiting
+	// to be sent, further updates are dropped.
+	dimensionQueueSize = 10000
+	// dimensionWorkers is the maximum number of concurrent requests updating
+	// dimensions.
+	dimensionWorkers = 4
+	// dimensionRequestInterval paces the requests updating dimensions so that
+	// the updates of all the resources of a large cluster, e.g. on the first
+	// sync, are spread over time instead of flooding the API.
+	dimensionRequestInterval = time.Second / 20
+)
+
+// dimensionClient sends properties and tags of dimensions to the SignalFx API.
+// Updates are queued and sent in the background by a pool of workers, at most
+// one request every requestInterval.
+type dimensionClient struct {
+	url     *url.URL
+	token   string
+	headers map[string]string
+	client  *http.Client
+	logger  *zap.Logger
+
+	queueSize       int
+	workers         int
+	requestInterval time.Duration
+
+	queue chan *queuedDimensionUpdate
+	done  chan struct{}
+	wg    sync.WaitGroup
+}
+
+// queuedDimensionUpdate is an update of the dimension key=value waiting to be sent.
+type queuedDimensionUpdate struct {
+	key    string
+	value  string
+	update *dimensionUpdate
+}
+
+// dimensionUpdate is the body of a request partially updating a dimension.
+// Properties mapped to nil are removed.
+type dimensionUpdate struct {
+	CustomProperties map[string]*string `json:"customProperties"`
+	Tags             []string           `json:"tags"`
+	TagsToRemove     []string           `json:"tagsToRemove"`
+}
+
+func newDimensionClient(
+	url *url.URL,
+	token string,
+	headers map[string]string,
+	client *http.Client,
+	logger *zap.Logger,
+) *dimensionClient {
+	return &dimensionClient{
+		url:             url,
+		token:           token,
+		headers:         headers,
+		client:          client,
+		logger:          logger,
+		queueSize:       dimensionQueueSize,
+		workers:         dimensionWorkers,
+		requestInterval: dimensionRequestInterval,
+	}
+}
+
+// start starts the workers sending the queued updates.
+func (dc *dimensionClient) start() {
+	dc.queue = make(chan *queuedDimensionUpdate, dc.queueSize)
+	dc.done = make(chan struct{})
+	ticker := time.NewTicker(dc.requestInterval)
+
+	dc.wg.Add(dc.workers)
+	for i := 0; i < dc.workers; i++ {
+		go dc.sendUpdates(ticker.C)
+	}
+	go func() {
+		<-dc.done
+		ticker.Stop()
+	}()
+}
+
+// stop stops the workers, the updates still queued are dropped.
+func (dc *dimensionClient) stop() {
+	if dc.done == nil {
+		return
+	}
+	close(dc.done)
+	dc.wg.Wait()
+}
+
+// sendUpdates sends queued updates, waiting for a tick before each request.
+// The ticker is shared by all the workers so that ticks pace the requests of
+// the whole pool.
+func (dc *dimensionClient) sendUpdates(ticks <-chan time.Time) {
+	defer dc.wg.Done()
+	for {
+		var u *queuedDimensionUpdate
+		select {
+		case u = <-dc.queue:
+		case <-dc.done:
+			return
+		}
+		select {
+		case <-ticks:
+		case <-dc.done:
+			return
+		}
+		if err := dc.patchDimension(u.key, u.value, u.update); err != nil {
+			dc.logger.Error("Failed to update dimension", zap.Error(err))
+		}
+	}
+}
+
+// pushMetadata queues an update of the dimension identifying each resource.
+// Properties without a value are sent as tags since SignalFx doesn't accept
+// empty property values. An error is returned if updates are dropped because
+// the queue is full.
+func (dc *dimensionClient) pushMetadata(metadata []*resourcemetadata.MetadataUpdate) error {
+	var numDropped int
+	for _, m := range metadata {
+		update := getDimensionUpdate(m)
+		if len(update.CustomProperties) == 0 && len(update.Tags) == 0 && len(update.TagsToRemove) == 0 {
+			continue
+		}
+		select {
+		case dc.queue <- &queuedDimensionUpdate{
+			key:    filterKeyChars(m.ResourceIDKey),
+			value:  m.ResourceID,
+			update: update,
+		}:
+		default:
+			numDropped++
+		}
+	}
+	if numDropped > 0 {
+		return fmt.Errorf("dropped %d dimension updates, the queue of %d updates is full", numDropped, dc.queueSize)
+	}
+	return nil
+}
+
+func getDimensionUpdate(m *resourcemetadata.MetadataUpdate) *dimensionUpdate {
+	update := &dimensionUpdate{
+		CustomProperties: map[string]*string{},
+		Tags:             []string{},
+		TagsToRemove:     []string{},
+	}
+
+	for _, props := range []map[string]string{m.MetadataToAdd, m.MetadataToUpdate} {
+		for k, v := range props {
+			if v == "" {
+				update.Tags = append(update.Tags, k)
+				// The key may have been a property with a value before.
+				update.CustomProperties[filterKeyChars(k)] = nil
+				continue
+			}
+			v := v
+			update.CustomProperties[filterKeyChars(k)] = &v
+		}
+	}
+	for k, v := range m.MetadataToRemove {
+		if v == "" {
+			update.TagsToRemove = append(update.TagsToRemove, k)
+			continue
+		}
+		update.CustomProperties[filterKeyChars(k)] = nil
+	}
+
+	return update
+}
+
+func (dc *dimensionClient) patchDimension(key, value string, update *dimensionUpdate) error {
+	body, err := json.Marshal(update)
+	if err != nil {
+		return err
+	}
+
+	// The key and value are escaped in the raw path only, otherwise they would
+	// be escaped a second time when building the URL.
+	u := *dc.url
+	rawPath := strings.TrimSuffix(u.EscapedPath(), "/")
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/v2/dimension/" + key + "/" + value + "/_/sfxagent"
+	u.RawPath = rawPath + "/v2/dimension/" + url.PathEscape(key) + "/" + url.PathEscape(value) + "/_/sfxagent"
+	req, err := http.NewRequest("PATCH", u.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	for k, v := range dc.headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if dc.token != "" {
+		req.Header.Set("X-Sf-Token", dc.token)
+	}
+
+	resp, err := dc.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf(
+			"failed to update dimension %s=%s: HTTP %d %q",
+			key, value,
+			resp.StatusCode,
+			http.StatusText(resp.StatusCode))
+	}
+
+	dc.logger.Debug("Updated dimension", zap.String("key", key), zap.String("value", value))
+	return nil
+}
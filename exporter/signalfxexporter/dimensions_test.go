@@ -0,0 +1,230 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signalfxexporter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector/component/componenttest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/resourcemetadata"
+)
+
+func TestPushMetadata(t *testing.T) {
+	var mu sync.Mutex
+	requests := map[string]*dimensionUpdate{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PATCH", r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "test-token", r.Header.Get("X-Sf-Token"))
+		assert.Equal(t, "test", r.Header.Get("test_header_"))
+
+		var update dimensionUpdate
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&update))
+		mu.Lock()
+		requests[r.URL.Path] = &update
+		mu.Unlock()
+
+		if r.URL.Path == "/v2/dimension/container_id/failing/_/sfxagent" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	core, logs := observer.New(zap.ErrorLevel)
+	dc := newDimensionClient(
+		u,
+		"test-token",
+		map[string]string{"test_header_": "test"},
+		&http.Client{Timeout: 1 * time.Second},
+		zap.New(core),
+	)
+	dc.requestInterval = time.Millisecond
+	dc.start()
+	defer dc.stop()
+
+	err = dc.pushMetadata([]*resourcemetadata.MetadataUpdate{
+		{
+			ResourceIDKey: "k8s.pod.uid",
+			ResourceID:    "pod-uid",
+			MetadataDelta: resourcemetadata.MetadataDelta{
+				MetadataToAdd:    map[string]string{"k8s.workload.name": "test", "foo": ""},
+				MetadataToRemove: map[string]string{"bar": "baz", "qux": ""},
+				MetadataToUpdate: map[string]string{"pod.creation_timestamp": "now"},
+			},
+		},
+		{
+			ResourceIDKey: "container.id",
+			ResourceID:    "failing",
+			MetadataDelta: resourcemetadata.MetadataDelta{
+				MetadataToAdd: map[string]string{"container.status": "running"},
+			},
+		},
+		{
+			ResourceIDKey: "container.id",
+			ResourceID:    "unchanged",
+		},
+	})
+	require.NoError(t, err)
+
+	// The updates are sent in the background, failures are logged.
+	require.Eventually(t, func() bool {
+		return logs.Len() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, `failed to update dimension container_id=failing: HTTP 400 "Bad Request"`,
+		logs.All()[0].ContextMap()["error"])
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(requests) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, 2, len(requests))
+	workload, creation := "test", "now"
+	assert.Equal(t, &dimensionUpdate{
+		CustomProperties: map[string]*string{
+			"k8s_workload_name":      &workload,
+			"pod_creation_timestamp": &creation,
+			"foo":                    nil,
+			"bar":                    nil,
+		},
+		Tags:         []string{"foo"},
+		TagsToRemove: []string{"qux"},
+	}, requests["/v2/dimension/k8s_pod_uid/pod-uid/_/sfxagent"])
+}
+
+func TestPatchDimensionEscaping(t *testing.T) {
+	paths := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths <- r.URL.EscapedPath()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	u, err := url.Parse(server.URL + "/base/")
+	require.NoError(t, err)
+	dc := newDimensionClient(u, "", nil, http.DefaultClient, zap.NewNop())
+
+	require.NoError(t, dc.patchDimension("key", "a/b c%d", &dimensionUpdate{}))
+	assert.Equal(t, "/base/v2/dimension/key/a%2Fb%20c%25d/_/sfxagent", <-paths)
+}
+
+func TestPushMetadataQueueFull(t *testing.T) {
+	u, err := url.Parse("http://localhost")
+	require.NoError(t, err)
+	dc := newDimensionClient(u, "", nil, http.DefaultClient, zap.NewNop())
+	dc.queueSize = 1
+	// Not started so that updates stay queued.
+	dc.queue = make(chan *queuedDimensionUpdate, dc.queueSize)
+
+	updates := []*resourcemetadata.MetadataUpdate{
+		{
+			ResourceIDKey: "container.id",
+			ResourceID:    "c1",
+			MetadataDelta: resourcemetadata.MetadataDelta{
+				MetadataToAdd: map[string]string{"container.status": "running"},
+			},
+		},
+		{
+			ResourceIDKey: "container.id",
+			ResourceID:    "c2",
+			MetadataDelta: resourcemetadata.MetadataDelta{
+				MetadataToAdd: map[string]string{"container.status": "running"},
+			},
+		},
+	}
+	err = dc.pushMetadata(updates)
+	require.EqualError(t, err, "dropped 1 dimension updates, the queue of 1 updates is full")
+	assert.Equal(t, 1, len(dc.queue))
+}
+
+func TestPushMetadataRequestInterval(t *testing.T) {
+	var mu sync.Mutex
+	var times []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		times = append(times, time.Now())
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	dc := newDimensionClient(u, "", nil, http.DefaultClient, zap.NewNop())
+	dc.requestInterval = 50 * time.Millisecond
+	dc.start()
+	defer dc.stop()
+
+	var updates []*resourcemetadata.MetadataUpdate
+	for i := 0; i < 3; i++ {
+		updates = append(updates, &resourcemetadata.MetadataUpdate{
+			ResourceIDKey: "container.id",
+			ResourceID:    fmt.Sprintf("c%d", i),
+			MetadataDelta: resourcemetadata.MetadataDelta{
+				MetadataToAdd: map[string]string{"container.status": "running"},
+			},
+		})
+	}
+	start := time.Now()
+	require.NoError(t, dc.pushMetadata(updates))
+
+	// The requests of all workers are paced by the same interval.
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(times) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.True(t, times[2].Sub(start) >= 2*dc.requestInterval)
+}
+
+func TestConsumeMetadataRegistration(t *testing.T) {
+	config := &Config{
+		AccessToken: "someToken",
+		Realm:       "xyz",
+	}
+	exp, err := New(config, zap.NewNop())
+	require.NoError(t, err)
+
+	se := exp.(*signalfxExporter)
+	assert.Equal(t, "https://api.xyz.signalfx.com", se.dimensions.url.String())
+
+	require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+	registered, ok := resourcemetadata.GetMetadataExporter("signalfx")
+	require.True(t, ok)
+	assert.Equal(t, se, registered)
+
+	require.NoError(t, exp.Shutdown(context.Background()))
+	_, ok = resourcemetadata.GetMetadataExporter("signalfx")
+	assert.False(t, ok)
+}
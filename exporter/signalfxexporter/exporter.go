@@ -35,6 +35,8 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector/exporter/exporterhelper"
 	sfxpb "github.com/signalfx/com_signalfx_metrics_protobuf"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/resourcemetadata"
 )
 
 // New returns a new SignalFx exporter.
@@ -77,6 +79,18 @@ func New(
 		logger.Info("SingalFX Config", zap.String("actual_url", actualURL))
 	}
 
+	var actualAPIURL *url.URL
+	if config.APIURL == "" {
+		actualAPIURL, _ = url.Parse(fmt.Sprintf("https://api.%s.signalfx.com", config.Realm))
+	} else {
+		u, err := url.Parse(config.APIURL)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"%q invalid \"api_url\": %v", config.Name(), err)
+		}
+		actualAPIURL = u
+	}
+
 	if config.Timeout == 0 {
 		config.Timeout = 5 * time.Second
 	}
@@ -110,8 +124,49 @@ func New(
 	exp, err := exporterhelper.NewMetricsExporterOld(
 		&config.ExporterSettings,
 		s.pushMetricsData)
+	if err != nil {
+		return nil, err
+	}
+
+	return &signalfxExporter{
+		MetricsExporterOld: exp,
+		name:               config.Name(),
+		dimensions: newDimensionClient(
+			actualAPIURL,
+			config.AccessToken,
+			config.Headers,
+			s.client,
+			logger,
+		),
+	}, nil
+}
+
+// signalfxExporter sends metrics and, for receivers collecting metadata of
+// resources, dimension properties.
+type signalfxExporter struct {
+	component.MetricsExporterOld
+	name       string
+	dimensions *dimensionClient
+}
+
+var _ resourcemetadata.MetadataExporter = (*signalfxExporter)(nil)
+
+func (se *signalfxExporter) Start(ctx context.Context, host component.Host) error {
+	se.dimensions.start()
+	resourcemetadata.RegisterMetadataExporter(se.name, se)
+	return se.MetricsExporterOld.Start(ctx, host)
+}
+
+func (se *signalfxExporter) Shutdown(ctx context.Context) error {
+	resourcemetadata.UnregisterMetadataExporter(se.name)
+	se.dimensions.stop()
+	return se.MetricsExporterOld.Shutdown(ctx)
+}
 
-	return exp, err
+// ConsumeMetadata queues updates of the properties of the dimensions identifying
+// resources, the updates are sent in the background.
+func (se *signalfxExporter) ConsumeMetadata(metadata []*resourcemetadata.MetadataUpdate) error {
+	return se.dimensions.pushMetadata(metadata)
 }
 
 // httpSender sends the data to the SignalFx backend.
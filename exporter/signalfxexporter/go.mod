@@ -6,7 +6,10 @@ require (
 	github.com/census-instrumentation/opencensus-proto v0.2.1
 	github.com/golang/protobuf v1.3.5
 	github.com/open-telemetry/opentelemetry-collector v0.3.1-0.20200427150635-ca4b8231de7c
+	github.com/open-telemetry/opentelemetry-collector-contrib/internal/resourcemetadata v0.0.0
 	github.com/signalfx/com_signalfx_metrics_protobuf v0.0.0-20190530013331-054be550cb49
 	github.com/stretchr/testify v1.5.1
-	go.uber.org/zap v1.12.0
+	go.uber.org/zap v1.14.1
 )
+
+replace github.com/open-telemetry/opentelemetry-collector-contrib/internal/resourcemetadata => ../../internal/resourcemetadata
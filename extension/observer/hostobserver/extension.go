@@ -0,0 +1,203 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hostobserver
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector/component"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
+)
+
+// Keys of the process metadata added to endpoint labels.
+const (
+	// LabelProcessName is the name of the process owning the socket.
+	LabelProcessName = "process.name"
+	// LabelProcessCommandLine is the full command line of the process owning the socket.
+	LabelProcessCommandLine = "process.command_line"
+	// LabelProcessPID is the PID of the process owning the socket.
+	LabelProcessPID = "process.pid"
+)
+
+// localhost is used as the target of sockets bound to all interfaces.
+const localhost = "localhost"
+
+var _ component.ServiceExtension = (*hostObserver)(nil)
+var _ observer.Observable = (*hostObserver)(nil)
+
+// hostObserver periodically scans the listening sockets of the host and reports
+// the differences to its listeners.
+type hostObserver struct {
+	logger   *zap.Logger
+	config   *Config
+	procRoot string
+
+	mu sync.Mutex
+	// endpoints is the last discovered set of endpoints keyed by endpoint ID.
+	endpoints map[string]observer.Endpoint
+	listeners []observer.Notify
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// newObserver creates a new host observer extension reading procfs from procRoot.
+func newObserver(logger *zap.Logger, config *Config, procRoot string) *hostObserver {
+	return &hostObserver{
+		logger:    logger,
+		config:    config,
+		procRoot:  procRoot,
+		endpoints: map[string]observer.Endpoint{},
+		stop:      make(chan struct{}),
+	}
+}
+
+// Start scanning for endpoints in the background.
+func (h *hostObserver) Start(ctx context.Context, host component.Host) error {
+	go func() {
+		ticker := time.NewTicker(h.config.RefreshInterval)
+		defer ticker.Stop()
+
+		h.refresh()
+		for {
+			select {
+			case <-ticker.C:
+				h.refresh()
+			case <-h.stop:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Shutdown stops scanning for endpoints.
+func (h *hostObserver) Shutdown(ctx context.Context) error {
+	h.stopOnce.Do(func() {
+		close(h.stop)
+	})
+	return nil
+}
+
+// ListAndWatch notifies listener of the currently known endpoints and sends
+// subsequent changes.
+func (h *hostObserver) ListAndWatch(listener observer.Notify) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.listeners = append(h.listeners, listener)
+	if len(h.endpoints) > 0 {
+		current := make([]observer.Endpoint, 0, len(h.endpoints))
+		for _, e := range h.endpoints {
+			current = append(current, e)
+		}
+		sortEndpoints(current)
+		listener.OnAdd(current)
+	}
+}
+
+// refresh discovers the current endpoints and notifies listeners of any differences
+// to the previously discovered endpoints.
+func (h *hostObserver) refresh() {
+	discovered, err := h.discover()
+	if err != nil {
+		h.logger.Error("failed to discover listening sockets", zap.Error(err))
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var removed, added, changed []observer.Endpoint
+	for id, e := range h.endpoints {
+		if _, ok := discovered[id]; !ok {
+			removed = append(removed, e)
+		}
+	}
+	for id, e := range discovered {
+		old, ok := h.endpoints[id]
+		switch {
+		case !ok:
+			added = append(added, e)
+		case !reflect.DeepEqual(old, e):
+			changed = append(changed, e)
+		}
+	}
+	h.endpoints = discovered
+
+	sortEndpoints(removed)
+	sortEndpoints(added)
+	sortEndpoints(changed)
+
+	for _, l := range h.listeners {
+		if len(removed) > 0 {
+			l.OnRemove(removed)
+		}
+		if len(added) > 0 {
+			l.OnAdd(added)
+		}
+		if len(changed) > 0 {
+			l.OnChange(changed)
+		}
+	}
+}
+
+// discover returns an endpoint for every listening socket keyed by endpoint ID.
+func (h *hostObserver) discover() (map[string]observer.Endpoint, error) {
+	sockets, err := listeningSockets(h.procRoot)
+	if err != nil {
+		return nil, err
+	}
+	owners := socketOwners(h.procRoot)
+
+	endpoints := map[string]observer.Endpoint{}
+	for _, s := range sockets {
+		target := s.ip.String()
+		if s.ip.IsUnspecified() {
+			target = localhost
+		}
+
+		// Sockets bound to all interfaces on both IPv4 and IPv6 are reported once.
+		id := fmt.Sprintf("%s/(%s:%d/%s)", h.config.Name(), target, s.port, s.protocol)
+		if _, ok := endpoints[id]; ok {
+			continue
+		}
+
+		labels := map[string]string{}
+		if proc, ok := owners[s.inode]; ok {
+			labels[LabelProcessName] = proc.name
+			labels[LabelProcessCommandLine] = proc.cmdline
+			labels[LabelProcessPID] = strconv.Itoa(proc.pid)
+		}
+
+		endpoints[id] = observer.NewPortEndpoint(id, target, s.port, s.protocol, labels)
+	}
+
+	return endpoints, nil
+}
+
+func sortEndpoints(endpoints []observer.Endpoint) {
+	sort.Slice(endpoints, func(i, j int) bool {
+		return endpoints[i].ID() < endpoints[j].ID()
+	})
+}
@@ -0,0 +1,157 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hostobserver
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector/component/componenttest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
+)
+
+// endpointSink records all endpoint notifications.
+type endpointSink struct {
+	sync.Mutex
+	added   []observer.Endpoint
+	removed []observer.Endpoint
+	changed []observer.Endpoint
+}
+
+var _ observer.Notify = (*endpointSink)(nil)
+
+func (s *endpointSink) OnAdd(added []observer.Endpoint) {
+	s.Lock()
+	defer s.Unlock()
+	s.added = append(s.added, added...)
+}
+
+func (s *endpointSink) OnRemove(removed []observer.Endpoint) {
+	s.Lock()
+	defer s.Unlock()
+	s.removed = append(s.removed, removed...)
+}
+
+func (s *endpointSink) OnChange(changed []observer.Endpoint) {
+	s.Lock()
+	defer s.Unlock()
+	s.changed = append(s.changed, changed...)
+}
+
+func newTestObserver(procRoot string) *hostObserver {
+	factory := &Factory{}
+	cfg := factory.CreateDefaultConfig().(*Config)
+	return newObserver(zap.NewNop(), cfg, procRoot)
+}
+
+func TestRefresh(t *testing.T) {
+	proc := newFakeProc(t)
+	defer proc.cleanup()
+
+	proc.writeNet("tcp", procNetTCP)
+	proc.writeNet("tcp6", procNetTCP6)
+	proc.writeNet("udp", procNetUDP)
+	proc.addProcess("1234", "redis-server", []string{"/usr/bin/redis-server", "*:6379"}, "20866", "20868")
+
+	obs := newTestObserver(proc.root)
+	sink := &endpointSink{}
+	obs.ListAndWatch(sink)
+
+	obs.refresh()
+
+	redisLabels := map[string]string{
+		LabelProcessName:        "redis-server",
+		LabelProcessCommandLine: "/usr/bin/redis-server *:6379",
+		LabelProcessPID:         "1234",
+	}
+	assert.Equal(t, []observer.Endpoint{
+		observer.NewPortEndpoint("host_observer/(127.0.0.1:3306/TCP)", "127.0.0.1", 3306, observer.ProtocolTCP, map[string]string{}),
+		observer.NewPortEndpoint("host_observer/(127.0.0.53:53/UDP)", "127.0.0.53", 53, observer.ProtocolUDP, map[string]string{}),
+		observer.NewPortEndpoint("host_observer/(::1:8080/TCP)", "::1", 8080, observer.ProtocolTCP, map[string]string{}),
+		observer.NewPortEndpoint("host_observer/(localhost:6379/TCP)", "localhost", 6379, observer.ProtocolTCP, redisLabels),
+	}, sink.added)
+
+	// Nothing changed so no notifications.
+	obs.refresh()
+	assert.Len(t, sink.added, 4)
+	assert.Empty(t, sink.removed)
+	assert.Empty(t, sink.changed)
+
+	// Process information becomes available and mysql goes away.
+	proc.addProcess("42", "mysqld", []string{"/usr/sbin/mysqld"}, "20869")
+	proc.writeNet("tcp", procNetTCP[:strings.Index(procNetTCP, "   1:")])
+	obs.refresh()
+
+	assert.Len(t, sink.added, 4)
+	assert.Equal(t, []observer.Endpoint{
+		observer.NewPortEndpoint("host_observer/(127.0.0.1:3306/TCP)", "127.0.0.1", 3306, observer.ProtocolTCP, map[string]string{}),
+	}, sink.removed)
+	assert.Equal(t, []observer.Endpoint{
+		observer.NewPortEndpoint("host_observer/(::1:8080/TCP)", "::1", 8080, observer.ProtocolTCP, map[string]string{
+			LabelProcessName:        "mysqld",
+			LabelProcessCommandLine: "/usr/sbin/mysqld",
+			LabelProcessPID:         "42",
+		}),
+	}, sink.changed)
+
+	// A late listener gets the current state.
+	late := &endpointSink{}
+	obs.ListAndWatch(late)
+	assert.Len(t, late.added, 3)
+}
+
+func TestRefreshError(t *testing.T) {
+	proc := newFakeProc(t)
+	defer proc.cleanup()
+
+	proc.writeNet("tcp", "header\n 0: invalid 00000000:0000 0A 0 0 0 0 0 1\n")
+
+	obs := newTestObserver(proc.root)
+	sink := &endpointSink{}
+	obs.ListAndWatch(sink)
+	obs.refresh()
+
+	assert.Empty(t, sink.added)
+}
+
+func TestStartShutdown(t *testing.T) {
+	proc := newFakeProc(t)
+	defer proc.cleanup()
+
+	proc.writeNet("tcp", procNetTCP)
+
+	obs := newTestObserver(proc.root)
+	obs.config.RefreshInterval = 10 * time.Millisecond
+	sink := &endpointSink{}
+	obs.ListAndWatch(sink)
+
+	require.NoError(t, obs.Start(context.Background(), componenttest.NewNopHost()))
+	require.Eventually(t, func() bool {
+		sink.Lock()
+		defer sink.Unlock()
+		return len(sink.added) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, obs.Shutdown(context.Background()))
+	// Shutting down again is a noop.
+	require.NoError(t, obs.Shutdown(context.Background()))
+}
@@ -0,0 +1,12 @@
+module github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer/hostobserver
+
+go 1.14
+
+require (
+	github.com/open-telemetry/opentelemetry-collector v0.3.1-0.20200427150635-ca4b8231de7c
+	github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer v0.0.0
+	github.com/stretchr/testify v1.5.1
+	go.uber.org/zap v1.14.1
+)
+
+replace github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer => ../
@@ -0,0 +1,210 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hostobserver
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
+)
+
+const (
+	defaultProcRoot = "/proc"
+
+	// tcpStateListen is the state of a listening TCP socket.
+	tcpStateListen = "0A"
+	// udpStateClose is the state of a bound but unconnected UDP socket.
+	udpStateClose = "07"
+)
+
+// procNetFiles are the files under <proc>/net containing the sockets of the host.
+var procNetFiles = []struct {
+	name        string
+	protocol    observer.Protocol
+	listenState string
+}{
+	{"tcp", observer.ProtocolTCP, tcpStateListen},
+	{"tcp6", observer.ProtocolTCP, tcpStateListen},
+	{"udp", observer.ProtocolUDP, udpStateClose},
+	{"udp6", observer.ProtocolUDP, udpStateClose},
+}
+
+// socket is a listening socket read from procfs.
+type socket struct {
+	protocol observer.Protocol
+	ip       net.IP
+	port     uint16
+	inode    string
+}
+
+// process is the process that owns a socket.
+type process struct {
+	pid     int
+	name    string
+	cmdline string
+}
+
+// listeningSockets returns all listening TCP sockets and unconnected UDP sockets
+// of the host. Files that do not exist (e.g. when IPv6 is disabled) are skipped.
+func listeningSockets(procRoot string) ([]socket, error) {
+	var sockets []socket
+	for _, f := range procNetFiles {
+		file, err := os.Open(filepath.Join(procRoot, "net", f.name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		s, err := parseProcNet(file, f.protocol, f.listenState)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed parsing %s: %v", f.name, err)
+		}
+		sockets = append(sockets, s...)
+	}
+	return sockets, nil
+}
+
+// parseProcNet parses the contents of /proc/net/{tcp,tcp6,udp,udp6} and returns the
+// sockets in listenState. The format of the file is:
+//
+//	sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+//	 0: 00000000:18EB 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 20866 ...
+func parseProcNet(r io.Reader, protocol observer.Protocol, listenState string) ([]socket, error) {
+	var sockets []socket
+
+	scanner := bufio.NewScanner(r)
+	// Skip the header.
+	scanner.Scan()
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 {
+			continue
+		}
+		if fields[3] != listenState {
+			continue
+		}
+
+		ip, port, err := parseAddr(fields[1])
+		if err != nil {
+			return nil, err
+		}
+		if _, remotePort, err := parseAddr(fields[2]); err != nil || remotePort != 0 {
+			// Connected sockets are not listening.
+			continue
+		}
+
+		sockets = append(sockets, socket{
+			protocol: protocol,
+			ip:       ip,
+			port:     port,
+			inode:    fields[9],
+		})
+	}
+
+	return sockets, scanner.Err()
+}
+
+// parseAddr parses an address of the form <hex ip>:<hex port>. The IP address is
+// stored as 32-bit words in host byte order (little endian on supported platforms).
+func parseAddr(s string) (net.IP, uint16, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 {
+		return nil, 0, fmt.Errorf("invalid address %q", s)
+	}
+
+	ipBytes, err := hex.DecodeString(parts[0])
+	if err != nil || (len(ipBytes) != net.IPv4len && len(ipBytes) != net.IPv6len) {
+		return nil, 0, fmt.Errorf("invalid address %q", s)
+	}
+	for i := 0; i < len(ipBytes); i += 4 {
+		ipBytes[i], ipBytes[i+1], ipBytes[i+2], ipBytes[i+3] = ipBytes[i+3], ipBytes[i+2], ipBytes[i+1], ipBytes[i]
+	}
+
+	port, err := strconv.ParseUint(parts[1], 16, 16)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid port in address %q: %v", s, err)
+	}
+
+	return net.IP(ipBytes), uint16(port), nil
+}
+
+// socketOwners maps socket inodes to the processes that have them open. Processes
+// that can not be inspected (e.g. due to permissions) are skipped.
+func socketOwners(procRoot string) map[string]process {
+	owners := map[string]process{}
+
+	dirs, err := ioutil.ReadDir(procRoot)
+	if err != nil {
+		return owners
+	}
+
+	for _, dir := range dirs {
+		pid, err := strconv.Atoi(dir.Name())
+		if err != nil || !dir.IsDir() {
+			continue
+		}
+
+		fdPath := filepath.Join(procRoot, dir.Name(), "fd")
+		fds, err := ioutil.ReadDir(fdPath)
+		if err != nil {
+			continue
+		}
+
+		var proc *process
+		for _, fd := range fds {
+			link, err := os.Readlink(filepath.Join(fdPath, fd.Name()))
+			if err != nil || !strings.HasPrefix(link, "socket:[") {
+				continue
+			}
+			inode := strings.TrimSuffix(strings.TrimPrefix(link, "socket:["), "]")
+
+			if proc == nil {
+				proc = readProcess(procRoot, pid)
+			}
+			owners[inode] = *proc
+		}
+	}
+
+	return owners
+}
+
+// readProcess reads the name and command line of a process.
+func readProcess(procRoot string, pid int) *process {
+	proc := &process{pid: pid}
+	dir := filepath.Join(procRoot, strconv.Itoa(pid))
+
+	if comm, err := ioutil.ReadFile(filepath.Join(dir, "comm")); err == nil {
+		proc.name = strings.TrimSpace(string(comm))
+	}
+	if cmdline, err := ioutil.ReadFile(filepath.Join(dir, "cmdline")); err == nil {
+		// Arguments are separated by null bytes.
+		proc.cmdline = strings.TrimSpace(strings.Join(strings.Split(strings.TrimRight(string(cmdline), "\x00"), "\x00"), " "))
+	}
+
+	return proc
+}
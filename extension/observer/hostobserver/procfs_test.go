@@ -0,0 +1,147 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hostobserver
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
+)
+
+const procNetTCP = `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000:18EB 00000000:0000 0A 00000000:00000000 00:00000000 00000000   999        0 20866 1 0000000000000000 100 0 0 10 0
+   1: 0100007F:0CEA 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 20867 1 0000000000000000 100 0 0 10 0
+   2: 0100007F:18EB 0100007F:D2A4 01 00000000:00000000 00:00000000 00000000   999        0 31337 1 0000000000000000 20 4 30 10 -1
+`
+
+const procNetTCP6 = `  sl  local_address                         remote_address                        st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000000000000000000000000000:18EB 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000   999        0 20868 1 0000000000000000 100 0 0 10 0
+   1: 00000000000000000000000001000000:1F90 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 20869 1 0000000000000000 100 0 0 10 0
+`
+
+const procNetUDP = `   sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode ref pointer drops
+  1: 3500007F:0035 00000000:0000 07 00000000:00000000 00:00000000 00000000   101        0 20870 2 0000000000000000 0
+  2: 0100007F:E5A7 0100007F:0035 01 00000000:00000000 00:00000000 00000000     0        0 31338 2 0000000000000000 0
+`
+
+// fakeProc creates a procfs like directory tree.
+type fakeProc struct {
+	t    *testing.T
+	root string
+}
+
+func newFakeProc(t *testing.T) *fakeProc {
+	root, err := ioutil.TempDir("", "hostobserver")
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "net"), 0755))
+	return &fakeProc{t: t, root: root}
+}
+
+func (f *fakeProc) cleanup() {
+	os.RemoveAll(f.root)
+}
+
+func (f *fakeProc) writeNet(name string, contents string) {
+	require.NoError(f.t, ioutil.WriteFile(filepath.Join(f.root, "net", name), []byte(contents), 0644))
+}
+
+func (f *fakeProc) addProcess(pid string, comm string, cmdline []string, inodes ...string) {
+	dir := filepath.Join(f.root, pid)
+	require.NoError(f.t, os.MkdirAll(filepath.Join(dir, "fd"), 0755))
+	require.NoError(f.t, ioutil.WriteFile(filepath.Join(dir, "comm"), []byte(comm+"\n"), 0644))
+	require.NoError(f.t, ioutil.WriteFile(filepath.Join(dir, "cmdline"), []byte(strings.Join(cmdline, "\x00")+"\x00"), 0644))
+	require.NoError(f.t, os.Symlink("/dev/null", filepath.Join(dir, "fd", "0")))
+	for i, inode := range inodes {
+		require.NoError(f.t, os.Symlink("socket:["+inode+"]", filepath.Join(dir, "fd", string(rune('3'+i)))))
+	}
+}
+
+func TestParseAddr(t *testing.T) {
+	ip, port, err := parseAddr("0100007F:18EB")
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1", ip.String())
+	assert.EqualValues(t, 6379, port)
+
+	ip, port, err = parseAddr("00000000000000000000000001000000:1F90")
+	require.NoError(t, err)
+	assert.Equal(t, "::1", ip.String())
+	assert.EqualValues(t, 8080, port)
+
+	ip, _, err = parseAddr("0000000000000000FFFF00000100007F:0050")
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1", ip.String())
+
+	for _, invalid := range []string{"", "0100007F", "0100007Z:18EB", "01007F:18EB", "0100007F:FFFFF"} {
+		_, _, err := parseAddr(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestParseProcNet(t *testing.T) {
+	tcp, err := parseProcNet(strings.NewReader(procNetTCP), observer.ProtocolTCP, tcpStateListen)
+	require.NoError(t, err)
+	assert.Equal(t, []socket{
+		{observer.ProtocolTCP, net.IPv4(0, 0, 0, 0).To4(), 6379, "20866"},
+		{observer.ProtocolTCP, net.IPv4(127, 0, 0, 1).To4(), 3306, "20867"},
+	}, tcp)
+
+	udp, err := parseProcNet(strings.NewReader(procNetUDP), observer.ProtocolUDP, udpStateClose)
+	require.NoError(t, err)
+	assert.Equal(t, []socket{
+		{observer.ProtocolUDP, net.IPv4(127, 0, 0, 53).To4(), 53, "20870"},
+	}, udp)
+
+	_, err = parseProcNet(strings.NewReader("header\n 0: invalid 00000000:0000 0A 0 0 0 0 0 1\n"), observer.ProtocolTCP, tcpStateListen)
+	assert.Error(t, err)
+}
+
+func TestListeningSockets(t *testing.T) {
+	proc := newFakeProc(t)
+	defer proc.cleanup()
+
+	// tcp6 and udp6 are missing which is the case when IPv6 is disabled.
+	proc.writeNet("tcp", procNetTCP)
+	proc.writeNet("udp", procNetUDP)
+
+	sockets, err := listeningSockets(proc.root)
+	require.NoError(t, err)
+	assert.Len(t, sockets, 3)
+}
+
+func TestSocketOwners(t *testing.T) {
+	proc := newFakeProc(t)
+	defer proc.cleanup()
+
+	proc.addProcess("1234", "redis-server", []string{"/usr/bin/redis-server", "*:6379"}, "20866", "20868")
+	proc.addProcess("42", "mysqld", []string{"/usr/sbin/mysqld"}, "20867")
+	// Not a process directory.
+	require.NoError(t, os.MkdirAll(filepath.Join(proc.root, "sys"), 0755))
+
+	owners := socketOwners(proc.root)
+	redis := process{pid: 1234, name: "redis-server", cmdline: "/usr/bin/redis-server *:6379"}
+	assert.Equal(t, map[string]process{
+		"20866": redis,
+		"20868": redis,
+		"20867": {pid: 42, name: "mysqld", cmdline: "/usr/sbin/mysqld"},
+	}, owners)
+}
@@ -0,0 +1,34 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sobserver
+
+import (
+	"github.com/open-telemetry/opentelemetry-collector/config/configmodels"
+)
+
+// Config defines configuration for the k8s observer.
+type Config struct {
+	configmodels.ExtensionSettings `mapstructure:",squash"`
+
+	// Node is the name of the node whose pods should be observed. When empty
+	// pods on all nodes are observed. When running as a daemonset the node
+	// name can be passed in through the downward API as an environment
+	// variable and referenced as node: ${K8S_NODE_NAME}.
+	Node string `mapstructure:"node"`
+
+	// Namespace restricts the observed pods to a single namespace. When empty
+	// pods in all namespaces are observed.
+	Namespace string `mapstructure:"namespace"`
+}
@@ -12,30 +12,6 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package interval
-
-import (
-	"testing"
-	"time"
-)
-
-func TestScheduler(t *testing.T) {
-	f := &fakeRunnable{}
-	s := NewRunner(time.Second, f)
-	go func() {
-		_ = s.Start()
-	}()
-	s.Stop()
-	// getting here is success
-}
-
-type fakeRunnable struct {
-}
-
-func (t *fakeRunnable) Setup() error {
-	return nil
-}
-
-func (fakeRunnable) Run() error {
-	return nil
-}
+// Package k8sobserver implements an observer extension that watches the
+// Kubernetes API for pods and reports their ports as endpoints.
+package k8sobserver
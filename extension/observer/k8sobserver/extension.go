@@ -0,0 +1,103 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sobserver
+
+import (
+	"context"
+	"sync"
+
+	"github.com/open-telemetry/opentelemetry-collector/component"
+	"go.uber.org/zap"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
+)
+
+const podNodeField = "spec.nodeName"
+
+var _ component.ServiceExtension = (*k8sObserver)(nil)
+var _ observer.Observable = (*k8sObserver)(nil)
+
+// k8sObserver watches pods through an informer and reports them as endpoints.
+type k8sObserver struct {
+	logger   *zap.Logger
+	config   *Config
+	informer cache.SharedInformer
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// newObserver creates a new k8s observer extension.
+func newObserver(logger *zap.Logger, config *Config, client kubernetes.Interface) (*k8sObserver, error) {
+	return &k8sObserver{
+		logger:   logger,
+		config:   config,
+		informer: newPodInformer(client, config.Namespace, config.Node),
+		stop:     make(chan struct{}),
+	}, nil
+}
+
+// newPodInformer creates an informer for pods optionally restricted to a namespace and node.
+func newPodInformer(client kubernetes.Interface, namespace string, node string) cache.SharedInformer {
+	fieldSelector := fields.Everything()
+	if node != "" {
+		fieldSelector = fields.OneTermEqualSelector(podNodeField, node)
+	}
+
+	return cache.NewSharedInformer(
+		&cache.ListWatch{
+			ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+				opts.FieldSelector = fieldSelector.String()
+				return client.CoreV1().Pods(namespace).List(opts)
+			},
+			WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+				opts.FieldSelector = fieldSelector.String()
+				return client.CoreV1().Pods(namespace).Watch(opts)
+			},
+		},
+		&v1.Pod{},
+		0,
+	)
+}
+
+// Start the informer in the background.
+func (k *k8sObserver) Start(ctx context.Context, host component.Host) error {
+	go k.informer.Run(k.stop)
+	return nil
+}
+
+// Shutdown stops the informer.
+func (k *k8sObserver) Shutdown(ctx context.Context) error {
+	k.stopOnce.Do(func() {
+		close(k.stop)
+	})
+	return nil
+}
+
+// ListAndWatch notifies watcher with the current state and sends subsequent
+// state changes.
+func (k *k8sObserver) ListAndWatch(listener observer.Notify) {
+	k.informer.AddEventHandler(&handler{
+		logger:      k.logger,
+		idNamespace: k.config.Name(),
+		watcher:     listener,
+	})
+}
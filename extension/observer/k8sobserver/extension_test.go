@@ -0,0 +1,116 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sobserver
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector/component/componenttest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
+)
+
+// endpointSink records all endpoint notifications.
+type endpointSink struct {
+	sync.Mutex
+	added   []observer.Endpoint
+	removed []observer.Endpoint
+	changed []observer.Endpoint
+}
+
+var _ observer.Notify = (*endpointSink)(nil)
+
+func (s *endpointSink) OnAdd(added []observer.Endpoint) {
+	s.Lock()
+	defer s.Unlock()
+	s.added = append(s.added, added...)
+}
+
+func (s *endpointSink) OnRemove(removed []observer.Endpoint) {
+	s.Lock()
+	defer s.Unlock()
+	s.removed = append(s.removed, removed...)
+}
+
+func (s *endpointSink) OnChange(changed []observer.Endpoint) {
+	s.Lock()
+	defer s.Unlock()
+	s.changed = append(s.changed, changed...)
+}
+
+func (s *endpointSink) reset() {
+	s.Lock()
+	defer s.Unlock()
+	s.added = nil
+	s.removed = nil
+	s.changed = nil
+}
+
+func (s *endpointSink) counts() (added, removed, changed int) {
+	s.Lock()
+	defer s.Unlock()
+	return len(s.added), len(s.removed), len(s.changed)
+}
+
+func TestObserver(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	factory := &Factory{}
+	cfg := factory.CreateDefaultConfig().(*Config)
+
+	obs, err := newObserver(zap.NewNop(), cfg, client)
+	require.NoError(t, err)
+
+	pod := newPod("pod-1", "1.2.3.4")
+	_, err = client.CoreV1().Pods(pod.Namespace).Create(pod)
+	require.NoError(t, err)
+
+	require.NoError(t, obs.Start(context.Background(), componenttest.NewNopHost()))
+	defer obs.Shutdown(context.Background())
+
+	sink := &endpointSink{}
+	obs.ListAndWatch(sink)
+
+	require.Eventually(t, func() bool {
+		added, _, _ := sink.counts()
+		return added == 2
+	}, 5*time.Second, 10*time.Millisecond, "initial pod endpoints not observed")
+
+	updated := newPod("pod-1", "1.2.3.4")
+	updated.Labels["env"] = "prod"
+	_, err = client.CoreV1().Pods(pod.Namespace).Update(updated)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		_, _, changed := sink.counts()
+		return changed == 2
+	}, 5*time.Second, 10*time.Millisecond, "pod endpoint changes not observed")
+
+	require.NoError(t, client.CoreV1().Pods(pod.Namespace).Delete(pod.Name, &metav1.DeleteOptions{}))
+
+	require.Eventually(t, func() bool {
+		_, removed, _ := sink.counts()
+		return removed == 2
+	}, 5*time.Second, 10*time.Millisecond, "pod endpoint removal not observed")
+
+	assert.NoError(t, obs.Shutdown(context.Background()))
+}
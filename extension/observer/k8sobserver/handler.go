@@ -0,0 +1,193 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sobserver
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.uber.org/zap"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
+)
+
+// Keys of the metadata added to endpoint labels in addition to the pod labels.
+const (
+	// LabelNamespace is the namespace of the pod.
+	LabelNamespace = "k8s.namespace.name"
+	// LabelPodName is the name of the pod.
+	LabelPodName = "k8s.pod.name"
+	// LabelPodUID is the UID of the pod.
+	LabelPodUID = "k8s.pod.uid"
+	// LabelContainerName is the name of the container declaring the port.
+	LabelContainerName = "k8s.container.name"
+	// LabelPortName is the name of the container port, if it has one.
+	LabelPortName = "k8s.port.name"
+	// LabelAnnotationPrefix is prepended to the keys of pod annotations.
+	LabelAnnotationPrefix = "k8s.annotation."
+)
+
+var _ cache.ResourceEventHandler = (*handler)(nil)
+
+// handler handles k8s cache informer callbacks and converts them to endpoint notifications.
+type handler struct {
+	logger *zap.Logger
+	// idNamespace should be some unique token to distinguish multiple handler instances.
+	idNamespace string
+	// watcher is the callback for discovered endpoints.
+	watcher observer.Notify
+}
+
+// OnAdd is called in response to a pod being added.
+func (h *handler) OnAdd(obj interface{}) {
+	pod, ok := obj.(*v1.Pod)
+	if !ok {
+		h.logger.Error("object received was not of type v1.Pod", zap.Any("received", obj))
+		return
+	}
+
+	if endpoints := convertPodToEndpoints(h.idNamespace, pod); len(endpoints) > 0 {
+		h.watcher.OnAdd(endpoints)
+	}
+}
+
+// OnUpdate is called in response to an existing pod changing.
+func (h *handler) OnUpdate(oldObj, newObj interface{}) {
+	oldPod, ok := oldObj.(*v1.Pod)
+	if !ok {
+		h.logger.Error("object received was not of type v1.Pod", zap.Any("received", oldObj))
+		return
+	}
+	newPod, ok := newObj.(*v1.Pod)
+	if !ok {
+		h.logger.Error("object received was not of type v1.Pod", zap.Any("received", newObj))
+		return
+	}
+
+	oldEndpoints := convertPodToEndpoints(h.idNamespace, oldPod)
+	newEndpoints := convertPodToEndpoints(h.idNamespace, newPod)
+
+	oldByID := map[string]observer.Endpoint{}
+	for _, e := range oldEndpoints {
+		oldByID[e.ID()] = e
+	}
+	newByID := map[string]observer.Endpoint{}
+	for _, e := range newEndpoints {
+		newByID[e.ID()] = e
+	}
+
+	var removed, added, changed []observer.Endpoint
+
+	for _, e := range oldEndpoints {
+		if _, ok := newByID[e.ID()]; !ok {
+			removed = append(removed, e)
+		}
+	}
+
+	for _, e := range newEndpoints {
+		old, ok := oldByID[e.ID()]
+		switch {
+		case !ok:
+			added = append(added, e)
+		case !reflect.DeepEqual(old, e):
+			changed = append(changed, e)
+		}
+	}
+
+	if len(removed) > 0 {
+		h.watcher.OnRemove(removed)
+	}
+	if len(added) > 0 {
+		h.watcher.OnAdd(added)
+	}
+	if len(changed) > 0 {
+		h.watcher.OnChange(changed)
+	}
+}
+
+// OnDelete is called in response to a pod being deleted.
+func (h *handler) OnDelete(obj interface{}) {
+	var pod *v1.Pod
+	switch o := obj.(type) {
+	case *v1.Pod:
+		pod = o
+	case cache.DeletedFinalStateUnknown:
+		pod, _ = o.Obj.(*v1.Pod)
+	}
+	if pod == nil {
+		h.logger.Error("object received was not of type v1.Pod", zap.Any("received", obj))
+		return
+	}
+
+	if endpoints := convertPodToEndpoints(h.idNamespace, pod); len(endpoints) > 0 {
+		h.watcher.OnRemove(endpoints)
+	}
+}
+
+// convertPodToEndpoints returns a HostEndpoint for the pod and a PortEndpoint for
+// every port declared by its containers. Pods that do not have an IP yet or that
+// have terminated have no endpoints.
+func convertPodToEndpoints(idNamespace string, pod *v1.Pod) []observer.Endpoint {
+	podIP := pod.Status.PodIP
+	if podIP == "" {
+		return nil
+	}
+	if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+		return nil
+	}
+
+	podID := fmt.Sprintf("%s/%s", idNamespace, pod.UID)
+	endpoints := []observer.Endpoint{
+		observer.NewHostEndpoint(podID, podIP, podLabels(pod)),
+	}
+
+	for _, container := range pod.Spec.Containers {
+		for _, port := range container.Ports {
+			labels := podLabels(pod)
+			labels[LabelContainerName] = container.Name
+			if port.Name != "" {
+				labels[LabelPortName] = port.Name
+			}
+
+			protocol := port.Protocol
+			if protocol == "" {
+				protocol = v1.ProtocolTCP
+			}
+
+			endpointID := fmt.Sprintf("%s/%s/%s/%d", podID, container.Name, strings.ToLower(string(protocol)), port.ContainerPort)
+			endpoints = append(endpoints, observer.NewPortEndpoint(endpointID, podIP, uint16(port.ContainerPort), observer.Protocol(protocol), labels))
+		}
+	}
+
+	return endpoints
+}
+
+// podLabels returns a new map with the pod labels, annotations and identifying metadata.
+func podLabels(pod *v1.Pod) map[string]string {
+	labels := make(map[string]string, len(pod.Labels)+len(pod.Annotations)+3)
+	for k, v := range pod.Labels {
+		labels[k] = v
+	}
+	for k, v := range pod.Annotations {
+		labels[LabelAnnotationPrefix+k] = v
+	}
+	labels[LabelNamespace] = pod.Namespace
+	labels[LabelPodName] = pod.Name
+	labels[LabelPodUID] = string(pod.UID)
+	return labels
+}
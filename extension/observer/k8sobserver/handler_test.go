@@ -0,0 +1,152 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sobserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
+)
+
+func newPod(name string, ip string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "default",
+			UID:         types.UID("pod-" + name + "-UID"),
+			Labels:      map[string]string{"app": "redis"},
+			Annotations: map[string]string{"prometheus.io/path": "/metrics"},
+		},
+		Spec: v1.PodSpec{
+			NodeName: "node-1",
+			Containers: []v1.Container{
+				{
+					Name: "redis",
+					Ports: []v1.ContainerPort{
+						{Name: "redis", ContainerPort: 6379, Protocol: v1.ProtocolTCP},
+					},
+				},
+			},
+		},
+		Status: v1.PodStatus{
+			PodIP: ip,
+			Phase: v1.PodRunning,
+		},
+	}
+}
+
+func expectedLabels(pod *v1.Pod, extra map[string]string) map[string]string {
+	labels := map[string]string{
+		"app":                               "redis",
+		"k8s.annotation.prometheus.io/path": "/metrics",
+		LabelNamespace:                      "default",
+		LabelPodName:                        pod.Name,
+		LabelPodUID:                         string(pod.UID),
+	}
+	for k, v := range extra {
+		labels[k] = v
+	}
+	return labels
+}
+
+func TestConvertPodToEndpoints(t *testing.T) {
+	pod := newPod("pod-1", "1.2.3.4")
+
+	endpoints := convertPodToEndpoints("k8s_observer", pod)
+
+	assert.Equal(t, []observer.Endpoint{
+		observer.NewHostEndpoint("k8s_observer/pod-pod-1-UID", "1.2.3.4", expectedLabels(pod, nil)),
+		observer.NewPortEndpoint("k8s_observer/pod-pod-1-UID/redis/tcp/6379", "1.2.3.4", 6379, observer.ProtocolTCP, expectedLabels(pod, map[string]string{
+			LabelContainerName: "redis",
+			LabelPortName:      "redis",
+		})),
+	}, endpoints)
+}
+
+func TestConvertPodWithoutEndpoints(t *testing.T) {
+	pending := newPod("pending", "")
+	assert.Empty(t, convertPodToEndpoints("k8s_observer", pending))
+
+	completed := newPod("completed", "1.2.3.4")
+	completed.Status.Phase = v1.PodSucceeded
+	assert.Empty(t, convertPodToEndpoints("k8s_observer", completed))
+}
+
+func TestHandlerAddDelete(t *testing.T) {
+	sink := &endpointSink{}
+	h := &handler{logger: zap.NewNop(), idNamespace: "k8s_observer", watcher: sink}
+	pod := newPod("pod-1", "1.2.3.4")
+
+	h.OnAdd(pod)
+	assert.Len(t, sink.added, 2)
+
+	h.OnDelete(cache.DeletedFinalStateUnknown{Key: "default/pod-1", Obj: pod})
+	assert.Len(t, sink.removed, 2)
+
+	// Not a pod so should be ignored.
+	h.OnAdd(&v1.Node{})
+	h.OnDelete(&v1.Node{})
+	assert.Len(t, sink.added, 2)
+	assert.Len(t, sink.removed, 2)
+}
+
+func TestHandlerUpdate(t *testing.T) {
+	sink := &endpointSink{}
+	h := &handler{logger: zap.NewNop(), idNamespace: "k8s_observer", watcher: sink}
+
+	// Pod gets an IP assigned.
+	pending := newPod("pod-1", "")
+	running := newPod("pod-1", "1.2.3.4")
+	h.OnUpdate(pending, running)
+	assert.Len(t, sink.added, 2)
+	assert.Empty(t, sink.removed)
+	assert.Empty(t, sink.changed)
+
+	// Labels change and a port is added.
+	relabeled := newPod("pod-1", "1.2.3.4")
+	relabeled.Labels["env"] = "prod"
+	relabeled.Spec.Containers[0].Ports = append(relabeled.Spec.Containers[0].Ports,
+		v1.ContainerPort{ContainerPort: 9121})
+	h.OnUpdate(running, relabeled)
+	assert.Len(t, sink.added, 3)
+	assert.Equal(t, "k8s_observer/pod-pod-1-UID/redis/tcp/9121", sink.added[2].ID())
+	assert.Len(t, sink.changed, 2)
+	assert.Empty(t, sink.removed)
+
+	// Port removed.
+	h.OnUpdate(relabeled, running)
+	assert.Len(t, sink.removed, 1)
+	assert.Equal(t, "k8s_observer/pod-pod-1-UID/redis/tcp/9121", sink.removed[0].ID())
+
+	// No change results in no notifications.
+	sink.reset()
+	h.OnUpdate(running, newPod("pod-1", "1.2.3.4"))
+	assert.Empty(t, sink.added)
+	assert.Empty(t, sink.removed)
+	assert.Empty(t, sink.changed)
+
+	// Pod completes.
+	completed := newPod("pod-1", "1.2.3.4")
+	completed.Status.Phase = v1.PodSucceeded
+	h.OnUpdate(running, completed)
+	assert.Len(t, sink.removed, 2)
+}
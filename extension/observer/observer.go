@@ -82,20 +82,21 @@ var _ Endpoint = (*HostEndpoint)(nil)
 // PortEndpoint is an endpoint that has a target as well as a port.
 type PortEndpoint struct {
 	endpointBase
-	Port uint16
+	Port     uint16
+	Protocol Protocol
 }
 
 func (p *PortEndpoint) String() string {
-	return fmt.Sprintf("PortEndpoint{ID: %v, Target: %v, Port: %d, Labels: %v}", p.ID(), p.target, p.Port, p.labels)
+	return fmt.Sprintf("PortEndpoint{ID: %v, Target: %v, Port: %d, Protocol: %v, Labels: %v}", p.ID(), p.target, p.Port, p.Protocol, p.labels)
 }
 
 // NewPortEndpoint creates a PortEndpoint.
-func NewPortEndpoint(id string, target string, port uint16, labels map[string]string) *PortEndpoint {
+func NewPortEndpoint(id string, target string, port uint16, protocol Protocol, labels map[string]string) *PortEndpoint {
 	return &PortEndpoint{endpointBase: endpointBase{
 		id:     id,
 		target: target,
 		labels: labels,
-	}, Port: port}
+	}, Port: port, Protocol: protocol}
 }
 
 var _ Endpoint = (*PortEndpoint)(nil)
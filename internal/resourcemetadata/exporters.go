@@ -0,0 +1,52 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcemetadata
+
+import (
+	"sync"
+)
+
+// metadataExporters holds metadata exporters by the name of their config.
+var metadataExporters = struct {
+	sync.RWMutex
+	exporters map[string]MetadataExporter
+}{exporters: map[string]MetadataExporter{}}
+
+// RegisterMetadataExporter makes an exporter available to receivers sending
+// metadata, under the name of its config (e.g. "signalfx/1"). Exporters are
+// expected to register when started, since they are started before receivers.
+func RegisterMetadataExporter(name string, exporter MetadataExporter) {
+	metadataExporters.Lock()
+	defer metadataExporters.Unlock()
+
+	metadataExporters.exporters[name] = exporter
+}
+
+// UnregisterMetadataExporter removes an exporter registered with RegisterMetadataExporter.
+func UnregisterMetadataExporter(name string) {
+	metadataExporters.Lock()
+	defer metadataExporters.Unlock()
+
+	delete(metadataExporters.exporters, name)
+}
+
+// GetMetadataExporter returns the metadata exporter registered under the given name.
+func GetMetadataExporter(name string) (MetadataExporter, bool) {
+	metadataExporters.RLock()
+	defer metadataExporters.RUnlock()
+
+	exporter, ok := metadataExporters.exporters[name]
+	return exporter, ok
+}
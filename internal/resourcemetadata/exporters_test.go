@@ -0,0 +1,42 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcemetadata
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterMetadataExporter(t *testing.T) {
+	_, ok := GetMetadataExporter("exporter/1")
+	assert.False(t, ok)
+
+	exporter := &nopMetadataExporter{}
+	RegisterMetadataExporter("exporter/1", exporter)
+	got, ok := GetMetadataExporter("exporter/1")
+	assert.True(t, ok)
+	assert.Equal(t, exporter, got)
+
+	UnregisterMetadataExporter("exporter/1")
+	_, ok = GetMetadataExporter("exporter/1")
+	assert.False(t, ok)
+}
+
+type nopMetadataExporter struct{}
+
+func (*nopMetadataExporter) ConsumeMetadata([]*MetadataUpdate) error {
+	return nil
+}
@@ -0,0 +1,5 @@
+module github.com/open-telemetry/opentelemetry-collector-contrib/internal/resourcemetadata
+
+go 1.14
+
+require github.com/stretchr/testify v1.5.1
@@ -0,0 +1,41 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package resourcemetadata defines how receivers collecting metadata of
+// resources (e.g. the properties of Kubernetes pods) pass it to exporters,
+// without receivers and exporters depending on each other.
+package resourcemetadata
+
+// MetadataExporter is implemented by exporters that can consume metadata
+// of resources, in addition to metrics. See RegisterMetadataExporter.
+type MetadataExporter interface {
+	// ConsumeMetadata receives changes to the properties of resources.
+	ConsumeMetadata(metadata []*MetadataUpdate) error
+}
+
+// MetadataUpdate describes changes to the properties of a resource, identified
+// by the value of its resource ID key (e.g. the value of "k8s.pod.uid").
+type MetadataUpdate struct {
+	ResourceIDKey string
+	ResourceID    string
+	MetadataDelta
+}
+
+// MetadataDelta keeps track of properties that were added, removed or
+// updated. Removed properties are mapped to their last known values.
+type MetadataDelta struct {
+	MetadataToAdd    map[string]string
+	MetadataToRemove map[string]string
+	MetadataToUpdate map[string]string
+}
@@ -15,6 +15,8 @@
 package k8sprocessor
 
 import (
+	"time"
+
 	"github.com/open-telemetry/opentelemetry-collector/config/configmodels"
 )
 
@@ -35,6 +37,66 @@ type Config struct {
 	// Filter section allows specifying filters to filter
 	// pods by labels, fields, namespaces, nodes, etc.
 	Filter FilterConfig `mapstructure:"filter"`
+
+	// Association section allows specifying how data is associated with pods.
+	// Associations are tried in order until one of them identifies a known pod.
+	// Data is associated by pod IP only when no associations are specified.
+	Association []PodAssociationConfig `mapstructure:"pod_association"`
+
+	// Metrics section allows specifying options that only apply to metrics.
+	Metrics MetricsConfig `mapstructure:"metrics"`
+
+	// Ignore section allows specifying pods that data must never be associated with.
+	Ignore IgnoreConfig `mapstructure:"ignore"`
+
+	// PodDeleteGracePeriod is how long a deleted pod is kept so that data sent by it
+	// right before it was deleted can still be tagged. Lowering it reduces the chances
+	// of tagging data with a deleted pod whose IP address was reused by a new pod.
+	PodDeleteGracePeriod time.Duration `mapstructure:"pod_delete_grace_period"`
+
+	// SyncPeriod is how often all pods (and owner, namespace and node metadata) are
+	// resynced with the kubernetes API.
+	SyncPeriod time.Duration `mapstructure:"sync_period"`
+
+	// PodCacheSize is the maximum number of entries in the pod cache. Each pod uses one
+	// entry per identifier it is associated by (see Association). The least recently
+	// updated entries are evicted first. The cache is unbounded when set to 0.
+	PodCacheSize int `mapstructure:"pod_cache_size"`
+}
+
+// IgnoreConfig section allows specifying pods that data must never be associated with.
+// Pods with the annotation `opentelemetry.io/k8s-processor/ignore: "true"` are
+// always ignored.
+type IgnoreConfig struct {
+	// PodNames is a list of regular expressions matched against pod names.
+	// Defaults to jaeger-agent and jaeger-collector. Setting it replaces the defaults.
+	PodNames []string `mapstructure:"pod_names"`
+
+	// Labels ignores pods by their labels. Check IgnoreRuleConfig for more details.
+	Labels []IgnoreRuleConfig `mapstructure:"labels"`
+
+	// Annotations ignores pods by their annotations. Check IgnoreRuleConfig for more details.
+	Annotations []IgnoreRuleConfig `mapstructure:"annotations"`
+}
+
+// IgnoreRuleConfig allows ignoring pods by exactly one label or annotation.
+type IgnoreRuleConfig struct {
+	// Key is the name of the label or annotation.
+	Key string `mapstructure:"key"`
+
+	// Value is an optional regular expression matched against the value of the
+	// label or annotation. Pods with the label or annotation are ignored regardless
+	// of its value when not set.
+	Value string `mapstructure:"value"`
+}
+
+// MetricsConfig section allows specifying options that only apply to metrics.
+type MetricsConfig struct {
+	// PodIPLabel is the name of a timeseries label holding a pod IP address.
+	// When set, each timeseries carrying the label is tagged with the metadata
+	// of the pod with that IP address, in addition to the resource being tagged
+	// as usual. This is useful for metrics that describe many pods at once.
+	PodIPLabel string `mapstructure:"pod_ip_label"`
 }
 
 // ExtractConfig section allows specifying extraction rules to extract
@@ -44,10 +106,17 @@ type ExtractConfig struct {
 	// The field accepts a list of strings.
 	//
 	// Metadata fields supported right now are,
-	//   namespace, podName, deployment, cluster, node and startTime
+	//   namespace, podName, deployment, replicaset, statefulset, daemonset,
+	//   job, cronjob, cluster, node and startTime
+	//
+	// Workload names are resolved from the owner references of the pod and,
+	// for deployments and cron jobs, of the owning replica set or job. This
+	// requires the collector to be allowed to list and watch replica sets and
+	// jobs respectively.
 	//
 	// Specifying anything other than these values will result in an error.
-	// By default all of the fields are extracted and added to spans.
+	// By default namespace, podName, deployment, cluster, node and startTime
+	// are extracted and added to spans.
 	Metadata []string `mapstructure:"metadata"`
 
 	// Annotations allows extracting data from pod annotations and record it
@@ -76,6 +145,13 @@ type ExtractConfig struct {
 //
 // - key represents the annotation name. This must exactly match an annotation name.
 //
+// - from represents the object the field is extracted from. One of pod (default),
+//   namespace (the namespace of the pod) or node (the node the pod runs on).
+//   When tag-name is not specified for namespace and node fields, the default tag
+//   name has the format k8s.<from>.<annotation>.<annotation key>.
+//   Extracting from namespaces and nodes requires the collector to be allowed to
+//   list and watch them.
+//
 // - regex is an optional field used to extract a sub-string from a complex field value.
 //   The supplied regular expression must contain one named parameter with the string "value"
 //   as the name. For example, if your pod spec contains the following annotation,
@@ -100,6 +176,7 @@ type FieldExtractConfig struct {
 	TagName string `mapstructure:"tag_name"`
 	Key     string `mapstructure:"key"`
 	Regex   string `mapstructure:"regex"`
+	From    string `mapstructure:"from"`
 }
 
 // FilterConfig section allows specifying filters to filter
@@ -168,3 +245,19 @@ type FieldFilterConfig struct {
 	//   equals, not-equals, exists, does-not-exist.
 	Op string `mapstructure:"op"`
 }
+
+// PodAssociationConfig specifies one source of a pod identifier.
+type PodAssociationConfig struct {
+	// From represents the source of the identifier. The following sources are supported:
+	//   - resource_attribute: the value of the resource attribute specified by `name`
+	//   - connection: the IP address of the client the data was received from
+	From string `mapstructure:"from"`
+
+	// Name is the resource attribute holding the identifier when From is
+	// resource_attribute. The following attributes are supported:
+	//   - k8s.pod.ip or ip: pod IP address
+	//   - k8s.pod.uid: pod UID
+	//   - k8s.pod.name: pod name, must be accompanied by the k8s.namespace.name attribute
+	//   - container.id: ID of any of the pod's containers
+	Name string `mapstructure:"name"`
+}
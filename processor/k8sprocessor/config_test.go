@@ -17,6 +17,7 @@ package k8sprocessor
 import (
 	"path"
 	"testing"
+	"time"
 
 	"github.com/open-telemetry/opentelemetry-collector/config"
 	"github.com/open-telemetry/opentelemetry-collector/config/configcheck"
@@ -50,6 +51,8 @@ func TestLoadConfig(t *testing.T) {
 				TypeVal: "k8s_tagger",
 				NameVal: "k8s_tagger",
 			},
+			PodDeleteGracePeriod: 2 * time.Minute,
+			SyncPeriod:           5 * time.Minute,
 		})
 
 	p1 := config.Processors["k8s_tagger/2"]
@@ -61,7 +64,7 @@ func TestLoadConfig(t *testing.T) {
 			},
 			Passthrough: false,
 			Extract: ExtractConfig{
-				Metadata: []string{"podName", "deployment", "cluster", "namespace", "node", "startTime"},
+				Metadata: []string{"podName", "deployment", "cluster", "namespace", "node", "startTime", "replicaset", "cronjob"},
 				Annotations: []FieldExtractConfig{
 					{TagName: "a1", Key: "annotation-one"},
 					{TagName: "a2", Key: "annotation-two", Regex: "field=(?P<value>.+)"},
@@ -69,6 +72,7 @@ func TestLoadConfig(t *testing.T) {
 				Labels: []FieldExtractConfig{
 					{TagName: "l1", Key: "label1"},
 					{TagName: "l2", Key: "label2", Regex: "field=(?P<value>.+)"},
+					{TagName: "team", Key: "team", From: "namespace"},
 				},
 			},
 			Filter: FilterConfig{
@@ -84,5 +88,21 @@ func TestLoadConfig(t *testing.T) {
 					{Key: "key2", Value: "value2", Op: "not-equals"},
 				},
 			},
+			Association: []PodAssociationConfig{
+				{From: "resource_attribute", Name: "k8s.pod.uid"},
+				{From: "resource_attribute", Name: "k8s.pod.ip"},
+				{From: "connection"},
+			},
+			Metrics: MetricsConfig{
+				PodIPLabel: "pod_ip",
+			},
+			Ignore: IgnoreConfig{
+				PodNames:    []string{"^istio-"},
+				Labels:      []IgnoreRuleConfig{{Key: "sidecar"}},
+				Annotations: []IgnoreRuleConfig{{Key: "example.com/telemetry", Value: "^off$"}},
+			},
+			PodDeleteGracePeriod: 30 * time.Second,
+			SyncPeriod:           10 * time.Minute,
+			PodCacheSize:         10000,
 		})
 }
@@ -20,9 +20,48 @@
 // the processor tries to identify the source IP address of the service that sent the spans and matches
 // it with the in memory data. If a match is found, the cached metadata is added to the spans as attributes.
 //
+// Metrics
+//
+// The processor can also be used in metrics pipelines, in which case the same extraction and filter rules are
+// applied to the resource of the metrics. Pod identifiers are looked up in the resource as well as in the node
+// attributes, where receivers such as collectd and signalfx put them. Metrics that describe many pods at once
+// usually carry the pod IP address as a timeseries label instead. Setting "metrics.pod_ip_label" to the name of
+// that label tags each timeseries with the metadata of its own pod.
+//
+//    k8s_tagger:
+//      metrics:
+//        pod_ip_label: pod_ip
+//
+// Pod association
+//
+// By default, data is associated with a pod by the pod IP address found in the "k8s.pod.ip" or "ip" resource
+// attribute, or else by the IP address of the client that sent the data. Other identifiers can be used by
+// listing them under "pod_association". They are tried in order until one of them identifies a known pod.
+//
+//    k8s_tagger:
+//      pod_association:
+//        - from: resource_attribute
+//          name: k8s.pod.uid
+//        - from: resource_attribute
+//          name: k8s.pod.name # also requires the k8s.namespace.name attribute
+//        - from: resource_attribute
+//          name: container.id
+//        - from: connection
+//
+// The processor only indexes pods by the identifiers used in the configured associations.
+//
 // RBAC
 //
-// TODO: mention the required RBAC rules.
+// The processor needs permission to list and watch pods. Depending on the extraction rules it also needs
+// permission to list and watch:
+//
+//    replicasets (apps)   to extract the deployment name
+//    jobs (batch)         to extract the cron job name
+//    namespaces           to extract namespace labels or annotations
+//    nodes                to extract node labels or annotations
+//
+// Owner, namespace and node metadata is synced before pods are processed. If it cannot be synced within
+// 30 seconds, for example because of missing permissions, pods are processed without it.
 //
 // Config
 //
@@ -101,8 +140,9 @@
 //
 // Host networking mode
 //
-// The processor cannot correct identify pods running in the host network mode and
-// enriching spans generated by such pods is not supported at the moment.
+// Pods running in the host network mode share the IP address of their node, so they
+// are not identified by their IP address. Data from such pods is only enriched when
+// another association, e.g. by pod UID, identifies them.
 //
 // As a sidecar
 //
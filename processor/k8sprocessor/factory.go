@@ -16,7 +16,6 @@ package k8sprocessor
 
 import (
 	"github.com/open-telemetry/opentelemetry-collector/component"
-	"github.com/open-telemetry/opentelemetry-collector/config/configerror"
 	"github.com/open-telemetry/opentelemetry-collector/config/configmodels"
 	"github.com/open-telemetry/opentelemetry-collector/consumer"
 	"go.uber.org/zap"
@@ -47,6 +46,8 @@ func (f *Factory) CreateDefaultConfig() configmodels.Processor {
 			TypeVal: configmodels.Type(typeStr),
 			NameVal: typeStr,
 		},
+		PodDeleteGracePeriod: kube.DefaultPodDeleteGracePeriod,
+		SyncPeriod:           kube.DefaultWatchSyncPeriod,
 	}
 }
 
@@ -56,6 +57,21 @@ func (f *Factory) CreateTraceProcessor(
 	nextConsumer consumer.TraceConsumerOld,
 	cfg configmodels.Processor,
 ) (component.TraceProcessorOld, error) {
+	return NewTraceProcessor(logger, nextConsumer, f.KubeClient, createProcessorOpts(cfg)...)
+}
+
+// CreateMetricsProcessor creates a metrics processor based on this config.
+func (f *Factory) CreateMetricsProcessor(
+	logger *zap.Logger,
+	nextConsumer consumer.MetricsConsumerOld,
+	cfg configmodels.Processor,
+) (component.MetricsProcessorOld, error) {
+	opts := createProcessorOpts(cfg)
+	opts = append(opts, WithMetricsPodIPLabel(cfg.(*Config).Metrics.PodIPLabel))
+	return NewMetricsProcessor(logger, nextConsumer, f.KubeClient, opts...)
+}
+
+func createProcessorOpts(cfg configmodels.Processor) []Option {
 	oCfg := cfg.(*Config)
 	opts := []Option{}
 	if oCfg.Passthrough {
@@ -71,14 +87,18 @@ func (f *Factory) CreateTraceProcessor(
 	opts = append(opts, WithFilterNamespace(oCfg.Filter.Namespace))
 	opts = append(opts, WithFilterLabels(oCfg.Filter.Labels...))
 	opts = append(opts, WithFilterFields(oCfg.Filter.Fields...))
-	return NewTraceProcessor(logger, nextConsumer, f.KubeClient, opts...)
-}
 
-// CreateMetricsProcessor creates a metrics processor based on this config.
-func (f *Factory) CreateMetricsProcessor(
-	logger *zap.Logger,
-	nextConsumer consumer.MetricsConsumerOld,
-	cfg configmodels.Processor,
-) (component.MetricsProcessorOld, error) {
-	return nil, configerror.ErrDataTypeIsNotSupported
+	// pod associations
+	opts = append(opts, WithPodAssociations(oCfg.Association...))
+
+	// ignore rules
+	opts = append(opts, WithIgnorePodNames(oCfg.Ignore.PodNames...))
+	opts = append(opts, WithIgnoreLabels(oCfg.Ignore.Labels...))
+	opts = append(opts, WithIgnoreAnnotations(oCfg.Ignore.Annotations...))
+
+	// pod cache
+	opts = append(opts, WithPodDeleteGracePeriod(oCfg.PodDeleteGracePeriod))
+	opts = append(opts, WithSyncPeriod(oCfg.SyncPeriod))
+	opts = append(opts, WithPodCacheSize(oCfg.PodCacheSize))
+	return opts
 }
@@ -15,8 +15,8 @@
 package kube
 
 import (
+	"container/list"
 	"fmt"
-	"regexp"
 	"strings"
 	"sync"
 	"time"
@@ -34,33 +34,72 @@ import (
 
 // WatchClient is the main interface provided by this package to a kubernetes cluster.
 type WatchClient struct {
-	m               sync.RWMutex
-	deleteMut       sync.Mutex
-	logger          *zap.Logger
-	kc              *kubernetes.Clientset
-	informer        cache.SharedInformer
-	deploymentRegex *regexp.Regexp
-	deleteQueue     []deleteRequest
-	stopCh          chan struct{}
-
-	Pods    map[string]*Pod
-	Rules   ExtractionRules
-	Filters Filters
+	m           sync.RWMutex
+	deleteMut   sync.Mutex
+	logger      *zap.Logger
+	kc          *kubernetes.Clientset
+	informer    cache.SharedInformer
+	deleteQueue []deleteRequest
+	stopCh      chan struct{}
+
+	// Informers used to resolve pod owners and the namespace and node of pods.
+	// They are only created when extraction rules need them.
+	replicaSetInformer cache.SharedInformer
+	jobInformer        cache.SharedInformer
+	namespaceInformer  cache.SharedInformer
+	nodeInformer       cache.SharedInformer
+
+	// podOrder tracks cache entries from least to most recently updated and
+	// podElements maps each entry to its position, to enforce Cache.MaxSize.
+	podOrder    *list.List
+	podElements map[PodIdentifier]*list.Element
+
+	Pods         map[PodIdentifier]*Pod
+	Rules        ExtractionRules
+	Filters      Filters
+	Associations []PodIdentifierKind
+	Ignore       IgnoreRules
+	Cache        CacheConfig
 }
 
-// New initializes a new k8s Client.
-func New(logger *zap.Logger, rules ExtractionRules, filters Filters, newClientSet APIClientsetProvider, newInformer InformerProvider) (Client, error) {
-
-	// Extract deployment name from the pod name. Pod name is created using
-	// format: [deployment-name]-[Random-String-For-ReplicaSet]-[Random-String-For-Pod]
-	dRegex, err := regexp.Compile(`^(.*)-[0-9a-zA-Z]*-[0-9a-zA-Z]*$`)
-	if err != nil {
-		return nil, err
+// New initializes a new k8s Client. Pods are indexed by an identifier of each of the
+// given kinds, or only by IP address when no kinds are given.
+func New(
+	logger *zap.Logger,
+	rules ExtractionRules,
+	filters Filters,
+	associations []PodIdentifierKind,
+	ignore IgnoreRules,
+	cacheConfig CacheConfig,
+	newClientSet APIClientsetProvider,
+	newInformer InformerProvider,
+) (Client, error) {
+	if len(associations) == 0 {
+		associations = []PodIdentifierKind{PodIdentifierKindIP}
+	}
+	if ignore.PodNames == nil {
+		ignore.PodNames = DefaultPodNameIgnorePatterns
+	}
+	if cacheConfig.DeleteGracePeriod == 0 {
+		cacheConfig.DeleteGracePeriod = DefaultPodDeleteGracePeriod
+	}
+	if cacheConfig.SyncPeriod == 0 {
+		cacheConfig.SyncPeriod = DefaultWatchSyncPeriod
+	}
+	c := &WatchClient{
+		logger:       logger,
+		Rules:        rules,
+		Filters:      filters,
+		Associations: associations,
+		Ignore:       ignore,
+		Cache:        cacheConfig,
+		stopCh:       make(chan struct{}),
 	}
-	c := &WatchClient{logger: logger, Rules: rules, Filters: filters, deploymentRegex: dRegex}
 	go c.deleteLoop(time.Second * 30)
 
-	c.Pods = map[string]*Pod{}
+	c.Pods = map[PodIdentifier]*Pod{}
+	c.podOrder = list.New()
+	c.podElements = map[PodIdentifier]*list.Element{}
 	if newClientSet == nil {
 		newClientSet = newAPIClientset
 	}
@@ -84,12 +123,26 @@ func New(logger *zap.Logger, rules ExtractionRules, filters Filters, newClientSe
 		newInformer = newSharedInformer
 	}
 
-	c.informer = newInformer(c.kc, c.Filters.Namespace, labelSelector, fieldSelector)
+	c.informer = newInformer(c.kc, c.Filters.Namespace, labelSelector, fieldSelector, c.Cache.SyncPeriod)
+
+	if c.Rules.Deployment {
+		c.replicaSetInformer = newReplicaSetSharedInformer(c.kc, c.Filters.Namespace, c.Cache.SyncPeriod)
+	}
+	if c.Rules.CronJob {
+		c.jobInformer = newJobSharedInformer(c.kc, c.Filters.Namespace, c.Cache.SyncPeriod)
+	}
+	if c.Rules.extractsFrom(MetadataFromNamespace) {
+		c.namespaceInformer = newNamespaceSharedInformer(c.kc, c.Filters.Namespace, c.Cache.SyncPeriod)
+	}
+	if c.Rules.extractsFrom(MetadataFromNode) {
+		c.nodeInformer = newNodeSharedInformer(c.kc, c.Filters.Node, c.Cache.SyncPeriod)
+	}
 	return c, err
 }
 
 // Start registers pod event handlers and starts watching the kubernetes cluster for pod changes.
 func (c *WatchClient) Start() {
+	c.startMetadataInformers()
 	c.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    c.handlePodAdd,
 		UpdateFunc: c.handlePodUpdate,
@@ -139,12 +192,16 @@ func (c *WatchClient) deleteLoop(interval time.Duration) {
 	// It iterates over the delete queue and deletes all that aren't
 	// in the grace period anymore.
 	for {
-		<-time.After(interval)
+		select {
+		case <-c.stopCh:
+			return
+		case <-time.After(interval):
+		}
 		var cutoff int
 		now := time.Now()
 		c.deleteMut.Lock()
 		for i, d := range c.deleteQueue {
-			if d.ts.Add(podDeleteGracePeriod).After(now) {
+			if d.ts.Add(c.Cache.DeleteGracePeriod).After(now) {
 				break
 			}
 			cutoff = i + 1
@@ -155,11 +212,11 @@ func (c *WatchClient) deleteLoop(interval time.Duration) {
 
 		c.m.Lock()
 		for _, d := range toDelete {
-			if p, ok := c.Pods[d.ip]; ok {
+			if p, ok := c.Pods[d.id]; ok {
 				// Sanity check: make sure we are deleting the same pod
-				// and the underlying state (ip<>pod mapping) has not changed.
+				// and the underlying state (identifier<>pod mapping) has not changed.
 				if p.Name == d.name {
-					delete(c.Pods, d.ip)
+					c.deletePod(d.id)
 				}
 			}
 		}
@@ -167,18 +224,24 @@ func (c *WatchClient) deleteLoop(interval time.Duration) {
 	}
 }
 
-// GetPodByIP takes an IP address and returns the pod the IP address is associated with.
-func (c *WatchClient) GetPodByIP(ip string) (*Pod, bool) {
+// GetPod takes a pod identifier and returns the pod the identifier is associated with.
+// It records the pod lookup hit and miss metrics so it must only be used to look up
+// the pods of the data being processed, the client reads c.Pods directly instead.
+func (c *WatchClient) GetPod(id PodIdentifier) (*Pod, bool) {
 	c.m.RLock()
-	pod, ok := c.Pods[ip]
+	pod, ok := c.Pods[id]
 	c.m.RUnlock()
 	if ok {
 		if pod.Ignore {
 			return nil, false
 		}
+		observability.RecordPodLookupHit()
 		return pod, ok
 	}
-	observability.RecordIPLookupMiss()
+	observability.RecordPodLookupMiss()
+	if id.isIP() {
+		observability.RecordIPLookupMiss()
+	}
 	return nil, false
 }
 
@@ -199,13 +262,7 @@ func (c *WatchClient) extractPodAttributes(pod *api_v1.Pod) map[string]string {
 		}
 	}
 
-	if c.Rules.Deployment {
-		// format: [deployment-name]-[Random-String-For-ReplicaSet]-[Random-String-For-Pod]
-		parts := c.deploymentRegex.FindStringSubmatch(pod.Name)
-		if len(parts) == 2 {
-			tags[tagDeploymentName] = parts[1]
-		}
-	}
+	c.extractOwnerAttributes(pod, tags)
 
 	if c.Rules.Node {
 		tags[tagNodeName] = pod.Spec.NodeName
@@ -219,13 +276,21 @@ func (c *WatchClient) extractPodAttributes(pod *api_v1.Pod) map[string]string {
 	}
 
 	for _, r := range c.Rules.Labels {
-		if v, ok := pod.Labels[r.Key]; ok {
+		obj := c.objectFor(pod, r.From)
+		if obj == nil {
+			continue
+		}
+		if v, ok := obj.GetLabels()[r.Key]; ok {
 			tags[r.Name] = c.extractField(v, r)
 		}
 	}
 
 	for _, r := range c.Rules.Annotations {
-		if v, ok := pod.Annotations[r.Key]; ok {
+		obj := c.objectFor(pod, r.From)
+		if obj == nil {
+			continue
+		}
+		if v, ok := obj.GetAnnotations()[r.Key]; ok {
 			tags[r.Name] = c.extractField(v, r)
 		}
 	}
@@ -247,25 +312,16 @@ func (c *WatchClient) extractField(v string, r FieldExtractionRule) string {
 }
 
 func (c *WatchClient) addOrUpdatePod(pod *api_v1.Pod) {
-	if pod.Status.PodIP == "" {
+	ids := c.podIdentifiers(pod)
+	if len(ids) == 0 {
 		return
 	}
 
-	c.m.Lock()
-	defer c.m.Unlock()
-	// compare initial scheduled timestamp for existing pod and new pod with same IP
-	// and only replace old pod if scheduled time of new pod is newer? This should fix
-	// the case where scheduler has assigned the same IP to a new pod but update event for
-	// the old pod came in later
-	if p, ok := c.Pods[pod.Status.PodIP]; ok {
-		if p.StartTime != nil && pod.Status.StartTime.Before(p.StartTime) {
-			return
-		}
-	}
 	newPod := &Pod{
-		Name:      pod.Name,
-		Address:   pod.Status.PodIP,
-		StartTime: pod.Status.StartTime,
+		Name:        pod.Name,
+		Address:     pod.Status.PodIP,
+		StartTime:   pod.Status.StartTime,
+		Identifiers: ids,
 	}
 
 	if c.shouldIgnorePod(pod) {
@@ -273,38 +329,143 @@ func (c *WatchClient) addOrUpdatePod(pod *api_v1.Pod) {
 	} else {
 		newPod.Attributes = c.extractPodAttributes(pod)
 	}
-	c.Pods[pod.Status.PodIP] = newPod
+
+	c.m.Lock()
+	defer c.m.Unlock()
+	for _, id := range ids {
+		if p, ok := c.Pods[id]; ok {
+			// compare initial scheduled timestamp for existing pod and new pod with same identifier
+			// and only replace old pod if scheduled time of new pod is newer? This should fix
+			// the case where scheduler has assigned the same IP to a new pod but update event for
+			// the old pod came in later
+			if p.StartTime != nil && pod.Status.StartTime.Before(p.StartTime) {
+				continue
+			}
+			// Identifiers such as container IDs change over the lifetime of a pod
+			// (e.g. when a container restarts). Drop the ones the pod no longer has.
+			if p.Name == newPod.Name {
+				c.forgetStaleIdentifiers(p, ids)
+			}
+		}
+		c.setPod(id, newPod)
+	}
 }
 
-func (c *WatchClient) forgetPod(pod *api_v1.Pod) {
-	if pod.Status.PodIP == "" {
+// setPod caches the pod under the given identifier and evicts the least recently
+// updated entries if the cache grows beyond its maximum size.
+// c.m must be held by the caller.
+func (c *WatchClient) setPod(id PodIdentifier, pod *Pod) {
+	c.Pods[id] = pod
+	if e, ok := c.podElements[id]; ok {
+		c.podOrder.MoveToBack(e)
+	} else {
+		c.podElements[id] = c.podOrder.PushBack(id)
+	}
+
+	if c.Cache.MaxSize <= 0 {
 		return
 	}
-	c.m.RLock()
-	p, ok := c.GetPodByIP(pod.Status.PodIP)
-	c.m.RUnlock()
+	for len(c.Pods) > c.Cache.MaxSize {
+		oldest := c.podOrder.Front().Value.(PodIdentifier)
+		c.deletePod(oldest)
+		observability.RecordPodCacheEviction()
+	}
+}
 
-	if ok && p.Name == pod.Name {
-		c.deleteMut.Lock()
-		c.deleteQueue = append(c.deleteQueue, deleteRequest{
-			ip:   pod.Status.PodIP,
+// deletePod removes the identifier from the cache.
+// c.m must be held by the caller.
+func (c *WatchClient) deletePod(id PodIdentifier) {
+	delete(c.Pods, id)
+	if e, ok := c.podElements[id]; ok {
+		c.podOrder.Remove(e)
+		delete(c.podElements, id)
+	}
+}
+
+// forgetStaleIdentifiers removes identifiers that still point to the old version
+// of a pod but that are not in the current set of identifiers of the pod.
+// c.m must be held by the caller.
+func (c *WatchClient) forgetStaleIdentifiers(old *Pod, current []PodIdentifier) {
+	for _, id := range old.Identifiers {
+		if containsIdentifier(current, id) {
+			continue
+		}
+		if p, ok := c.Pods[id]; ok && p == old {
+			c.deletePod(id)
+		}
+	}
+}
+
+// forgetPod queues the identifiers still associated with the deleted pod for
+// deletion. Ignored pods are cached too so they are queued as well, unlike IP
+// addresses the other identifiers are never reused by a new pod.
+func (c *WatchClient) forgetPod(pod *api_v1.Pod) {
+	now := time.Now()
+	var requests []deleteRequest
+	c.m.RLock()
+	for _, id := range c.podIdentifiers(pod) {
+		p, ok := c.Pods[id]
+		if !ok || p.Name != pod.Name {
+			continue
+		}
+		requests = append(requests, deleteRequest{
+			id:   id,
 			name: pod.Name,
-			ts:   time.Now(),
+			ts:   now,
 		})
-		c.deleteMut.Unlock()
 	}
+	c.m.RUnlock()
+
+	if len(requests) == 0 {
+		return
+	}
+	c.deleteMut.Lock()
+	c.deleteQueue = append(c.deleteQueue, requests...)
+	c.deleteMut.Unlock()
 }
 
-func (c *WatchClient) shouldIgnorePod(pod *api_v1.Pod) bool {
-	// Host network mode is not supported right now with IP based
-	// tagging as all pods in host network get same IP addresses.
-	// Such pods are very rare and usually are used to monitor or control
-	// host traffic (e.g, linkerd, flannel) instead of service business needs.
-	// We plan to support host network pods in future.
-	if pod.Spec.HostNetwork {
-		return true
+// podIdentifiers returns the identifiers of all configured kinds for the given pod.
+func (c *WatchClient) podIdentifiers(pod *api_v1.Pod) []PodIdentifier {
+	var ids []PodIdentifier
+	for _, kind := range c.Associations {
+		switch kind {
+		case PodIdentifierKindIP:
+			// Pods in the host network share the IP of their node, so they
+			// can't be identified by their IP.
+			if pod.Status.PodIP != "" && !pod.Spec.HostNetwork {
+				ids = append(ids, PodIdentifierFromIP(pod.Status.PodIP))
+			}
+		case PodIdentifierKindUID:
+			if pod.UID != "" {
+				ids = append(ids, PodIdentifierFromUID(string(pod.UID)))
+			}
+		case PodIdentifierKindName:
+			if pod.Name != "" {
+				ids = append(ids, PodIdentifierFromName(pod.Namespace, pod.Name))
+			}
+		case PodIdentifierKindContainerID:
+			for _, statuses := range [][]api_v1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+				for _, cs := range statuses {
+					if cs.ContainerID != "" {
+						ids = append(ids, PodIdentifierFromContainerID(cs.ContainerID))
+					}
+				}
+			}
+		}
+	}
+	return ids
+}
+
+func containsIdentifier(ids []PodIdentifier, id PodIdentifier) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
 	}
+	return false
+}
 
+func (c *WatchClient) shouldIgnorePod(pod *api_v1.Pod) bool {
 	// Check if user requested the pod to be ignored through annotations
 	if v, ok := pod.Annotations[ignoreAnnotation]; ok {
 		if strings.ToLower(strings.TrimSpace(v)) == "true" {
@@ -313,12 +474,31 @@ func (c *WatchClient) shouldIgnorePod(pod *api_v1.Pod) bool {
 	}
 
 	// Check well known names that should be ignored
-	for _, rexp := range podNameIgnorePatterns {
+	for _, rexp := range c.Ignore.PodNames {
 		if rexp.MatchString(pod.Name) {
 			return true
 		}
 	}
 
+	// Check user defined label and annotation rules
+	if matchesIgnoreRules(pod.Labels, c.Ignore.Labels) ||
+		matchesIgnoreRules(pod.Annotations, c.Ignore.Annotations) {
+		return true
+	}
+
+	return false
+}
+
+func matchesIgnoreRules(fields map[string]string, rules []IgnoreRule) bool {
+	for _, r := range rules {
+		v, ok := fields[r.Key]
+		if !ok {
+			continue
+		}
+		if r.Value == nil || r.Value.MatchString(v) {
+			return true
+		}
+	}
 	return false
 }
 
@@ -16,12 +16,16 @@ package kube
 
 import (
 	"regexp"
+	"strconv"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
 	"go.uber.org/zap"
+	apps_v1 "k8s.io/api/apps/v1"
+	batch_v1 "k8s.io/api/batch/v1"
 	api_v1 "k8s.io/api/core/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/selection"
@@ -100,7 +104,7 @@ func TestPodDelete(t *testing.T) {
 	assert.Equal(t, len(c.Pods), 1)
 	assert.Equal(t, len(c.deleteQueue), 1)
 	deleteRequest := c.deleteQueue[0]
-	assert.Equal(t, deleteRequest.ip, "1.1.1.1")
+	assert.Equal(t, deleteRequest.id, PodIdentifier("1.1.1.1"))
 	assert.Equal(t, deleteRequest.name, "podB")
 	assert.True(t, deleteRequest.ts.After(tsBeforeDelete))
 	assert.True(t, deleteRequest.ts.Before(time.Now()))
@@ -123,15 +127,181 @@ func TestDeleteQueue(t *testing.T) {
 	// test delete loop
 }
 
-func TestExtractionRules(t *testing.T) {
-	c := newTestClientWithRulesAndFilters(t, ExtractionRules{}, Filters{})
+func TestPodIdentifiers(t *testing.T) {
+	c := newTestClientWithAssociations(t, ExtractionRules{}, Filters{}, []PodIdentifierKind{
+		PodIdentifierKindIP,
+		PodIdentifierKindUID,
+		PodIdentifierKindName,
+		PodIdentifierKindContainerID,
+	})
 
+	pod := &api_v1.Pod{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "podA",
+			Namespace: "ns1",
+			UID:       "33333-66666",
+		},
+		Status: api_v1.PodStatus{
+			PodIP: "1.1.1.1",
+			InitContainerStatuses: []api_v1.ContainerStatus{
+				{ContainerID: "docker://init1"},
+			},
+			ContainerStatuses: []api_v1.ContainerStatus{
+				{ContainerID: "docker://c1"},
+				{ContainerID: "containerd://c2"},
+				// not started yet
+				{ContainerID: ""},
+			},
+		},
+	}
+	c.handlePodAdd(pod)
+
+	ids := []PodIdentifier{
+		PodIdentifierFromIP("1.1.1.1"),
+		PodIdentifierFromUID("33333-66666"),
+		PodIdentifierFromName("ns1", "podA"),
+		PodIdentifierFromContainerID("init1"),
+		PodIdentifierFromContainerID("docker://c1"),
+		PodIdentifierFromContainerID("c2"),
+	}
+	assert.Equal(t, len(ids), len(c.Pods))
+	for _, id := range ids {
+		p, ok := c.GetPod(id)
+		require.True(t, ok, "pod not found by %q", id)
+		assert.Equal(t, "podA", p.Name)
+	}
+
+	_, ok := c.GetPod(PodIdentifierFromName("ns2", "podA"))
+	assert.False(t, ok)
+
+	// container c1 restarts with a new ID, the old one must no longer resolve.
+	pod = pod.DeepCopy()
+	pod.Status.ContainerStatuses[0].ContainerID = "docker://c1-restarted"
+	c.handlePodUpdate(&api_v1.Pod{}, pod)
+	assert.Equal(t, len(ids), len(c.Pods))
+	_, ok = c.GetPod(PodIdentifierFromContainerID("c1"))
+	assert.False(t, ok)
+	_, ok = c.GetPod(PodIdentifierFromContainerID("c1-restarted"))
+	assert.True(t, ok)
+
+	// deleting the pod queues every identifier for deletion.
+	c.handlePodDelete(pod)
+	assert.Equal(t, len(ids), len(c.deleteQueue))
+}
+
+func TestPodIdentifierKindsDefaultToIP(t *testing.T) {
+	c := newTestClient(t)
+	assert.Equal(t, []PodIdentifierKind{PodIdentifierKindIP}, c.Associations)
+
+	pod := &api_v1.Pod{}
+	pod.Name = "podA"
+	pod.UID = "33333-66666"
+	c.handlePodAdd(pod)
+	assert.Equal(t, 0, len(c.Pods))
+}
+
+func TestPodDeleteDoesNotRecordLookups(t *testing.T) {
+	c := newTestClientWithAssociations(t, ExtractionRules{}, Filters{}, []PodIdentifierKind{
+		PodIdentifierKindIP,
+		PodIdentifierKindUID,
+		PodIdentifierKindName,
+	})
+
+	pod := &api_v1.Pod{}
+	pod.Name = "podA"
+	pod.Namespace = "ns1"
+	pod.UID = "33333-66666"
+	pod.Status.PodIP = "1.1.1.1"
+	c.handlePodAdd(pod)
+
+	hits := lookupCount(t, "otelsvc/k8s/pod_lookup_hit")
+	misses := lookupCount(t, "otelsvc/k8s/pod_lookup_miss")
+	c.handlePodDelete(pod)
+	// deleting a pod not in the cache must not record misses either.
+	other := pod.DeepCopy()
+	other.Name = "podB"
+	other.UID = "77777-88888"
+	c.handlePodDelete(other)
+	assert.Equal(t, 3, len(c.deleteQueue))
+	assert.Equal(t, hits, lookupCount(t, "otelsvc/k8s/pod_lookup_hit"))
+	assert.Equal(t, misses, lookupCount(t, "otelsvc/k8s/pod_lookup_miss"))
+
+	_, ok := c.GetPod(PodIdentifierFromUID("33333-66666"))
+	assert.True(t, ok)
+	assert.Equal(t, hits+1, lookupCount(t, "otelsvc/k8s/pod_lookup_hit"))
+}
+
+// lookupCount returns the current value of the given pod lookup metric.
+func lookupCount(t *testing.T, name string) float64 {
+	rows, err := view.RetrieveData(name)
+	require.NoError(t, err)
+	if len(rows) == 0 {
+		return 0
+	}
+	return rows[0].Data.(*view.SumData).Value
+}
+
+func TestIgnoredPodDelete(t *testing.T) {
+	c := newTestClientWithAssociations(t, ExtractionRules{}, Filters{}, []PodIdentifierKind{
+		PodIdentifierKindIP,
+		PodIdentifierKindUID,
+	})
+
+	pod := &api_v1.Pod{}
+	pod.Name = "podA"
+	pod.UID = "33333-66666"
+	pod.Status.PodIP = "1.1.1.1"
+	pod.Annotations = map[string]string{"opentelemetry.io/k8s-processor/ignore": "true"}
+	c.handlePodAdd(pod)
+	assert.Equal(t, 2, len(c.Pods))
+	_, ok := c.GetPod(PodIdentifierFromUID("33333-66666"))
+	assert.False(t, ok)
+
+	// ignored pods are cached too and must be queued for deletion.
+	c.handlePodDelete(pod)
+	require.Equal(t, 2, len(c.deleteQueue))
+	assert.Equal(t, PodIdentifierFromIP("1.1.1.1"), c.deleteQueue[0].id)
+	assert.Equal(t, PodIdentifierFromUID("33333-66666"), c.deleteQueue[1].id)
+}
+
+func TestHostNetworkPod(t *testing.T) {
+	c := newTestClientWithAssociations(t, ExtractionRules{}, Filters{}, []PodIdentifierKind{
+		PodIdentifierKindIP,
+		PodIdentifierKindUID,
+	})
+
+	pod := &api_v1.Pod{}
+	pod.Name = "podA"
+	pod.UID = "33333-66666"
+	pod.Status.PodIP = "1.1.1.1"
+	pod.Spec.HostNetwork = true
+	c.handlePodAdd(pod)
+
+	// the IP is the IP of the node, the pod is only indexed by its UID.
+	assert.Equal(t, 1, len(c.Pods))
+	p, ok := c.GetPod(PodIdentifierFromUID("33333-66666"))
+	require.True(t, ok)
+	assert.Equal(t, "podA", p.Name)
+	_, ok = c.GetPod(PodIdentifierFromIP("1.1.1.1"))
+	assert.False(t, ok)
+}
+
+func TestPodIdentifierIsIP(t *testing.T) {
+	assert.True(t, PodIdentifierFromIP("1.1.1.1").isIP())
+	assert.True(t, PodIdentifierFromIP("fe80::1").isIP())
+	assert.False(t, PodIdentifierFromUID("33333-66666").isIP())
+	assert.False(t, PodIdentifierFromName("ns1", "podA").isIP())
+	assert.False(t, PodIdentifierFromContainerID("docker://c1").isIP())
+}
+
+func TestExtractionRules(t *testing.T) {
 	pod := &api_v1.Pod{
 		ObjectMeta: meta_v1.ObjectMeta{
 			Name:              "auth-service-abc12-xyz3",
 			Namespace:         "ns1",
 			CreationTimestamp: meta_v1.Now(),
 			ClusterName:       "cluster1",
+			OwnerReferences:   []meta_v1.OwnerReference{controllerRef("ReplicaSet", "auth-service-abc12")},
 			Labels: map[string]string{
 				"label1": "lv1",
 				"label2": "k1=v1 k5=v5 extra!",
@@ -209,9 +379,18 @@ func TestExtractionRules(t *testing.T) {
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			c.Rules = tc.rules
+			c := newTestClientWithRulesAndFilters(t, tc.rules, Filters{})
+			if c.replicaSetInformer != nil {
+				require.NoError(t, c.replicaSetInformer.GetStore().Add(&apps_v1.ReplicaSet{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:            "auth-service-abc12",
+						Namespace:       "ns1",
+						OwnerReferences: []meta_v1.OwnerReference{controllerRef("Deployment", "auth-service")},
+					},
+				}))
+			}
 			c.handlePodAdd(pod)
-			p, ok := c.GetPodByIP(pod.Status.PodIP)
+			p, ok := c.GetPod(PodIdentifierFromIP(pod.Status.PodIP))
 			require.True(t, ok)
 
 			assert.Equal(t, len(tc.attributes), len(p.Attributes))
@@ -224,6 +403,176 @@ func TestExtractionRules(t *testing.T) {
 	}
 }
 
+func TestOwnerExtraction(t *testing.T) {
+	rules := ExtractionRules{
+		Deployment:  true,
+		ReplicaSet:  true,
+		StatefulSet: true,
+		DaemonSet:   true,
+		Job:         true,
+		CronJob:     true,
+	}
+	c := newTestClientWithRulesAndFilters(t, rules, Filters{})
+	require.NotNil(t, c.replicaSetInformer)
+	require.NotNil(t, c.jobInformer)
+	assert.Nil(t, c.namespaceInformer)
+	assert.Nil(t, c.nodeInformer)
+
+	require.NoError(t, c.replicaSetInformer.GetStore().Add(&apps_v1.ReplicaSet{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:            "web-abc12",
+			Namespace:       "ns1",
+			OwnerReferences: []meta_v1.OwnerReference{controllerRef("Deployment", "web")},
+		},
+	}))
+	require.NoError(t, c.jobInformer.GetStore().Add(&batch_v1.Job{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:            "backup-1588000000",
+			Namespace:       "ns1",
+			OwnerReferences: []meta_v1.OwnerReference{controllerRef("CronJob", "backup")},
+		},
+	}))
+
+	testCases := []struct {
+		name       string
+		owner      meta_v1.OwnerReference
+		attributes map[string]string
+	}{{
+		name:  "deployment",
+		owner: controllerRef("ReplicaSet", "web-abc12"),
+		attributes: map[string]string{
+			"k8s.replicaset.name": "web-abc12",
+			"k8s.deployment.name": "web",
+		},
+	}, {
+		name:  "unknown replicaset",
+		owner: controllerRef("ReplicaSet", "other-abc12"),
+		attributes: map[string]string{
+			"k8s.replicaset.name": "other-abc12",
+		},
+	}, {
+		name:  "cronjob",
+		owner: controllerRef("Job", "backup-1588000000"),
+		attributes: map[string]string{
+			"k8s.job.name":     "backup-1588000000",
+			"k8s.cronjob.name": "backup",
+		},
+	}, {
+		name:  "statefulset",
+		owner: controllerRef("StatefulSet", "db"),
+		attributes: map[string]string{
+			"k8s.statefulset.name": "db",
+		},
+	}, {
+		name:  "daemonset",
+		owner: controllerRef("DaemonSet", "agent"),
+		attributes: map[string]string{
+			"k8s.daemonset.name": "agent",
+		},
+	}, {
+		name: "not a controller",
+		owner: meta_v1.OwnerReference{
+			Kind: "ReplicaSet",
+			Name: "web-abc12",
+		},
+		attributes: map[string]string{},
+	},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pod := &api_v1.Pod{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:            "pod1",
+					Namespace:       "ns1",
+					OwnerReferences: []meta_v1.OwnerReference{tc.owner},
+				},
+				Status: api_v1.PodStatus{
+					PodIP: "1.1.1.1",
+				},
+			}
+			c.handlePodAdd(pod)
+			p, ok := c.GetPod(PodIdentifierFromIP("1.1.1.1"))
+			require.True(t, ok)
+			assert.Equal(t, tc.attributes, p.Attributes)
+		})
+	}
+}
+
+func TestNamespaceAndNodeExtraction(t *testing.T) {
+	rules := ExtractionRules{
+		Labels: []FieldExtractionRule{
+			{Name: "team", Key: "team", From: MetadataFromNamespace},
+			{Name: "zone", Key: "topology.kubernetes.io/zone", From: MetadataFromNode},
+			{Name: "app", Key: "app", From: MetadataFromPod},
+		},
+		Annotations: []FieldExtractionRule{
+			{Name: "owner", Key: "contact", From: MetadataFromNamespace, Regex: regexp.MustCompile(`email=(?P<value>\S+)`)},
+		},
+	}
+	c := newTestClientWithRulesAndFilters(t, rules, Filters{})
+	require.NotNil(t, c.namespaceInformer)
+	require.NotNil(t, c.nodeInformer)
+	assert.Nil(t, c.replicaSetInformer)
+	assert.Nil(t, c.jobInformer)
+	assert.Len(t, c.metadataInformers(), 2)
+
+	require.NoError(t, c.namespaceInformer.GetStore().Add(&api_v1.Namespace{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:        "ns1",
+			Labels:      map[string]string{"team": "payments"},
+			Annotations: map[string]string{"contact": "slack=#payments email=payments@example.com"},
+		},
+	}))
+	require.NoError(t, c.nodeInformer.GetStore().Add(&api_v1.Node{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:   "node1",
+			Labels: map[string]string{"topology.kubernetes.io/zone": "us-west-2a"},
+		},
+	}))
+
+	pod := &api_v1.Pod{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "pod1",
+			Namespace: "ns1",
+			Labels:    map[string]string{"app": "checkout", "team": "not-from-pod"},
+		},
+		Spec: api_v1.PodSpec{
+			NodeName: "node1",
+		},
+		Status: api_v1.PodStatus{
+			PodIP: "1.1.1.1",
+		},
+	}
+	c.handlePodAdd(pod)
+	p, ok := c.GetPod(PodIdentifierFromIP("1.1.1.1"))
+	require.True(t, ok)
+	assert.Equal(t, map[string]string{
+		"team":  "payments",
+		"zone":  "us-west-2a",
+		"app":   "checkout",
+		"owner": "payments@example.com",
+	}, p.Attributes)
+
+	// namespace and node not known (yet)
+	pod = pod.DeepCopy()
+	pod.Namespace = "ns2"
+	pod.Spec.NodeName = "node2"
+	pod.Status.PodIP = "2.2.2.2"
+	c.handlePodAdd(pod)
+	p, ok = c.GetPod(PodIdentifierFromIP("2.2.2.2"))
+	require.True(t, ok)
+	assert.Equal(t, map[string]string{"app": "checkout"}, p.Attributes)
+}
+
+func controllerRef(kind, name string) meta_v1.OwnerReference {
+	isController := true
+	return meta_v1.OwnerReference{
+		Kind:       kind,
+		Name:       name,
+		Controller: &isController,
+	}
+}
+
 func TestFilters(t *testing.T) {
 	testCases := []struct {
 		name    string
@@ -297,7 +646,7 @@ func TestPodIgnorePatterns(t *testing.T) {
 		ignore: false,
 		pod:    api_v1.Pod{},
 	}, {
-		ignore: true,
+		ignore: false,
 		pod: api_v1.Pod{
 			Spec: api_v1.PodSpec{
 				HostNetwork: true,
@@ -362,10 +711,148 @@ func TestPodIgnorePatterns(t *testing.T) {
 	}
 }
 
+func TestIgnoreRules(t *testing.T) {
+	ignore := IgnoreRules{
+		PodNames: []*regexp.Regexp{regexp.MustCompile(`^istio-`)},
+		Labels: []IgnoreRule{
+			{Key: "sidecar"},
+			{Key: "app", Value: regexp.MustCompile(`^(envoy|linkerd-proxy)$`)},
+		},
+		Annotations: []IgnoreRule{
+			{Key: "example.com/telemetry", Value: regexp.MustCompile(`^off$`)},
+		},
+	}
+	c := newTestClientWithConfig(t, ExtractionRules{}, Filters{}, nil, ignore, CacheConfig{})
+
+	testCases := []struct {
+		name   string
+		ignore bool
+		pod    api_v1.Pod
+	}{{
+		name:   "no match",
+		ignore: false,
+		pod: api_v1.Pod{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:        "checkout",
+				Labels:      map[string]string{"app": "checkout"},
+				Annotations: map[string]string{"example.com/telemetry": "on"},
+			},
+		},
+	}, {
+		name:   "name",
+		ignore: true,
+		pod:    api_v1.Pod{ObjectMeta: meta_v1.ObjectMeta{Name: "istio-ingressgateway"}},
+	}, {
+		name:   "default names are replaced",
+		ignore: false,
+		pod:    api_v1.Pod{ObjectMeta: meta_v1.ObjectMeta{Name: "jaeger-agent"}},
+	}, {
+		name:   "label exists",
+		ignore: true,
+		pod:    api_v1.Pod{ObjectMeta: meta_v1.ObjectMeta{Labels: map[string]string{"sidecar": ""}}},
+	}, {
+		name:   "label value",
+		ignore: true,
+		pod:    api_v1.Pod{ObjectMeta: meta_v1.ObjectMeta{Labels: map[string]string{"app": "envoy"}}},
+	}, {
+		name:   "label value mismatch",
+		ignore: false,
+		pod:    api_v1.Pod{ObjectMeta: meta_v1.ObjectMeta{Labels: map[string]string{"app": "envoy-admin"}}},
+	}, {
+		name:   "annotation value",
+		ignore: true,
+		pod:    api_v1.Pod{ObjectMeta: meta_v1.ObjectMeta{Annotations: map[string]string{"example.com/telemetry": "off"}}},
+	}, {
+		name:   "built-in annotation",
+		ignore: true,
+		pod:    api_v1.Pod{ObjectMeta: meta_v1.ObjectMeta{Annotations: map[string]string{"opentelemetry.io/k8s-processor/ignore": "true"}}},
+	},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.ignore, c.shouldIgnorePod(&tc.pod))
+		})
+	}
+}
+
+func TestCacheConfigDefaults(t *testing.T) {
+	c := newTestClient(t)
+	assert.Equal(t, DefaultPodDeleteGracePeriod, c.Cache.DeleteGracePeriod)
+	assert.Equal(t, DefaultWatchSyncPeriod, c.Cache.SyncPeriod)
+	assert.Equal(t, 0, c.Cache.MaxSize)
+	assert.Equal(t, DefaultPodNameIgnorePatterns, c.Ignore.PodNames)
+	assert.Equal(t, DefaultWatchSyncPeriod, c.informer.(fakeInformer).resyncPeriod)
+
+	c = newTestClientWithConfig(t, ExtractionRules{}, Filters{}, nil, IgnoreRules{}, CacheConfig{
+		DeleteGracePeriod: time.Second,
+		SyncPeriod:        time.Minute,
+	})
+	assert.Equal(t, time.Second, c.Cache.DeleteGracePeriod)
+	assert.Equal(t, time.Minute, c.informer.(fakeInformer).resyncPeriod)
+}
+
+func TestDeleteGracePeriod(t *testing.T) {
+	c := newTestClientWithConfig(t, ExtractionRules{}, Filters{}, nil, IgnoreRules{}, CacheConfig{
+		DeleteGracePeriod: time.Millisecond,
+	})
+	pod := &api_v1.Pod{}
+	pod.Name = "podA"
+	pod.Status.PodIP = "1.1.1.1"
+	c.handlePodAdd(pod)
+	c.handlePodDelete(pod)
+	require.Len(t, c.deleteQueue, 1)
+
+	// the loop started by New only runs every 30 seconds
+	go c.deleteLoop(time.Millisecond)
+	assert.Eventually(t, func() bool {
+		c.m.RLock()
+		defer c.m.RUnlock()
+		return len(c.Pods) == 0
+	}, time.Second, time.Millisecond)
+}
+
+func TestCacheMaxSize(t *testing.T) {
+	c := newTestClientWithConfig(t, ExtractionRules{}, Filters{}, nil, IgnoreRules{}, CacheConfig{MaxSize: 2})
+
+	for i, ip := range []string{"1.1.1.1", "2.2.2.2", "3.3.3.3"} {
+		pod := &api_v1.Pod{}
+		pod.Name = "pod" + strconv.Itoa(i)
+		pod.Status.PodIP = ip
+		c.handlePodAdd(pod)
+		// updating the first pod makes it the most recently updated one
+		if i == 1 {
+			pod := &api_v1.Pod{}
+			pod.Name = "pod0"
+			pod.Status.PodIP = "1.1.1.1"
+			c.handlePodUpdate(pod, pod)
+		}
+	}
+
+	assert.Len(t, c.Pods, 2)
+	assert.Equal(t, 2, c.podOrder.Len())
+	assert.Len(t, c.podElements, 2)
+	_, ok := c.GetPod(PodIdentifierFromIP("2.2.2.2"))
+	assert.False(t, ok)
+	_, ok = c.GetPod(PodIdentifierFromIP("1.1.1.1"))
+	assert.True(t, ok)
+	_, ok = c.GetPod(PodIdentifierFromIP("3.3.3.3"))
+	assert.True(t, ok)
+}
+
 func newTestClientWithRulesAndFilters(t *testing.T, e ExtractionRules, f Filters) *WatchClient {
-	c, err := New(zap.NewNop(), e, f, newFakeAPIClientset, newFakeInformer)
+	return newTestClientWithAssociations(t, e, f, nil)
+}
+
+func newTestClientWithAssociations(t *testing.T, e ExtractionRules, f Filters, a []PodIdentifierKind) *WatchClient {
+	return newTestClientWithConfig(t, e, f, a, IgnoreRules{}, CacheConfig{})
+}
+
+func newTestClientWithConfig(t *testing.T, e ExtractionRules, f Filters, a []PodIdentifierKind, i IgnoreRules, cc CacheConfig) *WatchClient {
+	c, err := New(zap.NewNop(), e, f, a, i, cc, newFakeAPIClientset, newFakeInformer)
 	require.NoError(t, err)
-	return c.(*WatchClient)
+	wc := c.(*WatchClient)
+	t.Cleanup(wc.Stop)
+	return wc
 }
 
 func newTestClient(t *testing.T) *WatchClient {
@@ -21,19 +21,19 @@ import (
 
 // FakeClient is used as a replacement for WatchClient in test cases.
 type FakeClient struct {
-	Pods    map[string]*Pod
+	Pods    map[PodIdentifier]*Pod
 	Rules   ExtractionRules
 	Filters Filters
 }
 
 // NewFakeClient instantiates a new FakeClient object and satisfies the ClientProvider type
-func NewFakeClient(logger *zap.Logger, rules ExtractionRules, filters Filters, newClientSet APIClientsetProvider, newInformer InformerProvider) (Client, error) {
-	return &FakeClient{map[string]*Pod{}, rules, filters}, nil
+func NewFakeClient(logger *zap.Logger, rules ExtractionRules, filters Filters, associations []PodIdentifierKind, ignore IgnoreRules, cacheConfig CacheConfig, newClientSet APIClientsetProvider, newInformer InformerProvider) (Client, error) {
+	return &FakeClient{map[PodIdentifier]*Pod{}, rules, filters}, nil
 }
 
-// GetPodByIP looks up FakeClient.Pods map by the provided string.
-func (f *FakeClient) GetPodByIP(ip string) (*Pod, bool) {
-	p, ok := f.Pods[ip]
+// GetPod looks up FakeClient.Pods map by the provided identifier.
+func (f *FakeClient) GetPod(id PodIdentifier) (*Pod, bool) {
+	p, ok := f.Pods[id]
 	return p, ok
 }
 
@@ -29,6 +29,7 @@ type fakeInformer struct {
 	namespace     string
 	labelSelector labels.Selector
 	fieldSelector fields.Selector
+	resyncPeriod  time.Duration
 }
 
 func newFakeInformer(
@@ -36,11 +37,13 @@ func newFakeInformer(
 	namespace string,
 	labelSelector labels.Selector,
 	fieldSelector fields.Selector,
+	resyncPeriod time.Duration,
 ) cache.SharedInformer {
 	return fakeInformer{
 		namespace:     namespace,
 		labelSelector: labelSelector,
 		fieldSelector: fieldSelector,
+		resyncPeriod:  resyncPeriod,
 	}
 }
 
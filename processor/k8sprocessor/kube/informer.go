@@ -15,6 +15,10 @@
 package kube
 
 import (
+	"time"
+
+	apps_v1 "k8s.io/api/apps/v1"
+	batch_v1 "k8s.io/api/batch/v1"
 	api_v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
@@ -32,6 +36,7 @@ type InformerProvider func(
 	namespace string,
 	labelSelector labels.Selector,
 	fieldSelector fields.Selector,
+	resyncPeriod time.Duration,
 ) cache.SharedInformer
 
 func newSharedInformer(
@@ -39,6 +44,7 @@ func newSharedInformer(
 	namespace string,
 	labelSelector labels.Selector,
 	fieldSelector fields.Selector,
+	resyncPeriod time.Duration,
 ) cache.SharedInformer {
 	informer := cache.NewSharedInformer(
 		&cache.ListWatch{
@@ -54,7 +60,87 @@ func newSharedInformer(
 			},
 		},
 		&api_v1.Pod{},
-		watchSyncPeriod,
+		resyncPeriod,
 	)
 	return informer
 }
+
+// newReplicaSetSharedInformer returns an informer for replica sets, which are used
+// to resolve the deployment owning a pod.
+func newReplicaSetSharedInformer(client *kubernetes.Clientset, namespace string, resyncPeriod time.Duration) cache.SharedInformer {
+	return cache.NewSharedInformer(
+		&cache.ListWatch{
+			ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+				return client.AppsV1().ReplicaSets(namespace).List(opts)
+			},
+			WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+				return client.AppsV1().ReplicaSets(namespace).Watch(opts)
+			},
+		},
+		&apps_v1.ReplicaSet{},
+		resyncPeriod,
+	)
+}
+
+// newJobSharedInformer returns an informer for jobs, which are used to resolve
+// the cron job owning a pod.
+func newJobSharedInformer(client *kubernetes.Clientset, namespace string, resyncPeriod time.Duration) cache.SharedInformer {
+	return cache.NewSharedInformer(
+		&cache.ListWatch{
+			ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+				return client.BatchV1().Jobs(namespace).List(opts)
+			},
+			WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+				return client.BatchV1().Jobs(namespace).Watch(opts)
+			},
+		},
+		&batch_v1.Job{},
+		resyncPeriod,
+	)
+}
+
+// newNamespaceSharedInformer returns an informer for namespaces. Only the given
+// namespace is watched when it is not empty.
+func newNamespaceSharedInformer(client *kubernetes.Clientset, namespace string, resyncPeriod time.Duration) cache.SharedInformer {
+	fieldSelector := fields.Everything()
+	if namespace != "" {
+		fieldSelector = fields.OneTermEqualSelector(objectNameField, namespace)
+	}
+	return cache.NewSharedInformer(
+		&cache.ListWatch{
+			ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+				opts.FieldSelector = fieldSelector.String()
+				return client.CoreV1().Namespaces().List(opts)
+			},
+			WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+				opts.FieldSelector = fieldSelector.String()
+				return client.CoreV1().Namespaces().Watch(opts)
+			},
+		},
+		&api_v1.Namespace{},
+		resyncPeriod,
+	)
+}
+
+// newNodeSharedInformer returns an informer for nodes. Only the given node is
+// watched when it is not empty.
+func newNodeSharedInformer(client *kubernetes.Clientset, node string, resyncPeriod time.Duration) cache.SharedInformer {
+	fieldSelector := fields.Everything()
+	if node != "" {
+		fieldSelector = fields.OneTermEqualSelector(objectNameField, node)
+	}
+	return cache.NewSharedInformer(
+		&cache.ListWatch{
+			ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+				opts.FieldSelector = fieldSelector.String()
+				return client.CoreV1().Nodes().List(opts)
+			},
+			WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+				opts.FieldSelector = fieldSelector.String()
+				return client.CoreV1().Nodes().Watch(opts)
+			},
+		},
+		&api_v1.Node{},
+		resyncPeriod,
+	)
+}
@@ -16,6 +16,7 @@ package kube
 
 import (
 	"regexp"
+	"strings"
 	"time"
 
 	"go.uber.org/zap"
@@ -26,40 +27,122 @@ import (
 
 const (
 	podNodeField            = "spec.nodeName"
+	objectNameField         = "metadata.name"
 	ignoreAnnotation string = "opentelemetry.io/k8s-processor/ignore"
 
-	tagClusterName    = "k8s.cluster.name"
-	tagDeploymentName = "k8s.deployment.name"
-	tagNamespaceName  = "k8s.namespace.name"
-	tagNodeName       = "k8s.node.name"
-	tagPodName        = "k8s.pod.name"
-	tagStartTime      = "k8s.pod.startTime"
+	tagClusterName     = "k8s.cluster.name"
+	tagDeploymentName  = "k8s.deployment.name"
+	tagReplicaSetName  = "k8s.replicaset.name"
+	tagStatefulSetName = "k8s.statefulset.name"
+	tagDaemonSetName   = "k8s.daemonset.name"
+	tagJobName         = "k8s.job.name"
+	tagCronJobName     = "k8s.cronjob.name"
+	tagNamespaceName   = "k8s.namespace.name"
+	tagNodeName        = "k8s.node.name"
+	tagPodName         = "k8s.pod.name"
+	tagStartTime       = "k8s.pod.startTime"
+
+	// MetadataFromPod is used to extract fields from the pod itself.
+	MetadataFromPod = "pod"
+	// MetadataFromNamespace is used to extract fields from the namespace of the pod.
+	MetadataFromNamespace = "namespace"
+	// MetadataFromNode is used to extract fields from the node the pod runs on.
+	MetadataFromNode = "node"
 )
 
 var (
-	// TODO: move these to config with default values
-	podNameIgnorePatterns = []*regexp.Regexp{
+	// DefaultPodNameIgnorePatterns are the pod name patterns ignored when no patterns are configured.
+	DefaultPodNameIgnorePatterns = []*regexp.Regexp{
 		regexp.MustCompile(`jaeger-agent`),
 		regexp.MustCompile(`jaeger-collector`),
 	}
-	podDeleteGracePeriod = time.Second * 120
-	watchSyncPeriod      = time.Minute * 5
+	// DefaultPodDeleteGracePeriod is how long deleted pods are kept by default.
+	DefaultPodDeleteGracePeriod = time.Second * 120
+	// DefaultWatchSyncPeriod is how often informers resync by default.
+	DefaultWatchSyncPeriod = time.Minute * 5
+	// metadataSyncTimeout is how long pod processing waits for owner, namespace
+	// and node informers to sync before starting anyway.
+	metadataSyncTimeout = time.Second * 30
 )
 
 // Client defines the main interface that allows querying pods by metadata.
 type Client interface {
-	GetPodByIP(string) (*Pod, bool)
+	GetPod(PodIdentifier) (*Pod, bool)
 	Start()
 	Stop()
 }
 
 // ClientProvider defines a func type that returns a new Client.
-type ClientProvider func(*zap.Logger, ExtractionRules, Filters, APIClientsetProvider, InformerProvider) (Client, error)
+type ClientProvider func(*zap.Logger, ExtractionRules, Filters, []PodIdentifierKind, IgnoreRules, CacheConfig, APIClientsetProvider, InformerProvider) (Client, error)
 
 // APIClientsetProvider APIClientsetProvider defines a func type that initializes and return a new kubernetes
 // Clientset object.
 type APIClientsetProvider func() (*kubernetes.Clientset, error)
 
+// PodIdentifierKind is a kind of key pods can be indexed and looked up by.
+type PodIdentifierKind string
+
+const (
+	// PodIdentifierKindIP indexes pods by their IP address.
+	PodIdentifierKindIP PodIdentifierKind = "ip"
+	// PodIdentifierKindUID indexes pods by their UID.
+	PodIdentifierKindUID PodIdentifierKind = "uid"
+	// PodIdentifierKindName indexes pods by their namespace and name.
+	PodIdentifierKindName PodIdentifierKind = "name"
+	// PodIdentifierKindContainerID indexes pods by the IDs of their containers.
+	PodIdentifierKindContainerID PodIdentifierKind = "container_id"
+)
+
+// PodIdentifier is a key that can be used to look up a pod. A pod is indexed
+// by one identifier for each PodIdentifierKind the client was created with
+// (several for container IDs). Each kind of identifier is
+// prefixed so that values of different kinds can never collide, except for IP
+// addresses which are used as is.
+type PodIdentifier string
+
+const (
+	podIdentifierUIDPrefix       = "uid:"
+	podIdentifierNamePrefix      = "name:"
+	podIdentifierContainerPrefix = "container:"
+)
+
+// PodIdentifierFromIP returns the identifier of the pod that has the given IP address.
+func PodIdentifierFromIP(ip string) PodIdentifier {
+	return PodIdentifier(ip)
+}
+
+// PodIdentifierFromUID returns the identifier of the pod that has the given UID.
+func PodIdentifierFromUID(uid string) PodIdentifier {
+	return PodIdentifier(podIdentifierUIDPrefix + uid)
+}
+
+// PodIdentifierFromName returns the identifier of the pod with the given name
+// in the given namespace.
+func PodIdentifierFromName(namespace, name string) PodIdentifier {
+	return PodIdentifier(podIdentifierNamePrefix + namespace + "/" + name)
+}
+
+// PodIdentifierFromContainerID returns the identifier of the pod running the
+// container with the given ID. The ID may optionally include the runtime
+// scheme as reported by kubernetes, e.g. docker://<id>.
+func PodIdentifierFromContainerID(id string) PodIdentifier {
+	if i := strings.Index(id, "://"); i >= 0 {
+		id = id[i+3:]
+	}
+	return PodIdentifier(podIdentifierContainerPrefix + id)
+}
+
+// isIP returns whether the identifier is an IP address, the only kind of
+// identifier that isn't prefixed.
+func (id PodIdentifier) isIP() bool {
+	for _, prefix := range []string{podIdentifierUIDPrefix, podIdentifierNamePrefix, podIdentifierContainerPrefix} {
+		if strings.HasPrefix(string(id), prefix) {
+			return false
+		}
+	}
+	return true
+}
+
 // Pod represents a kubernetes pod.
 type Pod struct {
 	Name       string
@@ -68,11 +151,14 @@ type Pod struct {
 	StartTime  *metav1.Time
 	Ignore     bool
 
+	// Identifiers are all the keys this pod is indexed by.
+	Identifiers []PodIdentifier
+
 	DeletedAt time.Time
 }
 
 type deleteRequest struct {
-	ip   string
+	id   PodIdentifier
 	name string
 	ts   time.Time
 }
@@ -101,15 +187,58 @@ type FieldFilter struct {
 	Op selection.Operator
 }
 
+// IgnoreRules is used to specify pods that must never be associated with any data.
+// Ignored pods are still tracked so that their IP addresses are not attributed to
+// other pods. A pod is ignored if any of the rules match it. Pods running in the host
+// network and pods with the annotation "opentelemetry.io/k8s-processor/ignore: true"
+// are always ignored.
+type IgnoreRules struct {
+	// PodNames are patterns matched against pod names.
+	// DefaultPodNameIgnorePatterns are used when nil.
+	PodNames    []*regexp.Regexp
+	Labels      []IgnoreRule
+	Annotations []IgnoreRule
+}
+
+// IgnoreRule matches pods by exactly one label or annotation.
+type IgnoreRule struct {
+	// Key is the label or annotation name.
+	Key string
+	// Value is matched against the label or annotation value.
+	// The rule matches any value when Value is nil.
+	Value *regexp.Regexp
+}
+
+// CacheConfig controls how long pods are cached and how often the cache is resynced.
+// Zero values are replaced by defaults.
+type CacheConfig struct {
+	// DeleteGracePeriod is how long a deleted pod is kept so that data sent by it
+	// right before it was deleted can still be associated with it.
+	DeleteGracePeriod time.Duration
+	// SyncPeriod is how often all pods (and owner, namespace and node metadata) are resynced.
+	SyncPeriod time.Duration
+	// MaxSize is the maximum number of identifier entries kept in the cache.
+	// The least recently updated entries are evicted first. Unbounded when zero.
+	MaxSize int
+}
+
 // ExtractionRules is used to specify the information that needs to be extracted
 // from pods and added to the spans as tags.
+//
+// Workload names (Deployment, ReplicaSet, StatefulSet, DaemonSet, Job and CronJob)
+// are resolved from the owner references of the pod and its controllers.
 type ExtractionRules struct {
-	Deployment bool
-	Namespace  bool
-	PodName    bool
-	Node       bool
-	Cluster    bool
-	StartTime  bool
+	Deployment  bool
+	ReplicaSet  bool
+	StatefulSet bool
+	DaemonSet   bool
+	Job         bool
+	CronJob     bool
+	Namespace   bool
+	PodName     bool
+	Node        bool
+	Cluster     bool
+	StartTime   bool
 
 	Annotations []FieldExtractionRule
 	Labels      []FieldExtractionRule
@@ -125,4 +254,7 @@ type FieldExtractionRule struct {
 	// Regex is a regular expression used to extract a sub-part of a field value.
 	// Full value is extracted when no regexp is provided.
 	Regex *regexp.Regexp
+	// From is the object the field is extracted from. One of MetadataFromPod,
+	// MetadataFromNamespace or MetadataFromNode. Defaults to MetadataFromPod.
+	From string
 }
@@ -0,0 +1,167 @@
+// Copyright 2019 Omnition Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+	api_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	kindReplicaSet  = "ReplicaSet"
+	kindDeployment  = "Deployment"
+	kindStatefulSet = "StatefulSet"
+	kindDaemonSet   = "DaemonSet"
+	kindJob         = "Job"
+	kindCronJob     = "CronJob"
+)
+
+// extractsFrom returns true if any label or annotation rule extracts fields
+// from the given kind of object.
+func (r ExtractionRules) extractsFrom(from string) bool {
+	for _, rules := range [][]FieldExtractionRule{r.Labels, r.Annotations} {
+		for _, rule := range rules {
+			if rule.From == from {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// metadataInformers returns the informers that have been created in addition to the pod informer.
+func (c *WatchClient) metadataInformers() []cache.SharedInformer {
+	var informers []cache.SharedInformer
+	for _, informer := range []cache.SharedInformer{c.replicaSetInformer, c.jobInformer, c.namespaceInformer, c.nodeInformer} {
+		if informer != nil {
+			informers = append(informers, informer)
+		}
+	}
+	return informers
+}
+
+// startMetadataInformers runs the owner, namespace and node informers and waits for them
+// to sync so that pods processed right after start up get complete metadata. Processing
+// starts anyway after metadataSyncTimeout, for example when the collector is missing RBAC
+// permissions for some of the resources. Pods are re-processed on every resync.
+func (c *WatchClient) startMetadataInformers() {
+	informers := c.metadataInformers()
+	if len(informers) == 0 {
+		return
+	}
+
+	var synced []cache.InformerSynced
+	for _, informer := range informers {
+		go informer.Run(c.stopCh)
+		synced = append(synced, informer.HasSynced)
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	stop := make(chan struct{})
+	go func() {
+		defer close(stop)
+		select {
+		case <-c.stopCh:
+		case <-done:
+		case <-time.After(metadataSyncTimeout):
+		}
+	}()
+	if !cache.WaitForCacheSync(stop, synced...) {
+		c.logger.Warn("owner, namespace or node metadata did not sync, pod metadata may be incomplete",
+			zap.Duration("timeout", metadataSyncTimeout))
+	}
+}
+
+// extractOwnerAttributes resolves the chain of controllers owning the pod and adds
+// the names of the requested workloads to tags.
+func (c *WatchClient) extractOwnerAttributes(pod *api_v1.Pod, tags map[string]string) {
+	ref := metav1.GetControllerOf(pod)
+	if ref == nil {
+		return
+	}
+
+	switch ref.Kind {
+	case kindReplicaSet:
+		if c.Rules.ReplicaSet {
+			tags[tagReplicaSetName] = ref.Name
+		}
+		if c.Rules.Deployment {
+			if owner := c.controllerOf(c.replicaSetInformer, pod.Namespace, ref.Name); owner != nil && owner.Kind == kindDeployment {
+				tags[tagDeploymentName] = owner.Name
+			}
+		}
+	case kindJob:
+		if c.Rules.Job {
+			tags[tagJobName] = ref.Name
+		}
+		if c.Rules.CronJob {
+			if owner := c.controllerOf(c.jobInformer, pod.Namespace, ref.Name); owner != nil && owner.Kind == kindCronJob {
+				tags[tagCronJobName] = owner.Name
+			}
+		}
+	case kindStatefulSet:
+		if c.Rules.StatefulSet {
+			tags[tagStatefulSetName] = ref.Name
+		}
+	case kindDaemonSet:
+		if c.Rules.DaemonSet {
+			tags[tagDaemonSetName] = ref.Name
+		}
+	}
+}
+
+// controllerOf returns the controller of the object with the given namespace and
+// name in the informer's store, if any.
+func (c *WatchClient) controllerOf(informer cache.SharedInformer, namespace, name string) *metav1.OwnerReference {
+	obj := lookupObject(informer, namespace+"/"+name)
+	if obj == nil {
+		return nil
+	}
+	return metav1.GetControllerOf(obj)
+}
+
+// objectFor returns the object fields should be extracted from.
+func (c *WatchClient) objectFor(pod *api_v1.Pod, from string) metav1.Object {
+	switch from {
+	case MetadataFromNamespace:
+		return lookupObject(c.namespaceInformer, pod.Namespace)
+	case MetadataFromNode:
+		return lookupObject(c.nodeInformer, pod.Spec.NodeName)
+	default:
+		return pod
+	}
+}
+
+// lookupObject returns the object with the given key in the informer's store.
+func lookupObject(informer cache.SharedInformer, key string) metav1.Object {
+	if informer == nil {
+		return nil
+	}
+	obj, exists, err := informer.GetStore().GetByKey(key)
+	if err != nil || !exists {
+		return nil
+	}
+	o, err := meta.Accessor(obj)
+	if err != nil {
+		return nil
+	}
+	return o
+}
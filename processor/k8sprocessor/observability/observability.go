@@ -30,6 +30,9 @@ func init() {
 		viewPodsAdded,
 		viewPodsDeleted,
 		viewIPLookupMiss,
+		viewPodLookupMiss,
+		viewPodLookupHit,
+		viewPodCacheEvicted,
 	)
 }
 
@@ -38,7 +41,11 @@ var (
 	mPodsAdded   = stats.Int64("otelsvc/k8s/pod_added", "Number of pod add events received", "1")
 	mPodsDeleted = stats.Int64("otelsvc/k8s/pod_deleted", "Number of pod delete events received", "1")
 
-	mIPLookupMiss = stats.Int64("otelsvc/k8s/ip_lookup_miss", "Number of times pod by IP lookup failed.", "1")
+	mIPLookupMiss  = stats.Int64("otelsvc/k8s/ip_lookup_miss", "Number of times pod by IP lookup failed.", "1")
+	mPodLookupMiss = stats.Int64("otelsvc/k8s/pod_lookup_miss", "Number of times pod lookup by an identifier failed.", "1")
+	mPodLookupHit  = stats.Int64("otelsvc/k8s/pod_lookup_hit", "Number of times pod lookup by an identifier succeeded.", "1")
+
+	mPodCacheEvicted = stats.Int64("otelsvc/k8s/pod_cache_evicted", "Number of pod cache entries evicted because the cache was full.", "1")
 )
 
 var viewPodsUpdated = &view.View{
@@ -69,6 +76,13 @@ var viewIPLookupMiss = &view.View{
 	Aggregation: view.Sum(),
 }
 
+var viewPodLookupMiss = &view.View{
+	Name:        mPodLookupMiss.Name(),
+	Description: mPodLookupMiss.Description(),
+	Measure:     mPodLookupMiss,
+	Aggregation: view.Sum(),
+}
+
 // RecordPodUpdated increments the metric that records pod update events received.
 func RecordPodUpdated() {
 	stats.Record(context.Background(), mPodsUpdated.M(int64(1)))
@@ -84,7 +98,36 @@ func RecordPodDeleted() {
 	stats.Record(context.Background(), mPodsDeleted.M(int64(1)))
 }
 
+var viewPodLookupHit = &view.View{
+	Name:        mPodLookupHit.Name(),
+	Description: mPodLookupHit.Description(),
+	Measure:     mPodLookupHit,
+	Aggregation: view.Sum(),
+}
+
+var viewPodCacheEvicted = &view.View{
+	Name:        mPodCacheEvicted.Name(),
+	Description: mPodCacheEvicted.Description(),
+	Measure:     mPodCacheEvicted,
+	Aggregation: view.Sum(),
+}
+
 // RecordIPLookupMiss increments the metric that records Pod lookup by IP misses.
 func RecordIPLookupMiss() {
 	stats.Record(context.Background(), mIPLookupMiss.M(int64(1)))
 }
+
+// RecordPodLookupMiss increments the metric that records Pod lookup misses.
+func RecordPodLookupMiss() {
+	stats.Record(context.Background(), mPodLookupMiss.M(int64(1)))
+}
+
+// RecordPodLookupHit increments the metric that records Pod lookup hits.
+func RecordPodLookupHit() {
+	stats.Record(context.Background(), mPodLookupHit.M(int64(1)))
+}
+
+// RecordPodCacheEviction increments the metric that records Pod cache evictions.
+func RecordPodCacheEviction() {
+	stats.Record(context.Background(), mPodCacheEvicted.M(int64(1)))
+}
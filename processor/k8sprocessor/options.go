@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"time"
 
 	"k8s.io/apimachinery/pkg/selection"
 
@@ -30,12 +31,17 @@ const (
 	filterOPExists       = "exists"
 	filterOPDoesNotExist = "does-not-exist"
 
-	metdataNamespace   = "namespace"
-	metadataPodName    = "podName"
-	metadataStartTime  = "startTime"
-	metadataDeployment = "deployment"
-	metadataCluster    = "cluster"
-	metadataNode       = "node"
+	metdataNamespace    = "namespace"
+	metadataPodName     = "podName"
+	metadataStartTime   = "startTime"
+	metadataDeployment  = "deployment"
+	metadataReplicaSet  = "replicaset"
+	metadataStatefulSet = "statefulset"
+	metadataDaemonSet   = "daemonset"
+	metadataJob         = "job"
+	metadataCronJob     = "cronjob"
+	metadataCluster     = "cluster"
+	metadataNode        = "node"
 )
 
 // Option represents a configuration option that can be passes.
@@ -74,6 +80,16 @@ func WithExtractMetadata(fields ...string) Option {
 				p.rules.StartTime = true
 			case metadataDeployment:
 				p.rules.Deployment = true
+			case metadataReplicaSet:
+				p.rules.ReplicaSet = true
+			case metadataStatefulSet:
+				p.rules.StatefulSet = true
+			case metadataDaemonSet:
+				p.rules.DaemonSet = true
+			case metadataJob:
+				p.rules.Job = true
+			case metadataCronJob:
+				p.rules.CronJob = true
 			case metadataCluster:
 				p.rules.Cluster = true
 			case metadataNode:
@@ -114,8 +130,18 @@ func extractFieldRules(fieldType string, fields ...FieldExtractConfig) ([]kube.F
 	rules := []kube.FieldExtractionRule{}
 	for _, a := range fields {
 		name := a.TagName
-		if name == "" {
-			name = fmt.Sprintf("k8s.%s.%s", fieldType, a.Key)
+		switch a.From {
+		case "", kube.MetadataFromPod:
+			a.From = kube.MetadataFromPod
+			if name == "" {
+				name = fmt.Sprintf("k8s.%s.%s", fieldType, a.Key)
+			}
+		case kube.MetadataFromNamespace, kube.MetadataFromNode:
+			if name == "" {
+				name = fmt.Sprintf("k8s.%s.%s.%s", a.From, fieldType, a.Key)
+			}
+		default:
+			return rules, fmt.Errorf("'%s' is not a valid %s source for key=%s", a.From, fieldType, a.Key)
 		}
 
 		var r *regexp.Regexp
@@ -132,12 +158,41 @@ func extractFieldRules(fieldType string, fields ...FieldExtractConfig) ([]kube.F
 		}
 
 		rules = append(rules, kube.FieldExtractionRule{
-			Name: name, Key: a.Key, Regex: r,
+			Name: name, Key: a.Key, Regex: r, From: a.From,
 		})
 	}
 	return rules, nil
 }
 
+// WithPodAssociations allows specifying how data is associated with pods. Pod
+// association by IP address is used when no associations are given.
+func WithPodAssociations(associations ...PodAssociationConfig) Option {
+	return func(p *kubernetesprocessor) error {
+		if len(associations) == 0 {
+			p.podAssociations = defaultPodAssociations
+			return nil
+		}
+		p.podAssociations = nil
+		for _, cfg := range associations {
+			a, err := newPodAssociation(cfg)
+			if err != nil {
+				return err
+			}
+			p.podAssociations = append(p.podAssociations, a)
+		}
+		return nil
+	}
+}
+
+// WithMetricsPodIPLabel allows tagging individual timeseries with the metadata of the
+// pod whose IP address is the value of the given label.
+func WithMetricsPodIPLabel(label string) Option {
+	return func(p *kubernetesprocessor) error {
+		p.metricsPodIPLabel = label
+		return nil
+	}
+}
+
 // WithFilterNode allows specifying options to control filtering pods by a node/host.
 func WithFilterNode(node, nodeFromEnvVar string) Option {
 	return func(p *kubernetesprocessor) error {
@@ -219,3 +274,102 @@ func WithFilterFields(filters ...FieldFilterConfig) Option {
 		return nil
 	}
 }
+
+// WithIgnorePodNames allows specifying regular expressions matching the names of pods
+// that data must never be associated with. The default patterns are used when none are given.
+func WithIgnorePodNames(patterns ...string) Option {
+	return func(p *kubernetesprocessor) error {
+		if len(patterns) == 0 {
+			p.ignore.PodNames = nil
+			return nil
+		}
+		names := []*regexp.Regexp{}
+		for _, pattern := range patterns {
+			r, err := regexp.Compile(pattern)
+			if err != nil {
+				return fmt.Errorf("'%s' is not a valid pod name ignore pattern: %v", pattern, err)
+			}
+			names = append(names, r)
+		}
+		p.ignore.PodNames = names
+		return nil
+	}
+}
+
+// WithIgnoreLabels allows ignoring pods by their labels.
+func WithIgnoreLabels(rules ...IgnoreRuleConfig) Option {
+	return func(p *kubernetesprocessor) error {
+		labels, err := ignoreRules("label", rules...)
+		if err != nil {
+			return err
+		}
+		p.ignore.Labels = labels
+		return nil
+	}
+}
+
+// WithIgnoreAnnotations allows ignoring pods by their annotations.
+func WithIgnoreAnnotations(rules ...IgnoreRuleConfig) Option {
+	return func(p *kubernetesprocessor) error {
+		annotations, err := ignoreRules("annotation", rules...)
+		if err != nil {
+			return err
+		}
+		p.ignore.Annotations = annotations
+		return nil
+	}
+}
+
+func ignoreRules(fieldType string, rules ...IgnoreRuleConfig) ([]kube.IgnoreRule, error) {
+	result := []kube.IgnoreRule{}
+	for _, r := range rules {
+		if r.Key == "" {
+			return result, fmt.Errorf("%s ignore rule must have a key", fieldType)
+		}
+		var value *regexp.Regexp
+		if r.Value != "" {
+			var err error
+			value, err = regexp.Compile(r.Value)
+			if err != nil {
+				return result, fmt.Errorf("'%s' is not a valid %s ignore value for key=%s: %v", r.Value, fieldType, r.Key, err)
+			}
+		}
+		result = append(result, kube.IgnoreRule{Key: r.Key, Value: value})
+	}
+	return result, nil
+}
+
+// WithPodDeleteGracePeriod allows specifying how long deleted pods are kept.
+// The default is used when zero.
+func WithPodDeleteGracePeriod(d time.Duration) Option {
+	return func(p *kubernetesprocessor) error {
+		if d < 0 {
+			return fmt.Errorf("pod delete grace period cannot be negative, got %v", d)
+		}
+		p.cache.DeleteGracePeriod = d
+		return nil
+	}
+}
+
+// WithSyncPeriod allows specifying how often pods are resynced. The default is used when zero.
+func WithSyncPeriod(d time.Duration) Option {
+	return func(p *kubernetesprocessor) error {
+		if d < 0 {
+			return fmt.Errorf("sync period cannot be negative, got %v", d)
+		}
+		p.cache.SyncPeriod = d
+		return nil
+	}
+}
+
+// WithPodCacheSize allows bounding the number of entries in the pod cache. The cache is
+// unbounded when zero.
+func WithPodCacheSize(size int) Option {
+	return func(p *kubernetesprocessor) error {
+		if size < 0 {
+			return fmt.Errorf("pod cache size cannot be negative, got %d", size)
+		}
+		p.cache.MaxSize = size
+		return nil
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2019 Omnition Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sprocessor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/k8sprocessor/kube"
+)
+
+func TestWithExtractMetadataWorkloads(t *testing.T) {
+	p := &kubernetesprocessor{}
+	require.NoError(t, WithExtractMetadata("replicaset", "statefulset", "daemonset", "job", "cronjob")(p))
+	assert.Equal(t, kube.ExtractionRules{
+		ReplicaSet:  true,
+		StatefulSet: true,
+		DaemonSet:   true,
+		Job:         true,
+		CronJob:     true,
+	}, p.rules)
+}
+
+func TestExtractFieldRulesFrom(t *testing.T) {
+	rules, err := extractFieldRules("label",
+		FieldExtractConfig{Key: "app"},
+		FieldExtractConfig{Key: "team", From: "namespace"},
+		FieldExtractConfig{TagName: "zone", Key: "topology.kubernetes.io/zone", From: "node"},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []kube.FieldExtractionRule{
+		{Name: "k8s.label.app", Key: "app", From: kube.MetadataFromPod},
+		{Name: "k8s.namespace.label.team", Key: "team", From: kube.MetadataFromNamespace},
+		{Name: "zone", Key: "topology.kubernetes.io/zone", From: kube.MetadataFromNode},
+	}, rules)
+
+	_, err = extractFieldRules("annotation", FieldExtractConfig{Key: "owner", From: "deployment"})
+	assert.EqualError(t, err, "'deployment' is not a valid annotation source for key=owner")
+}
+
+func TestWithIgnoreRules(t *testing.T) {
+	p := &kubernetesprocessor{}
+	require.NoError(t, WithIgnorePodNames("^istio-", "-proxy$")(p))
+	require.NoError(t, WithIgnoreLabels(IgnoreRuleConfig{Key: "sidecar"})(p))
+	require.NoError(t, WithIgnoreAnnotations(IgnoreRuleConfig{Key: "telemetry", Value: "^off$"})(p))
+
+	require.Len(t, p.ignore.PodNames, 2)
+	assert.Equal(t, "-proxy$", p.ignore.PodNames[1].String())
+	assert.Equal(t, []kube.IgnoreRule{{Key: "sidecar"}}, p.ignore.Labels)
+	require.Len(t, p.ignore.Annotations, 1)
+	assert.Equal(t, "telemetry", p.ignore.Annotations[0].Key)
+	assert.Equal(t, "^off$", p.ignore.Annotations[0].Value.String())
+
+	// defaults are used when no names are given
+	require.NoError(t, WithIgnorePodNames()(p))
+	assert.Nil(t, p.ignore.PodNames)
+
+	assert.Error(t, WithIgnorePodNames("(")(p))
+	assert.EqualError(t, WithIgnoreLabels(IgnoreRuleConfig{Value: "x"})(p), "label ignore rule must have a key")
+	assert.Error(t, WithIgnoreAnnotations(IgnoreRuleConfig{Key: "a", Value: "("})(p))
+}
+
+func TestWithPodCache(t *testing.T) {
+	p := &kubernetesprocessor{}
+	require.NoError(t, WithPodDeleteGracePeriod(time.Second)(p))
+	require.NoError(t, WithSyncPeriod(time.Minute)(p))
+	require.NoError(t, WithPodCacheSize(100)(p))
+	assert.Equal(t, kube.CacheConfig{
+		DeleteGracePeriod: time.Second,
+		SyncPeriod:        time.Minute,
+		MaxSize:           100,
+	}, p.cache)
+
+	assert.EqualError(t, WithPodDeleteGracePeriod(-time.Second)(p), "pod delete grace period cannot be negative, got -1s")
+	assert.EqualError(t, WithSyncPeriod(-time.Second)(p), "sync period cannot be negative, got -1s")
+	assert.EqualError(t, WithPodCacheSize(-1)(p), "pod cache size cannot be negative, got -1")
+}
@@ -0,0 +1,123 @@
+// Copyright 2019 Omnition Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sprocessor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/open-telemetry/opentelemetry-collector/client"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/k8sprocessor/kube"
+)
+
+const (
+	associationFromResourceAttribute = "resource_attribute"
+	associationFromConnection        = "connection"
+
+	k8sPodUIDLabelName    = "k8s.pod.uid"
+	k8sPodNameLabelName   = "k8s.pod.name"
+	k8sNamespaceLabelName = "k8s.namespace.name"
+	containerIDLabelName  = "container.id"
+)
+
+// podAssociationKinds maps the resource attributes that can be used to
+// associate data with a pod to the kind of pod identifier they hold.
+var podAssociationKinds = map[string]kube.PodIdentifierKind{
+	k8sIPLabelName:       kube.PodIdentifierKindIP,
+	clientIPLabelName:    kube.PodIdentifierKindIP,
+	k8sPodUIDLabelName:   kube.PodIdentifierKindUID,
+	k8sPodNameLabelName:  kube.PodIdentifierKindName,
+	containerIDLabelName: kube.PodIdentifierKindContainerID,
+}
+
+// defaultPodAssociations are used when no pod associations are configured. Data is
+// associated with pods by IP address only, either from the resource or from the
+// connection the data was received on.
+var defaultPodAssociations = []podAssociation{
+	{from: associationFromResourceAttribute, name: k8sIPLabelName, kind: kube.PodIdentifierKindIP},
+	{from: associationFromResourceAttribute, name: clientIPLabelName, kind: kube.PodIdentifierKindIP},
+	{from: associationFromConnection, kind: kube.PodIdentifierKindIP},
+}
+
+// podAssociation is a single source of a pod identifier.
+type podAssociation struct {
+	from string
+	name string
+	kind kube.PodIdentifierKind
+}
+
+func newPodAssociation(cfg PodAssociationConfig) (podAssociation, error) {
+	switch cfg.From {
+	case associationFromConnection:
+		if cfg.Name != "" {
+			return podAssociation{}, fmt.Errorf("pod association from '%s' does not support a name, got '%s'", cfg.From, cfg.Name)
+		}
+		return podAssociation{from: cfg.From, kind: kube.PodIdentifierKindIP}, nil
+	case associationFromResourceAttribute:
+		kind, ok := podAssociationKinds[cfg.Name]
+		if !ok {
+			return podAssociation{}, fmt.Errorf("'%s' is not a supported pod association resource attribute", cfg.Name)
+		}
+		return podAssociation{from: cfg.From, name: cfg.Name, kind: kind}, nil
+	default:
+		return podAssociation{}, fmt.Errorf("'%s' is not a valid pod association source", cfg.From)
+	}
+}
+
+// identifier returns the pod identifier found by this association along with the raw
+// value it was built from. Attributes are looked up in each of the given maps in order.
+func (a podAssociation) identifier(ctx context.Context, attrs ...map[string]string) (kube.PodIdentifier, string, bool) {
+	if a.from == associationFromConnection {
+		if c, ok := client.FromContext(ctx); ok && c.IP != "" {
+			return kube.PodIdentifierFromIP(c.IP), c.IP, true
+		}
+		return "", "", false
+	}
+
+	for _, m := range attrs {
+		v := m[a.name]
+		if v == "" {
+			continue
+		}
+		switch a.kind {
+		case kube.PodIdentifierKindIP:
+			return kube.PodIdentifierFromIP(v), v, true
+		case kube.PodIdentifierKindUID:
+			return kube.PodIdentifierFromUID(v), v, true
+		case kube.PodIdentifierKindContainerID:
+			return kube.PodIdentifierFromContainerID(v), v, true
+		case kube.PodIdentifierKindName:
+			// A pod name is only unique within its namespace.
+			if ns := m[k8sNamespaceLabelName]; ns != "" {
+				return kube.PodIdentifierFromName(ns, v), v, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// podIdentifierKinds returns the distinct kinds of identifiers used by associations.
+func podIdentifierKinds(associations []podAssociation) []kube.PodIdentifierKind {
+	var kinds []kube.PodIdentifierKind
+	seen := map[kube.PodIdentifierKind]bool{}
+	for _, a := range associations {
+		if !seen[a.kind] {
+			seen[a.kind] = true
+			kinds = append(kinds, a.kind)
+		}
+	}
+	return kinds
+}
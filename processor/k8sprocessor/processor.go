@@ -16,9 +16,10 @@ package k8sprocessor
 
 import (
 	"context"
+	"sort"
 
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
 	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
-	"github.com/open-telemetry/opentelemetry-collector/client"
 	"github.com/open-telemetry/opentelemetry-collector/component"
 	"github.com/open-telemetry/opentelemetry-collector/consumer"
 	"github.com/open-telemetry/opentelemetry-collector/consumer/consumerdata"
@@ -34,12 +35,17 @@ const (
 )
 
 type kubernetesprocessor struct {
-	logger          *zap.Logger
-	nextConsumer    consumer.TraceConsumerOld
-	kc              kube.Client
-	passthroughMode bool
-	rules           kube.ExtractionRules
-	filters         kube.Filters
+	logger              *zap.Logger
+	nextConsumer        consumer.TraceConsumerOld
+	nextMetricsConsumer consumer.MetricsConsumerOld
+	kc                  kube.Client
+	passthroughMode     bool
+	rules               kube.ExtractionRules
+	filters             kube.Filters
+	podAssociations     []podAssociation
+	metricsPodIPLabel   string
+	ignore              kube.IgnoreRules
+	cache               kube.CacheConfig
 }
 
 // NewTraceProcessor returns a component.TraceProcessorOld that adds the WithAttributeMap(attributes) to all spans
@@ -51,9 +57,32 @@ func NewTraceProcessor(
 	options ...Option,
 ) (component.TraceProcessorOld, error) {
 	kp := &kubernetesprocessor{logger: logger, nextConsumer: nextConsumer}
+	if err := kp.init(kubeClient, options...); err != nil {
+		return nil, err
+	}
+	return kp, nil
+}
+
+// NewMetricsProcessor returns a component.MetricsProcessorOld that adds pod metadata to the
+// resource of all metrics passed to it, and optionally to individual timeseries.
+func NewMetricsProcessor(
+	logger *zap.Logger,
+	nextMetricsConsumer consumer.MetricsConsumerOld,
+	kubeClient kube.ClientProvider,
+	options ...Option,
+) (component.MetricsProcessorOld, error) {
+	kp := &kubernetesprocessor{logger: logger, nextMetricsConsumer: nextMetricsConsumer}
+	if err := kp.init(kubeClient, options...); err != nil {
+		return nil, err
+	}
+	return kp, nil
+}
+
+func (kp *kubernetesprocessor) init(kubeClient kube.ClientProvider, options ...Option) error {
+	kp.podAssociations = defaultPodAssociations
 	for _, opt := range options {
 		if err := opt(kp); err != nil {
-			return nil, err
+			return err
 		}
 	}
 
@@ -61,13 +90,18 @@ func NewTraceProcessor(
 		kubeClient = kube.New
 	}
 	if !kp.passthroughMode {
-		kc, err := kubeClient(logger, kp.rules, kp.filters, nil, nil)
+		kinds := podIdentifierKinds(kp.podAssociations)
+		// Timeseries are always associated with pods by IP.
+		if kp.metricsPodIPLabel != "" {
+			kinds = podIdentifierKinds(append(kp.podAssociations, podAssociation{kind: kube.PodIdentifierKindIP}))
+		}
+		kc, err := kubeClient(kp.logger, kp.rules, kp.filters, kinds, kp.ignore, kp.cache, nil, nil)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		kp.kc = kc
 	}
-	return kp, nil
+	return nil
 }
 
 func (kp *kubernetesprocessor) GetCapabilities() component.ProcessorCapabilities {
@@ -89,77 +123,165 @@ func (kp *kubernetesprocessor) Shutdown(context.Context) error {
 }
 
 func (kp *kubernetesprocessor) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
-	var podIP string
 	// check if the application, a collector/agent or a prior processor has already
-	// annotated the batch with IP.
+	// annotated the batch with pod identifiers.
+	var attrs []map[string]string
 	if td.Resource != nil {
-		podIP = kp.k8sIPFromAttributes(td.Resource.Labels)
+		attrs = append(attrs, td.Resource.Labels)
 	}
 
 	// Jaeger client libs tag the process with the process/resource IP and
 	// jaeger to OC translator maps jaeger process to OC node.
 	// TODO: Should jaeger translator map jaeger process to OC resource instead?
-	if podIP == "" && td.SourceFormat == sourceFormatJaeger {
-		if td.Node != nil {
-			podIP = kp.k8sIPFromAttributes(td.Node.Attributes)
-		}
+	if td.SourceFormat == sourceFormatJaeger && td.Node != nil {
+		attrs = append(attrs, td.Node.Attributes)
 	}
 
-	// Check if the receiver detected client IP.
-	if podIP == "" {
-		if c, ok := client.FromContext(ctx); ok {
-			podIP = c.IP
-		}
+	td.Resource = kp.processResource(ctx, td.Resource, attrs)
+
+	// TODO: should add to spans that have a resource not the same as the batch?
+	return kp.nextConsumer.ConsumeTraceData(ctx, td)
+}
+
+func (kp *kubernetesprocessor) ConsumeMetricsData(ctx context.Context, md consumerdata.MetricsData) error {
+	// Receivers such as collectd and signalfx carry the metadata of the
+	// sender on the node instead of the resource.
+	var attrs []map[string]string
+	if md.Resource != nil {
+		attrs = append(attrs, md.Resource.Labels)
+	}
+	if md.Node != nil {
+		attrs = append(attrs, md.Node.Attributes)
 	}
 
-	if podIP != "" {
-		if td.Resource == nil {
-			td.Resource = &resourcepb.Resource{}
-		}
-		if td.Resource.Labels == nil {
-			td.Resource.Labels = map[string]string{}
+	md.Resource = kp.processResource(ctx, md.Resource, attrs)
+
+	if !kp.passthroughMode && kp.metricsPodIPLabel != "" {
+		for _, m := range md.Metrics {
+			kp.processTimeseries(m)
 		}
-		td.Resource.Labels[k8sIPLabelName] = podIP
+	}
+	return kp.nextMetricsConsumer.ConsumeMetricsData(ctx, md)
+}
+
+// processResource tags the resource with the pod IP and the metadata of the pod
+// the data is associated with. A new resource is returned if needed.
+func (kp *kubernetesprocessor) processResource(ctx context.Context, resource *resourcepb.Resource, attrs []map[string]string) *resourcepb.Resource {
+	if podIP := kp.podIP(ctx, attrs); podIP != "" {
+		resource = setResourceLabel(resource, k8sIPLabelName, podIP)
 	}
 
 	// Don't invoke any k8s client functionality in passthrough mode.
 	// Just tag the IP and forward the batch.
 	if kp.passthroughMode {
-		return kp.nextConsumer.ConsumeTraceData(ctx, td)
+		return resource
 	}
 
-	attrs := kp.getAttributesForPodIP(podIP)
-	if len(attrs) == 0 {
-		return kp.nextConsumer.ConsumeTraceData(ctx, td)
+	for k, v := range kp.getAttributesForPod(ctx, attrs) {
+		resource = setResourceLabel(resource, k, v)
 	}
+	return resource
+}
 
-	if td.Resource == nil {
-		td.Resource = &resourcepb.Resource{}
+// processTimeseries tags each timeseries of the metric that carries a pod IP label
+// with the metadata of that pod. Labels are added to the metric descriptor and every
+// timeseries, without a value for timeseries that were not associated with a pod.
+// Labels already defined by the metric are never overwritten.
+func (kp *kubernetesprocessor) processTimeseries(m *metricspb.Metric) {
+	if m.GetMetricDescriptor() == nil || len(m.Timeseries) == 0 {
+		return
 	}
-	if td.Resource.Labels == nil {
-		td.Resource.Labels = map[string]string{}
+
+	ipIndex := -1
+	existing := map[string]bool{}
+	for i, k := range m.MetricDescriptor.LabelKeys {
+		existing[k.Key] = true
+		if k.Key == kp.metricsPodIPLabel {
+			ipIndex = i
+		}
+	}
+	if ipIndex < 0 {
+		return
 	}
 
-	for k, v := range attrs {
-		td.Resource.Labels[k] = v
+	podAttrs := make([]map[string]string, len(m.Timeseries))
+	newKeys := map[string]bool{}
+	for i, ts := range m.Timeseries {
+		if ipIndex >= len(ts.LabelValues) || !ts.LabelValues[ipIndex].HasValue {
+			continue
+		}
+		pod, ok := kp.kc.GetPod(kube.PodIdentifierFromIP(ts.LabelValues[ipIndex].Value))
+		if !ok {
+			continue
+		}
+		podAttrs[i] = pod.Attributes
+		for k := range pod.Attributes {
+			if !existing[k] {
+				newKeys[k] = true
+			}
+		}
+	}
+	if len(newKeys) == 0 {
+		return
 	}
 
-	// TODO: should add to spans that have a resource not the same as the batch?
-	return kp.nextConsumer.ConsumeTraceData(ctx, td)
+	keys := make([]string, 0, len(newKeys))
+	for k := range newKeys {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	numKeys := len(m.MetricDescriptor.LabelKeys)
+	for _, k := range keys {
+		m.MetricDescriptor.LabelKeys = append(m.MetricDescriptor.LabelKeys, &metricspb.LabelKey{Key: k})
+	}
+	for i, ts := range m.Timeseries {
+		// Pad timeseries that omit trailing label values so new values line up with their keys.
+		for len(ts.LabelValues) < numKeys {
+			ts.LabelValues = append(ts.LabelValues, &metricspb.LabelValue{})
+		}
+		for _, k := range keys {
+			v, ok := podAttrs[i][k]
+			ts.LabelValues = append(ts.LabelValues, &metricspb.LabelValue{Value: v, HasValue: ok})
+		}
+	}
 }
 
-func (kp *kubernetesprocessor) getAttributesForPodIP(ip string) map[string]string {
-	pod, ok := kp.kc.GetPodByIP(ip)
-	if !ok {
-		return nil
+func setResourceLabel(resource *resourcepb.Resource, key, value string) *resourcepb.Resource {
+	if resource == nil {
+		resource = &resourcepb.Resource{}
 	}
-	return pod.Attributes
+	if resource.Labels == nil {
+		resource.Labels = map[string]string{}
+	}
+	resource.Labels[key] = value
+	return resource
 }
 
-func (kp *kubernetesprocessor) k8sIPFromAttributes(attrs map[string]string) string {
-	ip := attrs[k8sIPLabelName]
-	if ip == "" {
-		ip = attrs[clientIPLabelName]
+// podIP returns the first pod IP address found by the configured pod associations.
+func (kp *kubernetesprocessor) podIP(ctx context.Context, attrs []map[string]string) string {
+	for _, a := range kp.podAssociations {
+		if a.kind != kube.PodIdentifierKindIP {
+			continue
+		}
+		if _, ip, ok := a.identifier(ctx, attrs...); ok {
+			return ip
+		}
 	}
-	return ip
+	return ""
+}
+
+// getAttributesForPod tries the configured pod associations in order and returns
+// the attributes of the first pod found.
+func (kp *kubernetesprocessor) getAttributesForPod(ctx context.Context, attrs []map[string]string) map[string]string {
+	for _, a := range kp.podAssociations {
+		id, _, ok := a.identifier(ctx, attrs...)
+		if !ok {
+			continue
+		}
+		if pod, ok := kp.kc.GetPod(id); ok {
+			return pod.Attributes
+		}
+	}
+	return nil
 }
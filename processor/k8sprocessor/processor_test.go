@@ -19,6 +19,8 @@ import (
 	"testing"
 
 	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
 	"github.com/open-telemetry/opentelemetry-collector/client"
 	"github.com/open-telemetry/opentelemetry-collector/component"
 	"github.com/open-telemetry/opentelemetry-collector/component/componenttest"
@@ -136,7 +138,7 @@ func TestAddLabels(t *testing.T) {
 		"2": {},
 	}
 	for ip, attrs := range tests {
-		kc.Pods[ip] = &kube.Pod{Attributes: attrs}
+		kc.Pods[kube.PodIdentifierFromIP(ip)] = &kube.Pod{Attributes: attrs}
 	}
 
 	var i int
@@ -158,6 +160,227 @@ func TestAddLabels(t *testing.T) {
 	}
 }
 
+func TestPodAssociations(t *testing.T) {
+	next := &testConsumer{}
+	p, err := NewTraceProcessor(
+		zap.NewNop(),
+		next,
+		kube.NewFakeClient,
+		WithPodAssociations(
+			PodAssociationConfig{From: "resource_attribute", Name: "k8s.pod.uid"},
+			PodAssociationConfig{From: "resource_attribute", Name: "k8s.pod.name"},
+			PodAssociationConfig{From: "resource_attribute", Name: "container.id"},
+			PodAssociationConfig{From: "connection"},
+		),
+	)
+	require.NoError(t, err)
+
+	kc := fakeClientFromProcessor(t, p)
+	kc.Pods[kube.PodIdentifierFromUID("uid-1")] = &kube.Pod{Attributes: map[string]string{"pod": "by-uid"}}
+	kc.Pods[kube.PodIdentifierFromName("ns1", "pod-1")] = &kube.Pod{Attributes: map[string]string{"pod": "by-name"}}
+	kc.Pods[kube.PodIdentifierFromContainerID("c1")] = &kube.Pod{Attributes: map[string]string{"pod": "by-container"}}
+	kc.Pods[kube.PodIdentifierFromIP("1.1.1.1")] = &kube.Pod{Attributes: map[string]string{"pod": "by-ip"}}
+
+	tests := []struct {
+		name   string
+		labels map[string]string
+		ip     string
+		want   string
+		wantIP string
+	}{
+		{
+			name:   "uid first",
+			labels: map[string]string{"k8s.pod.uid": "uid-1", "k8s.pod.name": "pod-1", "k8s.namespace.name": "ns1"},
+			ip:     "1.1.1.1",
+			want:   "by-uid",
+			wantIP: "1.1.1.1",
+		},
+		{
+			name:   "unknown uid falls back to name",
+			labels: map[string]string{"k8s.pod.uid": "uid-2", "k8s.pod.name": "pod-1", "k8s.namespace.name": "ns1"},
+			want:   "by-name",
+		},
+		{
+			name:   "name requires namespace",
+			labels: map[string]string{"k8s.pod.name": "pod-1", "container.id": "c1"},
+			want:   "by-container",
+		},
+		{
+			name:   "connection",
+			labels: map[string]string{"container.id": "c2"},
+			ip:     "1.1.1.1",
+			want:   "by-ip",
+			wantIP: "1.1.1.1",
+		},
+		{
+			name:   "no match",
+			labels: map[string]string{"container.id": "c2"},
+		},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.ip != "" {
+				ctx = client.NewContext(ctx, &client.Client{IP: tt.ip})
+			}
+			err = p.ConsumeTraceData(ctx, consumerdata.TraceData{
+				Resource: &resourcepb.Resource{Labels: tt.labels},
+			})
+			require.NoError(t, err)
+
+			require.Len(t, next.data, i+1)
+			labels := next.data[i].Resource.Labels
+			assert.Equal(t, tt.want, labels["pod"])
+			assert.Equal(t, tt.wantIP, labels["k8s.pod.ip"])
+		})
+	}
+}
+
+func TestPodAssociationsInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  PodAssociationConfig
+		err  string
+	}{
+		{"unknown source", PodAssociationConfig{From: "span"}, "'span' is not a valid pod association source"},
+		{"unknown attribute", PodAssociationConfig{From: "resource_attribute", Name: "host.name"}, "'host.name' is not a supported pod association resource attribute"},
+		{"connection with name", PodAssociationConfig{From: "connection", Name: "ip"}, "pod association from 'connection' does not support a name, got 'ip'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewTraceProcessor(zap.NewNop(), &testConsumer{}, kube.NewFakeClient, WithPodAssociations(tt.cfg))
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestNewMetricsProcessor(t *testing.T) {
+	_, err := NewMetricsProcessor(
+		zap.NewNop(),
+		exportertest.NewNopMetricsExporterOld(),
+		kube.NewFakeClient,
+	)
+	require.NoError(t, err)
+}
+
+func TestMetricsResourceLabels(t *testing.T) {
+	next := &exportertest.SinkMetricsExporterOld{}
+	p, err := NewMetricsProcessor(
+		zap.NewNop(),
+		next,
+		kube.NewFakeClient,
+	)
+	require.NoError(t, err)
+
+	kc := fakeClientFromProcessor(t, p)
+	kc.Pods[kube.PodIdentifierFromIP("1.1.1.1")] = &kube.Pod{Attributes: map[string]string{"k8s.pod.name": "pod-1"}}
+	kc.Pods[kube.PodIdentifierFromIP("2.2.2.2")] = &kube.Pod{Attributes: map[string]string{"k8s.pod.name": "pod-2"}}
+
+	// IP from the node, as set by collectd and signalfx receivers
+	err = p.ConsumeMetricsData(context.Background(), consumerdata.MetricsData{
+		Node: &commonpb.Node{
+			Attributes: map[string]string{"ip": "1.1.1.1"},
+		},
+	})
+	require.NoError(t, err)
+
+	// IP from the connection
+	ctx := client.NewContext(context.Background(), &client.Client{IP: "2.2.2.2"})
+	err = p.ConsumeMetricsData(ctx, consumerdata.MetricsData{})
+	require.NoError(t, err)
+
+	// no IP
+	err = p.ConsumeMetricsData(context.Background(), consumerdata.MetricsData{})
+	require.NoError(t, err)
+
+	mds := next.AllMetrics()
+	require.Len(t, mds, 3)
+	assert.Equal(t, map[string]string{"k8s.pod.ip": "1.1.1.1", "k8s.pod.name": "pod-1"}, mds[0].Resource.Labels)
+	assert.Equal(t, map[string]string{"k8s.pod.ip": "2.2.2.2", "k8s.pod.name": "pod-2"}, mds[1].Resource.Labels)
+	assert.Nil(t, mds[2].Resource)
+}
+
+func TestMetricsTimeseriesLabels(t *testing.T) {
+	next := &exportertest.SinkMetricsExporterOld{}
+	p, err := NewMetricsProcessor(
+		zap.NewNop(),
+		next,
+		kube.NewFakeClient,
+		WithMetricsPodIPLabel("pod_ip"),
+	)
+	require.NoError(t, err)
+
+	kc := fakeClientFromProcessor(t, p)
+	kc.Pods[kube.PodIdentifierFromIP("1.1.1.1")] = &kube.Pod{Attributes: map[string]string{
+		"k8s.pod.name":       "pod-1",
+		"k8s.namespace.name": "ns1",
+	}}
+	kc.Pods[kube.PodIdentifierFromIP("2.2.2.2")] = &kube.Pod{Attributes: map[string]string{
+		"k8s.pod.name": "pod-2",
+		// already a metric label, must not be overwritten
+		"container": "from-pod",
+	}}
+
+	metric := &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{
+			Name: "requests",
+			LabelKeys: []*metricspb.LabelKey{
+				{Key: "pod_ip"},
+				{Key: "container"},
+			},
+		},
+		Timeseries: []*metricspb.TimeSeries{
+			{LabelValues: []*metricspb.LabelValue{{Value: "1.1.1.1", HasValue: true}, {Value: "app", HasValue: true}}},
+			{LabelValues: []*metricspb.LabelValue{{Value: "2.2.2.2", HasValue: true}, {Value: "app", HasValue: true}}},
+			{LabelValues: []*metricspb.LabelValue{{Value: "3.3.3.3", HasValue: true}}},
+		},
+	}
+	unrelated := &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{
+			Name:      "cpu",
+			LabelKeys: []*metricspb.LabelKey{{Key: "cpu"}},
+		},
+		Timeseries: []*metricspb.TimeSeries{
+			{LabelValues: []*metricspb.LabelValue{{Value: "1.1.1.1", HasValue: true}}},
+		},
+	}
+	err = p.ConsumeMetricsData(context.Background(), consumerdata.MetricsData{
+		Metrics: []*metricspb.Metric{metric, unrelated},
+	})
+	require.NoError(t, err)
+
+	mds := next.AllMetrics()
+	require.Len(t, mds, 1)
+	got := mds[0].Metrics[0]
+
+	var keys []string
+	for _, k := range got.MetricDescriptor.LabelKeys {
+		keys = append(keys, k.Key)
+	}
+	assert.Equal(t, []string{"pod_ip", "container", "k8s.namespace.name", "k8s.pod.name"}, keys)
+	assert.Equal(t, []*metricspb.LabelValue{
+		{Value: "1.1.1.1", HasValue: true},
+		{Value: "app", HasValue: true},
+		{Value: "ns1", HasValue: true},
+		{Value: "pod-1", HasValue: true},
+	}, got.Timeseries[0].LabelValues)
+	assert.Equal(t, []*metricspb.LabelValue{
+		{Value: "2.2.2.2", HasValue: true},
+		{Value: "app", HasValue: true},
+		{},
+		{Value: "pod-2", HasValue: true},
+	}, got.Timeseries[1].LabelValues)
+	assert.Equal(t, []*metricspb.LabelValue{
+		{Value: "3.3.3.3", HasValue: true},
+		{},
+		{},
+		{},
+	}, got.Timeseries[2].LabelValues)
+
+	assert.Len(t, mds[0].Metrics[1].MetricDescriptor.LabelKeys, 1)
+	assert.Len(t, mds[0].Metrics[1].Timeseries[0].LabelValues, 1)
+}
+
 func TestPassthroughStart(t *testing.T) {
 	next := &testConsumer{}
 	opts := []Option{WithPassthrough()}
@@ -175,7 +398,7 @@ func TestPassthroughStart(t *testing.T) {
 	assert.NoError(t, p.Shutdown(context.Background()))
 }
 
-func fakeClientFromProcessor(t *testing.T, p component.TraceProcessorOld) *kube.FakeClient {
+func fakeClientFromProcessor(t *testing.T, p component.Processor) *kube.FakeClient {
 	kp, ok := p.(*kubernetesprocessor)
 	if !ok {
 		assert.FailNow(t, "could not assert processor %s to kubernetesprocessor", p)
@@ -20,6 +20,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector/config/configmodels"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/protocol"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/transport"
 )
 
 const (
@@ -33,13 +34,19 @@ const (
 type Config struct {
 	configmodels.ReceiverSettings `mapstructure:",squash"`
 
-	// Transport is either "tcp" or "udp".
+	// Transport is either "tcp", "udp" or "pickle". The "pickle" transport
+	// receives the pickle protocol over TCP, typically on port 2004, as sent by
+	// carbon-relay and carbon-c-relay.
 	Transport string `mapstructure:"transport"`
 
 	// TCPIdleTimeout is the timout for idle TCP connections, it is ignored
 	// if transport being used is UDP.
 	TCPIdleTimeout time.Duration `mapstructure:"tcp_idle_timeout"`
 
+	// ProcessingSettings specify the workers parsing the received data and the
+	// batching of the resulting metrics.
+	transport.ProcessingSettings `mapstructure:",squash"`
+
 	// Parser specifies a parser and the respective configuration to be used
 	// by the receiver.
 	Parser *protocol.Config `mapstructure:"parser"`
@@ -25,6 +25,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/protocol"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/transport"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -40,7 +41,7 @@ func TestLoadConfig(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, cfg)
 
-	assert.Equal(t, len(cfg.Receivers), 3)
+	assert.Equal(t, len(cfg.Receivers), 4)
 
 	r0 := cfg.Receivers["carbon"]
 	assert.Equal(t, factory.CreateDefaultConfig(), r0)
@@ -55,6 +56,13 @@ func TestLoadConfig(t *testing.T) {
 			},
 			Transport:      "udp",
 			TCPIdleTimeout: 5 * time.Second,
+			ProcessingSettings: transport.ProcessingSettings{
+				Workers:         4,
+				QueueSize:       100,
+				QueueFullPolicy: transport.QueueFullDrop,
+				BatchSize:       500,
+				BatchTimeout:    time.Second,
+			},
 			Parser: &protocol.Config{
 				Type:   "plaintext",
 				Config: &protocol.PlaintextConfig{},
@@ -70,8 +78,9 @@ func TestLoadConfig(t *testing.T) {
 				NameVal:  "carbon/regex",
 				Endpoint: "localhost:2003",
 			},
-			Transport:      "tcp",
-			TCPIdleTimeout: 30 * time.Second,
+			Transport:          "tcp",
+			TCPIdleTimeout:     30 * time.Second,
+			ProcessingSettings: factory.CreateDefaultConfig().(*Config).ProcessingSettings,
 			Parser: &protocol.Config{
 				Type: "regex",
 				Config: &protocol.RegexParserConfig{
@@ -83,7 +92,8 @@ func TestLoadConfig(t *testing.T) {
 								"dot.key": "dot.value",
 								"key":     "value",
 							},
-							MetricType: "cumulative",
+							MetricType:     "cumulative",
+							TrackStartTime: true,
 						},
 						{
 							Regexp: `(?P<key_just>test)\.(?P<key_match>.*)`,
@@ -94,4 +104,35 @@ func TestLoadConfig(t *testing.T) {
 			},
 		},
 		r2)
+
+	r3 := cfg.Receivers["carbon/template"].(*Config)
+	assert.Equal(t,
+		&Config{
+			ReceiverSettings: configmodels.ReceiverSettings{
+				TypeVal:  configmodels.Type(typeStr),
+				NameVal:  "carbon/template",
+				Endpoint: "localhost:2003",
+			},
+			Transport:          "tcp",
+			TCPIdleTimeout:     30 * time.Second,
+			ProcessingSettings: factory.CreateDefaultConfig().(*Config).ProcessingSettings,
+			Parser: &protocol.Config{
+				Type: "template",
+				Config: &protocol.TemplateParserConfig{
+					Templates: []string{
+						"servers.* .host.measurement* dc=us-east",
+						"env.host.measurement.field*",
+					},
+					Rules: []*protocol.RegexRule{
+						{
+							Regexp:     `^servers\.(?P<key_host>[^.]+)\.rpc\.(?P<name_0>[^.]+)$`,
+							NamePrefix: "rpc",
+							MetricType: "cumulative",
+						},
+					},
+					MetricNameSeparator: "_",
+				},
+			},
+		},
+		r3)
 }
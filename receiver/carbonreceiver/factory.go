@@ -95,6 +95,12 @@ func (f *Factory) CreateDefaultConfig() configmodels.Receiver {
 		},
 		Transport:      "tcp",
 		TCPIdleTimeout: transport.TCPIdleTimeoutDefault,
+		ProcessingSettings: transport.ProcessingSettings{
+			QueueSize:       transport.QueueSizeDefault,
+			QueueFullPolicy: transport.QueueFullBlock,
+			BatchSize:       transport.BatchSizeDefault,
+			BatchTimeout:    transport.BatchTimeoutDefault,
+		},
 		Parser: &protocol.Config{
 			Type:   "plaintext",
 			Config: &protocol.PlaintextConfig{},
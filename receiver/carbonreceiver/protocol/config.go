@@ -27,6 +27,7 @@ var (
 	parserMap = map[string]func() ParserConfig{
 		"plaintext": plaintextDefaultConfig,
 		"regex":     regexDefaultConfig,
+		"template":  templateDefaultConfig,
 	}
 
 	// validParsers keeps a list of all valid parsers to be used in error
@@ -68,6 +69,11 @@ type ParserConfig interface {
 	BuildParser() (Parser, error)
 }
 
+// validator is implemented by the parser configurations validated when loaded.
+type validator interface {
+	validate() error
+}
+
 // LoadParserConfig is used to load the parser configuration according to the
 // specified parser type. It expects the passed viper to be pointing at the level
 // of the Config reference.
@@ -83,13 +89,16 @@ func LoadParserConfig(v *viper.Viper, config *Config) error {
 	config.Config = defaultCfgFn()
 
 	vParserCfg := v.Sub(configSection)
-	if vParserCfg == nil {
-		// Parser config section was not specified, use the default config.
-		return nil
+	if vParserCfg != nil {
+		if err := vParserCfg.UnmarshalExact(config.Config); err != nil {
+			return err
+		}
 	}
+	// Otherwise the parser config section was not specified, use the default
+	// config.
 
-	if err := vParserCfg.UnmarshalExact(config.Config); err != nil {
-		return err
+	if val, ok := config.Config.(validator); ok {
+		return val.validate()
 	}
 
 	return nil
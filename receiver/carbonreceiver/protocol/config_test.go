@@ -66,6 +66,54 @@ config:
 				Config: &RegexParserConfig{},
 			},
 		},
+		{
+			name: "template",
+			yaml: `
+type: template
+config:
+  templates:
+    - "servers.* .host.measurement* dc=us-east"
+  name_separator: "_"
+`,
+			cfg: Config{Type: "template"},
+			want: Config{
+				Type: "template",
+				Config: &TemplateParserConfig{
+					Templates:           []string{"servers.* .host.measurement* dc=us-east"},
+					MetricNameSeparator: "_",
+				},
+			},
+		},
+		{
+			name: "invalid_template",
+			yaml: `
+type: template
+config:
+  templates:
+    - "measurement*.host"
+`,
+			cfg: Config{Type: "template"},
+			want: Config{
+				Type: "template",
+				Config: &TemplateParserConfig{
+					Templates:           []string{"measurement*.host"},
+					MetricNameSeparator: ".",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "default_template",
+			yaml: `type: template`,
+			cfg:  Config{Type: "template"},
+			want: Config{
+				Type: "template",
+				Config: &TemplateParserConfig{
+					MetricNameSeparator: ".",
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -15,10 +15,18 @@
 package protocol
 
 import (
+	"errors"
+
 	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
 	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/open-telemetry/opentelemetry-collector/consumer/consumerdata"
 )
 
+// ErrNotMetric is returned by Parse for valid lines that aren't metrics, e.g.
+// spans, see SpanParser. These lines are neither passed to the next metrics
+// consumer nor reported as invalid.
+var ErrNotMetric = errors.New("the line is not a metric")
+
 // Parser abstracts the type of parsing being done by the receiver.
 type Parser interface {
 	// Parse receives the string with plaintext data, aka line, in the Carbon
@@ -35,9 +43,36 @@ type Parser interface {
 	//
 	// The <metric_timestamp> is the Unix time text of when the measurement was
 	// made.
+	//
+	// Parsers handling lines that aren't metrics should return ErrNotMetric
+	// for them.
 	Parse(line string) (*metricspb.Metric, error)
 }
 
+// PointParser is implemented by parsers that can also transform a Carbon metric
+// whose <metric_value> and <metric_timestamp> were already decoded into a
+// point, e.g. received with the pickle protocol, see
+// https://graphite.readthedocs.io/en/latest/feeding-carbon.html#the-pickle-protocol.
+type PointParser interface {
+	Parser
+
+	// ParsePoint transforms the <metric_path> of a Carbon metric and its point
+	// to the collector metric format. The <metric_path> is handled as by Parse.
+	ParsePoint(path string, point *metricspb.Point) (*metricspb.Metric, error)
+}
+
+// SpanParser is implemented by parsers of protocols that also receive spans
+// along with the metrics, e.g. Wavefront. The spans are batched and passed to
+// the next consumer if it also consumes traces, and skipped otherwise.
+type SpanParser interface {
+	Parser
+
+	// ParseLine transforms a line that is either a metric, as by Parse, or a
+	// span. It returns either the metric or the trace data of the span, or
+	// neither for valid lines that must be skipped.
+	ParseLine(line string) (*metricspb.Metric, *consumerdata.TraceData, error)
+}
+
 // Below a few helper functions useful to different parsers.
 func buildMetricForSinglePoint(
 	metricName string,
@@ -54,9 +89,8 @@ func buildMetricForSinglePoint(
 		},
 		Timeseries: []*metricspb.TimeSeries{
 			{
-				// TODO: StartTimestamp can be set if each cumulative time series are
-				//  	tracked but right now it is not clear if it brings benefits.
-				//		Perhaps as an option so cost is "pay for play".
+				// The StartTimestamp of cumulative time series is only set if
+				// tracked, see RegexRule.TrackStartTime.
 				LabelValues: labelValues,
 				Points:      []*metricspb.Point{point},
 			},
@@ -53,6 +53,9 @@ type ParsedPath struct {
 	// MetricType instructs the helper to generate the metric as the specified
 	// TargetMetricType.
 	MetricType TargetMetricType
+	// TrackStartTime instructs the helper to set the start timestamp of
+	// cumulative metrics, tracking their time series across the parsed lines.
+	TrackStartTime bool
 }
 
 type TargetMetricType string
@@ -67,9 +70,10 @@ const (
 // PathParser to implement a full parser.
 type PathParserHelper struct {
 	pathParser PathParser
+	startTimes *startTimeTracker
 }
 
-var _ (Parser) = (*PathParserHelper)(nil)
+var _ (PointParser) = (*PathParserHelper)(nil)
 
 // BuildParser creates a new Parser instance that receives plaintext
 // Carbon data.
@@ -79,6 +83,7 @@ func NewParser(pathParser PathParser) (Parser, error) {
 	}
 	return &PathParserHelper{
 		pathParser: pathParser,
+		startTimes: newStartTimeTracker(),
 	}, nil
 }
 
@@ -118,29 +123,52 @@ func (pph *PathParserHelper) Parse(line string) (*metricspb.Metric, error) {
 		return nil, fmt.Errorf("invalid carbon metric time [%s]: %v", line, err)
 	}
 
-	var metricType metricspb.MetricDescriptor_Type
 	point := metricspb.Point{
 		Timestamp: convertUnixSec(unixTime),
 	}
 	intVal, err := strconv.ParseInt(valueStr, 10, 64)
 	if err == nil {
-		if parsedPath.MetricType == CumulativeMetricType {
-			metricType = metricspb.MetricDescriptor_CUMULATIVE_INT64
-		} else {
-			metricType = metricspb.MetricDescriptor_GAUGE_INT64
-		}
 		point.Value = &metricspb.Point_Int64Value{Int64Value: intVal}
 	} else {
 		dblVal, err := strconv.ParseFloat(valueStr, 64)
 		if err != nil {
 			return nil, fmt.Errorf("invalid carbon metric value [%s]: %v", line, err)
 		}
+		point.Value = &metricspb.Point_DoubleValue{DoubleValue: dblVal}
+	}
+
+	return pph.buildMetricForParsedPath(&parsedPath, &point), nil
+}
+
+// ParsePoint transforms the <metric_path> of a Carbon metric whose value and
+// timestamp were already decoded into the point, see Parse for a description
+// of the <metric_path>.
+func (pph *PathParserHelper) ParsePoint(path string, point *metricspb.Point) (*metricspb.Metric, error) {
+	parsedPath := ParsedPath{}
+	err := pph.pathParser.ParsePath(path, &parsedPath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid carbon metric path [%s]: %v", path, err)
+	}
+
+	return pph.buildMetricForParsedPath(&parsedPath, point), nil
+}
+
+// buildMetricForParsedPath builds the metric of a point, the type of metric
+// being selected according to the type of the value of the point.
+func (pph *PathParserHelper) buildMetricForParsedPath(parsedPath *ParsedPath, point *metricspb.Point) *metricspb.Metric {
+	var metricType metricspb.MetricDescriptor_Type
+	if _, ok := point.Value.(*metricspb.Point_Int64Value); ok {
+		if parsedPath.MetricType == CumulativeMetricType {
+			metricType = metricspb.MetricDescriptor_CUMULATIVE_INT64
+		} else {
+			metricType = metricspb.MetricDescriptor_GAUGE_INT64
+		}
+	} else {
 		if parsedPath.MetricType == CumulativeMetricType {
 			metricType = metricspb.MetricDescriptor_CUMULATIVE_DOUBLE
 		} else {
 			metricType = metricspb.MetricDescriptor_GAUGE_DOUBLE
 		}
-		point.Value = &metricspb.Point_DoubleValue{DoubleValue: dblVal}
 	}
 
 	metric := buildMetricForSinglePoint(
@@ -148,6 +176,13 @@ func (pph *PathParserHelper) Parse(line string) (*metricspb.Metric, error) {
 		metricType,
 		parsedPath.LabelKeys,
 		parsedPath.LabelValues,
-		&point)
-	return metric, nil
+		point)
+	if parsedPath.TrackStartTime && parsedPath.MetricType == CumulativeMetricType {
+		metric.Timeseries[0].StartTimestamp = pph.startTimes.startTimestamp(
+			parsedPath.MetricName,
+			parsedPath.LabelKeys,
+			parsedPath.LabelValues,
+			point)
+	}
+	return metric
 }
@@ -0,0 +1,663 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/golang/protobuf/ptypes/timestamp"
+)
+
+// PickleDatapoint is a datapoint of a message of the Carbon pickle protocol,
+// see https://graphite.readthedocs.io/en/latest/feeding-carbon.html#the-pickle-protocol.
+type PickleDatapoint struct {
+	// Path is the <metric_path> of the datapoint, to be parsed by a
+	// PointParser.
+	Path string
+	// Point has the value and timestamp of the datapoint.
+	Point *metricspb.Point
+}
+
+// DecodePickle decodes a message of the Carbon pickle protocol, a pickled
+// list of tuples in the following format:
+//
+// 	[(<metric_path>, (<metric_timestamp>, <metric_value>)), ...]
+//
+// Only the opcodes building the basic Python types (None, bool, int, float,
+// str, bytes, list and tuple) are supported, any other opcode, e.g. importing
+// or calling an object, fails the decoding. This prevents arbitrary code from
+// being sent, unlike with the pickle module of Python, and the protocols 0 to 5
+// are supported.
+//
+// The integer values are decoded as int64 points and the float values as double
+// points. Values and timestamps sent as strings are parsed as in the plaintext
+// protocol.
+func DecodePickle(data []byte) ([]PickleDatapoint, error) {
+	u := unpickler{data: data, memo: map[int]interface{}{}}
+	obj, err := u.load()
+	if err != nil {
+		return nil, err
+	}
+
+	items, ok := pickleSequence(obj)
+	if !ok {
+		return nil, fmt.Errorf("pickle message is a %s, expected a list", pickleTypeName(obj))
+	}
+
+	datapoints := make([]PickleDatapoint, 0, len(items))
+	for i, item := range items {
+		dp, err := toPickleDatapoint(item)
+		if err != nil {
+			return nil, fmt.Errorf("invalid datapoint %d of pickle message: %v", i, err)
+		}
+		datapoints = append(datapoints, dp)
+	}
+	return datapoints, nil
+}
+
+func toPickleDatapoint(item interface{}) (PickleDatapoint, error) {
+	fields, ok := pickleSequence(item)
+	if !ok || len(fields) != 2 {
+		return PickleDatapoint{}, errors.New("expected a (path, (timestamp, value)) tuple")
+	}
+
+	path, ok := fields[0].(string)
+	if !ok {
+		return PickleDatapoint{}, fmt.Errorf("path is a %s, expected a str", pickleTypeName(fields[0]))
+	}
+
+	pair, ok := pickleSequence(fields[1])
+	if !ok || len(pair) != 2 {
+		return PickleDatapoint{}, fmt.Errorf("expected a (timestamp, value) tuple for path %q", path)
+	}
+
+	ts, err := toPickleTimestamp(pair[0])
+	if err != nil {
+		return PickleDatapoint{}, fmt.Errorf("invalid timestamp for path %q: %v", path, err)
+	}
+
+	point := &metricspb.Point{Timestamp: ts}
+	switch v := pair[1].(type) {
+	case int64:
+		point.Value = &metricspb.Point_Int64Value{Int64Value: v}
+	case float64:
+		point.Value = &metricspb.Point_DoubleValue{DoubleValue: v}
+	case string:
+		if intVal, err := strconv.ParseInt(v, 10, 64); err == nil {
+			point.Value = &metricspb.Point_Int64Value{Int64Value: intVal}
+		} else if dblVal, err := strconv.ParseFloat(v, 64); err == nil {
+			point.Value = &metricspb.Point_DoubleValue{DoubleValue: dblVal}
+		} else {
+			return PickleDatapoint{}, fmt.Errorf("invalid value %q for path %q", v, path)
+		}
+	default:
+		return PickleDatapoint{}, fmt.Errorf("value for path %q is a %s, expected a number", path, pickleTypeName(v))
+	}
+
+	return PickleDatapoint{Path: path, Point: point}, nil
+}
+
+// Converts a timestamp in Unix seconds, possibly with a fraction.
+func toPickleTimestamp(v interface{}) (*timestamp.Timestamp, error) {
+	var sec float64
+	switch t := v.(type) {
+	case int64:
+		return convertUnixSec(t), nil
+	case float64:
+		sec = t
+	case string:
+		if intVal, err := strconv.ParseInt(t, 10, 64); err == nil {
+			return convertUnixSec(intVal), nil
+		}
+		f, err := strconv.ParseFloat(t, 64)
+		if err != nil {
+			return nil, err
+		}
+		sec = f
+	default:
+		return nil, fmt.Errorf("timestamp is a %s, expected a number", pickleTypeName(v))
+	}
+
+	if math.IsNaN(sec) || math.IsInf(sec, 0) {
+		return nil, fmt.Errorf("timestamp %v is not finite", sec)
+	}
+	whole, frac := math.Modf(sec)
+	return &timestamp.Timestamp{
+		Seconds: int64(whole),
+		Nanos:   int32(frac * 1e9),
+	}, nil
+}
+
+// Pickle opcodes supported by the unpickler, see
+// https://github.com/python/cpython/blob/master/Lib/pickletools.py for their
+// documentation.
+const (
+	opMark           = '('
+	opStop           = '.'
+	opPop            = '0'
+	opPopMark        = '1'
+	opDup            = '2'
+	opFloat          = 'F'
+	opInt            = 'I'
+	opBinInt         = 'J'
+	opBinInt1        = 'K'
+	opLong           = 'L'
+	opBinInt2        = 'M'
+	opNone           = 'N'
+	opString         = 'S'
+	opBinString      = 'T'
+	opShortBinString = 'U'
+	opUnicode        = 'V'
+	opBinUnicode     = 'X'
+	opAppend         = 'a'
+	opAppends        = 'e'
+	opGet            = 'g'
+	opBinGet         = 'h'
+	opLongBinGet     = 'j'
+	opList           = 'l'
+	opEmptyList      = ']'
+	opPut            = 'p'
+	opBinPut         = 'q'
+	opLongBinPut     = 'r'
+	opTuple          = 't'
+	opEmptyTuple     = ')'
+	opBinFloat       = 'G'
+	// Protocol 2.
+	opProto    = '\x80'
+	opTuple1   = '\x85'
+	opTuple2   = '\x86'
+	opTuple3   = '\x87'
+	opNewTrue  = '\x88'
+	opNewFalse = '\x89'
+	opLong1    = '\x8a'
+	opLong4    = '\x8b'
+	// Protocol 3.
+	opBinBytes      = 'B'
+	opShortBinBytes = 'C'
+	// Protocol 4.
+	opShortBinUnicode = '\x8c'
+	opBinUnicode8     = '\x8d'
+	opBinBytes8       = '\x8e'
+	opMemoize         = '\x94'
+	opFrame           = '\x95'
+)
+
+// A Python list, mutable so that appends are seen through the memo.
+type pickleList struct {
+	items []interface{}
+}
+
+// Unpickles the basic Python types: None as nil, bool, int as int64, float as
+// float64, str and bytes as string, list as *pickleList and tuple as
+// []interface{}.
+type unpickler struct {
+	data  []byte
+	pos   int
+	stack []interface{}
+	// Positions of the marks on the stack.
+	marks []int
+	memo  map[int]interface{}
+}
+
+func (u *unpickler) load() (interface{}, error) {
+	for {
+		offset := u.pos
+		op, err := u.readByte()
+		if err != nil {
+			return nil, err
+		}
+
+		if op == opStop {
+			if len(u.stack) == 0 {
+				return nil, errors.New("pickle stack is empty on STOP")
+			}
+			return u.stack[len(u.stack)-1], nil
+		}
+
+		if err := u.execute(op); err != nil {
+			return nil, fmt.Errorf("pickle opcode 0x%02x at offset %d: %v", op, offset, err)
+		}
+	}
+}
+
+func (u *unpickler) execute(op byte) error {
+	switch op {
+	case opProto:
+		proto, err := u.readByte()
+		if err != nil {
+			return err
+		}
+		if proto > 5 {
+			return fmt.Errorf("unsupported pickle protocol %d", proto)
+		}
+	case opFrame:
+		// Frames only help buffering, the whole message is in memory.
+		_, err := u.read(8)
+		return err
+
+	case opMark:
+		u.marks = append(u.marks, len(u.stack))
+	case opPop:
+		if _, err := u.pop(); err != nil {
+			return err
+		}
+	case opPopMark:
+		_, err := u.popMark()
+		return err
+	case opDup:
+		top, err := u.top()
+		if err != nil {
+			return err
+		}
+		u.push(top)
+
+	case opNone:
+		u.push(nil)
+	case opNewTrue:
+		u.push(true)
+	case opNewFalse:
+		u.push(false)
+
+	case opInt:
+		line, err := u.readLine()
+		if err != nil {
+			return err
+		}
+		// Protocol 0 encodes booleans as INT.
+		switch line {
+		case "00":
+			u.push(false)
+			return nil
+		case "01":
+			u.push(true)
+			return nil
+		}
+		v, err := strconv.ParseInt(line, 10, 64)
+		if err != nil {
+			return err
+		}
+		u.push(v)
+	case opLong:
+		line, err := u.readLine()
+		if err != nil {
+			return err
+		}
+		v, err := strconv.ParseInt(strings.TrimSuffix(line, "L"), 10, 64)
+		if err != nil {
+			return err
+		}
+		u.push(v)
+	case opBinInt:
+		b, err := u.read(4)
+		if err != nil {
+			return err
+		}
+		u.push(int64(int32(binary.LittleEndian.Uint32(b))))
+	case opBinInt1:
+		b, err := u.readByte()
+		if err != nil {
+			return err
+		}
+		u.push(int64(b))
+	case opBinInt2:
+		b, err := u.read(2)
+		if err != nil {
+			return err
+		}
+		u.push(int64(binary.LittleEndian.Uint16(b)))
+	case opLong1, opLong4:
+		n, err := u.readSize(op == opLong1)
+		if err != nil {
+			return err
+		}
+		b, err := u.read(n)
+		if err != nil {
+			return err
+		}
+		v, err := decodeLong(b)
+		if err != nil {
+			return err
+		}
+		u.push(v)
+
+	case opFloat:
+		line, err := u.readLine()
+		if err != nil {
+			return err
+		}
+		v, err := strconv.ParseFloat(line, 64)
+		if err != nil {
+			return err
+		}
+		u.push(v)
+	case opBinFloat:
+		b, err := u.read(8)
+		if err != nil {
+			return err
+		}
+		u.push(math.Float64frombits(binary.BigEndian.Uint64(b)))
+
+	case opString:
+		line, err := u.readLine()
+		if err != nil {
+			return err
+		}
+		s, err := unquotePythonString(line)
+		if err != nil {
+			return err
+		}
+		u.push(s)
+	case opUnicode:
+		// Non-ASCII characters are escaped, metric paths are expected to be
+		// ASCII, so the escapes are kept as is.
+		line, err := u.readLine()
+		if err != nil {
+			return err
+		}
+		u.push(line)
+	case opShortBinString, opShortBinBytes, opShortBinUnicode:
+		return u.pushString(1)
+	case opBinString, opBinBytes, opBinUnicode:
+		return u.pushString(4)
+	case opBinUnicode8, opBinBytes8:
+		return u.pushString(8)
+
+	case opEmptyList:
+		u.push(&pickleList{})
+	case opList:
+		items, err := u.popMark()
+		if err != nil {
+			return err
+		}
+		u.push(&pickleList{items: items})
+	case opAppend:
+		item, err := u.pop()
+		if err != nil {
+			return err
+		}
+		return u.appendItems([]interface{}{item})
+	case opAppends:
+		items, err := u.popMark()
+		if err != nil {
+			return err
+		}
+		return u.appendItems(items)
+
+	case opEmptyTuple:
+		u.push([]interface{}{})
+	case opTuple:
+		items, err := u.popMark()
+		if err != nil {
+			return err
+		}
+		u.push(items)
+	case opTuple1, opTuple2, opTuple3:
+		n := int(op-opTuple1) + 1
+		if len(u.stack)-n < u.lastMark() {
+			return errors.New("pickle stack underflow")
+		}
+		items := make([]interface{}, n)
+		copy(items, u.stack[len(u.stack)-n:])
+		u.stack = u.stack[:len(u.stack)-n]
+		u.push(items)
+
+	case opPut, opBinPut, opLongBinPut, opMemoize:
+		var idx int
+		var err error
+		switch op {
+		case opPut:
+			idx, err = u.readLineIndex()
+		case opBinPut:
+			idx, err = u.readSize(true)
+		case opLongBinPut:
+			idx, err = u.readSize(false)
+		default:
+			idx = len(u.memo)
+		}
+		if err != nil {
+			return err
+		}
+		top, err := u.top()
+		if err != nil {
+			return err
+		}
+		u.memo[idx] = top
+	case opGet, opBinGet, opLongBinGet:
+		var idx int
+		var err error
+		switch op {
+		case opGet:
+			idx, err = u.readLineIndex()
+		case opBinGet:
+			idx, err = u.readSize(true)
+		default:
+			idx, err = u.readSize(false)
+		}
+		if err != nil {
+			return err
+		}
+		v, ok := u.memo[idx]
+		if !ok {
+			return fmt.Errorf("memo key %d not found", idx)
+		}
+		u.push(v)
+
+	default:
+		return errors.New("unsupported opcode")
+	}
+	return nil
+}
+
+func (u *unpickler) push(v interface{}) {
+	u.stack = append(u.stack, v)
+}
+
+func (u *unpickler) lastMark() int {
+	if len(u.marks) == 0 {
+		return 0
+	}
+	return u.marks[len(u.marks)-1]
+}
+
+func (u *unpickler) top() (interface{}, error) {
+	if len(u.stack) <= u.lastMark() {
+		return nil, errors.New("pickle stack underflow")
+	}
+	return u.stack[len(u.stack)-1], nil
+}
+
+func (u *unpickler) pop() (interface{}, error) {
+	v, err := u.top()
+	if err != nil {
+		return nil, err
+	}
+	u.stack = u.stack[:len(u.stack)-1]
+	return v, nil
+}
+
+// Pops the items pushed since the last mark, and the mark.
+func (u *unpickler) popMark() ([]interface{}, error) {
+	if len(u.marks) == 0 {
+		return nil, errors.New("pickle mark not found")
+	}
+	mark := u.lastMark()
+	u.marks = u.marks[:len(u.marks)-1]
+	items := make([]interface{}, len(u.stack)-mark)
+	copy(items, u.stack[mark:])
+	u.stack = u.stack[:mark]
+	return items, nil
+}
+
+func (u *unpickler) appendItems(items []interface{}) error {
+	top, err := u.top()
+	if err != nil {
+		return err
+	}
+	list, ok := top.(*pickleList)
+	if !ok {
+		return fmt.Errorf("cannot append to a %s", pickleTypeName(top))
+	}
+	list.items = append(list.items, items...)
+	return nil
+}
+
+func (u *unpickler) pushString(sizeLen int) error {
+	var n int
+	var err error
+	switch sizeLen {
+	case 1:
+		n, err = u.readSize(true)
+	case 4:
+		n, err = u.readSize(false)
+	default:
+		var b []byte
+		b, err = u.read(8)
+		if err == nil {
+			size := binary.LittleEndian.Uint64(b)
+			if size > uint64(len(u.data)) {
+				return errors.New("pickle string exceeds message")
+			}
+			n = int(size)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	b, err := u.read(n)
+	if err != nil {
+		return err
+	}
+	u.push(string(b))
+	return nil
+}
+
+func (u *unpickler) readByte() (byte, error) {
+	b, err := u.read(1)
+	if err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+// Reads n bytes of the message, never more than its remaining bytes.
+func (u *unpickler) read(n int) ([]byte, error) {
+	if n < 0 || n > len(u.data)-u.pos {
+		return nil, errors.New("unexpected end of pickle message")
+	}
+	b := u.data[u.pos : u.pos+n]
+	u.pos += n
+	return b, nil
+}
+
+// Reads an unsigned size of 1 or 4 little-endian bytes.
+func (u *unpickler) readSize(short bool) (int, error) {
+	if short {
+		b, err := u.readByte()
+		return int(b), err
+	}
+	b, err := u.read(4)
+	if err != nil {
+		return 0, err
+	}
+	return int(binary.LittleEndian.Uint32(b)), nil
+}
+
+// Reads the argument of a protocol 0 opcode, terminated by a newline.
+func (u *unpickler) readLine() (string, error) {
+	idx := bytes.IndexByte(u.data[u.pos:], '\n')
+	if idx < 0 {
+		return "", errors.New("unexpected end of pickle message")
+	}
+	line := string(u.data[u.pos : u.pos+idx])
+	u.pos += idx + 1
+	return line, nil
+}
+
+func (u *unpickler) readLineIndex() (int, error) {
+	line, err := u.readLine()
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(line)
+}
+
+// Decodes a little-endian two's complement integer, as of LONG1 and LONG4.
+func decodeLong(b []byte) (int64, error) {
+	if len(b) > 8 {
+		return 0, fmt.Errorf("integer of %d bytes exceeds 64 bits", len(b))
+	}
+	if len(b) == 0 {
+		return 0, nil
+	}
+	var v uint64
+	for i := len(b) - 1; i >= 0; i-- {
+		v = v<<8 | uint64(b[i])
+	}
+	// Sign-extend negative integers shorter than 8 bytes.
+	if len(b) < 8 && b[len(b)-1]&0x80 != 0 {
+		v |= ^uint64(0) << uint(8*len(b))
+	}
+	return int64(v), nil
+}
+
+// Unquotes the repr of a Python 2 str, as of the STRING opcode.
+func unquotePythonString(s string) (string, error) {
+	if len(s) < 2 || s[0] != s[len(s)-1] || (s[0] != '\'' && s[0] != '"') {
+		return "", fmt.Errorf("invalid quoted string %s", s)
+	}
+	inner := s[1 : len(s)-1]
+	if s[0] == '\'' {
+		inner = strings.NewReplacer(`\'`, `'`, `"`, `\"`).Replace(inner)
+	}
+	return strconv.Unquote(`"` + inner + `"`)
+}
+
+// Returns the items of a Python list or tuple.
+func pickleSequence(v interface{}) ([]interface{}, bool) {
+	switch s := v.(type) {
+	case *pickleList:
+		return s.items, true
+	case []interface{}:
+		return s, true
+	}
+	return nil, false
+}
+
+func pickleTypeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "None"
+	case bool:
+		return "bool"
+	case int64:
+		return "int"
+	case float64:
+		return "float"
+	case string:
+		return "str"
+	case *pickleList:
+		return "list"
+	case []interface{}:
+		return "tuple"
+	}
+	return fmt.Sprintf("%T", v)
+}
@@ -0,0 +1,221 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodePickle(t *testing.T) {
+	// The messages below were generated with the pickle module of Python, e.g.
+	// pickle.dumps([("test.metric;k=v", (1582230020, 1.5)), ("int.metric", (1582230020.5, 42))], protocol=2)
+	want := []PickleDatapoint{
+		{
+			Path: "test.metric;k=v",
+			Point: &metricspb.Point{
+				Timestamp: &timestamp.Timestamp{Seconds: 1582230020},
+				Value:     &metricspb.Point_DoubleValue{DoubleValue: 1.5},
+			},
+		},
+		{
+			Path: "int.metric",
+			Point: &metricspb.Point{
+				Timestamp: &timestamp.Timestamp{Seconds: 1582230020, Nanos: 5e8},
+				Value:     &metricspb.Point_Int64Value{Int64Value: 42},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		message string
+		want    []PickleDatapoint
+		wantErr string
+	}{
+		{
+			name:    "protocol_0",
+			message: "(lp0\n(Vtest.metric;k=v\np1\n(I1582230020\nF1.5\ntp2\ntp3\na(Vint.metric\np4\n(F1582230020.5\nI42\ntp5\ntp6\na.",
+			want:    want,
+		},
+		{
+			name:    "protocol_2",
+			message: "\x80\x02]q\x00(X\x0f\x00\x00\x00test.metric;k=vq\x01J\x04\xeaN^G?\xf8\x00\x00\x00\x00\x00\x00\x86q\x02\x86q\x03X\n\x00\x00\x00int.metricq\x04GA\xd7\x93\xba\x81 \x00\x00K*\x86q\x05\x86q\x06e.",
+			want:    want,
+		},
+		{
+			name:    "protocol_4",
+			message: "\x80\x04\x95E\x00\x00\x00\x00\x00\x00\x00]\x94(\x8c\x0ftest.metric;k=v\x94J\x04\xeaN^G?\xf8\x00\x00\x00\x00\x00\x00\x86\x94\x86\x94\x8c\nint.metric\x94GA\xd7\x93\xba\x81 \x00\x00K*\x86\x94\x86\x94e.",
+			want:    want,
+		},
+		{
+			name:    "python2_protocol_0",
+			message: "(lp0\n(S'py2.metric'\np1\n(L1582230020L\nI7\ntp2\ntp3\na.",
+			want: []PickleDatapoint{
+				{
+					Path: "py2.metric",
+					Point: &metricspb.Point{
+						Timestamp: &timestamp.Timestamp{Seconds: 1582230020},
+						Value:     &metricspb.Point_Int64Value{Int64Value: 7},
+					},
+				},
+			},
+		},
+		{
+			name:    "long_integers",
+			message: "\x80\x02]q\x00X\x04\x00\x00\x00longq\x01\x8a\x06\x00\x00\x00\x00\x00\x01\x8a\x06\x00\x00\x00\x00\x00\xff\x86q\x02\x86q\x03a.",
+			want: []PickleDatapoint{
+				{
+					Path: "long",
+					Point: &metricspb.Point{
+						Timestamp: &timestamp.Timestamp{Seconds: 1 << 40},
+						Value:     &metricspb.Point_Int64Value{Int64Value: -1 << 40},
+					},
+				},
+			},
+		},
+		{
+			name:    "negative_integer",
+			message: "\x80\x02]q\x00X\x03\x00\x00\x00negq\x01K\x01J\xd4\xfe\xff\xff\x86q\x02\x86q\x03a.",
+			want: []PickleDatapoint{
+				{
+					Path: "neg",
+					Point: &metricspb.Point{
+						Timestamp: &timestamp.Timestamp{Seconds: 1},
+						Value:     &metricspb.Point_Int64Value{Int64Value: -300},
+					},
+				},
+			},
+		},
+		{
+			name:    "strings",
+			message: "\x80\x02]q\x00X\x01\x00\x00\x00sq\x01X\n\x00\x00\x001582230020q\x02X\x03\x00\x00\x003.5q\x03\x86q\x04\x86q\x05a.",
+			want: []PickleDatapoint{
+				{
+					Path: "s",
+					Point: &metricspb.Point{
+						Timestamp: &timestamp.Timestamp{Seconds: 1582230020},
+						Value:     &metricspb.Point_DoubleValue{DoubleValue: 3.5},
+					},
+				},
+			},
+		},
+		{
+			name:    "empty_list",
+			message: "\x80\x02].",
+			want:    []PickleDatapoint{},
+		},
+		{
+			name:    "global_and_reduce",
+			message: "\x80\x02]q\x00cposix\nsystem\nq\x01X\n\x00\x00\x00echo pwnedq\x02\x85q\x03Rq\x04a.",
+			wantErr: "pickle opcode 0x63 at offset 5: unsupported opcode",
+		},
+		{
+			name:    "dict",
+			message: "\x80\x02}q\x00X\x01\x00\x00\x00aq\x01K\x01s.",
+			wantErr: "pickle opcode 0x7d at offset 2: unsupported opcode",
+		},
+		{
+			name:    "integer_too_large",
+			message: "\x80\x02]q\x00X\x03\x00\x00\x00bigq\x01K\x01\x8a\x09\x00\x00\x00\x00\x00\x00\x00\x00@\x86q\x02\x86q\x03a.",
+			wantErr: "pickle opcode 0x8a at offset 17: integer of 9 bytes exceeds 64 bits",
+		},
+		{
+			name:    "none_value",
+			message: "\x80\x02]q\x00X\x01\x00\x00\x00bq\x01K\x01N\x86q\x02\x86q\x03a.",
+			wantErr: `invalid datapoint 0 of pickle message: value for path "b" is a None, expected a number`,
+		},
+		{
+			name:    "not_a_list",
+			message: "\x80\x02K\x01.",
+			wantErr: "pickle message is a int, expected a list",
+		},
+		{
+			name:    "not_a_tuple",
+			message: "\x80\x02]K\x01a.",
+			wantErr: "invalid datapoint 0 of pickle message: expected a (path, (timestamp, value)) tuple",
+		},
+		{
+			name:    "truncated",
+			message: "\x80\x02]q\x00X\x0f\x00\x00\x00test",
+			wantErr: "pickle opcode 0x58 at offset 5: unexpected end of pickle message",
+		},
+		{
+			name:    "no_stop",
+			message: "\x80\x02]",
+			wantErr: "unexpected end of pickle message",
+		},
+		{
+			name:    "stack_underflow",
+			message: "\x80\x02a.",
+			wantErr: "pickle opcode 0x61 at offset 2: pickle stack underflow",
+		},
+		{
+			name:    "memo_key_not_found",
+			message: "\x80\x02h\x01.",
+			wantErr: "pickle opcode 0x68 at offset 2: memo key 1 not found",
+		},
+		{
+			name:    "empty",
+			message: "",
+			wantErr: "unexpected end of pickle message",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodePickle([]byte(tt.message))
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPathParserHelper_ParsePoint(t *testing.T) {
+	p, err := (&RegexParserConfig{
+		Rules: []*RegexRule{
+			{
+				Regexp:     `(?P<key_svc>[^.]+)\.(?P<name_0>requests)`,
+				MetricType: "cumulative",
+			},
+		},
+	}).BuildParser()
+	require.NoError(t, err)
+	pointParser := p.(PointParser)
+
+	point := &metricspb.Point{
+		Timestamp: &timestamp.Timestamp{Seconds: 1582230020},
+		Value:     &metricspb.Point_DoubleValue{DoubleValue: 2.5},
+	}
+	got, err := pointParser.ParsePoint("svc.requests", point)
+	require.NoError(t, err)
+	assert.Equal(t, buildMetricForSinglePoint(
+		"requests",
+		metricspb.MetricDescriptor_CUMULATIVE_DOUBLE,
+		[]*metricspb.LabelKey{{Key: "svc"}},
+		[]*metricspb.LabelValue{{Value: "svc", HasValue: true}},
+		point,
+	), got)
+
+	_, err = pointParser.ParsePoint(";k=v", point)
+	assert.Error(t, err)
+}
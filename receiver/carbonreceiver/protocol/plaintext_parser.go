@@ -65,13 +65,26 @@ func (p *PlaintextPathParser) ParsePath(path string, parsedPath *ParsedPath) err
 		return nil
 	}
 
-	tags := strings.Split(parts[1], ";")
-	keys := make([]*metricspb.LabelKey, 0, len(tags))
-	values := make([]*metricspb.LabelValue, 0, len(tags))
-	for _, tag := range tags {
+	keys, values, err := parseTags(path, parts[1])
+	if err != nil {
+		return err
+	}
+
+	parsedPath.LabelKeys = keys
+	parsedPath.LabelValues = values
+	return nil
+}
+
+// parseTags parses the tags of a metric path, i.e. the ";" separated list of
+// tags following the metric name, see PlaintextPathParser.ParsePath.
+func parseTags(path string, tags string) ([]*metricspb.LabelKey, []*metricspb.LabelValue, error) {
+	tagList := strings.Split(tags, ";")
+	keys := make([]*metricspb.LabelKey, 0, len(tagList))
+	values := make([]*metricspb.LabelValue, 0, len(tagList))
+	for _, tag := range tagList {
 		idx := strings.IndexByte(tag, '=')
 		if idx < 1 {
-			return fmt.Errorf("cannot parse metric path [%s]: incorrect key value separator for [%s]", path, tag)
+			return nil, nil, fmt.Errorf("cannot parse metric path [%s]: incorrect key value separator for [%s]", path, tag)
 		}
 
 		key := tag[:idx]
@@ -84,9 +97,7 @@ func (p *PlaintextPathParser) ParsePath(path string, parsedPath *ParsedPath) err
 		})
 	}
 
-	parsedPath.LabelKeys = keys
-	parsedPath.LabelValues = values
-	return nil
+	return keys, values, nil
 }
 
 func plaintextDefaultConfig() ParserConfig {
@@ -90,9 +90,18 @@ type RegexRule struct {
 	// "gauge" (the default) and "cumulative".
 	MetricType string `mapstructure:"type"`
 
+	// TrackStartTime sets the start timestamp of the cumulative metrics
+	// generated by the rule, by tracking each time series across the received
+	// lines. The start timestamp is the timestamp of the first point of the
+	// time series, or of the last point whose value decreased, i.e. after a
+	// reset. It requires the "cumulative" type, and is disabled by default
+	// since it keeps the state of each time series.
+	TrackStartTime bool `mapstructure:"track_start_time"`
+
 	// Some fields cached after the compilation of the regular expression.
 	compRegexp      *regexp.Regexp
 	metricNameParts []string
+	labelKeys       []string
 }
 
 var _ (ParserConfig) = (*RegexParserConfig)(nil)
@@ -136,6 +145,12 @@ func compileRegexRules(rules []*RegexRule) error {
 				GaugeMetricType,
 				CumulativeMetricType)
 		}
+		if r.TrackStartTime && TargetMetricType(r.MetricType) != CumulativeMetricType {
+			return fmt.Errorf(
+				`error on %d-th rule: track_start_time requires the %q metric type`,
+				i,
+				CumulativeMetricType)
+		}
 
 		rules[i].compRegexp = regex
 		var metricNameParts []string
@@ -154,6 +169,15 @@ func compileRegexRules(rules []*RegexRule) error {
 		}
 		sort.Strings(metricNameParts)
 		rules[i].metricNameParts = metricNameParts
+
+		// The labels are added in the same order to all metrics matching the
+		// rule, so their time series can be identified by their labels.
+		labelKeys := make([]string, 0, len(r.Labels))
+		for k := range r.Labels {
+			labelKeys = append(labelKeys, k)
+		}
+		sort.Strings(labelKeys)
+		rules[i].labelKeys = labelKeys
 	}
 
 	return nil
@@ -172,6 +196,16 @@ type regexPathParser struct {
 // a full description of the line format) according to the RegexParserConfig
 // settings.
 func (rpp *regexPathParser) ParsePath(path string, parsedPath *ParsedPath) error {
+	if rpp.matchRules(path, parsedPath) {
+		return nil
+	}
+
+	return rpp.plaintextPathParser.ParsePath(path, parsedPath)
+}
+
+// matchRules applies the first rule matching the path, if any, returning
+// false if no rule matches.
+func (rpp *regexPathParser) matchRules(path string, parsedPath *ParsedPath) bool {
 	for _, rule := range rpp.rules {
 		if rule.compRegexp.MatchString(path) {
 			ms := rule.compRegexp.FindStringSubmatch(path)
@@ -192,10 +226,10 @@ func (rpp *regexPathParser) ParsePath(path string, parsedPath *ParsedPath) error
 				}
 			}
 
-			for k, v := range rule.Labels {
+			for _, k := range rule.labelKeys {
 				keys = append(keys, &metricspb.LabelKey{Key: k})
 				values = append(values, &metricspb.LabelValue{
-					Value:    v,
+					Value:    rule.Labels[k],
 					HasValue: true,
 				})
 			}
@@ -221,11 +255,12 @@ func (rpp *regexPathParser) ParsePath(path string, parsedPath *ParsedPath) error
 			parsedPath.LabelKeys = keys
 			parsedPath.LabelValues = values
 			parsedPath.MetricType = TargetMetricType(rule.MetricType)
-			return nil
+			parsedPath.TrackStartTime = rule.TrackStartTime
+			return true
 		}
 	}
 
-	return rpp.plaintextPathParser.ParsePath(path, parsedPath)
+	return false
 }
 
 func regexDefaultConfig() ParserConfig {
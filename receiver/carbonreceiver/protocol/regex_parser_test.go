@@ -18,6 +18,7 @@ import (
 	"testing"
 
 	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -69,6 +70,18 @@ func TestRegexParserConfigBuildParser(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "track_start_time_gauge",
+			config: &RegexParserConfig{
+				Rules: []*RegexRule{
+					{
+						Regexp:         "(?P<key_good>test).env(?P<key_env>[^.]*).(?P<key_host>[^.]*)",
+						TrackStartTime: true,
+					},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "valid_rules",
 			config: &RegexParserConfig{
@@ -100,7 +113,7 @@ func Test_regexParser_parsePath(t *testing.T) {
 			{
 				Regexp:     `(?P<key_svc>[^.]+)\.(?P<key_host>[^.]+)\.cpu\.seconds`,
 				NamePrefix: "cpu_seconds",
-				Labels:     map[string]string{"k": "v"},
+				Labels:     map[string]string{"k": "v", "a": "b"},
 			},
 			{
 				Regexp:     `(?P<key_svc>[^.]+)\.(?P<key_host>[^.]+)\.rpc\.count`,
@@ -140,11 +153,13 @@ func Test_regexParser_parsePath(t *testing.T) {
 			wantKeys: []*metricspb.LabelKey{
 				{Key: "svc"},
 				{Key: "host"},
+				{Key: "a"},
 				{Key: "k"},
 			},
 			wantValues: []*metricspb.LabelValue{
 				{Value: "service_name", HasValue: true},
 				{Value: "host00", HasValue: true},
+				{Value: "b", HasValue: true},
 				{Value: "v", HasValue: true},
 			},
 		},
@@ -195,6 +210,42 @@ func Test_regexParser_parsePath(t *testing.T) {
 	}
 }
 
+func Test_regexParser_trackStartTime(t *testing.T) {
+	config := RegexParserConfig{
+		Rules: []*RegexRule{
+			{
+				Regexp:         `(?P<key_svc>[^.]+)\.rpc\.count`,
+				NamePrefix:     "rpc",
+				MetricType:     CumulativeMetricType,
+				TrackStartTime: true,
+			},
+			{
+				Regexp:     `(?P<key_svc>[^.]+)\.rpc\.errors`,
+				NamePrefix: "rpc_errors",
+				MetricType: CumulativeMetricType,
+			},
+		},
+	}
+	parser, err := config.BuildParser()
+	require.NoError(t, err)
+
+	tests := []struct {
+		line      string
+		wantStart *timestamp.Timestamp
+	}{
+		{line: "svc0.rpc.count 10 1582230020", wantStart: &timestamp.Timestamp{Seconds: 1582230020}},
+		{line: "svc0.rpc.count 20 1582230030", wantStart: &timestamp.Timestamp{Seconds: 1582230020}},
+		{line: "svc1.rpc.count 5 1582230030", wantStart: &timestamp.Timestamp{Seconds: 1582230030}},
+		{line: "svc0.rpc.count 1 1582230040", wantStart: &timestamp.Timestamp{Seconds: 1582230040}},
+		{line: "svc0.rpc.errors 1 1582230040"},
+	}
+	for _, tt := range tests {
+		got, err := parser.Parse(tt.line)
+		require.NoError(t, err)
+		assert.Equal(t, tt.wantStart, got.Timeseries[0].StartTimestamp, tt.line)
+	}
+}
+
 var res struct {
 	name       string
 	keys       []*metricspb.LabelKey
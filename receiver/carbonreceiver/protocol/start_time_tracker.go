@@ -0,0 +1,150 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/golang/protobuf/ptypes/timestamp"
+)
+
+const (
+	// staleSeriesTimeout is the duration after which a time series not
+	// received is forgotten by the startTimeTracker, its start timestamp being
+	// reset if received again.
+	staleSeriesTimeout = 15 * time.Minute
+)
+
+// startTimeTracker computes the start timestamps of cumulative time series
+// across the lines received, possibly in different batches. The start
+// timestamp of a time series is the timestamp of its first point, or of the
+// last point whose value is lower than the previous one, i.e. after a reset
+// of the cumulative value.
+type startTimeTracker struct {
+	// now returns the current time, used to forget the stale time series.
+	now func() time.Time
+
+	mu        sync.Mutex
+	series    map[string]*trackedSeries
+	lastSweep time.Time
+}
+
+type trackedSeries struct {
+	startNanos int64
+	lastNanos  int64
+	lastValue  float64
+	lastSeen   time.Time
+}
+
+func newStartTimeTracker() *startTimeTracker {
+	return &startTimeTracker{
+		now:    time.Now,
+		series: make(map[string]*trackedSeries),
+	}
+}
+
+// startTimestamp returns the start timestamp of the time series of the given
+// metric name and labels, updated with the point.
+func (stt *startTimeTracker) startTimestamp(
+	metricName string,
+	labelKeys []*metricspb.LabelKey,
+	labelValues []*metricspb.LabelValue,
+	point *metricspb.Point,
+) *timestamp.Timestamp {
+	if point.Timestamp == nil {
+		return nil
+	}
+	nanos := point.Timestamp.Seconds*1e9 + int64(point.Timestamp.Nanos)
+	value := pointValue(point)
+	key := seriesKey(metricName, labelKeys, labelValues)
+	now := stt.now()
+
+	stt.mu.Lock()
+	defer stt.mu.Unlock()
+
+	stt.sweep(now)
+	s, ok := stt.series[key]
+	switch {
+	case !ok:
+		s = &trackedSeries{
+			startNanos: nanos,
+			lastNanos:  nanos,
+			lastValue:  value,
+		}
+		stt.series[key] = s
+	case nanos < s.startNanos:
+		// The lines aren't necessarily received, or parsed, in order.
+		s.startNanos = nanos
+	case nanos >= s.lastNanos:
+		if value < s.lastValue {
+			s.startNanos = nanos
+		}
+		s.lastNanos = nanos
+		s.lastValue = value
+	}
+	s.lastSeen = now
+
+	return &timestamp.Timestamp{
+		Seconds: s.startNanos / 1e9,
+		Nanos:   int32(s.startNanos % 1e9),
+	}
+}
+
+// sweep forgets the stale time series, at most once per staleSeriesTimeout,
+// stt.mu must be held.
+func (stt *startTimeTracker) sweep(now time.Time) {
+	if now.Sub(stt.lastSweep) < staleSeriesTimeout {
+		return
+	}
+	stt.lastSweep = now
+	for key, s := range stt.series {
+		if now.Sub(s.lastSeen) >= staleSeriesTimeout {
+			delete(stt.series, key)
+		}
+	}
+}
+
+func pointValue(point *metricspb.Point) float64 {
+	switch v := point.Value.(type) {
+	case *metricspb.Point_Int64Value:
+		return float64(v.Int64Value)
+	case *metricspb.Point_DoubleValue:
+		return v.DoubleValue
+	}
+	return 0
+}
+
+// seriesKey identifies a time series by its metric name and labels, the label
+// keys and values being separated by characters not expected in them.
+func seriesKey(
+	metricName string,
+	labelKeys []*metricspb.LabelKey,
+	labelValues []*metricspb.LabelValue,
+) string {
+	var sb strings.Builder
+	sb.WriteString(metricName)
+	for i, k := range labelKeys {
+		sb.WriteByte(0)
+		sb.WriteString(k.Key)
+		sb.WriteByte(1)
+		if i < len(labelValues) && labelValues[i].HasValue {
+			sb.WriteString(labelValues[i].Value)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,80 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"testing"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_startTimeTracker_startTimestamp(t *testing.T) {
+	keys := []*metricspb.LabelKey{{Key: "host"}}
+	h0 := []*metricspb.LabelValue{{Value: "h0", HasValue: true}}
+	h1 := []*metricspb.LabelValue{{Value: "h1", HasValue: true}}
+
+	stt := newStartTimeTracker()
+	now := time.Unix(0, 0)
+	stt.now = func() time.Time { return now }
+
+	steps := []struct {
+		name   string
+		values []*metricspb.LabelValue
+		point  *metricspb.Point
+		want   int64
+	}{
+		{name: "first_point", values: h0, point: intPoint(10, 1), want: 10},
+		{name: "increase", values: h0, point: intPoint(20, 5), want: 10},
+		{name: "other_series", values: h1, point: intPoint(15, 1), want: 15},
+		{name: "same_value", values: h0, point: intPoint(30, 5), want: 10},
+		{name: "out_of_order", values: h0, point: intPoint(25, 3), want: 10},
+		{name: "earlier_than_start", values: h0, point: intPoint(5, 0), want: 5},
+		{name: "reset", values: h0, point: intPoint(40, 2), want: 40},
+		{name: "after_reset", values: h0, point: doublePoint(50, 2.5), want: 40},
+		{name: "other_series_unchanged", values: h1, point: intPoint(45, 4), want: 15},
+	}
+	for _, step := range steps {
+		got := stt.startTimestamp("m", keys, step.values, step.point)
+		assert.Equal(t, &timestamp.Timestamp{Seconds: step.want}, got, step.name)
+	}
+
+	// Stale series are forgotten.
+	now = now.Add(staleSeriesTimeout - time.Second)
+	got := stt.startTimestamp("m", keys, h0, intPoint(60, 10))
+	assert.Equal(t, &timestamp.Timestamp{Seconds: 40}, got)
+	now = now.Add(staleSeriesTimeout)
+	got = stt.startTimestamp("m", keys, h1, intPoint(70, 10))
+	assert.Equal(t, &timestamp.Timestamp{Seconds: 70}, got)
+	assert.Equal(t, 1, len(stt.series))
+
+	assert.Nil(t, stt.startTimestamp("m", keys, h0, &metricspb.Point{}))
+}
+
+func intPoint(sec int64, value int64) *metricspb.Point {
+	return &metricspb.Point{
+		Timestamp: &timestamp.Timestamp{Seconds: sec},
+		Value:     &metricspb.Point_Int64Value{Int64Value: value},
+	}
+}
+
+func doublePoint(sec int64, value float64) *metricspb.Point {
+	return &metricspb.Point{
+		Timestamp: &timestamp.Timestamp{Seconds: sec},
+		Value:     &metricspb.Point_DoubleValue{DoubleValue: value},
+	}
+}
@@ -0,0 +1,443 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+)
+
+const (
+	templateMeasurement = "measurement"
+	templateField       = "field"
+	templateWildcard    = "*"
+)
+
+// TemplateParserConfig has the configuration for a parser that breaks down the
+// Carbon "metric path" according to templates, modeled on the Graphite
+// templates of InfluxDB, see
+// https://docs.influxdata.com/influxdb/v1.8/supported_protocols/graphite/#templates.
+// Templates are easier to write than regular expressions for naming
+// hierarchies, the parts of the metric path separated by "." being mapped by
+// position to the parts of the template.
+//
+// Each template has the following format, where the filter and the labels are
+// optional:
+//
+// 	[filter] <template> [label0=value0,...,labelN=valueN]
+//
+// The filter is a "." separated list of glob patterns, see
+// https://golang.org/pkg/path/#Match, that must match the first parts of the
+// metric path for the template to be applied. The template is a "." separated
+// list of:
+//
+//   * "measurement": the part of the metric path is added to the metric name;
+//   * "field": the part of the metric path is added to the metric name, after
+//     the "measurement" parts;
+//   * "measurement*" or "field*", only as the last part of the template: the
+//     remaining parts of the metric path are added to the metric name;
+//   * an empty string: the part of the metric path is skipped;
+//   * any other string: the label key of the part of the metric path.
+//
+// The labels are added to all the metrics of the template, unless set by the
+// template itself. The tags of the metric path, see
+// https://graphite.readthedocs.io/en/latest/tags.html#carbon, are also added
+// as labels, the template being applied to the metric name. A tag overrides
+// the label of the same key, unless set by the template itself, and the last
+// of the tags with the same key is kept.
+//
+// Examples:
+//
+// 1. Template: "servers.* .host.measurement* dc=us-east"
+//    Metric path: "servers.host01.cpu.idle"
+//    Resulting metric:
+//        name: cpu.idle
+//        label keys: {"host", "dc"}
+//        label values: {"host01", "us-east"}
+//
+// 2. Template: "env.host.measurement.field*"
+//    Metric path: "prod.host02.disk.used.percent;mount=/data"
+//    Resulting metric:
+//        name: disk.used.percent
+//        label keys: {"env", "host", "mount"}
+//        label values: {"prod", "host02", "/data"}
+//
+type TemplateParserConfig struct {
+	// Templates are applied to the metric paths not matching any of the
+	// rules. The first template whose filter matches the metric path is
+	// applied, a template without a filter matching any metric path, so it
+	// must be the last one. If no template matches the metric is then
+	// processed by the "plaintext" parser.
+	Templates []string `mapstructure:"templates"`
+
+	// Rules are regular expression rules, see RegexParserConfig, taking
+	// precedence over the templates.
+	Rules []*RegexRule `mapstructure:"rules"`
+
+	// MetricNameSeparator is used when joining the parts of the metric name,
+	// by both the templates and the rules. The default is ".".
+	MetricNameSeparator string `mapstructure:"name_separator"`
+}
+
+var _ (ParserConfig) = (*TemplateParserConfig)(nil)
+var _ (validator) = (*TemplateParserConfig)(nil)
+
+// BuildParser builds the respective parser of the configuration instance.
+func (tpc *TemplateParserConfig) BuildParser() (Parser, error) {
+	tpp, err := tpc.compile()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewParser(tpp)
+}
+
+// validate validates the configuration, when it's loaded.
+func (tpc *TemplateParserConfig) validate() error {
+	_, err := tpc.compile()
+	return err
+}
+
+func (tpc *TemplateParserConfig) compile() (*templatePathParser, error) {
+	if tpc == nil {
+		return nil, errors.New("nil receiver on TemplateParserConfig.BuildParser")
+	}
+
+	if len(tpc.Templates) == 0 {
+		return nil, errors.New(`no template was specified`)
+	}
+
+	// The rules are copied so that validating the config doesn't change it.
+	var rules []*RegexRule
+	if len(tpc.Rules) > 0 {
+		rules = make([]*RegexRule, 0, len(tpc.Rules))
+		for _, r := range tpc.Rules {
+			rule := *r
+			rules = append(rules, &rule)
+		}
+		if err := compileRegexRules(rules); err != nil {
+			return nil, err
+		}
+	}
+
+	templates := make([]*pathTemplate, 0, len(tpc.Templates))
+	for i, t := range tpc.Templates {
+		if i > 0 && templates[i-1].filter == nil {
+			return nil, fmt.Errorf(
+				"%d-th template %q is never applied, the previous template has no filter", i, t)
+		}
+		pt, err := compileTemplate(t)
+		if err != nil {
+			return nil, fmt.Errorf("error compiling %d-th template: %v", i, err)
+		}
+		templates = append(templates, pt)
+	}
+
+	return &templatePathParser{
+		regexPathParser: regexPathParser{
+			rules:               rules,
+			metricNameSeparator: tpc.MetricNameSeparator,
+		},
+		templates: templates,
+	}, nil
+}
+
+// templatePartKind is how a part of the metric path is handled by a template.
+type templatePartKind int
+
+const (
+	skipPart templatePartKind = iota
+	measurementPart
+	fieldPart
+	labelPart
+)
+
+type templatePart struct {
+	kind     templatePartKind
+	labelKey string
+}
+
+// pathTemplate is a compiled template of a TemplateParserConfig.
+type pathTemplate struct {
+	filter []string
+	parts  []templatePart
+	// wildcard is the kind of the remaining parts of the metric path, if the
+	// template ends by "measurement*" or "field*".
+	wildcard  templatePartKind
+	numLabels int
+
+	defaultKeys   []string
+	defaultValues []string
+}
+
+func compileTemplate(template string) (*pathTemplate, error) {
+	fields := strings.Fields(template)
+	var filter, tmpl, labels string
+	switch len(fields) {
+	case 1:
+		tmpl = fields[0]
+	case 2:
+		if strings.Contains(fields[1], "=") {
+			tmpl, labels = fields[0], fields[1]
+		} else {
+			filter, tmpl = fields[0], fields[1]
+		}
+	case 3:
+		filter, tmpl, labels = fields[0], fields[1], fields[2]
+	default:
+		return nil, fmt.Errorf(
+			"template %q should have the format \"[filter] <template> [labels]\"", template)
+	}
+
+	pt := &pathTemplate{
+		wildcard: skipPart,
+	}
+
+	if filter != "" {
+		pt.filter = strings.Split(filter, ".")
+		for _, f := range pt.filter {
+			if _, err := path.Match(f, ""); err != nil {
+				return nil, fmt.Errorf("invalid filter %q: %v", filter, err)
+			}
+		}
+	}
+
+	labelKeys := map[string]bool{}
+	var hasName bool
+	parts := strings.Split(tmpl, ".")
+	for i, p := range parts {
+		switch p {
+		case "":
+			pt.parts = append(pt.parts, templatePart{kind: skipPart})
+		case templateMeasurement, templateField:
+			kind := measurementPart
+			if p == templateField {
+				kind = fieldPart
+			}
+			pt.parts = append(pt.parts, templatePart{kind: kind})
+			hasName = true
+		case templateMeasurement + templateWildcard, templateField + templateWildcard:
+			if i != len(parts)-1 {
+				return nil, fmt.Errorf(
+					"wildcard %q must be the last part of template %q", p, tmpl)
+			}
+			pt.wildcard = measurementPart
+			if p == templateField+templateWildcard {
+				pt.wildcard = fieldPart
+			}
+			hasName = true
+		default:
+			if strings.Contains(p, templateWildcard) {
+				return nil, fmt.Errorf("invalid label key %q on template %q", p, tmpl)
+			}
+			if labelKeys[p] {
+				return nil, fmt.Errorf("duplicate label key %q on template %q", p, tmpl)
+			}
+			labelKeys[p] = true
+			pt.parts = append(pt.parts, templatePart{kind: labelPart, labelKey: p})
+			pt.numLabels++
+		}
+	}
+	if !hasName {
+		return nil, fmt.Errorf(
+			"template %q has no %q or %q part", tmpl, templateMeasurement, templateField)
+	}
+
+	if labels == "" {
+		return pt, nil
+	}
+
+	defaults := map[string]string{}
+	for _, label := range strings.Split(labels, ",") {
+		idx := strings.IndexByte(label, '=')
+		if idx < 1 {
+			return nil, fmt.Errorf("invalid label %q, expected the format \"key=value\"", label)
+		}
+		key := label[:idx]
+		if _, ok := defaults[key]; ok {
+			return nil, fmt.Errorf("duplicate label key %q on labels %q", key, labels)
+		}
+		defaults[key] = label[idx+1:]
+	}
+	for k := range defaults {
+		// The labels set by the template take precedence.
+		if !labelKeys[k] {
+			pt.defaultKeys = append(pt.defaultKeys, k)
+		}
+	}
+	sort.Strings(pt.defaultKeys)
+	for _, k := range pt.defaultKeys {
+		pt.defaultValues = append(pt.defaultValues, defaults[k])
+	}
+
+	return pt, nil
+}
+
+// matches returns true if the filter of the template matches the parts of
+// the metric name.
+func (pt *pathTemplate) matches(nameParts []string) bool {
+	if len(nameParts) < len(pt.filter) {
+		return false
+	}
+	for i, f := range pt.filter {
+		if ok, _ := path.Match(f, nameParts[i]); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// apply applies the template to the parts of the metric name. The label keys
+// are the same for all the metric names, the labels of the parts missing
+// from a metric name having no value.
+func (pt *pathTemplate) apply(
+	nameParts []string,
+	separator string,
+) (string, []*metricspb.LabelKey, []*metricspb.LabelValue) {
+	var measurement, field []string
+	numLabels := pt.numLabels + len(pt.defaultKeys)
+	keys := make([]*metricspb.LabelKey, 0, numLabels)
+	values := make([]*metricspb.LabelValue, 0, numLabels)
+	for i, p := range pt.parts {
+		var value string
+		if i < len(nameParts) {
+			value = nameParts[i]
+		}
+		switch p.kind {
+		case measurementPart:
+			if value != "" {
+				measurement = append(measurement, value)
+			}
+		case fieldPart:
+			if value != "" {
+				field = append(field, value)
+			}
+		case labelPart:
+			keys = append(keys, &metricspb.LabelKey{Key: p.labelKey})
+			values = append(values, &metricspb.LabelValue{
+				Value:    value,
+				HasValue: i < len(nameParts),
+			})
+		}
+	}
+
+	if len(nameParts) > len(pt.parts) {
+		switch pt.wildcard {
+		case measurementPart:
+			measurement = append(measurement, nameParts[len(pt.parts):]...)
+		case fieldPart:
+			field = append(field, nameParts[len(pt.parts):]...)
+		}
+	}
+
+	for i, k := range pt.defaultKeys {
+		keys = append(keys, &metricspb.LabelKey{Key: k})
+		values = append(values, &metricspb.LabelValue{
+			Value:    pt.defaultValues[i],
+			HasValue: true,
+		})
+	}
+
+	return strings.Join(append(measurement, field...), separator), keys, values
+}
+
+type templatePathParser struct {
+	// regexPathParser applies the rules, taking precedence over the
+	// templates, and the "plaintext" parser if no template matches either.
+	regexPathParser
+
+	templates []*pathTemplate
+}
+
+// ParsePath converts the <metric_path> of a Carbon line (see PathParserHelper
+// a full description of the line format) according to the TemplateParserConfig
+// settings.
+func (tpp *templatePathParser) ParsePath(path string, parsedPath *ParsedPath) error {
+	if tpp.matchRules(path, parsedPath) {
+		return nil
+	}
+
+	name := path
+	var tags string
+	if idx := strings.IndexByte(path, ';'); idx >= 0 {
+		name, tags = path[:idx], path[idx+1:]
+	}
+	nameParts := strings.Split(name, ".")
+
+	for _, pt := range tpp.templates {
+		if !pt.matches(nameParts) {
+			continue
+		}
+
+		metricName, keys, values := pt.apply(nameParts, tpp.metricNameSeparator)
+		if metricName == "" {
+			metricName = name
+		}
+		if tags != "" {
+			tagKeys, tagValues, err := parseTags(path, tags)
+			if err != nil {
+				return err
+			}
+			keys, values = mergeTags(keys, values, pt.numLabels, tagKeys, tagValues)
+		}
+
+		parsedPath.MetricName = metricName
+		parsedPath.LabelKeys = keys
+		parsedPath.LabelValues = values
+		return nil
+	}
+
+	return tpp.plaintextPathParser.ParsePath(path, parsedPath)
+}
+
+// mergeTags merges the labels from the tags of the metric path with the labels
+// of a template, the first numTemplateLabels being set by the template itself.
+// Tags override the other labels of the template and the previous tags of the
+// same key, so that the label keys are unique.
+func mergeTags(
+	keys []*metricspb.LabelKey,
+	values []*metricspb.LabelValue,
+	numTemplateLabels int,
+	tagKeys []*metricspb.LabelKey,
+	tagValues []*metricspb.LabelValue,
+) ([]*metricspb.LabelKey, []*metricspb.LabelValue) {
+	indexes := make(map[string]int, len(keys)+len(tagKeys))
+	for i, k := range keys {
+		indexes[k.Key] = i
+	}
+	for i, k := range tagKeys {
+		idx, ok := indexes[k.Key]
+		switch {
+		case !ok:
+			indexes[k.Key] = len(keys)
+			keys = append(keys, k)
+			values = append(values, tagValues[i])
+		case idx >= numTemplateLabels:
+			values[idx] = tagValues[i]
+		}
+	}
+	return keys, values
+}
+
+func templateDefaultConfig() ParserConfig {
+	return &TemplateParserConfig{
+		MetricNameSeparator: ".",
+	}
+}
@@ -0,0 +1,271 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateParserConfigBuildParser(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  ParserConfig
+		wantErr string
+	}{
+		{
+			name:    "nil_method_receiver",
+			config:  (*TemplateParserConfig)(nil),
+			wantErr: "nil receiver on TemplateParserConfig.BuildParser",
+		},
+		{
+			name:    "no_templates",
+			config:  &TemplateParserConfig{},
+			wantErr: "no template was specified",
+		},
+		{
+			name: "invalid_rule",
+			config: &TemplateParserConfig{
+				Templates: []string{"measurement*"},
+				Rules:     []*RegexRule{{Regexp: "(?P<key_bad>test"}},
+			},
+			wantErr: "error compiling 0-th rule: error parsing regexp: missing closing ): `(?P<key_bad>test`",
+		},
+		{
+			name: "too_many_fields",
+			config: &TemplateParserConfig{
+				Templates: []string{"servers.* host.measurement* dc=us k=v"},
+			},
+			wantErr: `error compiling 0-th template: template "servers.* host.measurement* dc=us k=v" should have the format "[filter] <template> [labels]"`,
+		},
+		{
+			name: "invalid_filter",
+			config: &TemplateParserConfig{
+				Templates: []string{"servers.[ host.measurement*"},
+			},
+			wantErr: `error compiling 0-th template: invalid filter "servers.[": syntax error in pattern`,
+		},
+		{
+			name: "wildcard_not_last",
+			config: &TemplateParserConfig{
+				Templates: []string{"measurement*.host"},
+			},
+			wantErr: `error compiling 0-th template: wildcard "measurement*" must be the last part of template "measurement*.host"`,
+		},
+		{
+			name: "invalid_label_key",
+			config: &TemplateParserConfig{
+				Templates: []string{"host*.measurement"},
+			},
+			wantErr: `error compiling 0-th template: invalid label key "host*" on template "host*.measurement"`,
+		},
+		{
+			name: "duplicate_label_key",
+			config: &TemplateParserConfig{
+				Templates: []string{"host.host.measurement"},
+			},
+			wantErr: `error compiling 0-th template: duplicate label key "host" on template "host.host.measurement"`,
+		},
+		{
+			name: "no_name",
+			config: &TemplateParserConfig{
+				Templates: []string{"env.host"},
+			},
+			wantErr: `error compiling 0-th template: template "env.host" has no "measurement" or "field" part`,
+		},
+		{
+			name: "invalid_labels",
+			config: &TemplateParserConfig{
+				Templates: []string{"host.measurement dc=us,=v"},
+			},
+			wantErr: `error compiling 0-th template: invalid label "=v", expected the format "key=value"`,
+		},
+		{
+			name: "duplicate_labels",
+			config: &TemplateParserConfig{
+				Templates: []string{"host.measurement dc=us,dc=eu"},
+			},
+			wantErr: `error compiling 0-th template: duplicate label key "dc" on labels "dc=us,dc=eu"`,
+		},
+		{
+			name: "template_after_no_filter",
+			config: &TemplateParserConfig{
+				Templates: []string{"host.measurement*", "servers.* .host.measurement*"},
+			},
+			wantErr: `1-th template "servers.* .host.measurement*" is never applied, the previous template has no filter`,
+		},
+		{
+			name: "valid_templates",
+			config: &TemplateParserConfig{
+				Templates: []string{
+					"servers.* .host.measurement* dc=us-east",
+					"env.host.measurement.field*",
+				},
+				Rules: []*RegexRule{{Regexp: `(?P<key_svc>[^.]+)\.rpc\.count`}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.config.BuildParser()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+
+			assert.NoError(t, err)
+			require.NotNil(t, got)
+		})
+	}
+}
+
+func Test_templatePathParser_ParsePath(t *testing.T) {
+	config := TemplateParserConfig{
+		Templates: []string{
+			"servers.* .host.measurement* dc=us-east,host=default",
+			"stats.*.*.count .measurement.svc.field type=counter",
+			"env.host.measurement.field*",
+		},
+		Rules: []*RegexRule{
+			{
+				Regexp:     `^servers\.(?P<key_host>[^.]+)\.rpc\.(?P<name_0>[^.]+)$`,
+				NamePrefix: "rpc",
+			},
+		},
+		MetricNameSeparator: ".",
+	}
+	tpp, err := config.compile()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		path       string
+		wantName   string
+		wantKeys   []*metricspb.LabelKey
+		wantValues []*metricspb.LabelValue
+		wantErr    string
+	}{
+		{
+			name:     "rule_precedence",
+			path:     "servers.host00.rpc.count",
+			wantName: "rpc.count",
+			wantKeys: []*metricspb.LabelKey{{Key: "host"}},
+			wantValues: []*metricspb.LabelValue{
+				{Value: "host00", HasValue: true},
+			},
+		},
+		{
+			name:     "filter_wildcard_defaults",
+			path:     "servers.host01.cpu.idle",
+			wantName: "cpu.idle",
+			wantKeys: []*metricspb.LabelKey{{Key: "host"}, {Key: "dc"}},
+			wantValues: []*metricspb.LabelValue{
+				{Value: "host01", HasValue: true},
+				{Value: "us-east", HasValue: true},
+			},
+		},
+		{
+			name:     "measurement_and_field",
+			path:     "stats.api.login.count",
+			wantName: "api.count",
+			wantKeys: []*metricspb.LabelKey{{Key: "svc"}, {Key: "type"}},
+			wantValues: []*metricspb.LabelValue{
+				{Value: "login", HasValue: true},
+				{Value: "counter", HasValue: true},
+			},
+		},
+		{
+			name:     "no_filter_with_tags",
+			path:     "prod.host02.disk.used.percent;mount=/data",
+			wantName: "disk.used.percent",
+			wantKeys: []*metricspb.LabelKey{{Key: "env"}, {Key: "host"}, {Key: "mount"}},
+			wantValues: []*metricspb.LabelValue{
+				{Value: "prod", HasValue: true},
+				{Value: "host02", HasValue: true},
+				{Value: "/data", HasValue: true},
+			},
+		},
+		{
+			name:     "tags_override_defaults",
+			path:     "servers.host01.cpu.idle;dc=eu-west;host=other;rack=r1;rack=r2",
+			wantName: "cpu.idle",
+			wantKeys: []*metricspb.LabelKey{{Key: "host"}, {Key: "dc"}, {Key: "rack"}},
+			wantValues: []*metricspb.LabelValue{
+				{Value: "host01", HasValue: true},
+				{Value: "eu-west", HasValue: true},
+				{Value: "r2", HasValue: true},
+			},
+		},
+		{
+			name:     "template_labels_override_tags",
+			path:     "prod.h1.cpu;host=x",
+			wantName: "cpu",
+			wantKeys: []*metricspb.LabelKey{{Key: "env"}, {Key: "host"}},
+			wantValues: []*metricspb.LabelValue{
+				{Value: "prod", HasValue: true},
+				{Value: "h1", HasValue: true},
+			},
+		},
+		{
+			name:     "missing_parts",
+			path:     "prod",
+			wantName: "prod",
+			wantKeys: []*metricspb.LabelKey{{Key: "env"}, {Key: "host"}},
+			wantValues: []*metricspb.LabelValue{
+				{Value: "prod", HasValue: true},
+				{},
+			},
+		},
+		{
+			name:    "invalid_tags",
+			path:    "prod.host02.disk;mount",
+			wantErr: "cannot parse metric path [prod.host02.disk;mount]: incorrect key value separator for [mount]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParsedPath{}
+			err := tpp.ParsePath(tt.path, &got)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantName, got.MetricName)
+			assert.Equal(t, tt.wantKeys, got.LabelKeys)
+			assert.Equal(t, tt.wantValues, got.LabelValues)
+		})
+	}
+}
+
+func Test_templatePathParser_plaintextFallback(t *testing.T) {
+	config := TemplateParserConfig{
+		Templates: []string{"servers.* .host.measurement*"},
+	}
+	tpp, err := config.compile()
+	require.NoError(t, err)
+
+	got := ParsedPath{}
+	require.NoError(t, tpp.ParsePath("cpu.idle;host=h0", &got))
+	assert.Equal(t, "cpu.idle", got.MetricName)
+	assert.Equal(t, []*metricspb.LabelKey{{Key: "host"}}, got.LabelKeys)
+	assert.Equal(t, []*metricspb.LabelValue{{Value: "h0", HasValue: true}}, got.LabelValues)
+}
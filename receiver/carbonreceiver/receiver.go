@@ -36,7 +36,8 @@ var (
 )
 
 // carbonreceiver implements a component.MetricsReceiver for Carbon plaintext, aka "line", protocol.
-// see https://graphite.readthedocs.io/en/latest/feeding-carbon.html#the-plaintext-protocol.
+// see https://graphite.readthedocs.io/en/latest/feeding-carbon.html#the-plaintext-protocol,
+// and for the pickle protocol, see https://graphite.readthedocs.io/en/latest/feeding-carbon.html#the-pickle-protocol.
 type carbonReceiver struct {
 	sync.Mutex
 	logger *zap.Logger
@@ -81,6 +82,15 @@ func New(
 		return nil, err
 	}
 
+	if strings.ToLower(config.Transport) == "pickle" {
+		if _, ok := parser.(protocol.PointParser); !ok {
+			return nil, fmt.Errorf(
+				"parser %q does not support the pickle transport for receiver %q",
+				config.Parser.Type,
+				config.Name())
+		}
+	}
+
 	// This should be the last one built, or if any other error is raised after
 	// it, the server should be closed.
 	server, err := buildTransportServer(config, logger)
@@ -103,9 +113,11 @@ func New(
 func buildTransportServer(config Config, logger *zap.Logger) (transport.Server, error) {
 	switch strings.ToLower(config.Transport) {
 	case "", "tcp":
-		return transport.NewTCPServer(config.Endpoint, config.TCPIdleTimeout)
+		return transport.NewTCPServer(config.Endpoint, config.TCPIdleTimeout, config.ProcessingSettings)
 	case "udp":
-		return transport.NewUDPServer(config.Endpoint)
+		return transport.NewUDPServer(config.Endpoint, config.ProcessingSettings)
+	case "pickle":
+		return transport.NewPickleServer(config.Endpoint, config.TCPIdleTimeout, config.ProcessingSettings)
 	}
 
 	return nil, fmt.Errorf("unsupported transport %q for receiver %q", config.Transport, config.Name())
@@ -23,6 +23,7 @@ import (
 	"testing"
 	"time"
 
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
 	"github.com/open-telemetry/opentelemetry-collector/component/componenterror"
 	"github.com/open-telemetry/opentelemetry-collector/component/componenttest"
 	"github.com/open-telemetry/opentelemetry-collector/config/configmodels"
@@ -125,6 +126,62 @@ func Test_carbonreceiver_New(t *testing.T) {
 				nextConsumer: new(exportertest.SinkMetricsExporterOld),
 			},
 		},
+		{
+			name: "pickle_transport",
+			args: args{
+				config: Config{
+					ReceiverSettings: configmodels.ReceiverSettings{
+						NameVal:  "pickle_transport_rcv",
+						Endpoint: "localhost:2004",
+					},
+					Transport: "pickle",
+					Parser: &protocol.Config{
+						Type:   "plaintext",
+						Config: &protocol.PlaintextConfig{},
+					},
+				},
+				nextConsumer: new(exportertest.SinkMetricsExporterOld),
+			},
+		},
+		{
+			name: "pickle_transport_line_parser",
+			args: args{
+				config: Config{
+					ReceiverSettings: configmodels.ReceiverSettings{
+						NameVal:  "pickle_transport_rcv",
+						Endpoint: "localhost:2004",
+					},
+					Transport: "pickle",
+					Parser: &protocol.Config{
+						Type:   "line",
+						Config: lineParserConfig{},
+					},
+				},
+				nextConsumer: new(exportertest.SinkMetricsExporterOld),
+			},
+			wantErr: errors.New("parser \"line\" does not support the pickle transport for receiver \"pickle_transport_rcv\""),
+		},
+		{
+			name: "invalid_queue_full_policy",
+			args: args{
+				config: Config{
+					ReceiverSettings: configmodels.ReceiverSettings{
+						NameVal:  "invalid_queue_full_policy",
+						Endpoint: "localhost:2003",
+					},
+					Transport: "udp",
+					ProcessingSettings: transport.ProcessingSettings{
+						QueueFullPolicy: "retry",
+					},
+					Parser: &protocol.Config{
+						Type:   "plaintext",
+						Config: &protocol.PlaintextConfig{},
+					},
+				},
+				nextConsumer: new(exportertest.SinkMetricsExporterOld),
+			},
+			wantErr: errors.New("unknown queue full policy \"retry\", valid policies: \"block\" or \"drop\""),
+		},
 		{
 			name: "negative_tcp_idle_timeout",
 			args: args{
@@ -159,6 +216,17 @@ func Test_carbonreceiver_New(t *testing.T) {
 	}
 }
 
+// lineParserConfig builds a parser only parsing lines, not points.
+type lineParserConfig struct{}
+
+func (lineParserConfig) BuildParser() (protocol.Parser, error) {
+	return lineParserConfig{}, nil
+}
+
+func (lineParserConfig) Parse(line string) (*metricspb.Metric, error) {
+	return nil, nil
+}
+
 func Test_carbonreceiver_EndToEnd(t *testing.T) {
 	addr := testutils.GetAvailableLocalAddress(t)
 	host, portStr, err := net.SplitHostPort(addr)
@@ -195,6 +263,19 @@ func Test_carbonreceiver_EndToEnd(t *testing.T) {
 				return c
 			},
 		},
+		{
+			name: "default_config_pickle",
+			configFn: func() *Config {
+				cfg := (&Factory{}).CreateDefaultConfig().(*Config)
+				cfg.Transport = "pickle"
+				return cfg
+			},
+			clientFn: func(t *testing.T) *client.Graphite {
+				c, err := client.NewGraphite(client.Pickle, host, port)
+				require.NoError(t, err)
+				return c
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -103,6 +103,52 @@ func (r *reporter) OnMetricsProcessed(
 	obsreport.EndMetricsReceiveOp(ctx, "carbon", numReceivedTimeseries, numReceivedTimeseries, err)
 }
 
+// OnTraceDataReceived is called when a batch of received spans is about to be
+// passed to the next trace consumer, as OnDataReceived for metrics.
+func (r *reporter) OnTraceDataReceived(ctx context.Context) context.Context {
+	ctx = obsreport.ReceiverContext(ctx, r.name, "tcp", r.name)
+	return obsreport.StartTraceDataReceiveOp(ctx, r.name, "tcp")
+}
+
+// OnTracesProcessed is called when the received spans are passed to the next
+// trace consumer. The context and span passed to it should be the ones
+// returned by OnTraceDataReceived.
+func (r *reporter) OnTracesProcessed(
+	ctx context.Context,
+	numReceivedSpans int,
+	err error,
+) {
+	if err != nil {
+		r.logger.Debug(
+			"Carbon receiver failed to push spans into pipeline",
+			zap.String("receiver", r.name),
+			zap.Int("numReceivedSpans", numReceivedSpans),
+			zap.Error(err))
+
+		span := trace.FromContext(ctx)
+		span.SetStatus(trace.Status{
+			Code:    trace.StatusCodeUnknown,
+			Message: err.Error(),
+		})
+	}
+
+	obsreport.EndTraceDataReceiveOp(ctx, "carbon", numReceivedSpans, err)
+}
+
+// OnDataDropped is called when received data is dropped, because the workers
+// can't keep up or the receiver is shutting down. The dropped time series are
+// counted as refused, with the reason as error.
+func (r *reporter) OnDataDropped(numDroppedTimeSeries int, reason error) {
+	r.logger.Debug(
+		"Carbon receiver dropped data",
+		zap.String("receiver", r.name),
+		zap.Int("numDroppedTimeSeries", numDroppedTimeSeries),
+		zap.Error(reason))
+
+	ctx := r.OnDataReceived(context.Background())
+	obsreport.EndMetricsReceiveOp(ctx, "carbon", numDroppedTimeSeries, numDroppedTimeSeries, reason)
+}
+
 func (r *reporter) OnDebugf(template string, args ...interface{}) {
 	if r.logger.Check(zap.DebugLevel, "debug") != nil {
 		r.sugaredLogger.Debugf(template, args...)
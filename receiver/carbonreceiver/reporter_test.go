@@ -22,6 +22,8 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector/observability/observabilitytest"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/transport"
 )
 
 func TestReporterObservability(t *testing.T) {
@@ -46,3 +48,17 @@ func TestReporterObservability(t *testing.T) {
 	err = observabilitytest.CheckValueViewReceiverDroppedTimeSeries(receiverName, 10)
 	require.NoError(t, err)
 }
+
+func TestReporterOnDataDropped(t *testing.T) {
+	doneFn := observabilitytest.SetupRecordedMetricsTest()
+	defer doneFn()
+
+	const receiverName = "fake_carbon_receiver"
+	reporter := newReporter(receiverName, zap.NewNop())
+
+	reporter.OnDataDropped(7, transport.ErrQueueFull)
+	reporter.OnDataDropped(3, transport.ErrStopping)
+
+	err := observabilitytest.CheckValueViewReceiverDroppedTimeSeries(receiverName, 10)
+	require.NoError(t, err)
+}
@@ -0,0 +1,172 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/golang/protobuf/proto"
+	"github.com/open-telemetry/opentelemetry-collector/consumer/consumerdata"
+)
+
+// aggregateMetrics merges the metrics sharing the same descriptor, i.e. name,
+// type and label keys, into a single metric, and their time series sharing the
+// same label values and start timestamp into a single time series, with the
+// points sorted by timestamp. The order of the metrics and time series is the
+// order of their first occurrence. Metrics with a resource are left as is.
+func aggregateMetrics(metrics []*metricspb.Metric) []*metricspb.Metric {
+	if len(metrics) < 2 {
+		return metrics
+	}
+
+	aggregated := make([]*metricspb.Metric, 0, len(metrics))
+	byDescriptor := make(map[string]*aggregatedMetric, len(metrics))
+	for _, metric := range metrics {
+		if metric.MetricDescriptor == nil || metric.Resource != nil {
+			aggregated = append(aggregated, metric)
+			continue
+		}
+
+		timeseries := metric.Timeseries
+		key := descriptorKey(metric.MetricDescriptor)
+		am, ok := byDescriptor[key]
+		if !ok {
+			am = &aggregatedMetric{
+				metric:     metric,
+				timeseries: make(map[string]*metricspb.TimeSeries, len(timeseries)),
+			}
+			byDescriptor[key] = am
+			aggregated = append(aggregated, metric)
+			// The time series are added back in place, never ahead of the
+			// one being read.
+			metric.Timeseries = timeseries[:0]
+		}
+		for _, ts := range timeseries {
+			am.add(ts)
+		}
+	}
+
+	for _, am := range byDescriptor {
+		if !am.merged {
+			continue
+		}
+		for _, ts := range am.metric.Timeseries {
+			sort.SliceStable(ts.Points, func(i, j int) bool {
+				return timestampLess(ts.Points[i], ts.Points[j])
+			})
+		}
+	}
+
+	return aggregated
+}
+
+// aggregatedMetric is a metric aggregating the time series of the metrics
+// sharing its descriptor.
+type aggregatedMetric struct {
+	metric     *metricspb.Metric
+	timeseries map[string]*metricspb.TimeSeries
+	// merged is set once points were added to an existing time series.
+	merged bool
+}
+
+func (am *aggregatedMetric) add(ts *metricspb.TimeSeries) {
+	key := timeSeriesKey(ts)
+	existing, ok := am.timeseries[key]
+	if !ok {
+		am.timeseries[key] = ts
+		am.metric.Timeseries = append(am.metric.Timeseries, ts)
+		return
+	}
+	existing.Points = append(existing.Points, ts.Points...)
+	am.merged = true
+}
+
+// descriptorKey and timeSeriesKey separate the fields by characters not
+// expected in them.
+func descriptorKey(md *metricspb.MetricDescriptor) string {
+	var sb strings.Builder
+	sb.WriteString(md.Name)
+	sb.WriteByte(0)
+	sb.WriteString(md.Type.String())
+	sb.WriteByte(0)
+	sb.WriteString(md.Unit)
+	sb.WriteByte(0)
+	sb.WriteString(md.Description)
+	for _, k := range md.LabelKeys {
+		sb.WriteByte(0)
+		sb.WriteString(k.Key)
+		sb.WriteByte(1)
+		sb.WriteString(k.Description)
+	}
+	return sb.String()
+}
+
+func timeSeriesKey(ts *metricspb.TimeSeries) string {
+	var sb strings.Builder
+	if ts.StartTimestamp != nil {
+		sb.WriteString(strconv.FormatInt(ts.StartTimestamp.Seconds, 10))
+		sb.WriteByte('.')
+		sb.WriteString(strconv.FormatInt(int64(ts.StartTimestamp.Nanos), 10))
+	}
+	for _, v := range ts.LabelValues {
+		sb.WriteByte(0)
+		if v.HasValue {
+			sb.WriteByte(1)
+			sb.WriteString(v.Value)
+		}
+	}
+	return sb.String()
+}
+
+func timestampLess(a, b *metricspb.Point) bool {
+	if a.Timestamp == nil || b.Timestamp == nil {
+		return a.Timestamp == nil && b.Timestamp != nil
+	}
+	if a.Timestamp.Seconds != b.Timestamp.Seconds {
+		return a.Timestamp.Seconds < b.Timestamp.Seconds
+	}
+	return a.Timestamp.Nanos < b.Timestamp.Nanos
+}
+
+// aggregateTraces merges the trace data sharing the same node, resource and
+// source format, e.g. the spans of the same service, into a single trace data.
+// The order of the trace data is the order of their first occurrence.
+func aggregateTraces(traces []consumerdata.TraceData) []consumerdata.TraceData {
+	if len(traces) < 2 {
+		return traces
+	}
+
+	aggregated := make([]consumerdata.TraceData, 0, len(traces))
+	for _, td := range traces {
+		merged := false
+		for i := range aggregated {
+			a := &aggregated[i]
+			if a.SourceFormat == td.SourceFormat &&
+				proto.Equal(a.Node, td.Node) &&
+				proto.Equal(a.Resource, td.Resource) {
+				a.Spans = append(a.Spans, td.Spans...)
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			aggregated = append(aggregated, td)
+		}
+	}
+	return aggregated
+}
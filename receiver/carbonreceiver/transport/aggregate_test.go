@@ -0,0 +1,185 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/open-telemetry/opentelemetry-collector/consumer/consumerdata"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_aggregateMetrics(t *testing.T) {
+	tests := []struct {
+		name    string
+		metrics []*metricspb.Metric
+		want    []*metricspb.Metric
+	}{
+		{
+			name: "single",
+			metrics: []*metricspb.Metric{
+				gaugeMetric("a", "host", "h0", point(1, 10)),
+			},
+			want: []*metricspb.Metric{
+				gaugeMetric("a", "host", "h0", point(1, 10)),
+			},
+		},
+		{
+			name: "same_time_series",
+			metrics: []*metricspb.Metric{
+				gaugeMetric("a", "host", "h0", point(2, 20)),
+				gaugeMetric("b", "host", "h0", point(1, 10)),
+				gaugeMetric("a", "host", "h0", point(1, 10)),
+				gaugeMetric("a", "host", "h0", point(3, 30)),
+			},
+			want: []*metricspb.Metric{
+				gaugeMetric("a", "host", "h0", point(1, 10), point(2, 20), point(3, 30)),
+				gaugeMetric("b", "host", "h0", point(1, 10)),
+			},
+		},
+		{
+			name: "different_label_values",
+			metrics: []*metricspb.Metric{
+				gaugeMetric("a", "host", "h0", point(1, 10)),
+				gaugeMetric("a", "host", "h1", point(1, 11)),
+				gaugeMetric("a", "host", "h0", point(2, 20)),
+			},
+			want: []*metricspb.Metric{
+				{
+					MetricDescriptor: &metricspb.MetricDescriptor{
+						Name:      "a",
+						Type:      metricspb.MetricDescriptor_GAUGE_INT64,
+						LabelKeys: []*metricspb.LabelKey{{Key: "host"}},
+					},
+					Timeseries: []*metricspb.TimeSeries{
+						gaugeMetric("a", "host", "h0", point(1, 10), point(2, 20)).Timeseries[0],
+						gaugeMetric("a", "host", "h1", point(1, 11)).Timeseries[0],
+					},
+				},
+			},
+		},
+		{
+			name: "different_label_keys",
+			metrics: []*metricspb.Metric{
+				gaugeMetric("a", "host", "h0", point(1, 10)),
+				gaugeMetric("a", "svc", "h0", point(2, 20)),
+			},
+			want: []*metricspb.Metric{
+				gaugeMetric("a", "host", "h0", point(1, 10)),
+				gaugeMetric("a", "svc", "h0", point(2, 20)),
+			},
+		},
+		{
+			name: "different_types",
+			metrics: []*metricspb.Metric{
+				gaugeMetric("a", "host", "h0", point(1, 10)),
+				withType(gaugeMetric("a", "host", "h0", point(2, 20)), metricspb.MetricDescriptor_CUMULATIVE_INT64),
+			},
+			want: []*metricspb.Metric{
+				gaugeMetric("a", "host", "h0", point(1, 10)),
+				withType(gaugeMetric("a", "host", "h0", point(2, 20)), metricspb.MetricDescriptor_CUMULATIVE_INT64),
+			},
+		},
+		{
+			name: "different_start_timestamps",
+			metrics: []*metricspb.Metric{
+				withStart(gaugeMetric("a", "host", "h0", point(1, 10)), 1),
+				withStart(gaugeMetric("a", "host", "h0", point(2, 20)), 1),
+				withStart(gaugeMetric("a", "host", "h0", point(3, 5)), 3),
+			},
+			want: []*metricspb.Metric{
+				{
+					MetricDescriptor: &metricspb.MetricDescriptor{
+						Name:      "a",
+						Type:      metricspb.MetricDescriptor_GAUGE_INT64,
+						LabelKeys: []*metricspb.LabelKey{{Key: "host"}},
+					},
+					Timeseries: []*metricspb.TimeSeries{
+						withStart(gaugeMetric("a", "host", "h0", point(1, 10), point(2, 20)), 1).Timeseries[0],
+						withStart(gaugeMetric("a", "host", "h0", point(3, 5)), 3).Timeseries[0],
+					},
+				},
+			},
+		},
+		{
+			name: "with_resource",
+			metrics: []*metricspb.Metric{
+				gaugeMetric("a", "host", "h0", point(1, 10)),
+				withResource(gaugeMetric("a", "host", "h0", point(2, 20))),
+			},
+			want: []*metricspb.Metric{
+				gaugeMetric("a", "host", "h0", point(1, 10)),
+				withResource(gaugeMetric("a", "host", "h0", point(2, 20))),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, aggregateMetrics(tt.metrics))
+		})
+	}
+}
+
+func gaugeMetric(name, key, value string, points ...*metricspb.Point) *metricspb.Metric {
+	return &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{
+			Name:      name,
+			Type:      metricspb.MetricDescriptor_GAUGE_INT64,
+			LabelKeys: []*metricspb.LabelKey{{Key: key}},
+		},
+		Timeseries: []*metricspb.TimeSeries{
+			{
+				LabelValues: []*metricspb.LabelValue{{Value: value, HasValue: true}},
+				Points:      points,
+			},
+		},
+	}
+}
+
+func point(sec int64, value int64) *metricspb.Point {
+	return &metricspb.Point{
+		Timestamp: &timestamp.Timestamp{Seconds: sec},
+		Value:     &metricspb.Point_Int64Value{Int64Value: value},
+	}
+}
+
+func withType(metric *metricspb.Metric, metricType metricspb.MetricDescriptor_Type) *metricspb.Metric {
+	metric.MetricDescriptor.Type = metricType
+	return metric
+}
+
+func withStart(metric *metricspb.Metric, sec int64) *metricspb.Metric {
+	metric.Timeseries[0].StartTimestamp = &timestamp.Timestamp{Seconds: sec}
+	return metric
+}
+
+func withResource(metric *metricspb.Metric) *metricspb.Metric {
+	metric.Resource = &resourcepb.Resource{Type: "test"}
+	return metric
+}
+
+func Test_aggregateTraces(t *testing.T) {
+	var traces []consumerdata.TraceData
+	traces = append(traces, testTraces("s0", "a")...)
+	traces = append(traces, testTraces("s1", "b")...)
+	traces = append(traces, testTraces("s0", "c", "d")...)
+	traces = append(traces, testTraces("s1", "e")...)
+
+	want := append(testTraces("s0", "a", "c", "d"), testTraces("s1", "b", "e")...)
+	assert.Equal(t, want, aggregateTraces(traces))
+}
@@ -0,0 +1,191 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/open-telemetry/opentelemetry-collector/component/componenterror"
+	"github.com/open-telemetry/opentelemetry-collector/consumer"
+	"github.com/open-telemetry/opentelemetry-collector/consumer/consumerdata"
+)
+
+// batcher coalesces the metrics parsed by the workers of a Server, passing
+// them to the next consumer once the batch is full, or on every batch timeout,
+// so that metrics wait at most the batch timeout. The points of the same time
+// series in a batch are aggregated in a single metric. The spans, if any, are
+// batched the same way, in a separate batch passed to the next trace consumer.
+type batcher struct {
+	maxSize           int
+	timeout           time.Duration
+	nextConsumer      consumer.MetricsConsumerOld
+	nextTraceConsumer consumer.TraceConsumerOld
+	reporter          Reporter
+
+	// mu guards the pending batches.
+	mu                    sync.Mutex
+	metrics               []*metricspb.Metric
+	numReceivedTimeSeries int
+	numInvalidTimeSeries  int
+	traces                []consumerdata.TraceData
+	numSpans              int
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newBatcher(
+	maxSize int,
+	timeout time.Duration,
+	nextConsumer consumer.MetricsConsumerOld,
+	nextTraceConsumer consumer.TraceConsumerOld,
+	reporter Reporter,
+) *batcher {
+	b := &batcher{
+		maxSize:           maxSize,
+		timeout:           timeout,
+		nextConsumer:      nextConsumer,
+		nextTraceConsumer: nextTraceConsumer,
+		reporter:          reporter,
+		done:              make(chan struct{}),
+	}
+	b.wg.Add(1)
+	go b.flushOnTimeout()
+	return b
+}
+
+// add adds the metrics parsed from the received time series to the batch,
+// passing the batch to the next consumer if it's full.
+func (b *batcher) add(metrics []*metricspb.Metric, numReceivedTimeSeries int, numInvalidTimeSeries int) {
+	b.mu.Lock()
+	b.metrics = append(b.metrics, metrics...)
+	b.numReceivedTimeSeries += numReceivedTimeSeries
+	b.numInvalidTimeSeries += numInvalidTimeSeries
+	if len(b.metrics) < b.maxSize {
+		b.mu.Unlock()
+		return
+	}
+	metrics, numReceivedTimeSeries, numInvalidTimeSeries = b.take()
+	b.mu.Unlock()
+
+	b.send(metrics, numReceivedTimeSeries, numInvalidTimeSeries)
+}
+
+// addTraces adds the trace data parsed from the received spans to the batch
+// of spans, passing the batch to the next trace consumer if it's full. The
+// spans are dropped if there is no next trace consumer.
+func (b *batcher) addTraces(traces []consumerdata.TraceData) {
+	if b.nextTraceConsumer == nil {
+		return
+	}
+
+	b.mu.Lock()
+	b.traces = append(b.traces, traces...)
+	for _, td := range traces {
+		b.numSpans += len(td.Spans)
+	}
+	if b.numSpans < b.maxSize {
+		b.mu.Unlock()
+		return
+	}
+	traces, numSpans := b.takeTraces()
+	b.mu.Unlock()
+
+	b.sendTraces(traces, numSpans)
+}
+
+func (b *batcher) flushOnTimeout() {
+	defer b.wg.Done()
+	ticker := time.NewTicker(b.timeout)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			b.flush()
+		case <-b.done:
+			return
+		}
+	}
+}
+
+// flush passes the pending batches, if any, to the next consumers.
+func (b *batcher) flush() {
+	b.mu.Lock()
+	metrics, numReceivedTimeSeries, numInvalidTimeSeries := b.take()
+	traces, numSpans := b.takeTraces()
+	b.mu.Unlock()
+
+	if numReceivedTimeSeries > 0 {
+		b.send(metrics, numReceivedTimeSeries, numInvalidTimeSeries)
+	}
+	if len(traces) > 0 {
+		b.sendTraces(traces, numSpans)
+	}
+}
+
+// take returns the pending batch and starts a new one, b.mu must be held.
+func (b *batcher) take() ([]*metricspb.Metric, int, int) {
+	metrics := b.metrics
+	numReceivedTimeSeries := b.numReceivedTimeSeries
+	numInvalidTimeSeries := b.numInvalidTimeSeries
+	b.metrics = nil
+	b.numReceivedTimeSeries = 0
+	b.numInvalidTimeSeries = 0
+	return metrics, numReceivedTimeSeries, numInvalidTimeSeries
+}
+
+// takeTraces returns the pending batch of spans and starts a new one, b.mu
+// must be held.
+func (b *batcher) takeTraces() ([]consumerdata.TraceData, int) {
+	traces := b.traces
+	numSpans := b.numSpans
+	b.traces = nil
+	b.numSpans = 0
+	return traces, numSpans
+}
+
+func (b *batcher) send(metrics []*metricspb.Metric, numReceivedTimeSeries int, numInvalidTimeSeries int) {
+	ctx := b.reporter.OnDataReceived(context.Background())
+	var err error
+	if len(metrics) > 0 {
+		md := consumerdata.MetricsData{
+			Metrics: aggregateMetrics(metrics),
+		}
+		err = b.nextConsumer.ConsumeMetricsData(ctx, md)
+	}
+	b.reporter.OnMetricsProcessed(ctx, numReceivedTimeSeries, numInvalidTimeSeries, err)
+}
+
+func (b *batcher) sendTraces(traces []consumerdata.TraceData, numSpans int) {
+	ctx := b.reporter.OnTraceDataReceived(context.Background())
+	var errs []error
+	for _, td := range aggregateTraces(traces) {
+		if err := b.nextTraceConsumer.ConsumeTraceData(ctx, td); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	b.reporter.OnTracesProcessed(ctx, numSpans, componenterror.CombineErrors(errs))
+}
+
+// stop passes the pending batch to the next consumer, and stops passing
+// batches on timeout.
+func (b *batcher) stop() {
+	close(b.done)
+	b.wg.Wait()
+	b.flush()
+}
@@ -0,0 +1,152 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"testing"
+	"time"
+
+	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/open-telemetry/opentelemetry-collector/consumer/consumerdata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatcher_Size(t *testing.T) {
+	mc := &mockMetricsConsumer{}
+	mr := NewMockReporter(1)
+	b := newBatcher(3, time.Hour, mc, nil, mr)
+
+	b.add(testMetrics("a", "b"), 3, 1)
+	assert.Equal(t, 0, len(mc.md))
+	b.add(testMetrics("c"), 1, 0)
+	mr.WaitAllOnMetricsProcessedCalls()
+
+	require.Equal(t, 1, len(mc.md))
+	assert.Equal(t, testMetrics("a", "b", "c"), mc.md[0].Metrics)
+
+	// Nothing left to pass on stop.
+	b.stop()
+	assert.Equal(t, 1, len(mc.md))
+}
+
+func TestBatcher_Timeout(t *testing.T) {
+	mc := &mockMetricsConsumer{}
+	mr := NewMockReporter(1)
+	b := newBatcher(100, 10*time.Millisecond, mc, nil, mr)
+	defer b.stop()
+
+	b.add(testMetrics("a"), 1, 0)
+	mr.WaitAllOnMetricsProcessedCalls()
+
+	mc.Lock()
+	defer mc.Unlock()
+	require.Equal(t, 1, len(mc.md))
+	assert.Equal(t, testMetrics("a"), mc.md[0].Metrics)
+}
+
+func TestBatcher_Stop(t *testing.T) {
+	mc := &mockMetricsConsumer{}
+	mr := NewMockReporter(1)
+	b := newBatcher(100, time.Hour, mc, nil, mr)
+
+	b.add(testMetrics("a"), 1, 0)
+	b.stop()
+	mr.WaitAllOnMetricsProcessedCalls()
+
+	require.Equal(t, 1, len(mc.md))
+	assert.Equal(t, testMetrics("a"), mc.md[0].Metrics)
+}
+
+func TestBatcher_OnlyInvalid(t *testing.T) {
+	mc := &mockMetricsConsumer{}
+	mr := NewMockReporter(1)
+	b := newBatcher(100, time.Hour, mc, nil, mr)
+
+	// Only invalid time series, reported without calling the consumer.
+	b.add(nil, 2, 2)
+	b.stop()
+	mr.WaitAllOnMetricsProcessedCalls()
+
+	assert.Equal(t, 0, len(mc.md))
+}
+
+func TestBatcher_TracesSize(t *testing.T) {
+	mc := &mockMetricsConsumer{}
+	tc := &mockTraceConsumer{}
+	b := newBatcher(3, time.Hour, mc, tc, NewMockReporter(0))
+
+	b.addTraces(testTraces("s0", "a", "b"))
+	assert.Equal(t, 0, len(tc.td))
+	b.addTraces(testTraces("s0", "c"))
+
+	// The spans of the same service are passed at once.
+	require.Equal(t, 1, len(tc.td))
+	assert.Equal(t, testTraces("s0", "a", "b", "c"), tc.td)
+
+	// Nothing left to pass on stop, the spans aren't passed as metrics.
+	b.stop()
+	assert.Equal(t, 1, len(tc.td))
+	assert.Equal(t, 0, len(mc.md))
+}
+
+func TestBatcher_TracesStop(t *testing.T) {
+	tc := &mockTraceConsumer{}
+	b := newBatcher(100, time.Hour, &mockMetricsConsumer{}, tc, NewMockReporter(0))
+
+	b.addTraces(testTraces("s0", "a"))
+	b.addTraces(testTraces("s1", "b"))
+	b.stop()
+
+	require.Equal(t, 2, len(tc.td))
+	assert.Equal(t, testTraces("s0", "a"), tc.td[:1])
+	assert.Equal(t, testTraces("s1", "b"), tc.td[1:])
+}
+
+func TestBatcher_TracesWithoutConsumer(t *testing.T) {
+	b := newBatcher(1, time.Hour, &mockMetricsConsumer{}, nil, NewMockReporter(0))
+
+	// The spans are dropped.
+	b.addTraces(testTraces("s0", "a"))
+	b.stop()
+	assert.Equal(t, 0, len(b.traces))
+}
+
+func testMetrics(names ...string) []*metricspb.Metric {
+	metrics := make([]*metricspb.Metric, 0, len(names))
+	for _, name := range names {
+		metrics = append(metrics, &metricspb.Metric{
+			MetricDescriptor: &metricspb.MetricDescriptor{Name: name},
+		})
+	}
+	return metrics
+}
+
+// testTraces returns the trace data of spans of a service.
+func testTraces(serviceName string, spanNames ...string) []consumerdata.TraceData {
+	td := consumerdata.TraceData{
+		Node: &commonpb.Node{
+			ServiceInfo: &commonpb.ServiceInfo{Name: serviceName},
+		},
+	}
+	for _, name := range spanNames {
+		td.Spans = append(td.Spans, &tracepb.Span{
+			Name: &tracepb.TruncatableString{Value: name},
+		})
+	}
+	return []consumerdata.TraceData{td}
+}
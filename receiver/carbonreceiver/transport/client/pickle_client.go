@@ -0,0 +1,59 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+)
+
+// sendPickleMetrics sends the metrics in a single message of the pickle
+// protocol, pickled as by the pickle module of Python with protocol 2:
+//
+// 	[(<metric_path>, (<metric_timestamp>, <metric_value>)), ...]
+func (g *Graphite) sendPickleMetrics(metrics []Metric) error {
+	var buf bytes.Buffer
+	buf.Write([]byte{0x80, 2}) // PROTO 2
+	buf.WriteByte(']')         // EMPTY_LIST
+	buf.WriteByte('(')         // MARK
+	for _, metric := range metrics {
+		buf.WriteByte('X') // BINUNICODE
+		writeUint32(&buf, binary.LittleEndian, uint32(len(metric.Name)))
+		buf.WriteString(metric.Name)
+		buf.WriteByte('J') // BININT
+		writeUint32(&buf, binary.LittleEndian, uint32(int32(metric.Timestamp.Unix())))
+		buf.WriteByte('G') // BINFLOAT
+		b := make([]byte, 8)
+		binary.BigEndian.PutUint64(b, math.Float64bits(metric.Value))
+		buf.Write(b)
+		buf.WriteByte(0x86) // TUPLE2
+		buf.WriteByte(0x86) // TUPLE2
+	}
+	buf.WriteByte('e') // APPENDS
+	buf.WriteByte('.') // STOP
+
+	var message bytes.Buffer
+	writeUint32(&message, binary.BigEndian, uint32(buf.Len()))
+	message.Write(buf.Bytes())
+	_, err := g.Conn.Write(message.Bytes())
+	return err
+}
+
+func writeUint32(buf *bytes.Buffer, order binary.ByteOrder, v uint32) {
+	b := make([]byte, 4)
+	order.PutUint32(b, v)
+	buf.Write(b)
+}
@@ -34,6 +34,9 @@ type Graphite struct {
 	Port    int
 	Timeout time.Duration
 	Conn    io.Writer
+
+	// pickle is true if the metrics are sent with the pickle protocol.
+	pickle bool
 }
 
 // Transport is used as an enum to select the type of transport to be used.
@@ -42,9 +45,11 @@ type Transport int
 const (
 	defaultTimeout = 5
 
-	// Available transport options: TCP and UDP.
+	// Available transport options: TCP, UDP and Pickle, the pickle protocol
+	// over TCP.
 	TCP Transport = iota
 	UDP
+	Pickle
 )
 
 // NewGraphite is a method that's used to create a new Graphite instance.
@@ -68,7 +73,7 @@ func (g *Graphite) connect(transport Transport) error {
 		cl.Close()
 	}
 
-	address := fmt.Sprintf("%s:%d", g.Host, g.Port)
+	address := net.JoinHostPort(g.Host, strconv.Itoa(g.Port))
 	if g.Timeout == 0 {
 		g.Timeout = defaultTimeout * time.Second
 	}
@@ -77,8 +82,9 @@ func (g *Graphite) connect(transport Transport) error {
 	var conn net.Conn
 
 	switch transport {
-	case TCP:
+	case TCP, Pickle:
 		conn, err = net.DialTimeout("tcp", address, g.Timeout)
+		g.pickle = transport == Pickle
 	case UDP:
 		udpAddr, err := net.ResolveUDPAddr("udp", address)
 		if err != nil {
@@ -109,6 +115,9 @@ func (g *Graphite) Disconnect() (err error) {
 // SendMetric method can be used to just pass a metric name and value and
 // have it be sent to the Graphite host
 func (g *Graphite) SendMetric(metric Metric) error {
+	if g.pickle {
+		return g.sendPickleMetrics([]Metric{metric})
+	}
 	_, err := fmt.Fprint(g.Conn, metric.String())
 	if err != nil {
 		return err
@@ -119,6 +128,9 @@ func (g *Graphite) SendMetric(metric Metric) error {
 // SendMetrics method can be used to pass a set of metrics and
 // have it be sent to the Graphite host
 func (g *Graphite) SendMetrics(metrics []Metric) error {
+	if g.pickle {
+		return g.sendPickleMetrics(metrics)
+	}
 	sb := strings.Builder{}
 	for i, metric := range metrics {
 		if _, err := sb.WriteString(metric.String()); err != nil {
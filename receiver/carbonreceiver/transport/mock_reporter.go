@@ -23,13 +23,16 @@ import (
 // tests (eg.: wait for certain number of messages).
 type MockReporter struct {
 	wgMetricsProcessed sync.WaitGroup
+
+	mu                   sync.Mutex
+	numDroppedTimeSeries map[error]int
 }
 
 var _ (Reporter) = (*MockReporter)(nil)
 
 // NewMockReporter returns a new instance of a MockReporter.
 func NewMockReporter(expectedOnMetricsProcessedCalls int) *MockReporter {
-	m := MockReporter{}
+	m := MockReporter{numDroppedTimeSeries: map[error]int{}}
 	m.wgMetricsProcessed.Add(expectedOnMetricsProcessedCalls)
 	return &m
 }
@@ -50,6 +53,38 @@ func (m *MockReporter) OnMetricsProcessed(
 	m.wgMetricsProcessed.Done()
 }
 
+func (m *MockReporter) OnTraceDataReceived(ctx context.Context) context.Context {
+	return ctx
+}
+
+func (m *MockReporter) OnTracesProcessed(ctx context.Context, numReceivedSpans int, err error) {
+}
+
+func (m *MockReporter) OnDataDropped(numDroppedTimeSeries int, reason error) {
+	m.mu.Lock()
+	m.numDroppedTimeSeries[reason] += numDroppedTimeSeries
+	m.mu.Unlock()
+}
+
+// NumDroppedTimeSeries returns the number of time series reported as dropped.
+func (m *MockReporter) NumDroppedTimeSeries() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var n int
+	for _, num := range m.numDroppedTimeSeries {
+		n += num
+	}
+	return n
+}
+
+// NumDroppedTimeSeriesFor returns the number of time series reported as
+// dropped for the reason.
+func (m *MockReporter) NumDroppedTimeSeriesFor(reason error) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.numDroppedTimeSeries[reason]
+}
+
 func (m *MockReporter) OnDebugf(template string, args ...interface{}) {
 }
 
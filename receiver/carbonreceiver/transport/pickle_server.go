@@ -0,0 +1,137 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector/consumer"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/protocol"
+)
+
+const (
+	// PickleMaxMessageSize is the maximum size of the messages of the pickle
+	// protocol, the same as the limit of Carbon. Connections sending larger
+	// messages are closed.
+	PickleMaxMessageSize = 1 << 20
+)
+
+var (
+	errPickleParser = errors.New(
+		"the parser does not support the pickle protocol")
+)
+
+// pickleServer receives the pickle protocol over TCP, see
+// https://graphite.readthedocs.io/en/latest/feeding-carbon.html#the-pickle-protocol.
+// Each message is prefixed by its size, as a 4-byte big-endian unsigned
+// integer, and is a pickled list of (path, (timestamp, value)) tuples.
+type pickleServer struct {
+	*tcpServer
+}
+
+var _ (Server) = (*pickleServer)(nil)
+
+// NewPickleServer creates a transport.Server receiving the pickle protocol
+// over TCP. It requires a parser implementing protocol.PointParser.
+func NewPickleServer(
+	addr string,
+	idleTimeout time.Duration,
+	settings ProcessingSettings,
+) (Server, error) {
+	t, err := newTCPServer(addr, idleTimeout, settings)
+	if err != nil {
+		return nil, err
+	}
+	p := &pickleServer{tcpServer: t}
+	t.handleConn = p.handleConnection
+	return p, nil
+}
+
+func (p *pickleServer) ListenAndServe(
+	parser protocol.Parser,
+	nextConsumer consumer.MetricsConsumerOld,
+	reporter Reporter,
+) error {
+	if parser != nil {
+		if _, ok := parser.(protocol.PointParser); !ok {
+			return errPickleParser
+		}
+	}
+	return p.tcpServer.ListenAndServe(parser, nextConsumer, reporter)
+}
+
+// handleConnection reads the messages sent on the connection, decodes them and
+// submits their datapoints to the workers.
+func (p *pickleServer) handleConnection(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	header := make([]byte, 4)
+	for {
+		if err := conn.SetDeadline(time.Now().Add(p.idleTimeout)); err != nil {
+			p.reporter.OnDebugf(
+				"Pickle Transport (%s) - conn.SetDeadLine error: %v",
+				p.ln.Addr(),
+				err)
+			return
+		}
+
+		// Unlike lines, messages can't be resumed after a read error, e.g. an
+		// idle timeout, or the client closing the connection, so the
+		// connection is closed on any error.
+		if _, err := io.ReadFull(reader, header); err != nil {
+			p.reporter.OnDebugf(
+				"Pickle Transport (%s) - error: %v",
+				p.ln.Addr(),
+				err)
+			return
+		}
+
+		size := binary.BigEndian.Uint32(header)
+		if size > PickleMaxMessageSize {
+			p.reporter.OnDebugf(
+				"Pickle Transport (%s) - message of %d bytes exceeds the maximum of %d bytes",
+				p.ln.Addr(),
+				size,
+				PickleMaxMessageSize)
+			return
+		}
+
+		message := make([]byte, size)
+		if _, err := io.ReadFull(reader, message); err != nil {
+			p.reporter.OnDebugf(
+				"Pickle Transport (%s) - error: %v",
+				p.ln.Addr(),
+				err)
+			return
+		}
+
+		datapoints, err := protocol.DecodePickle(message)
+		if err != nil {
+			// The size prefix allows skipping to the next message.
+			p.reporter.OnTranslationError(context.Background(), err)
+			continue
+		}
+		if len(datapoints) > 0 {
+			p.pool.submit(pickleDatapoints(datapoints))
+		}
+	}
+}
@@ -26,6 +26,14 @@ import (
 var (
 	errNilListenAndServeParameters = errors.New(
 		"no parameter of ListenAndServe can be nil")
+
+	// ErrQueueFull is the reason of the data dropped because the queue of the
+	// workers is full, see QueueFullDrop.
+	ErrQueueFull = errors.New("queue of the workers is full")
+
+	// ErrStopping is the reason of the data dropped because the Server is
+	// closing.
+	ErrStopping = errors.New("the server is closing")
 )
 
 // Server abstracts the type of transport being used and offer an
@@ -48,15 +56,16 @@ type Server interface {
 // Reporter is used to report (via zPages, logs, metrics, etc) the events
 // happening when the Server is receiving and processing data.
 type Reporter interface {
-	// OnDataReceived is called when a message or request is received from
-	// a client. The returned context should be used in other calls to the same
-	// reporter instance. The caller code should include a call to end the
-	// returned span.
+	// OnDataReceived is called when a batch of received data is about to be
+	// passed to the next consumer. The returned context should be used in
+	// other calls to the same reporter instance. The caller code should
+	// include a call to end the returned span.
 	OnDataReceived(ctx context.Context) context.Context
 
 	// OnTranslationError is used to report a translation error from original
-	// format to the internal format of the Collector. The context
-	// passed to it should be the ones returned by OnDataReceived.
+	// format to the internal format of the Collector. Since data is parsed
+	// before being batched, the context passed to it is not necessarily one
+	// returned by OnDataReceived.
 	OnTranslationError(ctx context.Context, err error)
 
 	// OnMetricsProcessed is called when the received data is passed to next
@@ -69,6 +78,23 @@ type Reporter interface {
 		numInvalidTimeSeries int,
 		err error)
 
+	// OnTraceDataReceived is called when a batch of received spans is about
+	// to be passed to the next trace consumer, as OnDataReceived for metrics.
+	OnTraceDataReceived(ctx context.Context) context.Context
+
+	// OnTracesProcessed is called when the received spans are passed to the
+	// next trace consumer. The context passed to it should be the one returned
+	// by OnTraceDataReceived.
+	OnTracesProcessed(
+		ctx context.Context,
+		numReceivedSpans int,
+		err error)
+
+	// OnDataDropped is called when received data is dropped before being
+	// parsed, with the number of time series, e.g. lines, dropped and the
+	// reason, either ErrQueueFull or ErrStopping.
+	OnDataDropped(numDroppedTimeSeries int, reason error)
+
 	// OnDebugf allows less structured reporting for debugging scenarios.
 	OnDebugf(
 		template string,
@@ -23,6 +23,7 @@ import (
 	"testing"
 	"time"
 
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
 	"github.com/open-telemetry/opentelemetry-collector/consumer/consumerdata"
 	"github.com/open-telemetry/opentelemetry-collector/testutils"
 	"github.com/stretchr/testify/assert"
@@ -41,7 +42,7 @@ func Test_Server_ListenAndServe(t *testing.T) {
 		{
 			name: "tcp",
 			buildServerFn: func(addr string) (Server, error) {
-				return NewTCPServer(addr, 1*time.Second)
+				return NewTCPServer(addr, 1*time.Second, ProcessingSettings{})
 			},
 			buildClientFn: func(host string, port int) (*client.Graphite, error) {
 				return client.NewGraphite(client.TCP, host, port)
@@ -50,12 +51,21 @@ func Test_Server_ListenAndServe(t *testing.T) {
 		{
 			name: "udp",
 			buildServerFn: func(addr string) (Server, error) {
-				return NewUDPServer(addr)
+				return NewUDPServer(addr, ProcessingSettings{})
 			},
 			buildClientFn: func(host string, port int) (*client.Graphite, error) {
 				return client.NewGraphite(client.UDP, host, port)
 			},
 		},
+		{
+			name: "pickle",
+			buildServerFn: func(addr string) (Server, error) {
+				return NewPickleServer(addr, 1*time.Second, ProcessingSettings{})
+			},
+			buildClientFn: func(host string, port int) (*client.Graphite, error) {
+				return client.NewGraphite(client.Pickle, host, port)
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -110,6 +120,68 @@ func Test_Server_ListenAndServe(t *testing.T) {
 	}
 }
 
+func Test_TCPServer_Batching(t *testing.T) {
+	addr := testutils.GetAvailableLocalAddress(t)
+	svr, err := NewTCPServer(addr, 1*time.Second, ProcessingSettings{
+		BatchSize:    10,
+		BatchTimeout: time.Hour,
+	})
+	require.NoError(t, err)
+
+	host, portStr, err := net.SplitHostPort(addr)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(portStr)
+	require.NoError(t, err)
+
+	mc := &mockMetricsConsumer{}
+	p, err := (&protocol.PlaintextConfig{}).BuildParser()
+	require.NoError(t, err)
+	mr := NewMockReporter(1)
+
+	wgListenAndServe := sync.WaitGroup{}
+	wgListenAndServe.Add(1)
+	go func() {
+		defer wgListenAndServe.Done()
+		assert.Error(t, svr.ListenAndServe(p, mc, mr))
+	}()
+
+	gc, err := client.NewGraphite(client.TCP, host, port)
+	require.NoError(t, err)
+
+	ts := time.Date(2020, 2, 20, 20, 20, 20, 20, time.UTC)
+	var metrics []client.Metric
+	for i := 0; i < 10; i++ {
+		metrics = append(metrics, client.Metric{
+			Name: "test.metric" + strconv.Itoa(i), Value: float64(i), Timestamp: ts})
+	}
+	require.NoError(t, gc.SendMetrics(metrics))
+
+	// The batch is full, passed before the batch timeout.
+	mr.WaitAllOnMetricsProcessedCalls()
+	assert.NoError(t, gc.Disconnect())
+	assert.NoError(t, svr.Close())
+	wgListenAndServe.Wait()
+
+	require.Equal(t, 1, len(mc.md))
+	assert.Equal(t, 10, len(mc.md[0].Metrics))
+}
+
+func Test_PickleServer_ListenAndServe_ParserWithoutPoints(t *testing.T) {
+	svr, err := NewPickleServer(testutils.GetAvailableLocalAddress(t), 1*time.Second, ProcessingSettings{})
+	require.NoError(t, err)
+
+	err = svr.ListenAndServe(lineParser{}, &mockMetricsConsumer{}, NewMockReporter(0))
+	assert.Equal(t, errPickleParser, err)
+	assert.NoError(t, svr.Close())
+}
+
+// lineParser only parses lines, not points.
+type lineParser struct{}
+
+func (lineParser) Parse(line string) (*metricspb.Metric, error) {
+	return nil, nil
+}
+
 type mockMetricsConsumer struct {
 	sync.Mutex
 	md []consumerdata.MetricsData
@@ -122,3 +194,16 @@ func (m *mockMetricsConsumer) ConsumeMetricsData(ctx context.Context, td consume
 
 	return nil
 }
+
+type mockTraceConsumer struct {
+	sync.Mutex
+	td []consumerdata.TraceData
+}
+
+func (m *mockTraceConsumer) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	m.Lock()
+	m.td = append(m.td, td)
+	m.Unlock()
+
+	return nil
+}
@@ -16,7 +16,7 @@ package transport
 
 import (
 	"bufio"
-	"context"
+	"bytes"
 	"fmt"
 	"io"
 	"net"
@@ -24,10 +24,7 @@ import (
 	"sync"
 	"time"
 
-	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
 	"github.com/open-telemetry/opentelemetry-collector/consumer"
-	"github.com/open-telemetry/opentelemetry-collector/consumer/consumerdata"
-	"go.opencensus.io/trace"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/protocol"
 )
@@ -42,6 +39,10 @@ type tcpServer struct {
 	wg          sync.WaitGroup
 	idleTimeout time.Duration
 	reporter    Reporter
+	pool        *workerPool
+	// handleConn handles the connections accepted by the server, according to
+	// the protocol being received, e.g. plaintext or pickle.
+	handleConn func(conn net.Conn)
 }
 
 var _ (Server) = (*tcpServer)(nil)
@@ -50,7 +51,21 @@ var _ (Server) = (*tcpServer)(nil)
 func NewTCPServer(
 	addr string,
 	idleTimeout time.Duration,
+	settings ProcessingSettings,
 ) (Server, error) {
+	t, err := newTCPServer(addr, idleTimeout, settings)
+	if err != nil {
+		return nil, err
+	}
+	t.handleConn = t.handleConnection
+	return t, nil
+}
+
+func newTCPServer(
+	addr string,
+	idleTimeout time.Duration,
+	settings ProcessingSettings,
+) (*tcpServer, error) {
 	if idleTimeout < 0 {
 		return nil, fmt.Errorf("invalid idle timeout: %v", idleTimeout)
 	}
@@ -59,6 +74,11 @@ func NewTCPServer(
 		idleTimeout = TCPIdleTimeoutDefault
 	}
 
+	pool, err := newWorkerPool(settings)
+	if err != nil {
+		return nil, err
+	}
+
 	ln, err := net.Listen("tcp", addr)
 	if err != nil {
 		return nil, err
@@ -67,6 +87,7 @@ func NewTCPServer(
 	t := tcpServer{
 		ln:          ln,
 		idleTimeout: idleTimeout,
+		pool:        pool,
 	}
 	return &t, nil
 }
@@ -84,6 +105,7 @@ func (t *tcpServer) ListenAndServe(
 	connMapMtx := &sync.Mutex{}
 
 	t.reporter = reporter
+	t.pool.start(parser, nextConsumer, reporter)
 	var err error
 	for {
 		conn, acceptErr := t.ln.Accept()
@@ -93,7 +115,7 @@ func (t *tcpServer) ListenAndServe(
 			connMapMtx.Unlock()
 			t.wg.Add(1)
 			go func(c net.Conn) {
-				t.handleConnection(parser, nextConsumer, c)
+				t.handleConn(c)
 				connMapMtx.Lock()
 				delete(acceptedConnMap, c)
 				connMapMtx.Unlock()
@@ -135,28 +157,24 @@ func (t *tcpServer) ListenAndServe(
 func (t *tcpServer) Close() error {
 	err := t.ln.Close()
 	t.wg.Wait()
+	t.pool.stop()
 	return err
 }
 
-func (t *tcpServer) handleConnection(
-	p protocol.Parser,
-	nextConsumer consumer.MetricsConsumerOld,
-	conn net.Conn,
-) {
+// handleConnection reads the lines sent on the connection and submits them to
+// the workers. The lines read together, up to the batch size, are submitted
+// together.
+func (t *tcpServer) handleConnection(conn net.Conn) {
 	defer conn.Close()
-	var span *trace.Span
 	reader := bufio.NewReader(conn)
+	var lines textLines
 	for {
-		if span != nil {
-			span.End()
-			span = nil
-		}
-
 		if err := conn.SetDeadline(time.Now().Add(t.idleTimeout)); err != nil {
 			t.reporter.OnDebugf(
 				"TCP Transport (%s) - conn.SetDeadLine error: %v",
 				t.ln.Addr(),
 				err)
+			t.submit(lines)
 			return
 		}
 
@@ -172,31 +190,16 @@ func (t *tcpServer) handleConnection(
 		bytes, err := reader.ReadBytes((byte)('\n'))
 
 		// It is possible to have new data in bytes and err to be io.EOF
-		ctx := t.reporter.OnDataReceived(context.Background())
-		var numReceivedTimeSeries, numInvalidTimeSeries int
 		line := strings.TrimSpace(string(bytes))
 		if line != "" {
-			numReceivedTimeSeries++
-			metric, err := p.Parse(line)
-			if err != nil {
-				numInvalidTimeSeries++
-				t.reporter.OnTranslationError(ctx, err)
-				continue
-			}
+			lines = append(lines, line)
+		}
 
-			md := consumerdata.MetricsData{
-				Metrics: []*metricspb.Metric{metric},
-			}
-			err = nextConsumer.ConsumeMetricsData(ctx, md)
-			t.reporter.OnMetricsProcessed(ctx, numReceivedTimeSeries, numInvalidTimeSeries, err)
-			if err != nil {
-				// The protocol doesn't account for returning errors.
-				// Since this is a TCP connection it seems reasonable to close the
-				// connection as a way to report "error" back to client and minimize
-				// the effect of a client constantly submitting bad data.
-				span.End()
-				return
-			}
+		// Lines are submitted once no other full line was already received,
+		// so that reading doesn't wait for more data before submitting them.
+		if err != nil || len(lines) >= t.pool.settings.BatchSize || !hasBufferedLine(reader) {
+			t.submit(lines)
+			lines = nil
 		}
 
 		if netErr, ok := err.(*net.OpError); ok {
@@ -206,7 +209,6 @@ func (t *tcpServer) handleConnection(
 				netErr)
 			if !netErr.Temporary() || netErr.Timeout() {
 				// We want to end on timeout so idle connections are purged.
-				span.End()
 				return
 			}
 		}
@@ -216,8 +218,19 @@ func (t *tcpServer) handleConnection(
 				"TCP Transport (%s) - error: %v",
 				t.ln.Addr(),
 				err)
-			span.End()
 			return
 		}
 	}
 }
+
+func (t *tcpServer) submit(lines textLines) {
+	if len(lines) > 0 {
+		t.pool.submit(lines)
+	}
+}
+
+// hasBufferedLine returns whether a full line was already received.
+func hasBufferedLine(reader *bufio.Reader) bool {
+	buffered, _ := reader.Peek(reader.Buffered())
+	return bytes.IndexByte(buffered, '\n') >= 0
+}
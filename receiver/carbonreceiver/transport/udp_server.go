@@ -16,29 +16,30 @@ package transport
 
 import (
 	"bytes"
-	"context"
 	"io"
 	"net"
 	"strings"
-	"sync"
 
-	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
 	"github.com/open-telemetry/opentelemetry-collector/consumer"
-	"github.com/open-telemetry/opentelemetry-collector/consumer/consumerdata"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/protocol"
 )
 
 type udpServer struct {
-	wg         sync.WaitGroup
 	packetConn net.PacketConn
 	reporter   Reporter
+	pool       *workerPool
 }
 
 var _ (Server) = (*udpServer)(nil)
 
 // NewUDPServer creates a transport.Server using UDP as its transport.
-func NewUDPServer(addr string) (Server, error) {
+func NewUDPServer(addr string, settings ProcessingSettings) (Server, error) {
+	pool, err := newWorkerPool(settings)
+	if err != nil {
+		return nil, err
+	}
+
 	packetConn, err := net.ListenPacket("udp", addr)
 	if err != nil {
 		return nil, err
@@ -46,6 +47,7 @@ func NewUDPServer(addr string) (Server, error) {
 
 	u := udpServer{
 		packetConn: packetConn,
+		pool:       pool,
 	}
 	return &u, nil
 }
@@ -60,18 +62,18 @@ func (u *udpServer) ListenAndServe(
 	}
 
 	u.reporter = reporter
+	u.pool.start(parser, nextConsumer, reporter)
 
 	buf := make([]byte, 65527) // max size for udp packet body (assuming ipv6)
 	for {
 		n, _, err := u.packetConn.ReadFrom(buf)
 		if n > 0 {
-			u.wg.Add(1)
-			bufCopy := make([]byte, n)
-			copy(bufCopy, buf)
-			go func() {
-				u.handlePacket(parser, nextConsumer, bufCopy)
-				u.wg.Done()
-			}()
+			// The lines are copied out of the buffer, the packets are
+			// submitted to the workers instead of being parsed here so that
+			// reading isn't slowed down.
+			if lines := splitLines(buf[:n]); len(lines) > 0 {
+				u.pool.submit(lines)
+			}
 		}
 		if err != nil {
 			u.reporter.OnDebugf(
@@ -90,44 +92,22 @@ func (u *udpServer) ListenAndServe(
 
 func (u *udpServer) Close() error {
 	err := u.packetConn.Close()
-	u.wg.Wait()
+	u.pool.stop()
 	return err
 }
 
-func (u *udpServer) handlePacket(
-	p protocol.Parser,
-	nextConsumer consumer.MetricsConsumerOld,
-	data []byte,
-) {
-	ctx := u.reporter.OnDataReceived(context.Background())
-	var numReceivedTimeseries, numInvalidTimeseries int
-	var metrics []*metricspb.Metric
+// splitLines returns the non-empty lines of a packet.
+func splitLines(data []byte) textLines {
+	var lines textLines
 	buf := bytes.NewBuffer(data)
 	for {
 		bytes, err := buf.ReadBytes((byte)('\n'))
-		if err == io.EOF {
-			if len(bytes) == 0 {
-				// Completed without errors.
-				break
-			}
-		}
 		line := strings.TrimSpace(string(bytes))
 		if line != "" {
-			numReceivedTimeseries++
-			metric, err := p.Parse(line)
-			if err != nil {
-				numInvalidTimeseries++
-				u.reporter.OnTranslationError(ctx, err)
-				continue
-			}
-
-			metrics = append(metrics, metric)
+			lines = append(lines, line)
+		}
+		if err == io.EOF {
+			return lines
 		}
 	}
-
-	md := consumerdata.MetricsData{
-		Metrics: metrics,
-	}
-	err := nextConsumer.ConsumeMetricsData(ctx, md)
-	u.reporter.OnMetricsProcessed(ctx, numReceivedTimeseries, numInvalidTimeseries, err)
 }
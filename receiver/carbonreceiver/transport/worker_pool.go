@@ -0,0 +1,336 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/open-telemetry/opentelemetry-collector/consumer"
+	"github.com/open-telemetry/opentelemetry-collector/consumer/consumerdata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/protocol"
+)
+
+const (
+	// QueueSizeDefault is the default number of received messages waiting
+	// for a worker.
+	QueueSizeDefault = 1000
+
+	// BatchSizeDefault is the default maximum number of metrics passed to the
+	// next consumer at once.
+	BatchSizeDefault = 1000
+
+	// BatchTimeoutDefault is the default maximum duration metrics wait to be
+	// passed to the next consumer.
+	BatchTimeoutDefault = 200 * time.Millisecond
+
+	// QueueFullBlock blocks the reception of data while the queue is full.
+	QueueFullBlock = "block"
+
+	// QueueFullDrop drops the data received while the queue is full.
+	QueueFullDrop = "drop"
+)
+
+// ProcessingSettings are the settings of the processing of the data received
+// by a Server. Received messages, e.g. UDP packets or lines read together on a
+// TCP connection, are queued to be parsed by a bounded pool of workers, and the
+// resulting metrics are batched before being passed to the next consumer.
+// The zero value of each setting selects its default.
+type ProcessingSettings struct {
+	// Workers is the number of workers parsing the received data, the default
+	// is the number of CPUs.
+	Workers int `mapstructure:"workers"`
+
+	// QueueSize is the number of received messages waiting for a worker.
+	QueueSize int `mapstructure:"queue_size"`
+
+	// QueueFullPolicy selects what happens to the data received while the
+	// queue is full, either "block" (the default), to stop receiving until
+	// a worker is available, or "drop", to drop the data.
+	QueueFullPolicy string `mapstructure:"queue_full_policy"`
+
+	// BatchSize is the maximum number of metrics passed to the next consumer
+	// at once, a batch being passed as soon as it's full.
+	BatchSize int `mapstructure:"batch_size"`
+
+	// BatchTimeout is the maximum duration metrics wait in a batch not yet
+	// full before being passed to the next consumer.
+	BatchTimeout time.Duration `mapstructure:"batch_timeout"`
+}
+
+// withDefaults validates the settings and returns them with the defaults of
+// the settings not set.
+func (s ProcessingSettings) withDefaults() (ProcessingSettings, error) {
+	switch {
+	case s.Workers < 0:
+		return s, fmt.Errorf("invalid number of workers: %d", s.Workers)
+	case s.QueueSize < 0:
+		return s, fmt.Errorf("invalid queue size: %d", s.QueueSize)
+	case s.BatchSize < 0:
+		return s, fmt.Errorf("invalid batch size: %d", s.BatchSize)
+	case s.BatchTimeout < 0:
+		return s, fmt.Errorf("invalid batch timeout: %v", s.BatchTimeout)
+	}
+
+	switch s.QueueFullPolicy {
+	case "":
+		s.QueueFullPolicy = QueueFullBlock
+	case QueueFullBlock, QueueFullDrop:
+	default:
+		return s, fmt.Errorf(
+			"unknown queue full policy %q, valid policies: %q or %q",
+			s.QueueFullPolicy,
+			QueueFullBlock,
+			QueueFullDrop)
+	}
+
+	if s.Workers == 0 {
+		s.Workers = runtime.NumCPU()
+	}
+	if s.QueueSize == 0 {
+		s.QueueSize = QueueSizeDefault
+	}
+	if s.BatchSize == 0 {
+		s.BatchSize = BatchSizeDefault
+	}
+	if s.BatchTimeout == 0 {
+		s.BatchTimeout = BatchTimeoutDefault
+	}
+	return s, nil
+}
+
+// workItem is a message received by a Server, parsed by a worker.
+type workItem interface {
+	// numTimeSeries returns the number of time series of the message, e.g.
+	// its number of lines.
+	numTimeSeries() int
+
+	// parse parses the time series of the message, reporting the translation
+	// errors, and returns the metrics and the trace data of the spans, if any,
+	// along with the number of time series, excluding the lines that aren't
+	// metrics, and the number of invalid time series.
+	parse(p protocol.Parser, r Reporter) ([]*metricspb.Metric, []consumerdata.TraceData, int, int)
+}
+
+// textLines are lines of the plaintext protocol.
+type textLines []string
+
+func (l textLines) numTimeSeries() int {
+	return len(l)
+}
+
+func (l textLines) parse(p protocol.Parser, r Reporter) ([]*metricspb.Metric, []consumerdata.TraceData, int, int) {
+	spanParser, _ := p.(protocol.SpanParser)
+	numTimeSeries := len(l)
+	var numInvalidTimeSeries int
+	metrics := make([]*metricspb.Metric, 0, len(l))
+	var traces []consumerdata.TraceData
+	for _, line := range l {
+		var metric *metricspb.Metric
+		var td *consumerdata.TraceData
+		var err error
+		if spanParser != nil {
+			metric, td, err = spanParser.ParseLine(line)
+		} else {
+			metric, err = p.Parse(line)
+		}
+		switch {
+		case err == protocol.ErrNotMetric:
+			numTimeSeries--
+		case err != nil:
+			numInvalidTimeSeries++
+			r.OnTranslationError(context.Background(), err)
+		case td != nil:
+			numTimeSeries--
+			traces = append(traces, *td)
+		case metric != nil:
+			metrics = append(metrics, metric)
+		default:
+			numTimeSeries--
+		}
+	}
+	return metrics, traces, numTimeSeries, numInvalidTimeSeries
+}
+
+// pickleDatapoints are the datapoints of a message of the pickle protocol,
+// parsed by a protocol.PointParser.
+type pickleDatapoints []protocol.PickleDatapoint
+
+func (d pickleDatapoints) numTimeSeries() int {
+	return len(d)
+}
+
+func (d pickleDatapoints) parse(p protocol.Parser, r Reporter) ([]*metricspb.Metric, []consumerdata.TraceData, int, int) {
+	pointParser := p.(protocol.PointParser)
+	var numInvalidTimeSeries int
+	metrics := make([]*metricspb.Metric, 0, len(d))
+	for _, dp := range d {
+		metric, err := pointParser.ParsePoint(dp.Path, dp.Point)
+		if err != nil {
+			numInvalidTimeSeries++
+			r.OnTranslationError(context.Background(), err)
+			continue
+		}
+		metrics = append(metrics, metric)
+	}
+	return metrics, nil, len(d), numInvalidTimeSeries
+}
+
+// workerPool parses the messages received by a Server with a bounded number
+// of workers, and batches the resulting metrics.
+type workerPool struct {
+	settings ProcessingSettings
+	queue    chan workItem
+	// done is closed by stop, to unblock the messages being submitted.
+	done chan struct{}
+	// quit is closed by stop once no message can be queued anymore, for the
+	// workers to process the queued messages and exit.
+	quit chan struct{}
+
+	// mu guards the fields below, set by start and stop. The workers and the
+	// senders of messages are only started after start.
+	mu       sync.RWMutex
+	parser   protocol.Parser
+	reporter Reporter
+	batcher  *batcher
+	started  bool
+	stopped  bool
+	wg       sync.WaitGroup
+	// submitting tracks the messages being submitted, added while holding mu
+	// so that stop waits for them before the workers exit.
+	submitting sync.WaitGroup
+}
+
+func newWorkerPool(settings ProcessingSettings) (*workerPool, error) {
+	settings, err := settings.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+	return &workerPool{
+		settings: settings,
+		queue:    make(chan workItem, settings.QueueSize),
+		done:     make(chan struct{}),
+		quit:     make(chan struct{}),
+	}, nil
+}
+
+// start starts the workers, parsing with the parser and passing the metrics
+// to the next consumer, unless the pool was already stopped. The spans of
+// parsers that are also a protocol.SpanParser are passed to the next consumer
+// if it's also a consumer.TraceConsumerOld.
+func (wp *workerPool) start(
+	parser protocol.Parser,
+	nextConsumer consumer.MetricsConsumerOld,
+	reporter Reporter,
+) {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	wp.parser = parser
+	wp.reporter = reporter
+	if wp.started || wp.stopped {
+		return
+	}
+	wp.started = true
+	nextTraceConsumer, _ := nextConsumer.(consumer.TraceConsumerOld)
+	wp.batcher = newBatcher(wp.settings.BatchSize, wp.settings.BatchTimeout, nextConsumer, nextTraceConsumer, reporter)
+	wp.wg.Add(wp.settings.Workers)
+	for i := 0; i < wp.settings.Workers; i++ {
+		go wp.work()
+	}
+}
+
+// submit queues a received message, applying the queue full policy if the
+// queue is full. The messages submitted once the pool is stopped are dropped.
+func (wp *workerPool) submit(item workItem) {
+	wp.mu.RLock()
+	if wp.stopped {
+		wp.mu.RUnlock()
+		wp.reporter.OnDataDropped(item.numTimeSeries(), ErrStopping)
+		return
+	}
+	wp.submitting.Add(1)
+	wp.mu.RUnlock()
+	defer wp.submitting.Done()
+
+	if wp.settings.QueueFullPolicy == QueueFullDrop {
+		select {
+		case wp.queue <- item:
+		default:
+			wp.reporter.OnDataDropped(item.numTimeSeries(), ErrQueueFull)
+		}
+		return
+	}
+
+	select {
+	case wp.queue <- item:
+	case <-wp.done:
+		wp.reporter.OnDataDropped(item.numTimeSeries(), ErrStopping)
+	}
+}
+
+func (wp *workerPool) work() {
+	defer wp.wg.Done()
+	for {
+		select {
+		case item := <-wp.queue:
+			wp.process(item)
+		case <-wp.quit:
+			// Process the messages already queued before exiting.
+			for {
+				select {
+				case item := <-wp.queue:
+					wp.process(item)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (wp *workerPool) process(item workItem) {
+	metrics, traces, numTimeSeries, numInvalidTimeSeries := item.parse(wp.parser, wp.reporter)
+	if len(traces) > 0 {
+		wp.batcher.addTraces(traces)
+	}
+	wp.batcher.add(metrics, numTimeSeries, numInvalidTimeSeries)
+}
+
+// stop processes the queued messages and passes the pending metrics to the
+// next consumer. The messages should no longer be submitted.
+func (wp *workerPool) stop() {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	if wp.stopped {
+		return
+	}
+	wp.stopped = true
+	close(wp.done)
+	// The messages being submitted are either queued or dropped, and no other
+	// message can be queued once they are.
+	wp.submitting.Wait()
+	close(wp.quit)
+	if wp.started {
+		wp.wg.Wait()
+		wp.batcher.stop()
+	}
+}
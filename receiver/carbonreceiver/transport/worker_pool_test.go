@@ -0,0 +1,372 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/open-telemetry/opentelemetry-collector/consumer/consumerdata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/protocol"
+)
+
+func TestProcessingSettings_withDefaults(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings ProcessingSettings
+		want     ProcessingSettings
+		wantErr  error
+	}{
+		{
+			name: "defaults",
+			want: ProcessingSettings{
+				Workers:         runtime.NumCPU(),
+				QueueSize:       QueueSizeDefault,
+				QueueFullPolicy: QueueFullBlock,
+				BatchSize:       BatchSizeDefault,
+				BatchTimeout:    BatchTimeoutDefault,
+			},
+		},
+		{
+			name: "custom",
+			settings: ProcessingSettings{
+				Workers:         2,
+				QueueSize:       10,
+				QueueFullPolicy: QueueFullDrop,
+				BatchSize:       100,
+				BatchTimeout:    time.Second,
+			},
+			want: ProcessingSettings{
+				Workers:         2,
+				QueueSize:       10,
+				QueueFullPolicy: QueueFullDrop,
+				BatchSize:       100,
+				BatchTimeout:    time.Second,
+			},
+		},
+		{
+			name:     "negative_workers",
+			settings: ProcessingSettings{Workers: -1},
+			wantErr:  errors.New("invalid number of workers: -1"),
+		},
+		{
+			name:     "negative_queue_size",
+			settings: ProcessingSettings{QueueSize: -1},
+			wantErr:  errors.New("invalid queue size: -1"),
+		},
+		{
+			name:     "negative_batch_size",
+			settings: ProcessingSettings{BatchSize: -1},
+			wantErr:  errors.New("invalid batch size: -1"),
+		},
+		{
+			name:     "negative_batch_timeout",
+			settings: ProcessingSettings{BatchTimeout: -time.Second},
+			wantErr:  errors.New("invalid batch timeout: -1s"),
+		},
+		{
+			name:     "unknown_policy",
+			settings: ProcessingSettings{QueueFullPolicy: "retry"},
+			wantErr:  errors.New(`unknown queue full policy "retry", valid policies: "block" or "drop"`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.settings.withDefaults()
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWorkerPool_QueueFullDrop(t *testing.T) {
+	pool, err := newWorkerPool(ProcessingSettings{
+		Workers:         1,
+		QueueSize:       1,
+		QueueFullPolicy: QueueFullDrop,
+		BatchTimeout:    time.Hour,
+	})
+	require.NoError(t, err)
+
+	parser := newBlockingParser()
+	mc := &mockMetricsConsumer{}
+	mr := NewMockReporter(1)
+	pool.start(parser, mc, mr)
+
+	// The worker blocks on the first message, the second one is queued and
+	// the third one dropped.
+	pool.submit(textLines{"a 1 1"})
+	<-parser.parsing
+	pool.submit(textLines{"b 1 1"})
+	pool.submit(textLines{"c 1 1", "d 1 1"})
+	assert.Equal(t, 2, mr.NumDroppedTimeSeriesFor(ErrQueueFull))
+
+	close(parser.release)
+	pool.stop()
+	mr.WaitAllOnMetricsProcessedCalls()
+
+	require.Equal(t, 1, len(mc.md))
+	assert.Equal(t, 2, len(mc.md[0].Metrics))
+}
+
+func TestWorkerPool_QueueFullBlock(t *testing.T) {
+	pool, err := newWorkerPool(ProcessingSettings{
+		Workers:      1,
+		QueueSize:    1,
+		BatchTimeout: time.Hour,
+	})
+	require.NoError(t, err)
+
+	parser := newBlockingParser()
+	mc := &mockMetricsConsumer{}
+	mr := NewMockReporter(1)
+	pool.start(parser, mc, mr)
+
+	pool.submit(textLines{"a 1 1"})
+	<-parser.parsing
+	pool.submit(textLines{"b 1 1"})
+
+	submitted := make(chan struct{})
+	go func() {
+		pool.submit(textLines{"c 1 1", "d 1 1"})
+		close(submitted)
+	}()
+
+	select {
+	case <-submitted:
+		t.Fatal("submit should block while the queue is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(parser.release)
+	<-submitted
+	pool.stop()
+	mr.WaitAllOnMetricsProcessedCalls()
+
+	assert.Equal(t, 0, mr.NumDroppedTimeSeries())
+	require.Equal(t, 1, len(mc.md))
+	assert.Equal(t, 4, len(mc.md[0].Metrics))
+}
+
+func TestWorkerPool_SubmitAfterStop(t *testing.T) {
+	for _, policy := range []string{QueueFullBlock, QueueFullDrop} {
+		t.Run(policy, func(t *testing.T) {
+			pool, err := newWorkerPool(ProcessingSettings{QueueFullPolicy: policy})
+			require.NoError(t, err)
+
+			mc := &mockMetricsConsumer{}
+			mr := NewMockReporter(0)
+			pool.start(notMetricParser{}, mc, mr)
+			pool.stop()
+
+			pool.submit(textLines{"a 1 1", "b 1 1"})
+			assert.Equal(t, 2, mr.NumDroppedTimeSeriesFor(ErrStopping))
+			assert.Equal(t, 0, len(pool.queue))
+		})
+	}
+}
+
+func TestWorkerPool_SubmitDuringStop(t *testing.T) {
+	const numSubmitters, numMessages = 4, 200
+	for _, policy := range []string{QueueFullBlock, QueueFullDrop} {
+		t.Run(policy, func(t *testing.T) {
+			pool, err := newWorkerPool(ProcessingSettings{
+				Workers:         2,
+				QueueSize:       1,
+				QueueFullPolicy: policy,
+				BatchTimeout:    time.Hour,
+			})
+			require.NoError(t, err)
+
+			mr := &countingReporter{MockReporter: NewMockReporter(0)}
+			pool.start(notMetricParser{}, &mockMetricsConsumer{}, mr)
+
+			var wg sync.WaitGroup
+			wg.Add(numSubmitters)
+			for i := 0; i < numSubmitters; i++ {
+				go func() {
+					defer wg.Done()
+					for j := 0; j < numMessages; j++ {
+						pool.submit(textLines{"a 1 1"})
+					}
+				}()
+			}
+			pool.stop()
+			wg.Wait()
+
+			// Every message is either processed or reported as dropped.
+			assert.Equal(t, numSubmitters*numMessages, mr.numProcessed()+mr.NumDroppedTimeSeries())
+			if policy == QueueFullBlock {
+				// Blocking submissions are only dropped because of the stop.
+				assert.Equal(t, mr.NumDroppedTimeSeries(), mr.NumDroppedTimeSeriesFor(ErrStopping))
+			}
+		})
+	}
+}
+
+func TestWorkerPool_StopWithoutStart(t *testing.T) {
+	pool, err := newWorkerPool(ProcessingSettings{})
+	require.NoError(t, err)
+	pool.stop()
+	pool.stop()
+}
+
+func Test_textLines_parse(t *testing.T) {
+	lines := textLines{"a 1 1", "span", "invalid", "b 1 1"}
+	metrics, traces, numTimeSeries, numInvalidTimeSeries := lines.parse(notMetricParser{}, NewMockReporter(0))
+
+	// The lines that aren't metrics aren't counted as time series.
+	assert.Equal(t, 3, numTimeSeries)
+	assert.Equal(t, 1, numInvalidTimeSeries)
+	require.Equal(t, 2, len(metrics))
+	assert.Equal(t, "a", metrics[0].MetricDescriptor.Name)
+	assert.Equal(t, "b", metrics[1].MetricDescriptor.Name)
+	assert.Nil(t, traces)
+}
+
+func Test_textLines_parseSpans(t *testing.T) {
+	lines := textLines{"a 1 1", "span s0", "skip", "invalid", "b 1 1"}
+	metrics, traces, numTimeSeries, numInvalidTimeSeries := lines.parse(spanParser{}, NewMockReporter(0))
+
+	// Neither the spans nor the skipped lines are counted as time series.
+	assert.Equal(t, 3, numTimeSeries)
+	assert.Equal(t, 1, numInvalidTimeSeries)
+	require.Equal(t, 2, len(metrics))
+	assert.Equal(t, "a", metrics[0].MetricDescriptor.Name)
+	assert.Equal(t, "b", metrics[1].MetricDescriptor.Name)
+	assert.Equal(t, testTraces("s0", "span"), traces)
+}
+
+func TestWorkerPool_Spans(t *testing.T) {
+	pool, err := newWorkerPool(ProcessingSettings{Workers: 1})
+	require.NoError(t, err)
+	mc := &mockMetricsAndTraceConsumer{}
+	pool.start(spanParser{}, mc, NewMockReporter(1))
+
+	pool.submit(textLines{"a 1 1", "span s0", "span s0"})
+	pool.stop()
+
+	require.Equal(t, 1, len(mc.md))
+	assert.Equal(t, testMetrics("a"), mc.md[0].Metrics)
+	assert.Equal(t, testTraces("s0", "span", "span"), mc.td)
+}
+
+func TestWorkerPool_SpansWithoutTraceConsumer(t *testing.T) {
+	pool, err := newWorkerPool(ProcessingSettings{Workers: 1})
+	require.NoError(t, err)
+	mc := &mockMetricsConsumer{}
+	pool.start(spanParser{}, mc, NewMockReporter(1))
+
+	// The spans are skipped, the metrics are still passed.
+	pool.submit(textLines{"a 1 1", "span s0"})
+	pool.stop()
+
+	require.Equal(t, 1, len(mc.md))
+	assert.Equal(t, testMetrics("a"), mc.md[0].Metrics)
+}
+
+// countingReporter counts the time series processed.
+type countingReporter struct {
+	*MockReporter
+	mu                     sync.Mutex
+	numProcessedTimeSeries int
+}
+
+func (r *countingReporter) OnMetricsProcessed(_ context.Context, numReceivedTimeSeries int, _ int, _ error) {
+	r.mu.Lock()
+	r.numProcessedTimeSeries += numReceivedTimeSeries
+	r.mu.Unlock()
+}
+
+func (r *countingReporter) numProcessed() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.numProcessedTimeSeries
+}
+
+// notMetricParser returns protocol.ErrNotMetric for the "span" lines.
+type notMetricParser struct{}
+
+func (notMetricParser) Parse(line string) (*metricspb.Metric, error) {
+	switch line {
+	case "span":
+		return nil, protocol.ErrNotMetric
+	case "invalid":
+		return nil, errors.New("invalid line")
+	}
+	return &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{Name: line[:1]},
+	}, nil
+}
+
+// spanParser also parses the "span <service>" lines as spans, and skips the
+// "skip" lines.
+type spanParser struct {
+	notMetricParser
+}
+
+var _ protocol.SpanParser = spanParser{}
+
+func (p spanParser) ParseLine(line string) (*metricspb.Metric, *consumerdata.TraceData, error) {
+	switch {
+	case line == "skip":
+		return nil, nil, nil
+	case strings.HasPrefix(line, "span "):
+		td := testTraces(strings.TrimPrefix(line, "span "), "span")[0]
+		return nil, &td, nil
+	}
+	metric, err := p.Parse(line)
+	return metric, nil, err
+}
+
+type mockMetricsAndTraceConsumer struct {
+	mockMetricsConsumer
+	mockTraceConsumer
+}
+
+// blockingParser blocks parsing until released, signaling each line being
+// parsed.
+type blockingParser struct {
+	parsing chan struct{}
+	release chan struct{}
+}
+
+func newBlockingParser() *blockingParser {
+	return &blockingParser{
+		parsing: make(chan struct{}, 100),
+		release: make(chan struct{}),
+	}
+}
+
+func (p *blockingParser) Parse(line string) (*metricspb.Metric, error) {
+	p.parsing <- struct{}{}
+	<-p.release
+	return &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{Name: line},
+	}, nil
+}
@@ -24,9 +24,13 @@ import (
 	batchv1 "k8s.io/api/batch/v1"
 	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/cache"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/resourcemetadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/utils"
 )
 
 // TODO: Consider moving some of these constants to
@@ -52,6 +56,10 @@ const (
 	k8sKeyDaemonSetUID             = "k8s.daemonset.uid"
 	k8sKeyHPAUID                   = "k8s.hpa.uid"
 	k8sKeyResourceQuotaUID         = "k8s.resourcequota.uid"
+	k8sKeyServiceUID               = "k8s.service.uid"
+	k8sKeyPersistentVolumeUID      = "k8s.persistentvolume.uid"
+	k8sKeyPVCUID                   = "k8s.persistentvolumeclaim.uid"
+	k8sKeyIngressUID               = "k8s.ingress.uid"
 
 	// Resource labels keys for Name.
 	k8sKeyCronJobName               = "k8s.cronjob.name"
@@ -64,6 +72,11 @@ const (
 	k8sKeyDaemonSetName             = "k8s.daemonset.name"
 	k8sKeyHPAName                   = "k8s.hpa.name"
 	k8sKeyResourceQuotaName         = "k8s.resourcequota.name"
+	k8sKeyServiceName               = "k8s.service.name"
+	k8sKeyPersistentVolumeName      = "k8s.persistentvolume.name"
+	k8sKeyPVCName                   = "k8s.persistentvolumeclaim.name"
+	k8sKeyIngressName               = "k8s.ingress.name"
+	k8sKeyStorageClassName          = "k8s.storageclass.name"
 
 	// Resource labels for container.
 	containerKeyID       = "container.id"
@@ -88,6 +101,8 @@ type DataCollector struct {
 	logger                 *zap.Logger
 	metricsStore           *metricsStore
 	metadataStore          *metadataStore
+	metadataUpdatesStore   *metadataUpdatesStore
+	eventsStore            *eventsStore
 	nodeConditionsToReport []string
 }
 
@@ -99,16 +114,29 @@ func NewDataCollector(logger *zap.Logger, nodeConditionsToReport []string) *Data
 			metricsCache: map[types.UID][]consumerdata.MetricsData{},
 		},
 		metadataStore:          &metadataStore{},
+		metadataUpdatesStore:   newMetadataUpdatesStore(),
+		eventsStore:            newEventsStore(),
 		nodeConditionsToReport: nodeConditionsToReport,
 	}
 }
 
 // SetupMetadataStore initializes a metadata store for the kubernetes object.
-func (dc *DataCollector) SetupMetadataStore(o runtime.Object, store cache.Store) {
-	dc.metadataStore.setupStore(o, store)
+// namespace is the namespace watched by the informer exposing the store, empty
+// if it watches all namespaces.
+func (dc *DataCollector) SetupMetadataStore(o runtime.Object, namespace string, store cache.Store) {
+	dc.metadataStore.setupStore(o, namespace, store)
 }
 
 func (dc *DataCollector) RemoveFromMetricsStore(obj interface{}) {
+	// Endpoints are reported along with their service, which is left without
+	// endpoints.
+	if e, ok := obj.(*corev1.Endpoints); ok {
+		if svc := dc.metadataStore.getService(e.Namespace, e.Name); svc != nil {
+			dc.UpdateMetricsStore(svc, getMetricsForService(svc, nil))
+		}
+		return
+	}
+
 	if err := dc.metricsStore.remove(obj.(runtime.Object)); err != nil {
 		dc.logger.Error(
 			"failed to remove from metric cache",
@@ -132,6 +160,31 @@ func (dc *DataCollector) CollectMetricData() []consumerdata.MetricsData {
 	return dc.metricsStore.getMetricData()
 }
 
+// SyncEvent records a new or updated event, to be emitted on the next collection.
+// Events already recorded with the same resource version are ignored.
+func (dc *DataCollector) SyncEvent(obj interface{}) {
+	if e, ok := obj.(*corev1.Event); ok {
+		dc.eventsStore.update(e)
+	}
+}
+
+// RemoveEvent forgets an event deleted from the API server.
+func (dc *DataCollector) RemoveEvent(obj interface{}) {
+	if e, ok := obj.(*corev1.Event); ok {
+		dc.eventsStore.remove(e)
+	}
+}
+
+// CollectEventData returns records of events recorded since the last collection.
+func (dc *DataCollector) CollectEventData() []consumerdata.MetricsData {
+	events := dc.eventsStore.drain()
+	out := make([]consumerdata.MetricsData, 0, len(events))
+	for _, e := range events {
+		out = append(out, getMetricsForEvent(e, dc.metadataStore))
+	}
+	return out
+}
+
 // SyncMetrics updates the metric store with latest metrics from the kubernetes object.
 func (dc *DataCollector) SyncMetrics(obj interface{}) {
 	var rm []*resourceMetrics
@@ -147,6 +200,22 @@ func (dc *DataCollector) SyncMetrics(obj interface{}) {
 		rm = getMetricsForReplicationController(o)
 	case *corev1.ResourceQuota:
 		rm = getMetricsForResourceQuota(o)
+	case *corev1.Service:
+		rm = getMetricsForService(o, dc.metadataStore.getEndpoints(o.Namespace, o.Name))
+	case *corev1.Endpoints:
+		// Endpoints are reported along with their service.
+		svc := dc.metadataStore.getService(o.Namespace, o.Name)
+		if svc == nil {
+			return
+		}
+		obj = svc
+		rm = getMetricsForService(svc, o)
+	case *corev1.PersistentVolume:
+		rm = getMetricsForPersistentVolume(o)
+	case *corev1.PersistentVolumeClaim:
+		rm = getMetricsForPersistentVolumeClaim(o)
+	case *networkingv1beta1.Ingress:
+		rm = getMetricsForIngress(o)
 	case *appsv1.Deployment:
 		rm = getMetricsForDeployment(o)
 	case *appsv1.ReplicaSet:
@@ -172,35 +241,74 @@ func (dc *DataCollector) SyncMetrics(obj interface{}) {
 	dc.UpdateMetricsStore(obj, rm)
 }
 
-// SyncMetadata updates the metric store with latest metrics from the kubernetes object
+// SyncMetadata updates the metadata updates store with latest metadata from
+// the kubernetes object. Changes are sent along with the next CollectMetadataUpdates.
 func (dc *DataCollector) SyncMetadata(obj interface{}) {
+	var km []*KubernetesMetadata
+
 	switch o := obj.(type) {
 	case *corev1.Pod:
-		_ = getMetadataForPod(o, dc.metadataStore)
+		km = getMetadataForPod(o, dc.metadataStore)
 	case *corev1.Node:
-		_ = getMetadataForNode(o)
+		km = getMetadataForNode(o)
 	case *corev1.ReplicationController:
-		_ = getMetadataForReplicationController(o)
+		km = getMetadataForReplicationController(o)
+	case *corev1.Service:
+		km = getMetadataForService(o)
+	case *corev1.PersistentVolume:
+		km = getMetadataForPersistentVolume(o)
+	case *corev1.PersistentVolumeClaim:
+		km = getMetadataForPersistentVolumeClaim(o)
+	case *networkingv1beta1.Ingress:
+		km = getMetadataForIngress(o)
 	case *appsv1.Deployment:
-		_ = getMetadataForDeployment(o)
+		km = getMetadataForDeployment(o)
 	case *appsv1.ReplicaSet:
-		_ = getMetadataForReplicaSet(o)
+		km = getMetadataForReplicaSet(o)
 	case *appsv1.DaemonSet:
-		_ = getMetadataForDaemonSet(o)
+		km = getMetadataForDaemonSet(o)
 	case *appsv1.StatefulSet:
-		_ = getMetadataForStatefulSet(o)
+		km = getMetadataForStatefulSet(o)
 	case *batchv1.Job:
-		_ = getPropertiesForJob(o)
+		km = getPropertiesForJob(o)
 	case *batchv1beta1.CronJob:
-		_ = getMetadataForCronJob(o)
+		km = getMetadataForCronJob(o)
 	case *v2beta1.HorizontalPodAutoscaler:
-		_ = getMetadataForHPA(o)
+		km = getMetadataForHPA(o)
 	default:
 		return
 	}
 
-	// TODO:
-	// 	1) Send properties along the pipeline
-	//  2) Handle properties from more than one source for the same resource
+	// TODO: Handle properties from more than one source for the same resource
 	// Github issue: https://github.com/open-telemetry/opentelemetry-collector-contrib/issues/180
+	key, err := utils.GetUIDForObject(obj.(runtime.Object))
+	if err != nil {
+		dc.logger.Error(
+			"failed to update metadata",
+			zap.String("obj", reflect.TypeOf(obj).String()),
+			zap.Error(err),
+		)
+		return
+	}
+	dc.metadataUpdatesStore.update(key, km)
+}
+
+// RemoveFromMetadataStore forgets the metadata of a deleted kubernetes object.
+func (dc *DataCollector) RemoveFromMetadataStore(obj interface{}) {
+	key, err := utils.GetUIDForObject(obj.(runtime.Object))
+	if err != nil {
+		dc.logger.Error(
+			"failed to remove from metadata cache",
+			zap.String("obj", reflect.TypeOf(obj).String()),
+			zap.Error(err),
+		)
+		return
+	}
+	dc.metadataUpdatesStore.remove(key)
+}
+
+// CollectMetadataUpdates returns changes to the metadata of objects synced since
+// the last collection. The first collection returns all properties of all objects.
+func (dc *DataCollector) CollectMetadataUpdates() []*resourcemetadata.MetadataUpdate {
+	return dc.metadataUpdatesStore.collect()
 }
@@ -27,6 +27,7 @@ const (
 	// Keys for container properties.
 	containerKeyStatus       = "container.status"
 	containerKeyStatusReason = "container.status.reason"
+	containerKeyInit         = "container.init"
 
 	// Values for container properties
 	containerStatusRunning    = "running"
@@ -53,8 +54,71 @@ var containerReadyMetric = &metricspb.MetricDescriptor{
 	Type:        metricspb.MetricDescriptor_GAUGE_INT64,
 }
 
+var containerWaitingReasonMetric = &metricspb.MetricDescriptor{
+	Name: "kubernetes/container/waiting_reason",
+	Description: "Whether the container is waiting for the reason (0 for no, 1 for yes). " +
+		"Common reasons are always reported, other reasons only while the container is waiting for them",
+	Type:      metricspb.MetricDescriptor_GAUGE_INT64,
+	LabelKeys: []*metricspb.LabelKey{{Key: "reason"}},
+}
+
+var containerTerminatedReasonMetric = &metricspb.MetricDescriptor{
+	Name: "kubernetes/container/terminated_reason",
+	Description: "Whether the container is terminated for the reason (0 for no, 1 for yes). " +
+		"Common reasons are always reported, other reasons only while the container is terminated for them",
+	Type:      metricspb.MetricDescriptor_GAUGE_INT64,
+	LabelKeys: []*metricspb.LabelKey{{Key: "reason"}},
+}
+
+var containerLastTerminatedReasonMetric = &metricspb.MetricDescriptor{
+	Name: "kubernetes/container/last_terminated_reason",
+	Description: "Whether the last termination of the container was for the reason (0 for no, 1 for yes). " +
+		"Common reasons are always reported, other reasons only while they are the last termination reason",
+	Type:      metricspb.MetricDescriptor_GAUGE_INT64,
+	LabelKeys: []*metricspb.LabelKey{{Key: "reason"}},
+}
+
+var containerLastTerminationExitCodeMetric = &metricspb.MetricDescriptor{
+	Name:        "kubernetes/container/last_termination_exit_code",
+	Description: "Exit code of the last termination of the container, not reported until it terminated once",
+	Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+}
+
+// Reasons reported by the kubelet for waiting and terminated containers, as
+// in kube-state-metrics.
+var (
+	containerWaitingReasons = []string{
+		"ContainerCreating",
+		"CrashLoopBackOff",
+		"CreateContainerConfigError",
+		"CreateContainerError",
+		"ErrImagePull",
+		"ImagePullBackOff",
+		"InvalidImageName",
+	}
+	containerTerminatedReasons = []string{
+		"Completed",
+		"ContainerCannotRun",
+		"DeadlineExceeded",
+		"Error",
+		"Evicted",
+		"OOMKilled",
+	}
+)
+
 // getStatusMetricsForContainer returns metrics about the status of the container.
 func getStatusMetricsForContainer(cs corev1.ContainerStatus) []*metricspb.Metric {
+	var waitingReason, terminatedReason, lastTerminatedReason string
+	if cs.State.Waiting != nil {
+		waitingReason = cs.State.Waiting.Reason
+	}
+	if cs.State.Terminated != nil {
+		terminatedReason = cs.State.Terminated.Reason
+	}
+	if cs.LastTerminationState.Terminated != nil {
+		lastTerminatedReason = cs.LastTerminationState.Terminated.Reason
+	}
+
 	metrics := []*metricspb.Metric{
 		{
 			MetricDescriptor: containerRestartMetric,
@@ -68,11 +132,50 @@ func getStatusMetricsForContainer(cs corev1.ContainerStatus) []*metricspb.Metric
 				utils.GetInt64TimeSeries(boolToInt64(cs.Ready)),
 			},
 		},
+		{
+			MetricDescriptor: containerWaitingReasonMetric,
+			Timeseries:       getReasonTimeSeries(containerWaitingReasons, waitingReason),
+		},
+		{
+			MetricDescriptor: containerTerminatedReasonMetric,
+			Timeseries:       getReasonTimeSeries(containerTerminatedReasons, terminatedReason),
+		},
+		{
+			MetricDescriptor: containerLastTerminatedReasonMetric,
+			Timeseries:       getReasonTimeSeries(containerTerminatedReasons, lastTerminatedReason),
+		},
+	}
+
+	if cs.LastTerminationState.Terminated != nil {
+		metrics = append(metrics, &metricspb.Metric{
+			MetricDescriptor: containerLastTerminationExitCodeMetric,
+			Timeseries: []*metricspb.TimeSeries{
+				utils.GetInt64TimeSeries(int64(cs.LastTerminationState.Terminated.ExitCode)),
+			},
+		})
 	}
 
 	return metrics
 }
 
+// getReasonTimeSeries returns a timeseries for each of the known reasons, set
+// to 1 for the current reason and 0 otherwise. The current reason is also
+// reported if it is not known.
+func getReasonTimeSeries(knownReasons []string, reason string) []*metricspb.TimeSeries {
+	out := make([]*metricspb.TimeSeries, 0, len(knownReasons)+1)
+	known := false
+	for _, r := range knownReasons {
+		known = known || r == reason
+		out = append(out, utils.GetInt64TimeSeriesWithLabels(
+			boolToInt64(r == reason), []*metricspb.LabelValue{{Value: r}}))
+	}
+	if !known && reason != "" {
+		out = append(out, utils.GetInt64TimeSeriesWithLabels(
+			1, []*metricspb.LabelValue{{Value: reason}}))
+	}
+	return out
+}
+
 func boolToInt64(b bool) int64 {
 	if b {
 		return 1
@@ -147,16 +250,24 @@ func getAllContainerLabels(cs corev1.ContainerStatus,
 
 	out := utils.CloneStringMap(dims)
 
-	out[containerKeyID] = utils.StripContainerID(cs.ContainerID)
+	// Containers that never started, e.g. waiting for their image to be pulled,
+	// don't have an ID yet. They are identified by the pod and their name.
+	if cs.ContainerID != "" {
+		out[containerKeyID] = utils.StripContainerID(cs.ContainerID)
+	}
 	out[containerKeySpecName] = cs.Name
 	out[conventions.AttributeContainerImage] = cs.Image
 
 	return out
 }
 
-func getMetadataForContainer(cs corev1.ContainerStatus) *KubernetesMetadata {
+func getMetadataForContainer(cs corev1.ContainerStatus, init bool) *KubernetesMetadata {
 	properties := map[string]string{}
 
+	if init {
+		properties[containerKeyInit] = "true"
+	}
+
 	if cs.State.Running != nil {
 		properties[containerKeyStatus] = containerStatusRunning
 	}
@@ -0,0 +1,243 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collection
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/open-telemetry/opentelemetry-collector/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-collector/translator/conventions"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/utils"
+)
+
+const (
+	// Label keys for event records.
+	k8sKeyEventReason  = "k8s.event.reason"
+	k8sKeyEventType    = "k8s.event.type"
+	k8sKeyEventMessage = "k8s.event.message"
+
+	k8sKindPod = "Pod"
+)
+
+// eventMetric describes an event record. The value of a record is the number
+// of times the event occurred, between the first (start timestamp) and last
+// (point timestamp) occurrence.
+var eventMetric = &metricspb.MetricDescriptor{
+	Name:        "kubernetes/event",
+	Description: "Number of occurrences of a Kubernetes event",
+	Type:        metricspb.MetricDescriptor_CUMULATIVE_INT64,
+	LabelKeys: []*metricspb.LabelKey{
+		{Key: k8sKeyEventReason},
+		{Key: k8sKeyEventType},
+		{Key: k8sKeyEventMessage},
+	},
+}
+
+// eventsStore keeps track of events that have not been emitted yet. Unlike
+// metrics, each version of an event is emitted only once. Informer resyncs and
+// relists deliver events that have already been seen with the same resource
+// version, those are ignored.
+type eventsStore struct {
+	sync.Mutex
+	// seen maps event UIDs to the last resource version recorded.
+	seen map[types.UID]string
+	// pending holds the latest version of each event to emit.
+	pending map[types.UID]*corev1.Event
+}
+
+func newEventsStore() *eventsStore {
+	return &eventsStore{
+		seen:    map[types.UID]string{},
+		pending: map[types.UID]*corev1.Event{},
+	}
+}
+
+// update records the event unless this version of it has already been recorded.
+func (es *eventsStore) update(event *corev1.Event) {
+	es.Lock()
+	defer es.Unlock()
+
+	if rv, ok := es.seen[event.UID]; ok && rv == event.ResourceVersion {
+		return
+	}
+	es.seen[event.UID] = event.ResourceVersion
+	es.pending[event.UID] = event
+}
+
+// remove forgets the event once it is deleted (expired) from the API server.
+// A pending version is still emitted.
+func (es *eventsStore) remove(event *corev1.Event) {
+	es.Lock()
+	defer es.Unlock()
+
+	delete(es.seen, event.UID)
+}
+
+// drain returns the pending events and clears them.
+func (es *eventsStore) drain() []*corev1.Event {
+	es.Lock()
+	defer es.Unlock()
+
+	out := make([]*corev1.Event, 0, len(es.pending))
+	for _, e := range es.pending {
+		out = append(out, e)
+	}
+	es.pending = map[types.UID]*corev1.Event{}
+	return out
+}
+
+// getMetricsForEvent returns a record of the event tied to the involved object.
+func getMetricsForEvent(event *corev1.Event, mc *metadataStore) consumerdata.MetricsData {
+	count := int64(event.Count)
+	// Events reported through the events.k8s.io API may not have a count.
+	if count == 0 {
+		count = 1
+	}
+
+	first := event.FirstTimestamp.Time
+	last := eventLastTimestamp(event)
+	if first.IsZero() {
+		first = last
+	}
+
+	return consumerdata.MetricsData{
+		Resource: getResourceForEvent(event, mc),
+		Metrics: []*metricspb.Metric{
+			{
+				MetricDescriptor: eventMetric,
+				Timeseries: []*metricspb.TimeSeries{
+					{
+						StartTimestamp: toTimestamp(first),
+						LabelValues: []*metricspb.LabelValue{
+							{Value: event.Reason, HasValue: event.Reason != ""},
+							{Value: event.Type, HasValue: event.Type != ""},
+							{Value: event.Message, HasValue: event.Message != ""},
+						},
+						Points: []*metricspb.Point{
+							{
+								Timestamp: toTimestamp(last),
+								Value:     &metricspb.Point_Int64Value{Int64Value: count},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// eventLastTimestamp returns when the event last occurred.
+func eventLastTimestamp(event *corev1.Event) time.Time {
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+	if !event.EventTime.IsZero() {
+		return event.EventTime.Time
+	}
+	return event.CreationTimestamp.Time
+}
+
+// getResourceForEvent returns a resource describing the object involved in the event.
+func getResourceForEvent(event *corev1.Event, mc *metadataStore) *resourcepb.Resource {
+	obj := event.InvolvedObject
+	kind := strings.ToLower(obj.Kind)
+
+	labels := map[string]string{
+		getResourceIDKey(kind):            string(obj.UID),
+		"k8s." + kind + ".name":           obj.Name,
+		conventions.AttributeK8sNamespace: obj.Namespace,
+		conventions.AttributeK8sCluster:   event.ClusterName,
+	}
+
+	if wKind, wName := getWorkloadForObject(obj, mc); wKind != "" {
+		labels[k8sKeyWorkLoadKind] = strings.ToLower(wKind)
+		labels[k8sKeyWorkLoadName] = wName
+	}
+
+	return &resourcepb.Resource{
+		Type:   k8sType,
+		Labels: labels,
+	}
+}
+
+// getWorkloadForObject resolves the top level workload managing the object, if any.
+func getWorkloadForObject(obj corev1.ObjectReference, mc *metadataStore) (string, string) {
+	switch obj.Kind {
+	case k8sKindPod:
+		pods := mc.pods.get(obj.Namespace)
+		if pods == nil {
+			return "", ""
+		}
+		o, ok, _ := pods.GetByKey(utils.GetIDForCache(obj.Namespace, obj.Name))
+		if !ok {
+			return "", ""
+		}
+		ref := v1.GetControllerOf(o.(*corev1.Pod))
+		if ref == nil {
+			return "", ""
+		}
+		return getWorkloadForOwner(obj.Namespace, ref.Kind, ref.Name, mc)
+	case k8sKindReplicaSet, k8sKindJob:
+		return getWorkloadForOwner(obj.Namespace, obj.Kind, obj.Name, mc)
+	case k8sKindDeployment, k8sKindDaemonSet, k8sStatefulSet, k8sKindCronJob, k8sKindReplicationController:
+		return obj.Kind, obj.Name
+	}
+	return "", ""
+}
+
+// getWorkloadForOwner returns the deployment or cron job owning a replica set or job, if
+// it can be found, or the owner itself otherwise.
+func getWorkloadForOwner(namespace, kind, name string, mc *metadataStore) (string, string) {
+	var ref *v1.OwnerReference
+	switch kind {
+	case k8sKindReplicaSet:
+		if replicaSets := mc.replicaSets.get(namespace); replicaSets != nil {
+			if o, ok, _ := replicaSets.GetByKey(utils.GetIDForCache(namespace, name)); ok {
+				ref = utils.FindOwnerWithKind(o.(*appsv1.ReplicaSet).OwnerReferences, k8sKindDeployment)
+			}
+		}
+	case k8sKindJob:
+		if jobs := mc.jobs.get(namespace); jobs != nil {
+			if o, ok, _ := jobs.GetByKey(utils.GetIDForCache(namespace, name)); ok {
+				ref = utils.FindOwnerWithKind(o.(*batchv1.Job).OwnerReferences, k8sKindCronJob)
+			}
+		}
+	}
+	if ref != nil {
+		return ref.Kind, ref.Name
+	}
+	return kind, name
+}
+
+func toTimestamp(t time.Time) *timestamp.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return &timestamp.Timestamp{
+		Seconds: t.Unix(),
+		Nanos:   int32(t.Nanosecond()),
+	}
+}
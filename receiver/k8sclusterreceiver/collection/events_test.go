@@ -0,0 +1,168 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collection
+
+import (
+	"testing"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/testutils"
+)
+
+func TestEventMetrics(t *testing.T) {
+	pods := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	replicaSets := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	pod := newPodWithContainer("1")
+	pod.OwnerReferences = []v1.OwnerReference{controllerRef(k8sKindReplicaSet, "test-rs")}
+	require.NoError(t, pods.Add(pod))
+	require.NoError(t, replicaSets.Add(&appsv1.ReplicaSet{
+		ObjectMeta: v1.ObjectMeta{
+			Name:            "test-rs",
+			Namespace:       "test-namespace",
+			OwnerReferences: []v1.OwnerReference{controllerRef(k8sKindDeployment, "test-deployment")},
+		},
+	}))
+
+	ms := &metadataStore{}
+	ms.setupStore(&corev1.Pod{}, "", pods)
+	ms.setupStore(&appsv1.ReplicaSet{}, "test-namespace", replicaSets)
+
+	md := getMetricsForEvent(newEvent("1", "1"), ms)
+
+	testutils.AssertResource(t, *md.Resource, k8sType,
+		map[string]string{
+			"k8s.pod.uid":        "test-pod-1-uid",
+			"k8s.pod.name":       "test-pod-1",
+			"k8s.namespace.name": "test-namespace",
+			"k8s.cluster.name":   "test-cluster",
+			"k8s.workload.kind":  "deployment",
+			"k8s.workload.name":  "test-deployment",
+		},
+	)
+
+	require.Equal(t, 1, len(md.Metrics))
+	testutils.AssertMetricsWithLabels(t, *md.Metrics[0], "kubernetes/event",
+		metricspb.MetricDescriptor_CUMULATIVE_INT64,
+		map[string]string{
+			"k8s.event.reason":  "BackOff",
+			"k8s.event.type":    "Warning",
+			"k8s.event.message": "Back-off restarting failed container",
+		}, 3)
+
+	ts := md.Metrics[0].Timeseries[0]
+	require.Equal(t, int64(1000), ts.StartTimestamp.Seconds)
+	require.Equal(t, int64(2000), ts.Points[0].Timestamp.Seconds)
+}
+
+func TestEventWorkloads(t *testing.T) {
+	tests := []struct {
+		name         string
+		object       corev1.ObjectReference
+		expectedKind string
+		expectedName string
+	}{
+		{
+			name:   "Pod not found",
+			object: corev1.ObjectReference{Kind: "Pod", Namespace: "test-namespace", Name: "test-pod"},
+		},
+		{
+			name:         "ReplicaSet not found",
+			object:       corev1.ObjectReference{Kind: "ReplicaSet", Namespace: "test-namespace", Name: "test-rs"},
+			expectedKind: "ReplicaSet",
+			expectedName: "test-rs",
+		},
+		{
+			name:         "StatefulSet",
+			object:       corev1.ObjectReference{Kind: "StatefulSet", Namespace: "test-namespace", Name: "test-ss"},
+			expectedKind: "StatefulSet",
+			expectedName: "test-ss",
+		},
+		{
+			name:   "Node",
+			object: corev1.ObjectReference{Kind: "Node", Name: "test-node"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind, name := getWorkloadForObject(tt.object, &metadataStore{})
+			require.Equal(t, tt.expectedKind, kind)
+			require.Equal(t, tt.expectedName, name)
+		})
+	}
+}
+
+func TestCollectEventData(t *testing.T) {
+	dc := NewDataCollector(zap.NewNop(), nil)
+
+	dc.SyncEvent(newEvent("1", "1"))
+	dc.SyncEvent(newEvent("2", "1"))
+	require.Equal(t, 2, len(dc.CollectEventData()))
+
+	// Events are emitted only once.
+	require.Equal(t, 0, len(dc.CollectEventData()))
+
+	// Resyncs deliver the same version of events again.
+	dc.SyncEvent(newEvent("1", "1"))
+	require.Equal(t, 0, len(dc.CollectEventData()))
+
+	// Only the latest version of an updated event is emitted.
+	dc.SyncEvent(newEvent("1", "2"))
+	dc.SyncEvent(newEvent("1", "3"))
+	require.Equal(t, 1, len(dc.CollectEventData()))
+
+	// Events are forgotten once deleted.
+	dc.RemoveEvent(newEvent("2", "1"))
+	dc.SyncEvent(newEvent("2", "1"))
+	require.Equal(t, 1, len(dc.CollectEventData()))
+}
+
+func newEvent(id, resourceVersion string) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta: v1.ObjectMeta{
+			Name:            "test-pod-1.event-" + id,
+			Namespace:       "test-namespace",
+			UID:             types.UID("test-event-" + id + "-uid"),
+			ResourceVersion: resourceVersion,
+			ClusterName:     "test-cluster",
+		},
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      "Pod",
+			Namespace: "test-namespace",
+			Name:      "test-pod-1",
+			UID:       types.UID("test-pod-1-uid"),
+		},
+		Reason:         "BackOff",
+		Type:           corev1.EventTypeWarning,
+		Message:        "Back-off restarting failed container",
+		Count:          3,
+		FirstTimestamp: v1.NewTime(time.Unix(1000, 0)),
+		LastTimestamp:  v1.NewTime(time.Unix(2000, 0)),
+	}
+}
+
+func controllerRef(kind, name string) v1.OwnerReference {
+	isController := true
+	return v1.OwnerReference{Kind: kind, Name: name, Controller: &isController}
+}
@@ -0,0 +1,110 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collection
+
+import (
+	"sort"
+	"strings"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"github.com/open-telemetry/opentelemetry-collector/translator/conventions"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/utils"
+)
+
+const (
+	// Keys for ingress properties.
+	ingressKeyHosts = "ingress.hosts"
+)
+
+var ingressRulesMetric = &metricspb.MetricDescriptor{
+	Name:        "kubernetes/ingress/rules",
+	Description: "Number of rules of the ingress",
+	Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+}
+
+var ingressLoadBalancerIngressesMetric = &metricspb.MetricDescriptor{
+	Name:        "kubernetes/ingress/load_balancer_ingresses",
+	Description: "Number of load balancer ingress points of the ingress, 0 until the ingress controller exposed it",
+	Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+}
+
+func getMetricsForIngress(ing *networkingv1beta1.Ingress) []*resourceMetrics {
+	return []*resourceMetrics{
+		{
+			resource: getResourceForIngress(ing),
+			metrics: []*metricspb.Metric{
+				{
+					MetricDescriptor: ingressRulesMetric,
+					Timeseries: []*metricspb.TimeSeries{
+						utils.GetInt64TimeSeries(int64(len(ing.Spec.Rules))),
+					},
+				},
+				{
+					MetricDescriptor: ingressLoadBalancerIngressesMetric,
+					Timeseries: []*metricspb.TimeSeries{
+						utils.GetInt64TimeSeries(int64(len(ing.Status.LoadBalancer.Ingress))),
+					},
+				},
+			},
+		},
+	}
+}
+
+func getResourceForIngress(ing *networkingv1beta1.Ingress) *resourcepb.Resource {
+	return &resourcepb.Resource{
+		Type: k8sType,
+		Labels: map[string]string{
+			k8sKeyIngressUID:                  string(ing.UID),
+			k8sKeyIngressName:                 ing.Name,
+			conventions.AttributeK8sNamespace: ing.Namespace,
+			conventions.AttributeK8sCluster:   ing.ClusterName,
+		},
+	}
+}
+
+// getMetadataForIngress returns the properties of the ingress, along with
+// the hosts it serves and the services it routes traffic to.
+func getMetadataForIngress(ing *networkingv1beta1.Ingress) []*KubernetesMetadata {
+	km := getObjectMetadata(&ing.ObjectMeta, "ingress")
+
+	var hosts []string
+	addBackend := func(b *networkingv1beta1.IngressBackend) {
+		if b != nil && b.ServiceName != "" {
+			km.properties["kubernetes_service_"+b.ServiceName] = ""
+		}
+	}
+	addBackend(ing.Spec.Backend)
+	for _, rule := range ing.Spec.Rules {
+		if rule.Host != "" {
+			hosts = append(hosts, rule.Host)
+		}
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			addBackend(&path.Backend)
+		}
+	}
+
+	if len(hosts) > 0 {
+		sort.Strings(hosts)
+		km.properties[ingressKeyHosts] = strings.Join(hosts, ",")
+	}
+
+	return []*KubernetesMetadata{km}
+}
@@ -0,0 +1,108 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collection
+
+import (
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/testutils"
+)
+
+func TestIngressMetrics(t *testing.T) {
+	ing := newIngress("1")
+
+	actualResourceMetrics := getMetricsForIngress(ing)
+
+	require.Equal(t, 1, len(actualResourceMetrics))
+	require.Equal(t, 2, len(actualResourceMetrics[0].metrics))
+
+	rm := actualResourceMetrics[0]
+	testutils.AssertResource(t, *rm.resource, k8sType,
+		map[string]string{
+			"k8s.ingress.uid":    "test-ingress-1-uid",
+			"k8s.ingress.name":   "test-ingress-1",
+			"k8s.namespace.name": "test-namespace",
+			"k8s.cluster.name":   "test-cluster",
+		},
+	)
+
+	testutils.AssertMetrics(t, *rm.metrics[0], "kubernetes/ingress/rules",
+		metricspb.MetricDescriptor_GAUGE_INT64, 2)
+
+	testutils.AssertMetrics(t, *rm.metrics[1], "kubernetes/ingress/load_balancer_ingresses",
+		metricspb.MetricDescriptor_GAUGE_INT64, 1)
+}
+
+func TestIngressMetadata(t *testing.T) {
+	require.Equal(t,
+		[]*KubernetesMetadata{
+			{
+				resourceIDKey: "k8s.ingress.uid",
+				resourceID:    "test-ingress-1-uid",
+				properties: map[string]string{
+					"ingress.creation_timestamp": "0001-01-01T00:00:00Z",
+					"ingress.hosts":              "api.example.com,www.example.com",
+					"kubernetes_service_default": "",
+					"kubernetes_service_api":     "",
+					"kubernetes_service_www":     "",
+				},
+			},
+		},
+		getMetadataForIngress(newIngress("1")),
+	)
+}
+
+func newIngress(id string) *networkingv1beta1.Ingress {
+	return &networkingv1beta1.Ingress{
+		ObjectMeta: v1.ObjectMeta{
+			Name:        "test-ingress-" + id,
+			Namespace:   "test-namespace",
+			UID:         types.UID("test-ingress-" + id + "-uid"),
+			ClusterName: "test-cluster",
+		},
+		Spec: networkingv1beta1.IngressSpec{
+			Backend: &networkingv1beta1.IngressBackend{ServiceName: "default"},
+			Rules: []networkingv1beta1.IngressRule{
+				newIngressRule("www.example.com", "www"),
+				newIngressRule("api.example.com", "api"),
+			},
+		},
+		Status: networkingv1beta1.IngressStatus{
+			LoadBalancer: corev1.LoadBalancerStatus{
+				Ingress: []corev1.LoadBalancerIngress{{IP: "192.168.0.1"}},
+			},
+		},
+	}
+}
+
+func newIngressRule(host, service string) networkingv1beta1.IngressRule {
+	return networkingv1beta1.IngressRule{
+		Host: host,
+		IngressRuleValue: networkingv1beta1.IngressRuleValue{
+			HTTP: &networkingv1beta1.HTTPIngressRuleValue{
+				Paths: []networkingv1beta1.HTTPIngressPath{
+					{Path: "/", Backend: networkingv1beta1.IngressBackend{ServiceName: service}},
+				},
+			},
+		},
+	}
+}
@@ -21,6 +21,7 @@ import (
 
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/resourcemetadata"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/utils"
 )
 
@@ -38,14 +39,80 @@ type KubernetesMetadata struct {
 	properties    map[string]string
 }
 
+// getMetadataUpdate returns the changes between two sets of metadata of an object,
+// keyed by resource ID. Resources that are no longer part of the object are ignored,
+// resources that are new to the object have all their properties added.
+func getMetadataUpdate(oldMetadata, newMetadata map[string]*KubernetesMetadata) []*resourcemetadata.MetadataUpdate {
+	var out []*resourcemetadata.MetadataUpdate
+
+	for id, newM := range newMetadata {
+		var oldProperties map[string]string
+		if oldM, ok := oldMetadata[id]; ok {
+			oldProperties = oldM.properties
+		}
+
+		delta := getMetadataDelta(oldProperties, newM.properties)
+		if delta == nil {
+			continue
+		}
+		out = append(out, &resourcemetadata.MetadataUpdate{
+			ResourceIDKey: newM.resourceIDKey,
+			ResourceID:    newM.resourceID,
+			MetadataDelta: *delta,
+		})
+	}
+
+	return out
+}
+
+// getMetadataDelta returns the changes between two sets of properties, nil
+// if there are none.
+func getMetadataDelta(oldProperties, newProperties map[string]string) *resourcemetadata.MetadataDelta {
+	toAdd, toRemove, toUpdate := map[string]string{}, map[string]string{}, map[string]string{}
+
+	for k, v := range newProperties {
+		oldV, ok := oldProperties[k]
+		switch {
+		case !ok:
+			toAdd[k] = v
+		case oldV != v:
+			toUpdate[k] = v
+		}
+	}
+	for k, v := range oldProperties {
+		if _, ok := newProperties[k]; !ok {
+			toRemove[k] = v
+		}
+	}
+
+	if len(toAdd) == 0 && len(toRemove) == 0 && len(toUpdate) == 0 {
+		return nil
+	}
+	return &resourcemetadata.MetadataDelta{
+		MetadataToAdd:    toAdd,
+		MetadataToRemove: toRemove,
+		MetadataToUpdate: toUpdate,
+	}
+}
+
 // getGenericMetadata is responsible for collecting metadata from K8s resources that
 // live on v1.ObjectMeta.
 func getGenericMetadata(om *v1.ObjectMeta, resourceType string) *KubernetesMetadata {
+	km := getObjectMetadata(om, resourceType)
+
+	rType := strings.ToLower(resourceType)
+	km.properties[k8sKeyWorkLoadKind] = rType
+	km.properties[k8sKeyWorkLoadName] = om.Name
+
+	return km
+}
+
+// getObjectMetadata returns the labels, creation timestamp and owners of
+// any kubernetes object.
+func getObjectMetadata(om *v1.ObjectMeta, resourceType string) *KubernetesMetadata {
 	rType := strings.ToLower(resourceType)
 	properties := utils.MergeStringMaps(map[string]string{}, om.Labels)
 
-	properties[k8sKeyWorkLoadKind] = rType
-	properties[k8sKeyWorkLoadName] = om.Name
 	properties[fmt.Sprintf("%s.creation_timestamp",
 		rType)] = om.GetCreationTimestamp().Format(time.RFC3339)
 
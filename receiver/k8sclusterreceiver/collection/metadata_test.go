@@ -20,6 +20,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/resourcemetadata"
 )
 
 func Test_getGenericMetadata(t *testing.T) {
@@ -63,3 +65,96 @@ func Test_getGenericMetadata(t *testing.T) {
 		"foo1":                            "",
 	}, rm.properties)
 }
+
+func Test_getMetadataUpdate(t *testing.T) {
+	oldMetadata := map[string]*KubernetesMetadata{
+		"pod-uid": {
+			resourceIDKey: "k8s.pod.uid",
+			resourceID:    "pod-uid",
+			properties: map[string]string{
+				"foo":  "bar",
+				"foo1": "bar1",
+				"foo2": "bar2",
+			},
+		},
+		"container-id-1": {
+			resourceIDKey: "container.id",
+			resourceID:    "container-id-1",
+			properties:    map[string]string{"container.status": "running"},
+		},
+	}
+	newMetadata := map[string]*KubernetesMetadata{
+		"pod-uid": {
+			resourceIDKey: "k8s.pod.uid",
+			resourceID:    "pod-uid",
+			properties: map[string]string{
+				"foo":  "bar",
+				"foo1": "bar3",
+				"foo3": "",
+			},
+		},
+		"container-id-2": {
+			resourceIDKey: "container.id",
+			resourceID:    "container-id-2",
+			properties:    map[string]string{"container.status": "running"},
+		},
+	}
+
+	updates := getMetadataUpdate(oldMetadata, newMetadata)
+
+	assert.ElementsMatch(t, []*resourcemetadata.MetadataUpdate{
+		{
+			ResourceIDKey: "k8s.pod.uid",
+			ResourceID:    "pod-uid",
+			MetadataDelta: resourcemetadata.MetadataDelta{
+				MetadataToAdd:    map[string]string{"foo3": ""},
+				MetadataToRemove: map[string]string{"foo2": "bar2"},
+				MetadataToUpdate: map[string]string{"foo1": "bar3"},
+			},
+		},
+		{
+			ResourceIDKey: "container.id",
+			ResourceID:    "container-id-2",
+			MetadataDelta: resourcemetadata.MetadataDelta{
+				MetadataToAdd:    map[string]string{"container.status": "running"},
+				MetadataToRemove: map[string]string{},
+				MetadataToUpdate: map[string]string{},
+			},
+		},
+	}, updates)
+
+	// No changes.
+	assert.Empty(t, getMetadataUpdate(newMetadata, newMetadata))
+}
+
+func TestMetadataUpdatesStore(t *testing.T) {
+	ms := newMetadataUpdatesStore()
+	metadata := func(value string) []*KubernetesMetadata {
+		return []*KubernetesMetadata{{
+			resourceIDKey: "k8s.pod.uid",
+			resourceID:    "pod-uid",
+			properties:    map[string]string{"foo": value},
+		}}
+	}
+
+	// Changes are coalesced until collected.
+	ms.update("pod-uid", metadata("bar"))
+	ms.update("pod-uid", metadata("bar1"))
+	updates := ms.collect()
+	assert.Equal(t, 1, len(updates))
+	assert.Equal(t, map[string]string{"foo": "bar1"}, updates[0].MetadataToAdd)
+
+	// Nothing changed since the last collection.
+	ms.update("pod-uid", metadata("bar1"))
+	assert.Empty(t, ms.collect())
+
+	ms.update("pod-uid", metadata("bar2"))
+	updates = ms.collect()
+	assert.Equal(t, 1, len(updates))
+	assert.Equal(t, map[string]string{"foo": "bar2"}, updates[0].MetadataToUpdate)
+
+	// Deleted objects are forgotten.
+	ms.update("pod-uid", metadata("bar3"))
+	ms.remove("pod-uid")
+	assert.Empty(t, ms.collect())
+}
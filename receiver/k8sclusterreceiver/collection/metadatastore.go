@@ -15,30 +15,100 @@
 package collection
 
 import (
+	"sync"
+
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/cache"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/utils"
 )
 
 // metadataStore keeps track of required caches exposed by informers.
 // This store is used while collecting metadata about Pods to be able
 // to correlate other Kubernetes objects with a Pod.
 type metadataStore struct {
-	services    cache.Store
-	jobs        cache.Store
-	replicaSets cache.Store
+	services    namespacedStores
+	endpoints   namespacedStores
+	jobs        namespacedStores
+	replicaSets namespacedStores
+	pods        namespacedStores
 }
 
-// setupStore tracks metadata of services, jobs and replicasets.
-func (ms *metadataStore) setupStore(o runtime.Object, store cache.Store) {
+// setupStore tracks metadata of services, endpoints, jobs, replicasets and pods.
+// namespace is the namespace watched by the informer exposing the
+// store, empty if it watches all namespaces.
+func (ms *metadataStore) setupStore(o runtime.Object, namespace string, store cache.Store) {
 	switch o.(type) {
 	case *corev1.Service:
-		ms.services = store
+		ms.services.set(namespace, store)
+	case *corev1.Endpoints:
+		ms.endpoints.set(namespace, store)
 	case *batchv1.Job:
-		ms.jobs = store
+		ms.jobs.set(namespace, store)
 	case *appsv1.ReplicaSet:
-		ms.replicaSets = store
+		ms.replicaSets.set(namespace, store)
+	case *corev1.Pod:
+		ms.pods.set(namespace, store)
+	}
+}
+
+// getService returns the cached service, nil if it's not known.
+func (ms *metadataStore) getService(namespace, name string) *corev1.Service {
+	if obj := ms.services.getByKey(namespace, name); obj != nil {
+		return obj.(*corev1.Service)
+	}
+	return nil
+}
+
+// getEndpoints returns the cached endpoints of a service, nil if they're not known.
+func (ms *metadataStore) getEndpoints(namespace, name string) *corev1.Endpoints {
+	if obj := ms.endpoints.getByKey(namespace, name); obj != nil {
+		return obj.(*corev1.Endpoints)
+	}
+	return nil
+}
+
+// namespacedStores holds the stores of a kind of resource by the namespace
+// watched by their informers.
+type namespacedStores struct {
+	sync.RWMutex
+	stores map[string]cache.Store
+}
+
+func (ns *namespacedStores) set(namespace string, store cache.Store) {
+	ns.Lock()
+	defer ns.Unlock()
+
+	if ns.stores == nil {
+		ns.stores = map[string]cache.Store{}
+	}
+	ns.stores[namespace] = store
+}
+
+// get returns the store holding objects of the namespace, nil if there's none.
+func (ns *namespacedStores) get(namespace string) cache.Store {
+	ns.RLock()
+	defer ns.RUnlock()
+
+	if store, ok := ns.stores[namespace]; ok {
+		return store
+	}
+	return ns.stores[""]
+}
+
+// getByKey returns the object of the namespace with the name, nil if it's
+// not in the store or there's no store.
+func (ns *namespacedStores) getByKey(namespace, name string) interface{} {
+	store := ns.get(namespace)
+	if store == nil {
+		return nil
+	}
+	obj, ok, _ := store.GetByKey(utils.GetIDForCache(namespace, name))
+	if !ok {
+		return nil
 	}
+	return obj
 }
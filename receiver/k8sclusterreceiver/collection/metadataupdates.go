@@ -0,0 +1,85 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collection
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/resourcemetadata"
+)
+
+// metadataUpdatesStore keeps track of the latest metadata of objects and of
+// the metadata last sent to metadata exporters. Changes to an object are
+// coalesced until updates are collected, so that an object updated many times
+// within a collection interval results in a single update per resource.
+type metadataUpdatesStore struct {
+	sync.Mutex
+	// current maps object UIDs to the latest metadata of the object, keyed by resource ID.
+	current map[types.UID]map[string]*KubernetesMetadata
+	// sent maps object UIDs to the metadata last sent for the object.
+	sent map[types.UID]map[string]*KubernetesMetadata
+	// dirty holds UIDs of objects changed since the last collection.
+	dirty map[types.UID]bool
+}
+
+func newMetadataUpdatesStore() *metadataUpdatesStore {
+	return &metadataUpdatesStore{
+		current: map[types.UID]map[string]*KubernetesMetadata{},
+		sent:    map[types.UID]map[string]*KubernetesMetadata{},
+		dirty:   map[types.UID]bool{},
+	}
+}
+
+// update records the latest metadata of an object.
+func (ms *metadataUpdatesStore) update(uid types.UID, metadata []*KubernetesMetadata) {
+	ms.Lock()
+	defer ms.Unlock()
+
+	m := make(map[string]*KubernetesMetadata, len(metadata))
+	for _, km := range metadata {
+		m[km.resourceID] = km
+	}
+	ms.current[uid] = m
+	ms.dirty[uid] = true
+}
+
+// remove forgets a deleted object. Properties of deleted resources are not
+// removed from metadata exporters on purpose: their IDs are never reused and
+// the properties still describe the data reported while the resources existed.
+func (ms *metadataUpdatesStore) remove(uid types.UID) {
+	ms.Lock()
+	defer ms.Unlock()
+
+	delete(ms.current, uid)
+	delete(ms.sent, uid)
+	delete(ms.dirty, uid)
+}
+
+// collect returns updates for objects changed since the last collection.
+func (ms *metadataUpdatesStore) collect() []*resourcemetadata.MetadataUpdate {
+	ms.Lock()
+	defer ms.Unlock()
+
+	var out []*resourcemetadata.MetadataUpdate
+	for uid := range ms.dirty {
+		out = append(out, getMetadataUpdate(ms.sent[uid], ms.current[uid])...)
+		ms.sent[uid] = ms.current[uid]
+	}
+	ms.dirty = map[types.UID]bool{}
+
+	return out
+}
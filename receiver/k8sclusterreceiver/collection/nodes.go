@@ -75,7 +75,7 @@ func getResourceForNode(node *corev1.Node) *resourcepb.Resource {
 	}
 }
 
-var nodeConditionValues = map[corev1.ConditionStatus]int64{
+var conditionValues = map[corev1.ConditionStatus]int64{
 	corev1.ConditionTrue:    1,
 	corev1.ConditionFalse:   0,
 	corev1.ConditionUnknown: -1,
@@ -89,7 +89,7 @@ func nodeConditionValue(node *corev1.Node, condType corev1.NodeConditionType) in
 			break
 		}
 	}
-	return nodeConditionValues[status]
+	return conditionValues[status]
 }
 
 func getMetadataForNode(node *corev1.Node) []*KubernetesMetadata {
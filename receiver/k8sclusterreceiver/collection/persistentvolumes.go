@@ -0,0 +1,206 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collection
+
+import (
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"github.com/open-telemetry/opentelemetry-collector/translator/conventions"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/utils"
+)
+
+const (
+	// Keys for persistent volume and claim properties.
+	pvKeyReclaimPolicy = "persistentvolume.reclaim_policy"
+	pvcKeyVolumeName   = "persistentvolumeclaim.volume_name"
+)
+
+var persistentVolumePhaseMetric = &metricspb.MetricDescriptor{
+	Name: "kubernetes/persistent_volume/phase",
+	Description: "Current phase of the persistent volume (1 - Pending, 2 - Available, 3 - Bound, " +
+		"4 - Released, 5 - Failed, -1 - Unknown)",
+	Type: metricspb.MetricDescriptor_GAUGE_INT64,
+}
+
+var persistentVolumeCapacityMetric = &metricspb.MetricDescriptor{
+	Name:        "kubernetes/persistent_volume/capacity",
+	Description: "Storage capacity of the persistent volume, in bytes",
+	Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+}
+
+var persistentVolumeClaimPhaseMetric = &metricspb.MetricDescriptor{
+	Name:        "kubernetes/persistent_volume_claim/phase",
+	Description: "Current phase of the persistent volume claim (1 - Pending, 2 - Bound, 3 - Lost, -1 - Unknown)",
+	Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+}
+
+var persistentVolumeClaimRequestMetric = &metricspb.MetricDescriptor{
+	Name:        "kubernetes/persistent_volume_claim/request",
+	Description: "Storage requested by the persistent volume claim, in bytes",
+	Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+}
+
+var persistentVolumeClaimCapacityMetric = &metricspb.MetricDescriptor{
+	Name:        "kubernetes/persistent_volume_claim/capacity",
+	Description: "Storage capacity of the volume bound to the persistent volume claim, in bytes. Only sent once bound",
+	Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+}
+
+var persistentVolumePhaseValues = map[corev1.PersistentVolumePhase]int64{
+	corev1.VolumePending:   1,
+	corev1.VolumeAvailable: 2,
+	corev1.VolumeBound:     3,
+	corev1.VolumeReleased:  4,
+	corev1.VolumeFailed:    5,
+}
+
+var persistentVolumeClaimPhaseValues = map[corev1.PersistentVolumeClaimPhase]int64{
+	corev1.ClaimPending: 1,
+	corev1.ClaimBound:   2,
+	corev1.ClaimLost:    3,
+}
+
+func getMetricsForPersistentVolume(pv *corev1.PersistentVolume) []*resourceMetrics {
+	phase, ok := persistentVolumePhaseValues[pv.Status.Phase]
+	if !ok {
+		phase = -1
+	}
+
+	metrics := []*metricspb.Metric{
+		{
+			MetricDescriptor: persistentVolumePhaseMetric,
+			Timeseries: []*metricspb.TimeSeries{
+				utils.GetInt64TimeSeries(phase),
+			},
+		},
+	}
+
+	if capacity, ok := pv.Spec.Capacity[corev1.ResourceStorage]; ok {
+		metrics = append(metrics, &metricspb.Metric{
+			MetricDescriptor: persistentVolumeCapacityMetric,
+			Timeseries: []*metricspb.TimeSeries{
+				utils.GetInt64TimeSeries(capacity.Value()),
+			},
+		})
+	}
+
+	return []*resourceMetrics{
+		{
+			resource: getResourceForPersistentVolume(pv),
+			metrics:  metrics,
+		},
+	}
+}
+
+func getResourceForPersistentVolume(pv *corev1.PersistentVolume) *resourcepb.Resource {
+	labels := map[string]string{
+		k8sKeyPersistentVolumeUID:       string(pv.UID),
+		k8sKeyPersistentVolumeName:      pv.Name,
+		conventions.AttributeK8sCluster: pv.ClusterName,
+	}
+	if pv.Spec.StorageClassName != "" {
+		labels[k8sKeyStorageClassName] = pv.Spec.StorageClassName
+	}
+
+	return &resourcepb.Resource{
+		Type:   k8sType,
+		Labels: labels,
+	}
+}
+
+func getMetadataForPersistentVolume(pv *corev1.PersistentVolume) []*KubernetesMetadata {
+	km := getObjectMetadata(&pv.ObjectMeta, "persistentvolume")
+	if pv.Spec.StorageClassName != "" {
+		km.properties[k8sKeyStorageClassName] = pv.Spec.StorageClassName
+	}
+	km.properties[pvKeyReclaimPolicy] = string(pv.Spec.PersistentVolumeReclaimPolicy)
+	if ref := pv.Spec.ClaimRef; ref != nil {
+		km.properties[k8sKeyPVCName] = ref.Name
+		km.properties[k8sKeyPVCUID] = string(ref.UID)
+		km.properties[conventions.AttributeK8sNamespace] = ref.Namespace
+	}
+	return []*KubernetesMetadata{km}
+}
+
+func getMetricsForPersistentVolumeClaim(pvc *corev1.PersistentVolumeClaim) []*resourceMetrics {
+	phase, ok := persistentVolumeClaimPhaseValues[pvc.Status.Phase]
+	if !ok {
+		phase = -1
+	}
+
+	metrics := []*metricspb.Metric{
+		{
+			MetricDescriptor: persistentVolumeClaimPhaseMetric,
+			Timeseries: []*metricspb.TimeSeries{
+				utils.GetInt64TimeSeries(phase),
+			},
+		},
+	}
+
+	if request, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+		metrics = append(metrics, &metricspb.Metric{
+			MetricDescriptor: persistentVolumeClaimRequestMetric,
+			Timeseries: []*metricspb.TimeSeries{
+				utils.GetInt64TimeSeries(request.Value()),
+			},
+		})
+	}
+
+	if capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+		metrics = append(metrics, &metricspb.Metric{
+			MetricDescriptor: persistentVolumeClaimCapacityMetric,
+			Timeseries: []*metricspb.TimeSeries{
+				utils.GetInt64TimeSeries(capacity.Value()),
+			},
+		})
+	}
+
+	return []*resourceMetrics{
+		{
+			resource: getResourceForPersistentVolumeClaim(pvc),
+			metrics:  metrics,
+		},
+	}
+}
+
+func getResourceForPersistentVolumeClaim(pvc *corev1.PersistentVolumeClaim) *resourcepb.Resource {
+	labels := map[string]string{
+		k8sKeyPVCUID:                      string(pvc.UID),
+		k8sKeyPVCName:                     pvc.Name,
+		conventions.AttributeK8sNamespace: pvc.Namespace,
+		conventions.AttributeK8sCluster:   pvc.ClusterName,
+	}
+	if pvc.Spec.StorageClassName != nil && *pvc.Spec.StorageClassName != "" {
+		labels[k8sKeyStorageClassName] = *pvc.Spec.StorageClassName
+	}
+
+	return &resourcepb.Resource{
+		Type:   k8sType,
+		Labels: labels,
+	}
+}
+
+func getMetadataForPersistentVolumeClaim(pvc *corev1.PersistentVolumeClaim) []*KubernetesMetadata {
+	km := getObjectMetadata(&pvc.ObjectMeta, "persistentvolumeclaim")
+	if pvc.Spec.StorageClassName != nil && *pvc.Spec.StorageClassName != "" {
+		km.properties[k8sKeyStorageClassName] = *pvc.Spec.StorageClassName
+	}
+	if pvc.Spec.VolumeName != "" {
+		km.properties[pvcKeyVolumeName] = pvc.Spec.VolumeName
+	}
+	return []*KubernetesMetadata{km}
+}
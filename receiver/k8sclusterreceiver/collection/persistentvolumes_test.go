@@ -0,0 +1,188 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collection
+
+import (
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/testutils"
+)
+
+func TestPersistentVolumeMetrics(t *testing.T) {
+	pv := newPersistentVolume("1")
+
+	actualResourceMetrics := getMetricsForPersistentVolume(pv)
+
+	require.Equal(t, 1, len(actualResourceMetrics))
+	require.Equal(t, 2, len(actualResourceMetrics[0].metrics))
+
+	rm := actualResourceMetrics[0]
+	testutils.AssertResource(t, *rm.resource, k8sType,
+		map[string]string{
+			"k8s.persistentvolume.uid":  "test-pv-1-uid",
+			"k8s.persistentvolume.name": "test-pv-1",
+			"k8s.storageclass.name":     "standard",
+			"k8s.cluster.name":          "test-cluster",
+		},
+	)
+
+	testutils.AssertMetrics(t, *rm.metrics[0], "kubernetes/persistent_volume/phase",
+		metricspb.MetricDescriptor_GAUGE_INT64, 3)
+
+	testutils.AssertMetrics(t, *rm.metrics[1], "kubernetes/persistent_volume/capacity",
+		metricspb.MetricDescriptor_GAUGE_INT64, 10*1024*1024*1024)
+
+	pv.Status.Phase = ""
+	rm = getMetricsForPersistentVolume(pv)[0]
+	testutils.AssertMetrics(t, *rm.metrics[0], "kubernetes/persistent_volume/phase",
+		metricspb.MetricDescriptor_GAUGE_INT64, -1)
+}
+
+func TestPersistentVolumeMetadata(t *testing.T) {
+	require.Equal(t,
+		[]*KubernetesMetadata{
+			{
+				resourceIDKey: "k8s.persistentvolume.uid",
+				resourceID:    "test-pv-1-uid",
+				properties: map[string]string{
+					"persistentvolume.creation_timestamp": "0001-01-01T00:00:00Z",
+					"persistentvolume.reclaim_policy":     "Delete",
+					"k8s.storageclass.name":               "standard",
+					"k8s.persistentvolumeclaim.name":      "test-pvc-1",
+					"k8s.persistentvolumeclaim.uid":       "test-pvc-1-uid",
+					"k8s.namespace.name":                  "test-namespace",
+				},
+			},
+		},
+		getMetadataForPersistentVolume(newPersistentVolume("1")),
+	)
+}
+
+func TestPersistentVolumeClaimMetrics(t *testing.T) {
+	pvc := newPersistentVolumeClaim("1")
+
+	actualResourceMetrics := getMetricsForPersistentVolumeClaim(pvc)
+
+	require.Equal(t, 1, len(actualResourceMetrics))
+	require.Equal(t, 3, len(actualResourceMetrics[0].metrics))
+
+	rm := actualResourceMetrics[0]
+	testutils.AssertResource(t, *rm.resource, k8sType,
+		map[string]string{
+			"k8s.persistentvolumeclaim.uid":  "test-pvc-1-uid",
+			"k8s.persistentvolumeclaim.name": "test-pvc-1",
+			"k8s.storageclass.name":          "standard",
+			"k8s.namespace.name":             "test-namespace",
+			"k8s.cluster.name":               "test-cluster",
+		},
+	)
+
+	testutils.AssertMetrics(t, *rm.metrics[0], "kubernetes/persistent_volume_claim/phase",
+		metricspb.MetricDescriptor_GAUGE_INT64, 2)
+
+	testutils.AssertMetrics(t, *rm.metrics[1], "kubernetes/persistent_volume_claim/request",
+		metricspb.MetricDescriptor_GAUGE_INT64, 5*1024*1024*1024)
+
+	testutils.AssertMetrics(t, *rm.metrics[2], "kubernetes/persistent_volume_claim/capacity",
+		metricspb.MetricDescriptor_GAUGE_INT64, 10*1024*1024*1024)
+
+	// Pending claims have no capacity yet.
+	pvc.Status = corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending}
+	rm = getMetricsForPersistentVolumeClaim(pvc)[0]
+	require.Equal(t, 2, len(rm.metrics))
+	testutils.AssertMetrics(t, *rm.metrics[0], "kubernetes/persistent_volume_claim/phase",
+		metricspb.MetricDescriptor_GAUGE_INT64, 1)
+}
+
+func TestPersistentVolumeClaimMetadata(t *testing.T) {
+	require.Equal(t,
+		[]*KubernetesMetadata{
+			{
+				resourceIDKey: "k8s.persistentvolumeclaim.uid",
+				resourceID:    "test-pvc-1-uid",
+				properties: map[string]string{
+					"app": "db",
+					"persistentvolumeclaim.creation_timestamp": "0001-01-01T00:00:00Z",
+					"persistentvolumeclaim.volume_name":        "test-pv-1",
+					"k8s.storageclass.name":                    "standard",
+				},
+			},
+		},
+		getMetadataForPersistentVolumeClaim(newPersistentVolumeClaim("1")),
+	)
+}
+
+func newPersistentVolume(id string) *corev1.PersistentVolume {
+	return &corev1.PersistentVolume{
+		ObjectMeta: v1.ObjectMeta{
+			Name:        "test-pv-" + id,
+			UID:         types.UID("test-pv-" + id + "-uid"),
+			ClusterName: "test-cluster",
+		},
+		Spec: corev1.PersistentVolumeSpec{
+			Capacity: corev1.ResourceList{
+				corev1.ResourceStorage: resource.MustParse("10Gi"),
+			},
+			StorageClassName:              "standard",
+			PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimDelete,
+			ClaimRef: &corev1.ObjectReference{
+				Kind:      "PersistentVolumeClaim",
+				Name:      "test-pvc-" + id,
+				Namespace: "test-namespace",
+				UID:       types.UID("test-pvc-" + id + "-uid"),
+			},
+		},
+		Status: corev1.PersistentVolumeStatus{
+			Phase: corev1.VolumeBound,
+		},
+	}
+}
+
+func newPersistentVolumeClaim(id string) *corev1.PersistentVolumeClaim {
+	storageClass := "standard"
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: v1.ObjectMeta{
+			Name:        "test-pvc-" + id,
+			Namespace:   "test-namespace",
+			UID:         types.UID("test-pvc-" + id + "-uid"),
+			ClusterName: "test-cluster",
+			Labels: map[string]string{
+				"app": "db",
+			},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: &storageClass,
+			VolumeName:       "test-pv-" + id,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse("5Gi"),
+				},
+			},
+		},
+		Status: corev1.PersistentVolumeClaimStatus{
+			Phase: corev1.ClaimBound,
+			Capacity: corev1.ResourceList{
+				corev1.ResourceStorage: resource.MustParse("10Gi"),
+			},
+		},
+	}
+}
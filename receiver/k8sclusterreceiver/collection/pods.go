@@ -15,11 +15,13 @@
 package collection
 
 import (
+	"fmt"
 	"strings"
 	"time"
 
 	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
 	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"github.com/iancoleman/strcase"
 	"github.com/open-telemetry/opentelemetry-collector/translator/conventions"
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
@@ -42,6 +44,36 @@ var podPhaseMetric = &metricspb.MetricDescriptor{
 	Type:        metricspb.MetricDescriptor_GAUGE_INT64,
 }
 
+var podQOSClassMetric = &metricspb.MetricDescriptor{
+	Name:        "kubernetes/pod/qos_class",
+	Description: "Quality of Service class of the pod (1 - Guaranteed, 2 - Burstable, 3 - BestEffort)",
+	Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+}
+
+var podStatusReasonMetric = &metricspb.MetricDescriptor{
+	Name: "kubernetes/pod/status_reason",
+	Description: "Whether the pod is in its phase for the reason (0 for no, 1 for yes). " +
+		"Common reasons are always reported, other reasons only while the pod is in its phase for them",
+	Type:      metricspb.MetricDescriptor_GAUGE_INT64,
+	LabelKeys: []*metricspb.LabelKey{{Key: "reason"}},
+}
+
+// Conditions of pods reported as metrics.
+var podConditionTypes = []corev1.PodConditionType{
+	corev1.PodScheduled,
+	corev1.PodInitialized,
+	corev1.ContainersReady,
+	corev1.PodReady,
+}
+
+// Reasons of pod statuses, as in kube-state-metrics.
+var podStatusReasons = []string{
+	"Evicted",
+	"NodeLost",
+	"Shutdown",
+	"UnexpectedAdmissionError",
+}
+
 func getMetricsForPod(pod *corev1.Pod) []*resourceMetrics {
 	metrics := []*metricspb.Metric{
 		{
@@ -50,24 +82,47 @@ func getMetricsForPod(pod *corev1.Pod) []*resourceMetrics {
 				utils.GetInt64TimeSeries(int64(phaseToInt(pod.Status.Phase))),
 			},
 		},
+		{
+			MetricDescriptor: podStatusReasonMetric,
+			Timeseries:       getReasonTimeSeries(podStatusReasons, pod.Status.Reason),
+		},
+	}
+
+	for _, condType := range podConditionTypes {
+		metrics = append(metrics, &metricspb.Metric{
+			MetricDescriptor: &metricspb.MetricDescriptor{
+				Name: getPodConditionMetric(condType),
+				Description: fmt.Sprintf("Whether the pod condition %s is true (1), "+
+					"false (0) or in an unknown state (-1)", condType),
+				Type: metricspb.MetricDescriptor_GAUGE_INT64,
+			},
+			Timeseries: []*metricspb.TimeSeries{
+				utils.GetInt64TimeSeries(podConditionValue(pod, condType)),
+			},
+		})
+	}
+
+	if qosClass := qosClassToInt(pod.Status.QOSClass); qosClass != 0 {
+		metrics = append(metrics, &metricspb.Metric{
+			MetricDescriptor: podQOSClassMetric,
+			Timeseries: []*metricspb.TimeSeries{
+				utils.GetInt64TimeSeries(int64(qosClass)),
+			},
+		})
 	}
 
 	podRes := getResourceForPod(pod)
 
 	containerResByName := map[string]*resourceMetrics{}
 
-	for _, cs := range pod.Status.ContainerStatuses {
-		if cs.ContainerID == "" {
-			continue
-		}
-
+	for _, cs := range getAllContainerStatuses(pod) {
 		contLabels := getAllContainerLabels(cs, podRes.Labels)
 		containerResByName[cs.Name] = &resourceMetrics{resource: getResourceForContainer(contLabels)}
 
 		containerResByName[cs.Name].metrics = getStatusMetricsForContainer(cs)
 	}
 
-	for _, c := range pod.Spec.Containers {
+	for _, c := range getAllContainers(pod) {
 		cr := containerResByName[c.Name]
 
 		// This likely will not happen since both pod spec and status return
@@ -92,6 +147,23 @@ func getMetricsForPod(pod *corev1.Pod) []*resourceMetrics {
 	return out
 }
 
+// getAllContainerStatuses returns the statuses of the init containers and
+// containers of the pod.
+func getAllContainerStatuses(pod *corev1.Pod) []corev1.ContainerStatus {
+	out := make([]corev1.ContainerStatus, 0,
+		len(pod.Status.InitContainerStatuses)+len(pod.Status.ContainerStatuses))
+	out = append(out, pod.Status.InitContainerStatuses...)
+	return append(out, pod.Status.ContainerStatuses...)
+}
+
+// getAllContainers returns the specs of the init containers and containers
+// of the pod.
+func getAllContainers(pod *corev1.Pod) []corev1.Container {
+	out := make([]corev1.Container, 0, len(pod.Spec.InitContainers)+len(pod.Spec.Containers))
+	out = append(out, pod.Spec.InitContainers...)
+	return append(out, pod.Spec.Containers...)
+}
+
 func listResourceMetrics(rms map[string]*resourceMetrics) []*resourceMetrics {
 	out := make([]*resourceMetrics, len(rms))
 
@@ -135,6 +207,34 @@ func phaseToInt(phase corev1.PodPhase) int32 {
 	}
 }
 
+func qosClassToInt(qosClass corev1.PodQOSClass) int32 {
+	switch qosClass {
+	case corev1.PodQOSGuaranteed:
+		return 1
+	case corev1.PodQOSBurstable:
+		return 2
+	case corev1.PodQOSBestEffort:
+		return 3
+	default:
+		return 0
+	}
+}
+
+func getPodConditionMetric(condType corev1.PodConditionType) string {
+	return fmt.Sprintf("kubernetes/pod/condition_%s", strcase.ToSnake(string(condType)))
+}
+
+func podConditionValue(pod *corev1.Pod, condType corev1.PodConditionType) int64 {
+	status := corev1.ConditionUnknown
+	for _, c := range pod.Status.Conditions {
+		if c.Type == condType {
+			status = c.Status
+			break
+		}
+	}
+	return conditionValues[status]
+}
+
 // getMetadataForPod returns all metadata associated with the pod.
 func getMetadataForPod(pod *corev1.Pod, mc *metadataStore) []*KubernetesMetadata {
 	properties := utils.MergeStringMaps(map[string]string{}, pod.Labels)
@@ -153,21 +253,21 @@ func getMetadataForPod(pod *corev1.Pod, mc *metadataStore) []*KubernetesMetadata
 		properties[k8sKeyWorkLoadName] = or.Name
 	}
 
-	if mc.services != nil {
+	if services := mc.services.get(pod.Namespace); services != nil {
 		properties = utils.MergeStringMaps(properties,
-			getPodServiceTags(pod, mc.services),
+			getPodServiceTags(pod, services),
 		)
 	}
 
-	if mc.jobs != nil {
+	if jobs := mc.jobs.get(pod.Namespace); jobs != nil {
 		properties = utils.MergeStringMaps(properties,
-			collectPodJobProperties(pod, mc.jobs),
+			collectPodJobProperties(pod, jobs),
 		)
 	}
 
-	if mc.replicaSets != nil {
+	if replicaSets := mc.replicaSets.get(pod.Namespace); replicaSets != nil {
 		properties = utils.MergeStringMaps(properties,
-			collectPodReplicaSetProperties(pod, mc.replicaSets),
+			collectPodReplicaSetProperties(pod, replicaSets),
 		)
 	}
 
@@ -275,13 +375,13 @@ func getPodWorkloadProperties(workloadName string, workloadType string) map[stri
 
 func getPodContainerProperties(pod *corev1.Pod) []*KubernetesMetadata {
 	rm := make([]*KubernetesMetadata, 0)
-	for _, cs := range pod.Status.ContainerStatuses {
+	for i, cs := range getAllContainerStatuses(pod) {
 		// Skip if container id returned is empty.
 		if cs.ContainerID == "" {
 			continue
 		}
 
-		rm = append(rm, getMetadataForContainer(cs))
+		rm = append(rm, getMetadataForContainer(cs, i < len(pod.Status.InitContainerStatuses)))
 	}
 	return rm
 }
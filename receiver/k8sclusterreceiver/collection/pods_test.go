@@ -37,7 +37,7 @@ func TestPodAndContainerMetrics(t *testing.T) {
 
 	rm := actualResourceMetrics[0]
 
-	require.Equal(t, 1, len(actualResourceMetrics[0].metrics))
+	require.Equal(t, 6, len(actualResourceMetrics[0].metrics))
 	testutils.AssertResource(t, *rm.resource, k8sType,
 		map[string]string{
 			"k8s.pod.uid":        "test-pod-1-uid",
@@ -51,9 +51,15 @@ func TestPodAndContainerMetrics(t *testing.T) {
 	testutils.AssertMetrics(t, *rm.metrics[0], "kubernetes/pod/phase",
 		metricspb.MetricDescriptor_GAUGE_INT64, 3)
 
+	testutils.AssertMetricsWithLabels(t, *rm.metrics[1], "kubernetes/pod/status_reason",
+		metricspb.MetricDescriptor_GAUGE_INT64, map[string]string{"reason": "Evicted"}, 0)
+
+	testutils.AssertMetrics(t, *rm.metrics[2], "kubernetes/pod/condition_pod_scheduled",
+		metricspb.MetricDescriptor_GAUGE_INT64, -1)
+
 	rm = actualResourceMetrics[1]
 
-	require.Equal(t, 4, len(actualResourceMetrics[1].metrics))
+	require.Equal(t, 7, len(actualResourceMetrics[1].metrics))
 	testutils.AssertResource(t, *rm.resource, "container",
 		map[string]string{
 			"container.id":         "container-id",
@@ -73,13 +79,157 @@ func TestPodAndContainerMetrics(t *testing.T) {
 	testutils.AssertMetrics(t, *rm.metrics[1], "kubernetes/container/ready",
 		metricspb.MetricDescriptor_GAUGE_INT64, 1)
 
-	testutils.AssertMetricsWithLabels(t, *rm.metrics[2], "kubernetes/container/request",
+	testutils.AssertMetricsWithLabels(t, *rm.metrics[2], "kubernetes/container/waiting_reason",
+		metricspb.MetricDescriptor_GAUGE_INT64, map[string]string{"reason": "ContainerCreating"}, 0)
+
+	testutils.AssertMetricsWithLabels(t, *rm.metrics[3], "kubernetes/container/terminated_reason",
+		metricspb.MetricDescriptor_GAUGE_INT64, map[string]string{"reason": "Completed"}, 0)
+
+	testutils.AssertMetricsWithLabels(t, *rm.metrics[4], "kubernetes/container/last_terminated_reason",
+		metricspb.MetricDescriptor_GAUGE_INT64, map[string]string{"reason": "Completed"}, 0)
+
+	testutils.AssertMetricsWithLabels(t, *rm.metrics[5], "kubernetes/container/request",
 		metricspb.MetricDescriptor_GAUGE_INT64, map[string]string{"resource": "cpu"}, 10000)
 
-	testutils.AssertMetricsWithLabels(t, *rm.metrics[3], "kubernetes/container/limit",
+	testutils.AssertMetricsWithLabels(t, *rm.metrics[6], "kubernetes/container/limit",
 		metricspb.MetricDescriptor_GAUGE_INT64, map[string]string{"resource": "cpu"}, 20000)
 }
 
+func TestPodStatusMetrics(t *testing.T) {
+	pod := newPodWithContainer("1")
+	pod.Status.Phase = corev1.PodFailed
+	pod.Status.Reason = "Evicted"
+	pod.Status.QOSClass = corev1.PodQOSBurstable
+	pod.Status.Conditions = []corev1.PodCondition{
+		{Type: corev1.PodScheduled, Status: corev1.ConditionTrue},
+		{Type: corev1.PodInitialized, Status: corev1.ConditionFalse},
+	}
+
+	rms := getMetricsForPod(pod)
+	require.Equal(t, 2, len(rms))
+	metrics := metricsByName(rms[0].metrics)
+	require.Equal(t, 7, len(metrics))
+
+	require.Equal(t, map[string]int64{
+		"Evicted":                  1,
+		"NodeLost":                 0,
+		"Shutdown":                 0,
+		"UnexpectedAdmissionError": 0,
+	}, reasonValues(metrics["kubernetes/pod/status_reason"]))
+	testutils.AssertMetrics(t, *metrics["kubernetes/pod/condition_pod_scheduled"], "kubernetes/pod/condition_pod_scheduled",
+		metricspb.MetricDescriptor_GAUGE_INT64, 1)
+	testutils.AssertMetrics(t, *metrics["kubernetes/pod/condition_initialized"], "kubernetes/pod/condition_initialized",
+		metricspb.MetricDescriptor_GAUGE_INT64, 0)
+	testutils.AssertMetrics(t, *metrics["kubernetes/pod/condition_containers_ready"], "kubernetes/pod/condition_containers_ready",
+		metricspb.MetricDescriptor_GAUGE_INT64, -1)
+	testutils.AssertMetrics(t, *metrics["kubernetes/pod/condition_ready"], "kubernetes/pod/condition_ready",
+		metricspb.MetricDescriptor_GAUGE_INT64, -1)
+	testutils.AssertMetrics(t, *metrics["kubernetes/pod/qos_class"], "kubernetes/pod/qos_class",
+		metricspb.MetricDescriptor_GAUGE_INT64, 2)
+}
+
+func TestContainerStatusMetrics(t *testing.T) {
+	pod := newPodWithContainer("1")
+	pod.Spec.InitContainers = []corev1.Container{{Name: "init-container-name"}}
+	pod.Status.InitContainerStatuses = []corev1.ContainerStatus{
+		{
+			Name:         "init-container-name",
+			Image:        "init-container-image-name",
+			ContainerID:  "docker://init-container-id",
+			RestartCount: 2,
+			State: corev1.ContainerState{
+				Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+			},
+			LastTerminationState: corev1.ContainerState{
+				Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137},
+			},
+		},
+	}
+	// The container never started as its image can't be pulled.
+	pod.Status.ContainerStatuses[0].ContainerID = ""
+	pod.Status.ContainerStatuses[0].State = corev1.ContainerState{
+		Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"},
+	}
+
+	rms := getMetricsForPod(pod)
+	require.Equal(t, 3, len(rms))
+
+	containers := map[string]*resourceMetrics{}
+	for _, rm := range rms[1:] {
+		containers[rm.resource.Labels["container.spec.name"]] = rm
+	}
+
+	rm := containers["init-container-name"]
+	require.Equal(t, "init-container-id", rm.resource.Labels["container.id"])
+	metrics := metricsByName(rm.metrics)
+	require.Equal(t, 6, len(metrics))
+	testutils.AssertMetrics(t, *metrics["kubernetes/container/restarts"], "kubernetes/container/restarts",
+		metricspb.MetricDescriptor_GAUGE_INT64, 2)
+	require.Equal(t, map[string]int64{
+		"ContainerCreating":          0,
+		"CrashLoopBackOff":           1,
+		"CreateContainerConfigError": 0,
+		"CreateContainerError":       0,
+		"ErrImagePull":               0,
+		"ImagePullBackOff":           0,
+		"InvalidImageName":           0,
+	}, reasonValues(metrics["kubernetes/container/waiting_reason"]))
+	require.Equal(t, map[string]int64{
+		"Completed":          0,
+		"ContainerCannotRun": 0,
+		"DeadlineExceeded":   0,
+		"Error":              0,
+		"Evicted":            0,
+		"OOMKilled":          1,
+	}, reasonValues(metrics["kubernetes/container/last_terminated_reason"]))
+	testutils.AssertMetrics(t, *metrics["kubernetes/container/last_termination_exit_code"],
+		"kubernetes/container/last_termination_exit_code", metricspb.MetricDescriptor_GAUGE_INT64, 137)
+
+	// Containers without an ID are still reported, identified by the pod and their name.
+	rm = containers["container-name"]
+	testutils.AssertResource(t, *rm.resource, "container",
+		map[string]string{
+			"container.spec.name":  "container-name",
+			"container.image.name": "container-image-name",
+			"k8s.pod.uid":          "test-pod-1-uid",
+			"k8s.pod.name":         "test-pod-1",
+			"k8s.node.name":        "test-node",
+			"k8s.namespace.name":   "test-namespace",
+			"k8s.cluster.name":     "test-cluster",
+		},
+	)
+	metrics = metricsByName(rm.metrics)
+	require.Equal(t, int64(1), reasonValues(metrics["kubernetes/container/waiting_reason"])["ImagePullBackOff"])
+	require.NotContains(t, metrics, "kubernetes/container/last_termination_exit_code")
+}
+
+func TestReasonTimeSeriesWithUnknownReason(t *testing.T) {
+	ts := getReasonTimeSeries([]string{"Known"}, "Unknown")
+
+	require.Equal(t, 2, len(ts))
+	require.Equal(t, "Known", ts[0].LabelValues[0].Value)
+	require.Equal(t, int64(0), ts[0].Points[0].GetInt64Value())
+	require.Equal(t, "Unknown", ts[1].LabelValues[0].Value)
+	require.Equal(t, int64(1), ts[1].Points[0].GetInt64Value())
+}
+
+func metricsByName(metrics []*metricspb.Metric) map[string]*metricspb.Metric {
+	out := map[string]*metricspb.Metric{}
+	for _, m := range metrics {
+		out[m.MetricDescriptor.Name] = m
+	}
+	return out
+}
+
+// reasonValues returns the values of the timeseries of a reason metric by reason.
+func reasonValues(m *metricspb.Metric) map[string]int64 {
+	out := map[string]int64{}
+	for _, ts := range m.Timeseries {
+		out[ts.LabelValues[0].Value] = ts.Points[0].GetInt64Value()
+	}
+	return out
+}
+
 func TestPodAndContainerMetadata(t *testing.T) {
 	pod := newPodWithContainer("1")
 
@@ -116,6 +266,37 @@ func TestPodAndContainerMetadata(t *testing.T) {
 	)
 }
 
+func TestInitContainerMetadata(t *testing.T) {
+	pod := newPodWithContainer("1")
+	pod.Status.InitContainerStatuses = []corev1.ContainerStatus{
+		{
+			Name:        "init-container-name",
+			ContainerID: "docker://init-container-id",
+			State: corev1.ContainerState{
+				Terminated: &corev1.ContainerStateTerminated{Reason: "Completed"},
+			},
+		},
+	}
+
+	actualMetadata := getMetadataForPod(pod, &metadataStore{})
+
+	require.Equal(t, 3, len(actualMetadata))
+	require.Equal(t,
+		KubernetesMetadata{
+			resourceIDKey: "container.id",
+			resourceID:    "init-container-id",
+			properties: map[string]string{
+				"container.init":          "true",
+				"container.status":        "terminated",
+				"container.status.reason": "Completed",
+			},
+		},
+		*actualMetadata[1],
+	)
+	require.Equal(t, "container-id", actualMetadata[2].resourceID)
+	require.NotContains(t, actualMetadata[2].properties, "container.init")
+}
+
 func newPodWithContainer(id string) *corev1.Pod {
 	return &corev1.Pod{
 		ObjectMeta: v1.ObjectMeta{
@@ -0,0 +1,95 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collection
+
+import (
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"github.com/open-telemetry/opentelemetry-collector/translator/conventions"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/utils"
+)
+
+const (
+	// Keys for service properties.
+	serviceKeyType      = "service.type"
+	serviceKeyClusterIP = "service.cluster_ip"
+)
+
+var serviceReadyEndpointsMetric = &metricspb.MetricDescriptor{
+	Name:        "kubernetes/service/ready_endpoints",
+	Description: "Number of addresses of the service ready to serve traffic",
+	Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+}
+
+var serviceNotReadyEndpointsMetric = &metricspb.MetricDescriptor{
+	Name:        "kubernetes/service/not_ready_endpoints",
+	Description: "Number of addresses of the service not ready to serve traffic, e.g. failing their readiness probe",
+	Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+}
+
+// getMetricsForService returns the readiness of the endpoints of the service.
+// endpoints may be nil, before the endpoints of the service are known.
+func getMetricsForService(svc *corev1.Service, endpoints *corev1.Endpoints) []*resourceMetrics {
+	var ready, notReady int
+	if endpoints != nil {
+		for _, subset := range endpoints.Subsets {
+			ready += len(subset.Addresses)
+			notReady += len(subset.NotReadyAddresses)
+		}
+	}
+
+	return []*resourceMetrics{
+		{
+			resource: getResourceForService(svc),
+			metrics: []*metricspb.Metric{
+				{
+					MetricDescriptor: serviceReadyEndpointsMetric,
+					Timeseries: []*metricspb.TimeSeries{
+						utils.GetInt64TimeSeries(int64(ready)),
+					},
+				},
+				{
+					MetricDescriptor: serviceNotReadyEndpointsMetric,
+					Timeseries: []*metricspb.TimeSeries{
+						utils.GetInt64TimeSeries(int64(notReady)),
+					},
+				},
+			},
+		},
+	}
+}
+
+func getResourceForService(svc *corev1.Service) *resourcepb.Resource {
+	return &resourcepb.Resource{
+		Type: k8sType,
+		Labels: map[string]string{
+			k8sKeyServiceUID:                  string(svc.UID),
+			k8sKeyServiceName:                 svc.Name,
+			conventions.AttributeK8sNamespace: svc.Namespace,
+			conventions.AttributeK8sCluster:   svc.ClusterName,
+		},
+	}
+}
+
+func getMetadataForService(svc *corev1.Service) []*KubernetesMetadata {
+	km := getObjectMetadata(&svc.ObjectMeta, "service")
+	km.properties[serviceKeyType] = string(svc.Spec.Type)
+	if svc.Spec.ClusterIP != "" {
+		km.properties[serviceKeyClusterIP] = svc.Spec.ClusterIP
+	}
+	return []*KubernetesMetadata{km}
+}
@@ -0,0 +1,158 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collection
+
+import (
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/testutils"
+)
+
+func TestServiceMetrics(t *testing.T) {
+	svc := newService("1")
+
+	actualResourceMetrics := getMetricsForService(svc, newEndpoints("1"))
+
+	require.Equal(t, 1, len(actualResourceMetrics))
+	require.Equal(t, 2, len(actualResourceMetrics[0].metrics))
+
+	rm := actualResourceMetrics[0]
+	testutils.AssertResource(t, *rm.resource, k8sType,
+		map[string]string{
+			"k8s.service.uid":    "test-service-1-uid",
+			"k8s.service.name":   "test-service-1",
+			"k8s.namespace.name": "test-namespace",
+			"k8s.cluster.name":   "test-cluster",
+		},
+	)
+
+	testutils.AssertMetrics(t, *rm.metrics[0], "kubernetes/service/ready_endpoints",
+		metricspb.MetricDescriptor_GAUGE_INT64, 3)
+
+	testutils.AssertMetrics(t, *rm.metrics[1], "kubernetes/service/not_ready_endpoints",
+		metricspb.MetricDescriptor_GAUGE_INT64, 1)
+
+	// Services without known endpoints have no endpoints.
+	rm = getMetricsForService(svc, nil)[0]
+	testutils.AssertMetrics(t, *rm.metrics[0], "kubernetes/service/ready_endpoints",
+		metricspb.MetricDescriptor_GAUGE_INT64, 0)
+	testutils.AssertMetrics(t, *rm.metrics[1], "kubernetes/service/not_ready_endpoints",
+		metricspb.MetricDescriptor_GAUGE_INT64, 0)
+}
+
+func TestServiceMetadata(t *testing.T) {
+	require.Equal(t,
+		[]*KubernetesMetadata{
+			{
+				resourceIDKey: "k8s.service.uid",
+				resourceID:    "test-service-1-uid",
+				properties: map[string]string{
+					"foo":                        "bar",
+					"service.creation_timestamp": "0001-01-01T00:00:00Z",
+					"service.type":               "ClusterIP",
+					"service.cluster_ip":         "10.0.0.10",
+				},
+			},
+		},
+		getMetadataForService(newService("1")),
+	)
+}
+
+func TestSyncServiceEndpoints(t *testing.T) {
+	dc := NewDataCollector(zap.NewNop(), nil)
+	services := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	endpoints := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	dc.SetupMetadataStore(&corev1.Service{}, "", services)
+	dc.SetupMetadataStore(&corev1.Endpoints{}, "", endpoints)
+
+	// Endpoints of unknown services are ignored.
+	e := newEndpoints("1")
+	require.NoError(t, endpoints.Add(e))
+	dc.SyncMetrics(e)
+	require.Empty(t, dc.CollectMetricData())
+
+	svc := newService("1")
+	require.NoError(t, services.Add(svc))
+	dc.SyncMetrics(svc)
+	requireServiceEndpoints(t, dc, 3, 1)
+
+	// Updated endpoints are reported with their service.
+	e = newEndpoints("1")
+	e.Subsets[0].NotReadyAddresses = nil
+	require.NoError(t, endpoints.Update(e))
+	dc.SyncMetrics(e)
+	requireServiceEndpoints(t, dc, 3, 0)
+
+	require.NoError(t, endpoints.Delete(e))
+	dc.RemoveFromMetricsStore(e)
+	requireServiceEndpoints(t, dc, 0, 0)
+
+	require.NoError(t, services.Delete(svc))
+	dc.RemoveFromMetricsStore(svc)
+	require.Empty(t, dc.CollectMetricData())
+}
+
+func requireServiceEndpoints(t *testing.T, dc *DataCollector, ready, notReady int64) {
+	mds := dc.CollectMetricData()
+	require.Equal(t, 1, len(mds))
+	require.Equal(t, "test-service-1-uid", mds[0].Resource.Labels["k8s.service.uid"])
+	require.Equal(t, ready, mds[0].Metrics[0].Timeseries[0].Points[0].GetInt64Value())
+	require.Equal(t, notReady, mds[0].Metrics[1].Timeseries[0].Points[0].GetInt64Value())
+}
+
+func newService(id string) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: v1.ObjectMeta{
+			Name:        "test-service-" + id,
+			Namespace:   "test-namespace",
+			UID:         types.UID("test-service-" + id + "-uid"),
+			ClusterName: "test-cluster",
+			Labels: map[string]string{
+				"foo": "bar",
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Type:      corev1.ServiceTypeClusterIP,
+			ClusterIP: "10.0.0.10",
+		},
+	}
+}
+
+func newEndpoints(id string) *corev1.Endpoints {
+	return &corev1.Endpoints{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "test-service-" + id,
+			Namespace: "test-namespace",
+			UID:       types.UID("test-endpoints-" + id + "-uid"),
+		},
+		Subsets: []corev1.EndpointSubset{
+			{
+				Addresses:         []corev1.EndpointAddress{{IP: "10.1.0.1"}, {IP: "10.1.0.2"}},
+				NotReadyAddresses: []corev1.EndpointAddress{{IP: "10.1.0.3"}},
+			},
+			{
+				Addresses: []corev1.EndpointAddress{{IP: "10.1.0.4"}},
+			},
+		},
+	}
+}
@@ -20,14 +20,51 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector/config/configmodels"
 )
 
+// AuthType describes how the receiver authenticates to the API server.
+type AuthType string
+
+const (
+	// AuthTypeServiceAccount uses the service account of the pod the
+	// collector is running in.
+	AuthTypeServiceAccount AuthType = "serviceAccount"
+	// AuthTypeKubeConfig uses credentials of the current context of a
+	// kubeconfig file, to run the collector outside of the cluster.
+	AuthTypeKubeConfig AuthType = "kubeConfig"
+)
+
 // Config defines configuration for kubernetes cluster receiver.
 type Config struct {
 	configmodels.ReceiverSettings `mapstructure:",squash"`
 
+	// AuthType is how to authenticate to the API server, either serviceAccount
+	// or kubeConfig.
+	AuthType AuthType `mapstructure:"auth_type"`
+
+	// KubeConfigPath is the path of the kubeconfig file used with the
+	// kubeConfig auth type. When empty, the KUBECONFIG environment variable
+	// or ~/.kube/config is used.
+	KubeConfigPath string `mapstructure:"kube_config_path"`
+
+	// Namespaces restricts namespaced resources watched to these namespaces.
+	// When empty resources in all namespaces are watched.
+	Namespaces []string `mapstructure:"namespaces"`
+
+	// Resources enables or disables watching resources by name (e.g. cronjobs).
+	// Resources are watched unless disabled.
+	Resources map[string]bool `mapstructure:"resources"`
+
 	// Collection interval for metrics.
 	CollectionInterval time.Duration `mapstructure:"collection_interval"`
 
 	// Node condition types to report. See all condition types, see
 	// here: https://kubernetes.io/docs/concepts/architecture/nodes/#condition.
 	NodeConditionTypesToReport []string `mapstructure:"node_conditions_to_report"`
+
+	// CollectEvents enables watching Kubernetes events. Each new or updated
+	// event is emitted once, at the next collection interval.
+	CollectEvents bool `mapstructure:"collect_events"`
+
+	// MetadataExporters is a list of names of metrics exporters to send
+	// properties of resources to. Exporters must support metadata.
+	MetadataExporters []string `mapstructure:"metadata_exporters"`
 }
@@ -53,6 +53,15 @@ func TestLoadConfig(t *testing.T) {
 			},
 			CollectionInterval:         30 * time.Second,
 			NodeConditionTypesToReport: []string{"Ready", "MemoryPressure"},
+			CollectEvents:              true,
+			MetadataExporters:          []string{"exampleexporter"},
+			AuthType:                   AuthTypeKubeConfig,
+			KubeConfigPath:             "/etc/kube/config",
+			Namespaces:                 []string{"default", "monitoring"},
+			Resources: map[string]bool{
+				"cronjobs":                 false,
+				"horizontalpodautoscalers": false,
+			},
 		})
 
 	r3 := cfg.Receivers["k8s_cluster/partial_settings"].(*Config)
@@ -62,6 +71,7 @@ func TestLoadConfig(t *testing.T) {
 				TypeVal: configmodels.Type(receiverType),
 				NameVal: "k8s_cluster/partial_settings",
 			},
+			AuthType:                   AuthTypeServiceAccount,
 			CollectionInterval:         30 * time.Second,
 			NodeConditionTypesToReport: []string{"Ready"},
 		})
@@ -16,6 +16,7 @@ package k8sclusterreceiver
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/open-telemetry/opentelemetry-collector/component"
@@ -25,6 +26,9 @@ import (
 	"go.uber.org/zap"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/resourcemetadata"
 )
 
 const (
@@ -41,15 +45,33 @@ type kubernetesReceiver struct {
 	logger   *zap.Logger
 	consumer consumer.MetricsConsumerOld
 	cancel   context.CancelFunc
+
+	metadataExporters []resourcemetadata.MetadataExporter
 }
 
 func (kr *kubernetesReceiver) Start(ctx context.Context, host component.Host) error {
+	kr.metadataExporters = kr.metadataExporters[:0]
+	for _, name := range kr.config.MetadataExporters {
+		exporter, ok := resourcemetadata.GetMetadataExporter(name)
+		if !ok {
+			return fmt.Errorf("metadata exporter %q not found, it must be a metrics exporter supporting metadata", name)
+		}
+		kr.metadataExporters = append(kr.metadataExporters, exporter)
+	}
+
 	var c context.Context
 	c, kr.cancel = context.WithCancel(ctx)
 
 	go func() {
 		kr.resourceWatcher.startWatchingResources(c.Done())
 
+		// Send all properties of existing resources once known, changes are then
+		// sent every collection interval.
+		if len(kr.metadataExporters) > 0 {
+			kr.resourceWatcher.waitForInitialSync(c.Done())
+			kr.dispatchMetadata()
+		}
+
 		ticker := time.NewTicker(kr.config.CollectionInterval)
 		defer ticker.Stop()
 
@@ -62,6 +84,8 @@ func (kr *kubernetesReceiver) Start(ctx context.Context, host component.Host) er
 				err := componenterror.CombineErrors(errs)
 
 				obsreport.EndMetricsReceiveOp(c, dataformat, numPoints, numTimeseries, err)
+
+				kr.dispatchMetadata()
 			case <-c.Done():
 				return
 			}
@@ -77,7 +101,11 @@ func (kr *kubernetesReceiver) Shutdown(context.Context) error {
 }
 
 func (kr *kubernetesReceiver) dispatchMetricData(ctx context.Context) (numTimeseries int, numPoints int, errs []error) {
-	for _, m := range kr.resourceWatcher.dataCollector.CollectMetricData() {
+	data := kr.resourceWatcher.dataCollector.CollectMetricData()
+	if kr.config.CollectEvents {
+		data = append(data, kr.resourceWatcher.dataCollector.CollectEventData()...)
+	}
+	for _, m := range data {
 		if err := kr.consumer.ConsumeMetricsData(ctx, m); err != nil {
 			errs = append(errs, err)
 			numTs, numPts := obsreport.CountMetricPoints(m)
@@ -88,6 +116,43 @@ func (kr *kubernetesReceiver) dispatchMetricData(ctx context.Context) (numTimese
 	return numTimeseries, numPoints, errs
 }
 
+// createRestConfig returns the config of clients of the API server for the
+// configured auth type.
+func createRestConfig(config *Config) (*rest.Config, error) {
+	switch config.AuthType {
+	case AuthTypeServiceAccount, "":
+		return rest.InClusterConfig()
+	case AuthTypeKubeConfig:
+		loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+		loadingRules.ExplicitPath = config.KubeConfigPath
+		return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+			loadingRules, &clientcmd.ConfigOverrides{},
+		).ClientConfig()
+	}
+	return nil, fmt.Errorf("invalid auth_type %q, must be %q or %q",
+		config.AuthType, AuthTypeServiceAccount, AuthTypeKubeConfig)
+}
+
+// dispatchMetadata sends changes to the metadata of resources to metadata exporters.
+func (kr *kubernetesReceiver) dispatchMetadata() {
+	if len(kr.metadataExporters) == 0 {
+		return
+	}
+
+	updates := kr.resourceWatcher.dataCollector.CollectMetadataUpdates()
+	if len(updates) == 0 {
+		return
+	}
+	for i, exporter := range kr.metadataExporters {
+		if err := exporter.ConsumeMetadata(updates); err != nil {
+			kr.logger.Error("failed to export metadata",
+				zap.String("exporter", kr.config.MetadataExporters[i]),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
 // newReceiver creates the Kubernetes cluster receiver with the given configuration.
 func newReceiver(
 	logger *zap.Logger,
@@ -95,7 +160,7 @@ func newReceiver(
 	consumer consumer.MetricsConsumerOld,
 ) (component.MetricsReceiver, error) {
 
-	k8sConfig, err := rest.InClusterConfig()
+	k8sConfig, err := createRestConfig(config)
 
 	if err != nil {
 		return nil, err
@@ -105,7 +170,7 @@ func newReceiver(
 	if err != nil {
 		return nil, err
 	}
-	resourceWatcher, err := newResourceWatcher(logger, config, client, false)
+	resourceWatcher, err := newResourceWatcher(logger, config, client)
 
 	if err != nil {
 		return nil, err
@@ -17,6 +17,7 @@ package k8sclusterreceiver
 import (
 	"context"
 	"strconv"
+	"sync"
 	"testing"
 	"time"
 
@@ -30,11 +31,12 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/fake"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/resourcemetadata"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/testutils"
 )
 
 func TestReceiver(t *testing.T) {
-	client := fake.NewSimpleClientset()
+	client := newFakeClientWithAllResources()
 	consumer := &exportertest.SinkMetricsExporterOld{}
 
 	r, err := setupReceiver(client, consumer)
@@ -72,7 +74,7 @@ func TestReceiver(t *testing.T) {
 }
 
 func TestReceiverWithManyResources(t *testing.T) {
-	client := fake.NewSimpleClientset()
+	client := newFakeClientWithAllResources()
 	consumer := &exportertest.SinkMetricsExporterOld{}
 
 	r, err := setupReceiver(client, consumer)
@@ -93,22 +95,167 @@ func TestReceiverWithManyResources(t *testing.T) {
 	r.Shutdown(ctx)
 }
 
-func setupReceiver(client *fake.Clientset,
-	consumer consumer.MetricsConsumerOld) (*kubernetesReceiver, error) {
+func TestReceiverWithEvents(t *testing.T) {
+	client := newFakeClientWithAllResources()
+	consumer := &exportertest.SinkMetricsExporterOld{}
 
-	logger := zap.NewNop()
-	config := &Config{
+	r, err := setupReceiverWithConfig(client, consumer, &Config{
 		CollectionInterval:         1 * time.Second,
 		NodeConditionTypesToReport: []string{"Ready"},
+		CollectEvents:              true,
+	})
+
+	require.NoError(t, err)
+
+	createPods(t, client, 1)
+	e := &corev1.Event{
+		ObjectMeta: v1.ObjectMeta{
+			UID:             types.UID("event0"),
+			Name:            "0.event",
+			Namespace:       "test",
+			ResourceVersion: "1",
+		},
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      "Pod",
+			UID:       types.UID("pod0"),
+			Name:      "0",
+			Namespace: "test",
+		},
+		Reason: "Scheduled",
+		Type:   corev1.EventTypeNormal,
+		Count:  1,
 	}
+	_, err = client.CoreV1().Events(e.Namespace).Create(e)
+	require.NoError(t, err)
 
-	rw, err := newResourceWatcher(logger, config, client, true)
+	ctx := context.Background()
+	r.Start(ctx, componenttest.NewNopHost())
+
+	// Expects metric data from the pod and a record of the event.
+	require.Eventually(t, func() bool {
+		return len(consumer.AllMetrics()) == 2
+	}, 10*time.Second, 100*time.Millisecond,
+		"event not collected")
+
+	// The event is not emitted again until it is updated.
+	e.Count = 2
+	e.ResourceVersion = "2"
+	_, err = client.CoreV1().Events(e.Namespace).Update(e)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		var events int
+		for _, md := range consumer.AllMetrics() {
+			if md.Metrics[0].MetricDescriptor.Name == "kubernetes/event" {
+				events++
+			}
+		}
+		return events == 2
+	}, 10*time.Second, 100*time.Millisecond,
+		"updated event not collected")
+
+	r.Shutdown(ctx)
+}
+
+func TestReceiverWithMetadata(t *testing.T) {
+	client := newFakeClientWithAllResources()
+	consumer := &exportertest.SinkMetricsExporterOld{}
+	exporter := &mockMetadataExporter{}
+	resourcemetadata.RegisterMetadataExporter("mock", exporter)
+	defer resourcemetadata.UnregisterMetadataExporter("mock")
+
+	r, err := setupReceiverWithConfig(client, consumer, &Config{
+		CollectionInterval:         1 * time.Second,
+		NodeConditionTypesToReport: []string{"Ready"},
+		MetadataExporters:          []string{"mock"},
+	})
+
+	require.NoError(t, err)
+
+	createPods(t, client, 2)
+
+	ctx := context.Background()
+	require.NoError(t, r.Start(ctx, componenttest.NewNopHost()))
+
+	// All properties of existing pods are sent on startup.
+	require.Eventually(t, func() bool {
+		return len(exporter.getUpdates()) == 2
+	}, 10*time.Second, 100*time.Millisecond,
+		"metadata not sent")
+
+	for _, u := range exporter.getUpdates() {
+		require.Equal(t, "k8s.pod.uid", u.ResourceIDKey)
+		require.Contains(t, u.MetadataToAdd, "pod.creation_timestamp")
+	}
+
+	// Changes are sent once collected.
+	p, err := client.CoreV1().Pods("test").Get("0", v1.GetOptions{})
+	require.NoError(t, err)
+	p.Labels = map[string]string{"foo": "bar"}
+	_, err = client.CoreV1().Pods("test").Update(p)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		updates := exporter.getUpdates()
+		return len(updates) == 3 && updates[2].ResourceID == "pod0" &&
+			updates[2].MetadataToAdd["foo"] == "bar"
+	}, 10*time.Second, 100*time.Millisecond,
+		"metadata update not sent")
+
+	r.Shutdown(ctx)
+}
+
+func TestReceiverWithMissingMetadataExporter(t *testing.T) {
+	client := newFakeClientWithAllResources()
+	consumer := &exportertest.SinkMetricsExporterOld{}
+
+	r, err := setupReceiverWithConfig(client, consumer, &Config{
+		CollectionInterval: 1 * time.Second,
+		MetadataExporters:  []string{"missing"},
+	})
+
+	require.NoError(t, err)
+	require.EqualError(t, r.Start(context.Background(), componenttest.NewNopHost()),
+		`metadata exporter "missing" not found, it must be a metrics exporter supporting metadata`)
+}
+
+type mockMetadataExporter struct {
+	sync.Mutex
+	updates []*resourcemetadata.MetadataUpdate
+}
+
+func (m *mockMetadataExporter) ConsumeMetadata(metadata []*resourcemetadata.MetadataUpdate) error {
+	m.Lock()
+	defer m.Unlock()
+	m.updates = append(m.updates, metadata...)
+	return nil
+}
+
+func (m *mockMetadataExporter) getUpdates() []*resourcemetadata.MetadataUpdate {
+	m.Lock()
+	defer m.Unlock()
+	return m.updates
+}
+
+func setupReceiver(client *fake.Clientset,
+	consumer consumer.MetricsConsumerOld) (*kubernetesReceiver, error) {
+	return setupReceiverWithConfig(client, consumer, &Config{
+		CollectionInterval:         1 * time.Second,
+		NodeConditionTypesToReport: []string{"Ready"},
+	})
+}
+
+func setupReceiverWithConfig(client *fake.Clientset,
+	consumer consumer.MetricsConsumerOld, config *Config) (*kubernetesReceiver, error) {
+
+	logger := zap.NewNop()
+	rw, err := newResourceWatcher(logger, config, client)
 
 	if err != nil {
 		return nil, err
 	}
 
-	rw.dataCollector.SetupMetadataStore(&corev1.Service{}, &testutils.MockStore{})
+	rw.dataCollector.SetupMetadataStore(&corev1.Service{}, "", &testutils.MockStore{})
 
 	return &kubernetesReceiver{
 		resourceWatcher: rw,
@@ -118,6 +265,29 @@ func setupReceiver(client *fake.Clientset,
 	}, nil
 }
 
+// newFakeClientWithAllResources returns a fake client whose API serves all
+// resources watched by the receiver.
+func newFakeClientWithAllResources() *fake.Clientset {
+	client := fake.NewSimpleClientset()
+	for _, r := range watchedResources {
+		client.Resources = appendAPIResource(client.Resources, r.groupVersion, r.name)
+	}
+	return client
+}
+
+func appendAPIResource(lists []*v1.APIResourceList, groupVersion string, name string) []*v1.APIResourceList {
+	for _, l := range lists {
+		if l.GroupVersion == groupVersion {
+			l.APIResources = append(l.APIResources, v1.APIResource{Name: name})
+			return lists
+		}
+	}
+	return append(lists, &v1.APIResourceList{
+		GroupVersion: groupVersion,
+		APIResources: []v1.APIResource{{Name: name}},
+	})
+}
+
 func createPods(t *testing.T, client *fake.Clientset, numPods int) {
 	for i := 0; i < numPods; i++ {
 		p := &corev1.Pod{
@@ -15,13 +15,21 @@
 package k8sclusterreceiver
 
 import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
 	"go.uber.org/zap"
 	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/api/autoscaling/v2beta1"
 	batchv1 "k8s.io/api/batch/v1"
 	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
@@ -29,69 +37,338 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/collection"
 )
 
+// watchedResource describes a kind of resource watched by the receiver.
+type watchedResource struct {
+	// name is the name of the resource in the API, also used to enable or
+	// disable watching it.
+	name          string
+	groupVersion  string
+	clusterScoped bool
+	object        runtime.Object
+	informer      func(informers.SharedInformerFactory) cache.SharedIndexInformer
+}
+
+// watchedResources lists all kinds of resources the receiver can watch.
+var watchedResources = []watchedResource{
+	{
+		name: "pods", groupVersion: "v1", object: &corev1.Pod{},
+		informer: func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+			return f.Core().V1().Pods().Informer()
+		},
+	},
+	{
+		name: "nodes", groupVersion: "v1", clusterScoped: true, object: &corev1.Node{},
+		informer: func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+			return f.Core().V1().Nodes().Informer()
+		},
+	},
+	{
+		name: "namespaces", groupVersion: "v1", clusterScoped: true, object: &corev1.Namespace{},
+		informer: func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+			return f.Core().V1().Namespaces().Informer()
+		},
+	},
+	{
+		name: "replicationcontrollers", groupVersion: "v1", object: &corev1.ReplicationController{},
+		informer: func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+			return f.Core().V1().ReplicationControllers().Informer()
+		},
+	},
+	{
+		name: "resourcequotas", groupVersion: "v1", object: &corev1.ResourceQuota{},
+		informer: func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+			return f.Core().V1().ResourceQuotas().Informer()
+		},
+	},
+	{
+		name: "services", groupVersion: "v1", object: &corev1.Service{},
+		informer: func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+			return f.Core().V1().Services().Informer()
+		},
+	},
+	{
+		name: "endpoints", groupVersion: "v1", object: &corev1.Endpoints{},
+		informer: func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+			return f.Core().V1().Endpoints().Informer()
+		},
+	},
+	{
+		name: "persistentvolumes", groupVersion: "v1", clusterScoped: true, object: &corev1.PersistentVolume{},
+		informer: func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+			return f.Core().V1().PersistentVolumes().Informer()
+		},
+	},
+	{
+		name: "persistentvolumeclaims", groupVersion: "v1", object: &corev1.PersistentVolumeClaim{},
+		informer: func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+			return f.Core().V1().PersistentVolumeClaims().Informer()
+		},
+	},
+	{
+		name: "daemonsets", groupVersion: "apps/v1", object: &appsv1.DaemonSet{},
+		informer: func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+			return f.Apps().V1().DaemonSets().Informer()
+		},
+	},
+	{
+		name: "deployments", groupVersion: "apps/v1", object: &appsv1.Deployment{},
+		informer: func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+			return f.Apps().V1().Deployments().Informer()
+		},
+	},
+	{
+		name: "replicasets", groupVersion: "apps/v1", object: &appsv1.ReplicaSet{},
+		informer: func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+			return f.Apps().V1().ReplicaSets().Informer()
+		},
+	},
+	{
+		name: "statefulsets", groupVersion: "apps/v1", object: &appsv1.StatefulSet{},
+		informer: func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+			return f.Apps().V1().StatefulSets().Informer()
+		},
+	},
+	{
+		name: "jobs", groupVersion: "batch/v1", object: &batchv1.Job{},
+		informer: func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+			return f.Batch().V1().Jobs().Informer()
+		},
+	},
+	{
+		name: "cronjobs", groupVersion: "batch/v1beta1", object: &batchv1beta1.CronJob{},
+		informer: func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+			return f.Batch().V1beta1().CronJobs().Informer()
+		},
+	},
+	{
+		name: "horizontalpodautoscalers", groupVersion: "autoscaling/v2beta1", object: &v2beta1.HorizontalPodAutoscaler{},
+		informer: func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+			return f.Autoscaling().V2beta1().HorizontalPodAutoscalers().Informer()
+		},
+	},
+	{
+		name: "ingresses", groupVersion: "networking.k8s.io/v1beta1", object: &networkingv1beta1.Ingress{},
+		informer: func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+			return f.Networking().V1beta1().Ingresses().Informer()
+		},
+	},
+}
+
+// Backoff between checks for resource APIs missing from the API server.
+var defaultMissingAPIBackoff = wait.Backoff{
+	Duration: 30 * time.Second,
+	Factor:   2,
+	Steps:    math.MaxInt32,
+	Cap:      30 * time.Minute,
+}
+
 type resourceWatcher struct {
-	client                kubernetes.Interface
-	sharedInformerFactory informers.SharedInformerFactory
-	dataCollector         *collection.DataCollector
-	logger                *zap.Logger
-	// This field is temporary and will be removed once the
-	// metadata syncing details are finalized.
+	client kubernetes.Interface
+	// mu guards sharedInformerFactories, updated when missing resources
+	// become available.
+	mu sync.Mutex
+	// sharedInformerFactories holds an informer factory per watched namespace,
+	// or a single one keyed by "" when watching all namespaces. Cluster scoped
+	// resources are always watched by the factory keyed by "".
+	sharedInformerFactories map[string]informers.SharedInformerFactory
+	namespaces              []string
+	dataCollector           *collection.DataCollector
+	logger                  *zap.Logger
+	// Metadata is collected only if there's at least one metadata exporter.
 	collectMedata bool
+	// missingResources are enabled resources whose API is not served, they
+	// are watched once available.
+	missingResources  []watchedResource
+	missingAPIBackoff wait.Backoff
 }
 
 // newResourceWatcher creates a Kubernetes resource watcher.
 func newResourceWatcher(logger *zap.Logger, config *Config,
-	client kubernetes.Interface, collectMetadata bool) (*resourceWatcher, error) {
+	client kubernetes.Interface) (*resourceWatcher, error) {
 	rw := &resourceWatcher{
-		client:        client,
-		logger:        logger,
-		dataCollector: collection.NewDataCollector(logger, config.NodeConditionTypesToReport),
-		collectMedata: collectMetadata,
+		client:                  client,
+		logger:                  logger,
+		sharedInformerFactories: map[string]informers.SharedInformerFactory{},
+		namespaces:              config.Namespaces,
+		dataCollector:           collection.NewDataCollector(logger, config.NodeConditionTypesToReport),
+		collectMedata:           len(config.MetadataExporters) > 0,
+		missingAPIBackoff:       defaultMissingAPIBackoff,
+	}
+	if len(rw.namespaces) == 0 {
+		rw.namespaces = []string{metav1.NamespaceAll}
 	}
 
-	rw.prepareSharedInformerFactory()
+	if err := rw.prepareSharedInformerFactories(config); err != nil {
+		return nil, err
+	}
 
 	return rw, nil
 }
 
-func (rw *resourceWatcher) prepareSharedInformerFactory() {
-	factory := informers.NewSharedInformerFactoryWithOptions(rw.client, 0)
+func (rw *resourceWatcher) prepareSharedInformerFactories(config *Config) error {
+	for name := range config.Resources {
+		if !isWatchedResource(name) {
+			return fmt.Errorf("%q is not a supported resource", name)
+		}
+	}
 
 	// Add shared informers for each resource type that has to be watched.
-	rw.setupInformers(&corev1.Pod{}, factory.Core().V1().Pods().Informer())
-	rw.setupInformers(&corev1.Node{}, factory.Core().V1().Nodes().Informer())
-	rw.setupInformers(&corev1.Namespace{}, factory.Core().V1().Namespaces().Informer())
-	rw.setupInformers(&corev1.ReplicationController{},
-		factory.Core().V1().ReplicationControllers().Informer(),
-	)
-	rw.setupInformers(&corev1.ResourceQuota{}, factory.Core().V1().ResourceQuotas().Informer())
-	rw.setupInformers(&corev1.Service{}, factory.Core().V1().Services().Informer())
-	rw.setupInformers(&appsv1.DaemonSet{}, factory.Apps().V1().DaemonSets().Informer())
-	rw.setupInformers(&appsv1.Deployment{}, factory.Apps().V1().Deployments().Informer())
-	rw.setupInformers(&appsv1.ReplicaSet{}, factory.Apps().V1().ReplicaSets().Informer())
-	rw.setupInformers(&appsv1.StatefulSet{}, factory.Apps().V1().StatefulSets().Informer())
-	rw.setupInformers(&batchv1.Job{}, factory.Batch().V1().Jobs().Informer())
-	rw.setupInformers(&batchv1beta1.CronJob{}, factory.Batch().V1beta1().CronJobs().Informer())
-	rw.setupInformers(&v2beta1.HorizontalPodAutoscaler{},
-		factory.Autoscaling().V2beta1().HorizontalPodAutoscalers().Informer(),
-	)
-
-	rw.sharedInformerFactory = factory
+	for _, r := range watchedResources {
+		if enabled, ok := config.Resources[r.name]; ok && !enabled {
+			continue
+		}
+		if !rw.isAPIAvailable(r) {
+			rw.logger.Warn("resource API not available, will watch it once available",
+				zap.String("resource", r.name),
+				zap.String("groupVersion", r.groupVersion),
+			)
+			rw.missingResources = append(rw.missingResources, r)
+			continue
+		}
+		rw.setupResource(r)
+	}
+
+	if config.CollectEvents {
+		for _, ns := range rw.namespaces {
+			rw.setupEventsInformer(rw.informerFactory(ns).Core().V1().Events().Informer())
+		}
+	}
+
+	return nil
+}
+
+func isWatchedResource(name string) bool {
+	for _, r := range watchedResources {
+		if r.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// informerFactory returns the informer factory of the namespace, created if needed.
+func (rw *resourceWatcher) informerFactory(namespace string) informers.SharedInformerFactory {
+	factory, ok := rw.sharedInformerFactories[namespace]
+	if !ok {
+		factory = informers.NewSharedInformerFactoryWithOptions(rw.client, 0,
+			informers.WithNamespace(namespace))
+		rw.sharedInformerFactories[namespace] = factory
+	}
+	return factory
+}
+
+// setupResource adds informers watching the resource in all watched namespaces.
+func (rw *resourceWatcher) setupResource(r watchedResource) {
+	if r.clusterScoped {
+		rw.setupInformers(r.object, metav1.NamespaceAll, r.informer(rw.informerFactory(metav1.NamespaceAll)))
+		return
+	}
+	for _, ns := range rw.namespaces {
+		rw.setupInformers(r.object, ns, r.informer(rw.informerFactory(ns)))
+	}
+}
+
+// isAPIAvailable returns whether the API server serves the resource.
+func (rw *resourceWatcher) isAPIAvailable(r watchedResource) bool {
+	resources, err := rw.client.Discovery().ServerResourcesForGroupVersion(r.groupVersion)
+	if err != nil {
+		rw.logger.Debug("failed to discover resources",
+			zap.String("groupVersion", r.groupVersion), zap.Error(err))
+		return false
+	}
+	for _, res := range resources.APIResources {
+		if res.Name == r.name {
+			return true
+		}
+	}
+	return false
 }
 
 // startWatchingResources starts up all informers.
 func (rw *resourceWatcher) startWatchingResources(stopper <-chan struct{}) {
-	rw.sharedInformerFactory.Start(stopper)
+	for _, factory := range rw.informerFactories() {
+		factory.Start(stopper)
+	}
+	for _, r := range rw.missingResources {
+		go rw.watchWhenAvailable(r, stopper)
+	}
+}
+
+// watchWhenAvailable checks whether the resource API became available, backing
+// off between checks, and starts watching the resource once it is.
+func (rw *resourceWatcher) watchWhenAvailable(r watchedResource, stopper <-chan struct{}) {
+	backoff := rw.missingAPIBackoff
+	for {
+		select {
+		case <-time.After(backoff.Step()):
+		case <-stopper:
+			return
+		}
+
+		if rw.isAPIAvailable(r) {
+			break
+		}
+	}
+
+	rw.logger.Info("resource API available, watching resource", zap.String("resource", r.name))
+
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	rw.setupResource(r)
+	for _, factory := range rw.sharedInformerFactories {
+		factory.Start(stopper)
+	}
+}
+
+// informerFactories returns all informer factories.
+func (rw *resourceWatcher) informerFactories() []informers.SharedInformerFactory {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+
+	out := make([]informers.SharedInformerFactory, 0, len(rw.sharedInformerFactories))
+	for _, factory := range rw.sharedInformerFactories {
+		out = append(out, factory)
+	}
+	return out
+}
+
+// waitForInitialSync blocks until all informers listed existing resources, or
+// until stopped.
+func (rw *resourceWatcher) waitForInitialSync(stopper <-chan struct{}) {
+	for _, factory := range rw.informerFactories() {
+		for t, synced := range factory.WaitForCacheSync(stopper) {
+			if !synced {
+				rw.logger.Warn("informer cache not synced", zap.String("type", t.String()))
+			}
+		}
+	}
 }
 
 // setupInformers adds event handlers to informers and setups a metadataStore.
-func (rw *resourceWatcher) setupInformers(o runtime.Object, informer cache.SharedIndexInformer) {
+func (rw *resourceWatcher) setupInformers(o runtime.Object, namespace string, informer cache.SharedIndexInformer) {
 	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    rw.onAdd,
 		UpdateFunc: rw.onUpdate,
 		DeleteFunc: rw.onDelete,
 	})
-	rw.dataCollector.SetupMetadataStore(o, informer.GetStore())
+	rw.dataCollector.SetupMetadataStore(o, namespace, informer.GetStore())
+}
+
+// setupEventsInformer adds event handlers recording Kubernetes events. Events
+// are kept apart from other resources since they are emitted only once.
+func (rw *resourceWatcher) setupEventsInformer(informer cache.SharedIndexInformer) {
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: rw.dataCollector.SyncEvent,
+		UpdateFunc: func(_ interface{}, newObj interface{}) {
+			rw.dataCollector.SyncEvent(newObj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			rw.dataCollector.RemoveEvent(obj)
+		},
+	})
 }
 
 func (rw *resourceWatcher) onAdd(obj interface{}) {
@@ -99,7 +376,14 @@ func (rw *resourceWatcher) onAdd(obj interface{}) {
 }
 
 func (rw *resourceWatcher) onDelete(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
 	rw.dataCollector.RemoveFromMetricsStore(obj)
+
+	if rw.collectMedata {
+		rw.dataCollector.RemoveFromMetadataStore(obj)
+	}
 }
 
 func (rw *resourceWatcher) onUpdate(_ interface{}, newObj interface{}) {
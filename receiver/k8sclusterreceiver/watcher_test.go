@@ -0,0 +1,131 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sclusterreceiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector/component/componenttest"
+	"github.com/open-telemetry/opentelemetry-collector/exporter/exportertest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestPrepareSharedInformerFactories(t *testing.T) {
+	rw, err := newResourceWatcher(zap.NewNop(), &Config{
+		Namespaces: []string{"ns1", "ns2"},
+		Resources:  map[string]bool{"cronjobs": false, "pods": true},
+	}, newFakeClientWithAllResources())
+	require.NoError(t, err)
+
+	// Namespaced resources are watched in each namespace, cluster scoped
+	// resources cluster wide.
+	assert.Equal(t, 3, len(rw.sharedInformerFactories))
+	assert.Contains(t, rw.sharedInformerFactories, "ns1")
+	assert.Contains(t, rw.sharedInformerFactories, "ns2")
+	assert.Contains(t, rw.sharedInformerFactories, "")
+	assert.Empty(t, rw.missingResources)
+
+	_, err = newResourceWatcher(zap.NewNop(), &Config{
+		Resources: map[string]bool{"foos": false},
+	}, newFakeClientWithAllResources())
+	require.EqualError(t, err, `"foos" is not a supported resource`)
+}
+
+func TestNamespacedWatch(t *testing.T) {
+	client := newFakeClientWithAllResources()
+	consumer := &exportertest.SinkMetricsExporterOld{}
+
+	r, err := setupReceiverWithConfig(client, consumer, &Config{
+		CollectionInterval: 1 * time.Second,
+		Namespaces:         []string{"test"},
+		Resources:          map[string]bool{"nodes": false},
+	})
+	require.NoError(t, err)
+
+	createPods(t, client, 2)
+	createNodes(t, client, 1)
+	_, err = client.CoreV1().Pods("other").Create(&corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{UID: "other-pod", Name: "other-pod", Namespace: "other"},
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, r.Start(ctx, componenttest.NewNopHost()))
+	defer r.Shutdown(ctx)
+
+	// Expects metric data only from pods in the watched namespace.
+	require.Eventually(t, func() bool {
+		return len(r.resourceWatcher.dataCollector.CollectMetricData()) == 2
+	}, 10*time.Second, 100*time.Millisecond,
+		"metrics not collected")
+	require.Never(t, func() bool {
+		return len(r.resourceWatcher.dataCollector.CollectMetricData()) != 2
+	}, 500*time.Millisecond, 100*time.Millisecond)
+}
+
+func TestMissingResourceAPI(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	for _, r := range watchedResources {
+		if r.name != "cronjobs" {
+			client.Resources = appendAPIResource(client.Resources, r.groupVersion, r.name)
+		}
+	}
+
+	rw, err := newResourceWatcher(zap.NewNop(), &Config{}, client)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(rw.missingResources))
+	require.Equal(t, "cronjobs", rw.missingResources[0].name)
+
+	cronJob := &batchv1beta1.CronJob{
+		ObjectMeta: v1.ObjectMeta{UID: "cronjob", Name: "cronjob", Namespace: "test"},
+	}
+	_, err = client.BatchV1beta1().CronJobs("test").Create(cronJob)
+	require.NoError(t, err)
+
+	// The resource is watched once its API is available.
+	client.Resources = appendAPIResource(client.Resources, "batch/v1beta1", "cronjobs")
+	rw.missingAPIBackoff = wait.Backoff{Duration: 10 * time.Millisecond}
+
+	stopper := make(chan struct{})
+	defer close(stopper)
+	rw.startWatchingResources(stopper)
+
+	require.Eventually(t, func() bool {
+		return len(rw.dataCollector.CollectMetricData()) == 1
+	}, 10*time.Second, 100*time.Millisecond,
+		"metrics not collected")
+}
+
+func TestCreateRestConfig(t *testing.T) {
+	restConfig, err := createRestConfig(&Config{
+		AuthType:       AuthTypeKubeConfig,
+		KubeConfigPath: "testdata/kubeconfig.yaml",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "https://kubernetes.example.com:6443", restConfig.Host)
+	assert.Equal(t, "test-token", restConfig.BearerToken)
+
+	_, err = createRestConfig(&Config{AuthType: "none"})
+	require.EqualError(t, err, `invalid auth_type "none", must be "serviceAccount" or "kubeConfig"`)
+}
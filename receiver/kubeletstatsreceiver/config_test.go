@@ -0,0 +1,65 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubeletstatsreceiver
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector/config"
+	"github.com/open-telemetry/opentelemetry-collector/config/configmodels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kubeletstatsreceiver/kubelet"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := config.ExampleComponents()
+	assert.Nil(t, err)
+
+	factory := &Factory{}
+	factories.Receivers[configmodels.Type(typeStr)] = factory
+	cfg, err := config.LoadConfigFile(
+		t, path.Join(".", "testdata", "config.yaml"), factories,
+	)
+
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	assert.Equal(t, len(cfg.Receivers), 2)
+
+	r1 := cfg.Receivers["kubeletstats"]
+	assert.Equal(t, r1, factory.CreateDefaultConfig())
+
+	r2 := cfg.Receivers["kubeletstats/all_settings"].(*Config)
+	assert.Equal(t, r2,
+		&Config{
+			ReceiverSettings: configmodels.ReceiverSettings{
+				TypeVal:  configmodels.Type(typeStr),
+				NameVal:  "kubeletstats/all_settings",
+				Endpoint: "10.0.0.1:10250",
+			},
+			ClientConfig: kubelet.ClientConfig{
+				AuthType:           kubelet.AuthTypeTLS,
+				CAFile:             "/etc/kubelet/ca.crt",
+				CertFile:           "/etc/kubelet/client.crt",
+				KeyFile:            "/etc/kubelet/client.key",
+				InsecureSkipVerify: true,
+			},
+			CollectionInterval: 30 * time.Second,
+		})
+}
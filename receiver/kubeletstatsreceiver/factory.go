@@ -0,0 +1,80 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubeletstatsreceiver
+
+import (
+	"context"
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector/component"
+	"github.com/open-telemetry/opentelemetry-collector/config/configerror"
+	"github.com/open-telemetry/opentelemetry-collector/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-collector/consumer"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kubeletstatsreceiver/kubelet"
+)
+
+const (
+	// Value of "type" key in configuration.
+	typeStr = "kubeletstats"
+
+	// Default config values.
+	defaultEndpoint           = "localhost:10250"
+	defaultCollectionInterval = 10 * time.Second
+)
+
+var _ component.ReceiverFactoryOld = (*Factory)(nil)
+
+// Factory is the factory for kubelet stats receiver.
+type Factory struct {
+}
+
+func (f Factory) Type() configmodels.Type {
+	return typeStr
+}
+
+func (f Factory) CreateDefaultConfig() configmodels.Receiver {
+	return &Config{
+		ReceiverSettings: configmodels.ReceiverSettings{
+			TypeVal:  typeStr,
+			NameVal:  typeStr,
+			Endpoint: defaultEndpoint,
+		},
+		ClientConfig: kubelet.ClientConfig{
+			AuthType: kubelet.AuthTypeServiceAccount,
+		},
+		CollectionInterval: defaultCollectionInterval,
+	}
+}
+
+func (f Factory) CustomUnmarshaler() component.CustomUnmarshaler {
+	return nil
+}
+
+func (f Factory) CreateTraceReceiver(ctx context.Context, logger *zap.Logger, cfg configmodels.Receiver,
+	consumer consumer.TraceConsumerOld) (component.TraceReceiver, error) {
+	return nil, configerror.ErrDataTypeIsNotSupported
+}
+
+func (f Factory) CreateMetricsReceiver(logger *zap.Logger, cfg configmodels.Receiver,
+	consumer consumer.MetricsConsumerOld) (component.MetricsReceiver, error) {
+	rCfg := cfg.(*Config)
+	client, err := kubelet.NewClient(rCfg.Endpoint, &rCfg.ClientConfig, logger)
+	if err != nil {
+		return nil, err
+	}
+	return newReceiver(logger, rCfg, client, consumer), nil
+}
@@ -0,0 +1,51 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubeletstatsreceiver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/open-telemetry/opentelemetry-collector/config/configcheck"
+	"github.com/open-telemetry/opentelemetry-collector/config/configerror"
+	"github.com/open-telemetry/opentelemetry-collector/exporter/exportertest"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kubeletstatsreceiver/kubelet"
+)
+
+func TestFactory(t *testing.T) {
+	f := &Factory{}
+	require.Equal(t, "kubeletstats", string(f.Type()))
+
+	cfg := f.CreateDefaultConfig()
+	require.NoError(t, configcheck.ValidateConfig(cfg))
+
+	r, err := f.CreateTraceReceiver(context.Background(), zap.NewNop(), cfg, exportertest.NewNopTraceExporterOld())
+	require.Equal(t, configerror.ErrDataTypeIsNotSupported, err)
+	require.Nil(t, r)
+
+	rCfg := cfg.(*Config)
+	rCfg.AuthType = kubelet.AuthTypeNone
+	r, err = f.CreateMetricsReceiver(zap.NewNop(), rCfg, exportertest.NewNopMetricsExporterOld())
+	require.NoError(t, err)
+	require.NotNil(t, r)
+
+	rCfg.AuthType = "invalid"
+	r, err = f.CreateMetricsReceiver(zap.NewNop(), rCfg, exportertest.NewNopMetricsExporterOld())
+	require.Error(t, err)
+	require.Nil(t, r)
+}
@@ -0,0 +1,13 @@
+module github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kubeletstatsreceiver
+
+go 1.14
+
+require (
+	github.com/census-instrumentation/opencensus-proto v0.2.1
+	github.com/golang/protobuf v1.3.5
+	github.com/open-telemetry/opentelemetry-collector v0.3.1-0.20200427150635-ca4b8231de7c
+	github.com/stretchr/testify v1.5.1
+	go.uber.org/zap v1.14.1
+	k8s.io/api v0.17.4
+	k8s.io/apimachinery v0.17.4
+)
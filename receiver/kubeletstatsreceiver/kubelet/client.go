@@ -0,0 +1,172 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubelet
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// AuthType describes how the receiver authenticates to the kubelet.
+type AuthType string
+
+const (
+	// AuthTypeNone sends plain HTTP requests to the read only port of the kubelet.
+	AuthTypeNone AuthType = "none"
+	// AuthTypeTLS authenticates with a client certificate.
+	AuthTypeTLS AuthType = "tls"
+	// AuthTypeServiceAccount authenticates with the token of the service
+	// account of the pod the collector is running in.
+	AuthTypeServiceAccount AuthType = "serviceAccount"
+)
+
+// Locations of the credentials of the service account, set by kubernetes.
+var (
+	svcAcctCACertPath = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
+	svcAcctTokenPath  = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+)
+
+const defaultTimeout = 10 * time.Second
+
+// ClientConfig defines how to connect to the kubelet.
+type ClientConfig struct {
+	// AuthType is how to authenticate to the kubelet: none, tls or serviceAccount.
+	AuthType AuthType `mapstructure:"auth_type"`
+	// CAFile is the path of the CA certificate verifying the kubelet serving
+	// certificate. Defaults to the CA of the service account with the
+	// serviceAccount auth type, and to system CAs otherwise.
+	CAFile string `mapstructure:"ca_file"`
+	// CertFile and KeyFile are the paths of the client certificate and key
+	// used with the tls auth type.
+	CertFile string `mapstructure:"cert_file"`
+	KeyFile  string `mapstructure:"key_file"`
+	// InsecureSkipVerify disables verification of the kubelet serving
+	// certificate, often self signed.
+	InsecureSkipVerify bool `mapstructure:"insecure_skip_verify"`
+}
+
+// Client gets resources from the kubelet API.
+type Client interface {
+	// Get returns the body of the response to a GET request of the path.
+	Get(path string) ([]byte, error)
+}
+
+type clientImpl struct {
+	baseURL   string
+	tokenPath string
+	client    *http.Client
+	logger    *zap.Logger
+}
+
+// NewClient returns a client of the kubelet listening at endpoint, either
+// host:port or a URL.
+func NewClient(endpoint string, cfg *ClientConfig, logger *zap.Logger) (Client, error) {
+	scheme := "https"
+	if cfg.AuthType == AuthTypeNone {
+		scheme = "http"
+	}
+	if !strings.Contains(endpoint, "://") {
+		endpoint = scheme + "://" + endpoint
+	}
+	if _, err := url.Parse(endpoint); err != nil {
+		return nil, fmt.Errorf("invalid kubelet endpoint %q: %v", endpoint, err)
+	}
+
+	c := &clientImpl{
+		baseURL: strings.TrimSuffix(endpoint, "/"),
+		client:  &http.Client{Timeout: defaultTimeout},
+		logger:  logger,
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: cfg.InsecureSkipVerify}
+	caFile := cfg.CAFile
+	switch cfg.AuthType {
+	case AuthTypeNone:
+		return c, nil
+	case AuthTypeTLS:
+		if cfg.CertFile == "" || cfg.KeyFile == "" {
+			return nil, fmt.Errorf("%q auth type requires cert_file and key_file", cfg.AuthType)
+		}
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	case AuthTypeServiceAccount:
+		if caFile == "" {
+			caFile = svcAcctCACertPath
+		}
+		c.tokenPath = svcAcctTokenPath
+	default:
+		return nil, fmt.Errorf("invalid auth_type %q, must be %q, %q or %q",
+			cfg.AuthType, AuthTypeNone, AuthTypeTLS, AuthTypeServiceAccount)
+	}
+
+	if caFile != "" && !cfg.InsecureSkipVerify {
+		pem, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate: %v", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no CA certificate found in %s", caFile)
+		}
+	}
+	c.client.Transport = &http.Transport{TLSClientConfig: tlsConfig}
+
+	return c, nil
+}
+
+func (c *clientImpl) Get(path string) ([]byte, error) {
+	req, err := http.NewRequest("GET", c.baseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	c.logger.Debug("Requesting kubelet", zap.String("url", req.URL.String()))
+
+	// Tokens of service accounts may be rotated, it is read for every request.
+	if c.tokenPath != "" {
+		token, err := ioutil.ReadFile(c.tokenPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read service account token: %v", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("kubelet request %s failed: HTTP %d %q",
+			path, resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+	return body, nil
+}
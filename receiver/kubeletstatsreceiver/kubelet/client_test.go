@@ -0,0 +1,130 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubelet
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestServiceAccountClient(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer s3cr3t" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(r.URL.Path))
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "kubelet")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	setServiceAccountPaths(t, dir, server)
+
+	client, err := NewClient(server.Listener.Addr().String(), &ClientConfig{
+		AuthType: AuthTypeServiceAccount,
+	}, zap.NewNop())
+	require.NoError(t, err)
+
+	body, err := client.Get("/stats/summary")
+	require.NoError(t, err)
+	require.Equal(t, "/stats/summary", string(body))
+
+	// Rotated tokens are picked up.
+	require.NoError(t, ioutil.WriteFile(svcAcctTokenPath, []byte("other"), 0600))
+	_, err = client.Get("/stats/summary")
+	require.EqualError(t, err, `kubelet request /stats/summary failed: HTTP 401 "Unauthorized"`)
+}
+
+func TestNoneClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Empty(t, r.Header.Get("Authorization"))
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.Listener.Addr().String(), &ClientConfig{
+		AuthType: AuthTypeNone,
+	}, zap.NewNop())
+	require.NoError(t, err)
+
+	body, err := client.Get("/pods")
+	require.NoError(t, err)
+	require.Equal(t, "{}", string(body))
+}
+
+func TestNewClientErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  ClientConfig
+		err  string
+	}{
+		{
+			name: "invalid auth type",
+			cfg:  ClientConfig{AuthType: "token"},
+			err:  `invalid auth_type "token", must be "none", "tls" or "serviceAccount"`,
+		},
+		{
+			name: "tls without certificate",
+			cfg:  ClientConfig{AuthType: AuthTypeTLS},
+			err:  `"tls" auth type requires cert_file and key_file`,
+		},
+		{
+			name: "tls with missing certificate",
+			cfg:  ClientConfig{AuthType: AuthTypeTLS, CertFile: "testdata/missing.crt", KeyFile: "testdata/missing.key"},
+			err:  "failed to load client certificate: open testdata/missing.crt: no such file or directory",
+		},
+		{
+			name: "missing CA",
+			cfg:  ClientConfig{AuthType: AuthTypeServiceAccount, CAFile: "testdata/missing.crt"},
+			err:  "failed to read CA certificate: open testdata/missing.crt: no such file or directory",
+		},
+		{
+			name: "invalid CA",
+			cfg:  ClientConfig{AuthType: AuthTypeServiceAccount, CAFile: "testdata/pods.json"},
+			err:  "no CA certificate found in testdata/pods.json",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewClient("localhost:10250", &tt.cfg, zap.NewNop())
+			require.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+// setServiceAccountPaths points the service account credentials to files in
+// dir, trusting the certificate of server.
+func setServiceAccountPaths(t *testing.T, dir string, server *httptest.Server) {
+	caPath, tokenPath := svcAcctCACertPath, svcAcctTokenPath
+	t.Cleanup(func() {
+		svcAcctCACertPath, svcAcctTokenPath = caPath, tokenPath
+	})
+
+	svcAcctCACertPath = filepath.Join(dir, "ca.crt")
+	svcAcctTokenPath = filepath.Join(dir, "token")
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, ioutil.WriteFile(svcAcctCACertPath, ca, 0600))
+	require.NoError(t, ioutil.WriteFile(svcAcctTokenPath, []byte("s3cr3t\n"), 0600))
+}
@@ -0,0 +1,318 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubelet
+
+import (
+	"strings"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/open-telemetry/opentelemetry-collector/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-collector/translator/conventions"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Resource label keys, the same as the ones of the k8s_cluster receiver so
+// that metrics of both receivers can be joined.
+const (
+	// Resource types.
+	k8sType       = "k8s"
+	containerType = "container"
+
+	k8sKeyNodeName       = "k8s.node.name"
+	k8sKeyPodUID         = "k8s.pod.uid"
+	k8sKeyVolumeName     = "k8s.volume.name"
+	containerKeyID       = "container.id"
+	containerKeySpecName = "container.spec.name"
+
+	// Label keys.
+	labelInterface = "interface"
+	labelDirection = "direction"
+)
+
+// Metadata provides information about resources that is not part of the
+// stats summary.
+type Metadata struct {
+	// containerIDs maps pod UIDs to IDs of their containers by container name.
+	containerIDs map[string]map[string]string
+}
+
+// NewMetadata returns metadata of the pods running on the node.
+func NewMetadata(pods *corev1.PodList) Metadata {
+	m := Metadata{containerIDs: map[string]map[string]string{}}
+	if pods == nil {
+		return m
+	}
+	for _, pod := range pods.Items {
+		ids := map[string]string{}
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.ContainerID != "" {
+				ids[cs.Name] = stripContainerID(cs.ContainerID)
+			}
+		}
+		m.containerIDs[string(pod.UID)] = ids
+	}
+	return m
+}
+
+// stripContainerID returns the container ID without the runtime scheme,
+// e.g. docker://.
+func stripContainerID(id string) string {
+	if i := strings.Index(id, "://"); i >= 0 {
+		return id[i+3:]
+	}
+	return id
+}
+
+// MetricsData returns metrics of the node, pods, containers and volumes of
+// the summary, one MetricsData per resource.
+func MetricsData(summary *Summary, metadata Metadata) []*consumerdata.MetricsData {
+	var out []*consumerdata.MetricsData
+
+	node := summary.Node
+	nodeLabels := map[string]string{k8sKeyNodeName: node.NodeName}
+	nb := newMetricsBuilder("kubernetes/node/", node.StartTime.Time)
+	nb.addCPUMetrics(node.CPU)
+	nb.addMemoryMetrics(node.Memory)
+	nb.addFilesystemMetrics("filesystem/", node.Fs)
+	nb.addNetworkMetrics(node.Network)
+	out = appendMetricsData(out, k8sType, nodeLabels, nb.metrics)
+
+	for _, pod := range summary.Pods {
+		podLabels := map[string]string{
+			k8sKeyNodeName:                    node.NodeName,
+			k8sKeyPodUID:                      pod.PodRef.UID,
+			conventions.AttributeK8sPod:       pod.PodRef.Name,
+			conventions.AttributeK8sNamespace: pod.PodRef.Namespace,
+		}
+
+		pb := newMetricsBuilder("kubernetes/pod/", pod.StartTime.Time)
+		pb.addCPUMetrics(pod.CPU)
+		pb.addMemoryMetrics(pod.Memory)
+		pb.addFilesystemMetrics("ephemeral_storage/", pod.EphemeralStorage)
+		pb.addNetworkMetrics(pod.Network)
+		out = appendMetricsData(out, k8sType, podLabels, pb.metrics)
+
+		for _, container := range pod.Containers {
+			containerLabels := copyLabels(podLabels)
+			containerLabels[containerKeySpecName] = container.Name
+			if id, ok := metadata.containerIDs[pod.PodRef.UID][container.Name]; ok {
+				containerLabels[containerKeyID] = id
+			}
+
+			cb := newMetricsBuilder("kubernetes/container/", container.StartTime.Time)
+			cb.addCPUMetrics(container.CPU)
+			cb.addMemoryMetrics(container.Memory)
+			cb.addFilesystemMetrics("filesystem/", container.Rootfs)
+			cb.addFilesystemMetrics("logs/", container.Logs)
+			out = appendMetricsData(out, containerType, containerLabels, cb.metrics)
+		}
+
+		for _, volume := range pod.VolumeStats {
+			volumeLabels := copyLabels(podLabels)
+			volumeLabels[k8sKeyVolumeName] = volume.Name
+
+			vb := newMetricsBuilder("kubernetes/volume/", pod.StartTime.Time)
+			vb.addFilesystemMetrics("", &volume.FsStats)
+			out = appendMetricsData(out, k8sType, volumeLabels, vb.metrics)
+		}
+	}
+
+	return out
+}
+
+func appendMetricsData(out []*consumerdata.MetricsData, resourceType string,
+	labels map[string]string, metrics []*metricspb.Metric) []*consumerdata.MetricsData {
+	if len(metrics) == 0 {
+		return out
+	}
+	return append(out, &consumerdata.MetricsData{
+		Resource: &resourcepb.Resource{
+			Type:   resourceType,
+			Labels: labels,
+		},
+		Metrics: metrics,
+	})
+}
+
+func copyLabels(labels map[string]string) map[string]string {
+	out := make(map[string]string, len(labels))
+	for k, v := range labels {
+		out[k] = v
+	}
+	return out
+}
+
+// metricsBuilder builds metrics of a resource. Cumulative metrics start
+// when the resource started.
+type metricsBuilder struct {
+	prefix    string
+	startTime *timestamp.Timestamp
+	metrics   []*metricspb.Metric
+}
+
+func newMetricsBuilder(prefix string, startTime time.Time) *metricsBuilder {
+	return &metricsBuilder{
+		prefix:    prefix,
+		startTime: toTimestamp(startTime),
+	}
+}
+
+func (b *metricsBuilder) addCPUMetrics(s *CPUStats) {
+	if s == nil {
+		return
+	}
+	ts := toTimestamp(s.Time.Time)
+	if s.UsageNanoCores != nil {
+		b.addDouble("cpu/utilization", "CPU usage, in cores",
+			metricspb.MetricDescriptor_GAUGE_DOUBLE, ts, float64(*s.UsageNanoCores)/1e9)
+	}
+	if s.UsageCoreNanoSeconds != nil {
+		b.addDouble("cpu/time", "Cumulative CPU time, in seconds",
+			metricspb.MetricDescriptor_CUMULATIVE_DOUBLE, ts, float64(*s.UsageCoreNanoSeconds)/1e9)
+	}
+}
+
+func (b *metricsBuilder) addMemoryMetrics(s *MemoryStats) {
+	if s == nil {
+		return
+	}
+	ts := toTimestamp(s.Time.Time)
+	b.addGaugeInt64("memory/available", "Memory available, in bytes", ts, s.AvailableBytes)
+	b.addGaugeInt64("memory/usage", "Memory usage, in bytes", ts, s.UsageBytes)
+	b.addGaugeInt64("memory/working_set", "Memory working set, in bytes", ts, s.WorkingSetBytes)
+	b.addGaugeInt64("memory/rss", "Resident memory, in bytes", ts, s.RSSBytes)
+	b.addCumulativeInt64("memory/page_faults", "Cumulative number of page faults", ts, s.PageFaults)
+	b.addCumulativeInt64("memory/major_page_faults", "Cumulative number of major page faults", ts, s.MajorPageFaults)
+}
+
+func (b *metricsBuilder) addFilesystemMetrics(prefix string, s *FsStats) {
+	if s == nil {
+		return
+	}
+	ts := toTimestamp(s.Time.Time)
+	b.addGaugeInt64(prefix+"available", "Filesystem space available, in bytes", ts, s.AvailableBytes)
+	b.addGaugeInt64(prefix+"capacity", "Filesystem capacity, in bytes", ts, s.CapacityBytes)
+	b.addGaugeInt64(prefix+"usage", "Filesystem space used, in bytes", ts, s.UsedBytes)
+	b.addGaugeInt64(prefix+"inodes", "Filesystem inodes", ts, s.Inodes)
+	b.addGaugeInt64(prefix+"inodes/free", "Filesystem inodes free", ts, s.InodesFree)
+	b.addGaugeInt64(prefix+"inodes/used", "Filesystem inodes used", ts, s.InodesUsed)
+}
+
+func (b *metricsBuilder) addNetworkMetrics(s *NetworkStats) {
+	if s == nil {
+		return
+	}
+	ts := toTimestamp(s.Time.Time)
+	i := s.InterfaceStats
+	b.addNetworkMetric("network/io", "Cumulative network traffic, in bytes", ts, i.Name, i.RxBytes, i.TxBytes)
+	b.addNetworkMetric("network/errors", "Cumulative number of network errors", ts, i.Name, i.RxErrors, i.TxErrors)
+}
+
+// addNetworkMetric adds a metric with a timeseries per direction of traffic.
+func (b *metricsBuilder) addNetworkMetric(name, description string, ts *timestamp.Timestamp,
+	iface string, rx, tx *uint64) {
+	var timeseries []*metricspb.TimeSeries
+	for _, dv := range []struct {
+		direction string
+		value     *uint64
+	}{{"receive", rx}, {"transmit", tx}} {
+		if dv.value == nil {
+			continue
+		}
+		timeseries = append(timeseries, &metricspb.TimeSeries{
+			StartTimestamp: b.startTime,
+			LabelValues: []*metricspb.LabelValue{
+				{Value: iface, HasValue: iface != ""},
+				{Value: dv.direction, HasValue: true},
+			},
+			Points: []*metricspb.Point{int64Point(ts, *dv.value)},
+		})
+	}
+	if len(timeseries) == 0 {
+		return
+	}
+	b.metrics = append(b.metrics, &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{
+			Name:        b.prefix + name,
+			Description: description,
+			Type:        metricspb.MetricDescriptor_CUMULATIVE_INT64,
+			LabelKeys:   []*metricspb.LabelKey{{Key: labelInterface}, {Key: labelDirection}},
+		},
+		Timeseries: timeseries,
+	})
+}
+
+func (b *metricsBuilder) addGaugeInt64(name, description string, ts *timestamp.Timestamp, value *uint64) {
+	if value == nil {
+		return
+	}
+	b.addMetric(name, description, metricspb.MetricDescriptor_GAUGE_INT64, nil, int64Point(ts, *value))
+}
+
+func (b *metricsBuilder) addCumulativeInt64(name, description string, ts *timestamp.Timestamp, value *uint64) {
+	if value == nil {
+		return
+	}
+	b.addMetric(name, description, metricspb.MetricDescriptor_CUMULATIVE_INT64, b.startTime, int64Point(ts, *value))
+}
+
+func (b *metricsBuilder) addDouble(name, description string, metricType metricspb.MetricDescriptor_Type,
+	ts *timestamp.Timestamp, value float64) {
+	var startTime *timestamp.Timestamp
+	if metricType == metricspb.MetricDescriptor_CUMULATIVE_DOUBLE {
+		startTime = b.startTime
+	}
+	b.addMetric(name, description, metricType, startTime, &metricspb.Point{
+		Timestamp: ts,
+		Value:     &metricspb.Point_DoubleValue{DoubleValue: value},
+	})
+}
+
+func (b *metricsBuilder) addMetric(name, description string, metricType metricspb.MetricDescriptor_Type,
+	startTime *timestamp.Timestamp, point *metricspb.Point) {
+	b.metrics = append(b.metrics, &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{
+			Name:        b.prefix + name,
+			Description: description,
+			Type:        metricType,
+		},
+		Timeseries: []*metricspb.TimeSeries{
+			{
+				StartTimestamp: startTime,
+				Points:         []*metricspb.Point{point},
+			},
+		},
+	})
+}
+
+func int64Point(ts *timestamp.Timestamp, value uint64) *metricspb.Point {
+	return &metricspb.Point{
+		Timestamp: ts,
+		Value:     &metricspb.Point_Int64Value{Int64Value: int64(value)},
+	}
+}
+
+func toTimestamp(t time.Time) *timestamp.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return &timestamp.Timestamp{
+		Seconds: t.Unix(),
+		Nanos:   int32(t.Nanosecond()),
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubelet
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/open-telemetry/opentelemetry-collector/consumer/consumerdata"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestMetricsData(t *testing.T) {
+	mds := MetricsData(loadSummary(t), NewMetadata(loadPods(t)))
+
+	// One node, pod, container and volume.
+	require.Equal(t, 4, len(mds))
+
+	node := mds[0]
+	require.Equal(t, "k8s", node.Resource.Type)
+	require.Equal(t, map[string]string{"k8s.node.name": "minikube"}, node.Resource.Labels)
+	require.Equal(t, 16, len(node.Metrics))
+	cpuUtilization := metricByName(t, node, "kubernetes/node/cpu/utilization")
+	require.Equal(t, metricspb.MetricDescriptor_GAUGE_DOUBLE, cpuUtilization.MetricDescriptor.Type)
+	require.Equal(t, 0.2367, cpuUtilization.Timeseries[0].Points[0].GetDoubleValue())
+	require.Nil(t, cpuUtilization.Timeseries[0].StartTimestamp)
+	cpuTime := metricByName(t, node, "kubernetes/node/cpu/time")
+	require.Equal(t, metricspb.MetricDescriptor_CUMULATIVE_DOUBLE, cpuTime.MetricDescriptor.Type)
+	require.Equal(t, 3440.915, cpuTime.Timeseries[0].Points[0].GetDoubleValue())
+	// Cumulative metrics start when the node started.
+	require.Equal(t, int64(1589983531), cpuTime.Timeseries[0].StartTimestamp.Seconds)
+	require.Equal(t, int64(1589998056), cpuTime.Timeseries[0].Points[0].Timestamp.Seconds)
+
+	networkIO := metricByName(t, node, "kubernetes/node/network/io")
+	require.Equal(t, 2, len(networkIO.Timeseries))
+	require.Equal(t, "eth0", networkIO.Timeseries[0].LabelValues[0].Value)
+	require.Equal(t, "receive", networkIO.Timeseries[0].LabelValues[1].Value)
+	require.Equal(t, int64(13578521), networkIO.Timeseries[0].Points[0].GetInt64Value())
+	require.Equal(t, "transmit", networkIO.Timeseries[1].LabelValues[1].Value)
+	require.Equal(t, int64(3245116), networkIO.Timeseries[1].Points[0].GetInt64Value())
+
+	pod := mds[1]
+	require.Equal(t, "k8s", pod.Resource.Type)
+	require.Equal(t, map[string]string{
+		"k8s.node.name":      "minikube",
+		"k8s.pod.uid":        "5795d0c442cb997ff93c49feeb9f6386",
+		"k8s.pod.name":       "kube-scheduler-minikube",
+		"k8s.namespace.name": "kube-system",
+	}, pod.Resource.Labels)
+	require.Equal(t, int64(16060416),
+		metricByName(t, pod, "kubernetes/pod/memory/working_set").Timeseries[0].Points[0].GetInt64Value())
+	require.Equal(t, int64(65536),
+		metricByName(t, pod, "kubernetes/pod/ephemeral_storage/usage").Timeseries[0].Points[0].GetInt64Value())
+
+	container := mds[2]
+	require.Equal(t, "container", container.Resource.Type)
+	require.Equal(t, map[string]string{
+		"k8s.node.name":       "minikube",
+		"k8s.pod.uid":         "5795d0c442cb997ff93c49feeb9f6386",
+		"k8s.pod.name":        "kube-scheduler-minikube",
+		"k8s.namespace.name":  "kube-system",
+		"container.spec.name": "kube-scheduler",
+		"container.id":        "5cb4efa08a8a4fdbe4d5ec8d6ef3a86b00e3c2b05b0dbe4e39d2bcc1a4fd5a05",
+	}, container.Resource.Labels)
+	require.Equal(t, int64(36864),
+		metricByName(t, container, "kubernetes/container/filesystem/usage").Timeseries[0].Points[0].GetInt64Value())
+	require.Equal(t, int64(28672),
+		metricByName(t, container, "kubernetes/container/logs/usage").Timeseries[0].Points[0].GetInt64Value())
+
+	volume := mds[3]
+	require.Equal(t, "kubeconfig", volume.Resource.Labels["k8s.volume.name"])
+	require.Equal(t, "5795d0c442cb997ff93c49feeb9f6386", volume.Resource.Labels["k8s.pod.uid"])
+	require.Equal(t, 6, len(volume.Metrics))
+	require.Equal(t, int64(1034035200),
+		metricByName(t, volume, "kubernetes/volume/available").Timeseries[0].Points[0].GetInt64Value())
+}
+
+func TestMetricsDataWithoutMetadata(t *testing.T) {
+	mds := MetricsData(loadSummary(t), NewMetadata(nil))
+
+	require.Equal(t, 4, len(mds))
+	require.NotContains(t, mds[2].Resource.Labels, "container.id")
+}
+
+func TestMetricsDataOmitsMissingStats(t *testing.T) {
+	mds := MetricsData(&Summary{
+		Node: NodeStats{NodeName: "node"},
+		Pods: []PodStats{{PodRef: PodReference{Name: "pod", Namespace: "ns", UID: "uid"}}},
+	}, Metadata{})
+
+	require.Empty(t, mds)
+}
+
+func TestStripContainerID(t *testing.T) {
+	require.Equal(t, "abc", stripContainerID("docker://abc"))
+	require.Equal(t, "abc", stripContainerID("containerd://abc"))
+	require.Equal(t, "abc", stripContainerID("abc"))
+}
+
+func metricByName(t *testing.T, md *consumerdata.MetricsData, name string) *metricspb.Metric {
+	for _, m := range md.Metrics {
+		if m.MetricDescriptor.Name == name {
+			return m
+		}
+	}
+	t.Fatalf("metric %s not found", name)
+	return nil
+}
+
+func loadSummary(t *testing.T) *Summary {
+	data, err := ioutil.ReadFile("testdata/stats-summary.json")
+	require.NoError(t, err)
+	summary := &Summary{}
+	require.NoError(t, json.Unmarshal(data, summary))
+	return summary
+}
+
+func loadPods(t *testing.T) *corev1.PodList {
+	data, err := ioutil.ReadFile("testdata/pods.json")
+	require.NoError(t, err)
+	pods := &corev1.PodList{}
+	require.NoError(t, json.Unmarshal(data, pods))
+	return pods
+}
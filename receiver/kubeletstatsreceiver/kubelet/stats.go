@@ -0,0 +1,149 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubelet
+
+import (
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The following types are the subset of the kubelet stats summary API
+// (k8s.io/kubernetes/pkg/kubelet/apis/stats/v1alpha1) used by the receiver.
+// Fields are pointers when the kubelet may omit them.
+
+// Summary is a top-level container for holding NodeStats and PodStats.
+type Summary struct {
+	Node NodeStats  `json:"node"`
+	Pods []PodStats `json:"pods"`
+}
+
+// NodeStats holds node-level unprocessed sample stats.
+type NodeStats struct {
+	NodeName  string        `json:"nodeName"`
+	StartTime v1.Time       `json:"startTime"`
+	CPU       *CPUStats     `json:"cpu,omitempty"`
+	Memory    *MemoryStats  `json:"memory,omitempty"`
+	Network   *NetworkStats `json:"network,omitempty"`
+	Fs        *FsStats      `json:"fs,omitempty"`
+}
+
+// PodStats holds pod-level unprocessed sample stats.
+type PodStats struct {
+	PodRef           PodReference     `json:"podRef"`
+	StartTime        v1.Time          `json:"startTime"`
+	Containers       []ContainerStats `json:"containers"`
+	CPU              *CPUStats        `json:"cpu,omitempty"`
+	Memory           *MemoryStats     `json:"memory,omitempty"`
+	Network          *NetworkStats    `json:"network,omitempty"`
+	VolumeStats      []VolumeStats    `json:"volume,omitempty"`
+	EphemeralStorage *FsStats         `json:"ephemeral-storage,omitempty"`
+}
+
+// PodReference contains enough information to locate the referenced pod.
+type PodReference struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	UID       string `json:"uid"`
+}
+
+// ContainerStats holds container-level unprocessed sample stats.
+type ContainerStats struct {
+	Name      string       `json:"name"`
+	StartTime v1.Time      `json:"startTime"`
+	CPU       *CPUStats    `json:"cpu,omitempty"`
+	Memory    *MemoryStats `json:"memory,omitempty"`
+	Rootfs    *FsStats     `json:"rootfs,omitempty"`
+	Logs      *FsStats     `json:"logs,omitempty"`
+}
+
+// CPUStats contains data about CPU usage.
+type CPUStats struct {
+	Time                 v1.Time `json:"time"`
+	UsageNanoCores       *uint64 `json:"usageNanoCores,omitempty"`
+	UsageCoreNanoSeconds *uint64 `json:"usageCoreNanoSeconds,omitempty"`
+}
+
+// MemoryStats contains data about memory usage.
+type MemoryStats struct {
+	Time            v1.Time `json:"time"`
+	AvailableBytes  *uint64 `json:"availableBytes,omitempty"`
+	UsageBytes      *uint64 `json:"usageBytes,omitempty"`
+	WorkingSetBytes *uint64 `json:"workingSetBytes,omitempty"`
+	RSSBytes        *uint64 `json:"rssBytes,omitempty"`
+	PageFaults      *uint64 `json:"pageFaults,omitempty"`
+	MajorPageFaults *uint64 `json:"majorPageFaults,omitempty"`
+}
+
+// NetworkStats contains data about network resources. The stats of the
+// default interface are inlined.
+type NetworkStats struct {
+	Time           v1.Time `json:"time"`
+	InterfaceStats `json:",inline"`
+	Interfaces     []InterfaceStats `json:"interfaces,omitempty"`
+}
+
+// InterfaceStats contains resource value data about a network interface.
+type InterfaceStats struct {
+	Name     string  `json:"name"`
+	RxBytes  *uint64 `json:"rxBytes,omitempty"`
+	RxErrors *uint64 `json:"rxErrors,omitempty"`
+	TxBytes  *uint64 `json:"txBytes,omitempty"`
+	TxErrors *uint64 `json:"txErrors,omitempty"`
+}
+
+// FsStats contains data about filesystem usage.
+type FsStats struct {
+	Time           v1.Time `json:"time"`
+	AvailableBytes *uint64 `json:"availableBytes,omitempty"`
+	CapacityBytes  *uint64 `json:"capacityBytes,omitempty"`
+	UsedBytes      *uint64 `json:"usedBytes,omitempty"`
+	InodesFree     *uint64 `json:"inodesFree,omitempty"`
+	Inodes         *uint64 `json:"inodes,omitempty"`
+	InodesUsed     *uint64 `json:"inodesUsed,omitempty"`
+}
+
+// VolumeStats contains data about volume usage.
+type VolumeStats struct {
+	FsStats `json:",inline"`
+	Name    string `json:"name,omitempty"`
+}
+
+// StatsSummary gets the stats summary of the node and its pods from the kubelet.
+func StatsSummary(c Client) (*Summary, error) {
+	body, err := c.Get("/stats/summary")
+	if err != nil {
+		return nil, err
+	}
+	summary := &Summary{}
+	if err := json.Unmarshal(body, summary); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// Pods gets the pods running on the node from the kubelet.
+func Pods(c Client) (*corev1.PodList, error) {
+	body, err := c.Get("/pods")
+	if err != nil {
+		return nil, err
+	}
+	pods := &corev1.PodList{}
+	if err := json.Unmarshal(body, pods); err != nil {
+		return nil, err
+	}
+	return pods, nil
+}
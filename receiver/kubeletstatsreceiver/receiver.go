@@ -0,0 +1,115 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubeletstatsreceiver
+
+import (
+	"context"
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector/component"
+	"github.com/open-telemetry/opentelemetry-collector/component/componenterror"
+	"github.com/open-telemetry/opentelemetry-collector/consumer"
+	"github.com/open-telemetry/opentelemetry-collector/obsreport"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kubeletstatsreceiver/kubelet"
+)
+
+const (
+	dataformat = "kubeletstats"
+	transport  = "http"
+)
+
+var _ component.MetricsReceiver = (*kubeletStatsReceiver)(nil)
+
+type kubeletStatsReceiver struct {
+	config   *Config
+	logger   *zap.Logger
+	client   kubelet.Client
+	consumer consumer.MetricsConsumerOld
+	cancel   context.CancelFunc
+}
+
+func newReceiver(
+	logger *zap.Logger,
+	config *Config,
+	client kubelet.Client,
+	consumer consumer.MetricsConsumerOld,
+) *kubeletStatsReceiver {
+	return &kubeletStatsReceiver{
+		config:   config,
+		logger:   logger,
+		client:   client,
+		consumer: consumer,
+	}
+}
+
+func (r *kubeletStatsReceiver) Start(ctx context.Context, host component.Host) error {
+	var c context.Context
+	c, r.cancel = context.WithCancel(ctx)
+
+	go func() {
+		ticker := time.NewTicker(r.config.CollectionInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				r.scrape(c)
+			case <-c.Done():
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (r *kubeletStatsReceiver) Shutdown(context.Context) error {
+	r.cancel()
+	return nil
+}
+
+// scrape gets stats from the kubelet and sends them along as metrics.
+func (r *kubeletStatsReceiver) scrape(ctx context.Context) {
+	c := obsreport.StartMetricsReceiveOp(ctx, dataformat, transport)
+
+	summary, err := kubelet.StatsSummary(r.client)
+	if err != nil {
+		r.logger.Error("failed to get stats summary from kubelet", zap.Error(err))
+		obsreport.EndMetricsReceiveOp(c, dataformat, 0, 0, err)
+		return
+	}
+
+	// Container IDs are not part of the summary, metrics of containers are
+	// still sent without them if pods can't be listed.
+	pods, err := kubelet.Pods(r.client)
+	if err != nil {
+		r.logger.Warn("failed to get pods from kubelet", zap.Error(err))
+	}
+
+	var numTimeseries, numPoints int
+	var errs []error
+	for _, md := range kubelet.MetricsData(summary, kubelet.NewMetadata(pods)) {
+		numTs, numPts := obsreport.CountMetricPoints(*md)
+		numTimeseries += numTs
+		numPoints += numPts
+		if err := r.consumer.ConsumeMetricsData(c, *md); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	obsreport.EndMetricsReceiveOp(c, dataformat, numPoints, numTimeseries, componenterror.CombineErrors(errs))
+}
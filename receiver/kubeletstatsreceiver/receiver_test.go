@@ -0,0 +1,100 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubeletstatsreceiver
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector/component/componenttest"
+	"github.com/open-telemetry/opentelemetry-collector/exporter/exportertest"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestReceiver(t *testing.T) {
+	consumer := &exportertest.SinkMetricsExporterOld{}
+	r := newReceiver(zap.NewNop(), testConfig(), &fakeClient{t: t}, consumer)
+
+	ctx := context.Background()
+	require.NoError(t, r.Start(ctx, componenttest.NewNopHost()))
+
+	// Expects metrics of the node, pod, container and volume.
+	require.Eventually(t, func() bool {
+		return len(consumer.AllMetrics()) >= 4
+	}, 10*time.Second, 100*time.Millisecond,
+		"metrics not collected")
+	require.NoError(t, r.Shutdown(ctx))
+
+	container := consumer.AllMetrics()[2]
+	require.Equal(t, "kube-scheduler", container.Resource.Labels["container.spec.name"])
+	require.Equal(t,
+		"5cb4efa08a8a4fdbe4d5ec8d6ef3a86b00e3c2b05b0dbe4e39d2bcc1a4fd5a05",
+		container.Resource.Labels["container.id"])
+}
+
+func TestReceiverWithoutPods(t *testing.T) {
+	consumer := &exportertest.SinkMetricsExporterOld{}
+	r := newReceiver(zap.NewNop(), testConfig(), &fakeClient{t: t, failPods: true}, consumer)
+
+	r.scrape(context.Background())
+
+	// Metrics of containers are sent without container IDs.
+	require.Equal(t, 4, len(consumer.AllMetrics()))
+	container := consumer.AllMetrics()[2]
+	require.Equal(t, "kube-scheduler", container.Resource.Labels["container.spec.name"])
+	require.NotContains(t, container.Resource.Labels, "container.id")
+}
+
+func TestReceiverWithoutSummary(t *testing.T) {
+	consumer := &exportertest.SinkMetricsExporterOld{}
+	r := newReceiver(zap.NewNop(), testConfig(), &fakeClient{t: t, failSummary: true}, consumer)
+
+	r.scrape(context.Background())
+
+	require.Empty(t, consumer.AllMetrics())
+}
+
+func testConfig() *Config {
+	cfg := (&Factory{}).CreateDefaultConfig().(*Config)
+	cfg.CollectionInterval = 10 * time.Millisecond
+	return cfg
+}
+
+// fakeClient serves the stats summary and pods of a kubelet from testdata.
+type fakeClient struct {
+	t           *testing.T
+	failSummary bool
+	failPods    bool
+}
+
+func (c *fakeClient) Get(path string) ([]byte, error) {
+	switch {
+	case path == "/stats/summary" && !c.failSummary:
+		return c.read("kubelet/testdata/stats-summary.json")
+	case path == "/pods" && !c.failPods:
+		return c.read("kubelet/testdata/pods.json")
+	}
+	return nil, errors.New("kubelet request failed")
+}
+
+func (c *fakeClient) read(file string) ([]byte, error) {
+	data, err := ioutil.ReadFile(file)
+	require.NoError(c.t, err)
+	return data, nil
+}
@@ -53,6 +53,8 @@ type receiverTemplate struct {
 	// Rule is the discovery rule that when matched will create a receiver instance
 	// based on receiverTemplate.
 	Rule string `mapstructure:"rule"`
+	// rule is the compiled form of Rule.
+	rule rule
 }
 
 // newReceiverTemplate creates a receiverTemplate instance from the full name of a subreceiver
@@ -78,6 +80,10 @@ type Config struct {
 	receiverTemplates             map[string]receiverTemplate
 	// WatchObservers are the extensions to listen to endpoints from.
 	WatchObservers []configmodels.Type `mapstructure:"watch_observers"`
+	// StatusEndpoint is the address to serve the status page on. The status page
+	// lists the observed endpoints, whether the templates matched them and the
+	// running subreceivers with their configs. Disabled when empty.
+	StatusEndpoint string `mapstructure:"status_endpoint"`
 }
 
 // Copied from the Viper but changed to use the same delimiter.
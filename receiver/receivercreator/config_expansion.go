@@ -0,0 +1,134 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package receivercreator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/antonmedv/expr"
+)
+
+// expressionEvaluator evaluates a single expression found in a config value.
+type expressionEvaluator func(expression string) (interface{}, error)
+
+// expandConfig returns a copy of cfg where every backtick delimited expression
+// in string values has been evaluated against env. For example
+// "`endpoint`:`port`" expands to "10.0.0.1:6379".
+func expandConfig(cfg userConfigMap, env endpointEnv) (userConfigMap, error) {
+	expanded, err := expandMap(cfg, func(expression string) (interface{}, error) {
+		return expr.Eval(expression, map[string]interface{}(env))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return expanded, nil
+}
+
+// validateConfigExpressions compiles all expressions in cfg to catch errors when
+// the config is loaded instead of when an endpoint is discovered.
+func validateConfigExpressions(cfg userConfigMap) error {
+	_, err := expandMap(cfg, func(expression string) (interface{}, error) {
+		if _, err := expr.Compile(expression, expr.Env(endpointEnvTemplate)); err != nil {
+			return nil, err
+		}
+		return "", nil
+	})
+	return err
+}
+
+func expandMap(cfg map[string]interface{}, eval expressionEvaluator) (map[string]interface{}, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	expanded := make(map[string]interface{}, len(cfg))
+	for k, v := range cfg {
+		value, err := expandValue(v, eval)
+		if err != nil {
+			return nil, fmt.Errorf("failed to expand %q: %v", k, err)
+		}
+		expanded[k] = value
+	}
+	return expanded, nil
+}
+
+func expandValue(value interface{}, eval expressionEvaluator) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return evalBackticksInConfigValue(v, eval)
+	case map[string]interface{}:
+		return expandMap(v, eval)
+	case []interface{}:
+		expanded := make([]interface{}, len(v))
+		for i, item := range v {
+			value, err := expandValue(item, eval)
+			if err != nil {
+				return nil, err
+			}
+			expanded[i] = value
+		}
+		return expanded, nil
+	default:
+		return v, nil
+	}
+}
+
+// evalBackticksInConfigValue expands the backtick delimited expressions in value.
+// If value consists of a single expression the result is returned as is so that
+// it keeps its type (e.g. a port number stays an integer). Otherwise the results
+// are formatted and joined with the surrounding text.
+func evalBackticksInConfigValue(value string, eval expressionEvaluator) (interface{}, error) {
+	if !strings.Contains(value, "`") {
+		return value, nil
+	}
+
+	parts := strings.Split(value, "`")
+	if len(parts)%2 == 0 {
+		return nil, errors.New("expression is missing closing backtick")
+	}
+
+	// Single expression without any surrounding text.
+	if len(parts) == 3 && parts[0] == "" && parts[2] == "" {
+		return evalExpression(parts[1], eval)
+	}
+
+	var sb strings.Builder
+	for i, part := range parts {
+		// Every odd part is an expression.
+		if i%2 == 0 {
+			sb.WriteString(part)
+			continue
+		}
+		res, err := evalExpression(part, eval)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&sb, "%v", res)
+	}
+
+	return sb.String(), nil
+}
+
+func evalExpression(expression string, eval expressionEvaluator) (interface{}, error) {
+	if strings.TrimSpace(expression) == "" {
+		return nil, errors.New("expression cannot be empty")
+	}
+	res, err := eval(expression)
+	if err != nil {
+		return nil, fmt.Errorf("failed evaluating expression %q: %v", expression, err)
+	}
+	return res, nil
+}
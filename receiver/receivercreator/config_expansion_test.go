@@ -0,0 +1,98 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package receivercreator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
+)
+
+func TestExpandConfig(t *testing.T) {
+	env, err := endpointToEnv(observer.NewPortEndpoint("pod-1:8080", "10.0.0.1", 8080, observer.ProtocolTCP, map[string]string{
+		"app":                               "web",
+		"k8s.annotation.prometheus.io/path": "/stats",
+	}))
+	require.NoError(t, err)
+
+	cfg := userConfigMap{
+		"endpoint":    "`endpoint`:`port`",
+		"port":        "`port`",
+		"path":        "`labels[\"k8s.annotation.prometheus.io/path\"]`",
+		"static":      "value",
+		"number":      5,
+		"url":         "http://`endpoint`:`port + 1`/metrics",
+		"nested":      map[string]interface{}{"app": "`labels[\"app\"]`"},
+		"list":        []interface{}{"`endpoint`", 1},
+		"is_port":     "`type.port`",
+		"missing_key": "`labels[\"missing\"]`",
+	}
+
+	expanded, err := expandConfig(cfg, env)
+	require.NoError(t, err)
+	assert.Equal(t, userConfigMap{
+		"endpoint":    "10.0.0.1:8080",
+		"port":        uint16(8080),
+		"path":        "/stats",
+		"static":      "value",
+		"number":      5,
+		"url":         "http://10.0.0.1:8081/metrics",
+		"nested":      map[string]interface{}{"app": "web"},
+		"list":        []interface{}{"10.0.0.1", 1},
+		"is_port":     true,
+		"missing_key": "",
+	}, expanded)
+
+	// The original config should not be modified.
+	assert.Equal(t, "`endpoint`:`port`", cfg["endpoint"])
+}
+
+func TestExpandConfigErrors(t *testing.T) {
+	env, err := endpointToEnv(observer.NewHostEndpoint("pod-1", "10.0.0.1", nil))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		cfg     userConfigMap
+		wantErr string
+	}{
+		{"unclosed", userConfigMap{"endpoint": "`endpoint"}, `failed to expand "endpoint": expression is missing closing backtick`},
+		{"empty", userConfigMap{"endpoint": "``"}, `failed to expand "endpoint": expression cannot be empty`},
+		{"invalid", userConfigMap{"nested": map[string]interface{}{"endpoint": "`endpoint +`"}}, `failed to expand "nested": failed to expand "endpoint": failed evaluating expression "endpoint +"`},
+		{"list", userConfigMap{"list": []interface{}{"`(`"}}, `failed to expand "list": failed evaluating expression "("`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := expandConfig(tt.cfg, env)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestValidateConfigExpressions(t *testing.T) {
+	assert.NoError(t, validateConfigExpressions(userConfigMap{
+		"endpoint": "`endpoint`:`port`",
+		"nested":   map[string]interface{}{"path": "`labels[\"path\"]`"},
+	}))
+	assert.NoError(t, validateConfigExpressions(nil))
+
+	err := validateConfigExpressions(userConfigMap{"endpoint": "`endpiont`:`port`"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `failed to expand "endpoint": failed evaluating expression "endpiont"`)
+}
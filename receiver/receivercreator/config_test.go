@@ -81,9 +81,29 @@ func TestLoadConfig(t *testing.T) {
 	assert.NotNil(t, r1)
 	assert.Len(t, r1.receiverTemplates, 1)
 	assert.Contains(t, r1.receiverTemplates, "examplereceiver/1")
-	assert.Equal(t, "enabled", r1.receiverTemplates["examplereceiver/1"].Rule)
+	assert.Equal(t, "type.port", r1.receiverTemplates["examplereceiver/1"].Rule)
 	assert.Equal(t, userConfigMap{
 		endpointConfigKey: "localhost:12345",
 	}, r1.receiverTemplates["examplereceiver/1"].config)
 	assert.Equal(t, []configmodels.Type{"mock_observer"}, r1.WatchObservers)
 }
+
+func TestInvalidRule(t *testing.T) {
+	factories, err := config.ExampleComponents()
+	require.NoError(t, err)
+	factories.Receivers[configmodels.Type(typeStr)] = &Factory{}
+
+	_, err = config.LoadConfigFile(t, path.Join(".", "testdata", "invalid-rule.yaml"), factories)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `subreceiver "examplereceiver/1" rule is invalid`)
+}
+
+func TestInvalidConfigExpression(t *testing.T) {
+	factories, err := config.ExampleComponents()
+	require.NoError(t, err)
+	factories.Receivers[configmodels.Type(typeStr)] = &Factory{}
+
+	_, err = config.LoadConfigFile(t, path.Join(".", "testdata", "invalid-config-expression.yaml"), factories)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `subreceiver "examplereceiver/1" config is invalid`)
+}
@@ -3,12 +3,14 @@ module github.com/open-telemetry/opentelemetry-collector-contrib/receiver/receiv
 go 1.14
 
 require (
+	github.com/antonmedv/expr v1.8.4
 	github.com/census-instrumentation/opencensus-proto v0.2.1
 	github.com/open-telemetry/opentelemetry-collector v0.3.1-0.20200427150635-ca4b8231de7c
 	github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer v0.0.0
 	github.com/spf13/cast v1.3.1
 	github.com/spf13/viper v1.6.2
 	github.com/stretchr/testify v1.5.1
+	go.opencensus.io v0.22.3
 	go.uber.org/zap v1.13.0
 )
 
@@ -0,0 +1,85 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package receivercreator
+
+import (
+	"context"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+// TODO: re-think if receiver should register it's own telemetry views or if some other
+// mechanism should be used by the collector to discover views from all components
+
+func init() {
+	view.Register(
+		viewReceiversStarted,
+		viewReceiversStopped,
+		viewReceiversRestarted,
+	)
+}
+
+var (
+	tagSubreceiverKey, _ = tag.NewKey("subreceiver")
+
+	mReceiversStarted   = stats.Int64("otelsvc/receiver_creator/receivers_started", "Number of subreceivers started", "1")
+	mReceiversStopped   = stats.Int64("otelsvc/receiver_creator/receivers_stopped", "Number of subreceivers stopped", "1")
+	mReceiversRestarted = stats.Int64("otelsvc/receiver_creator/receivers_restarted", "Number of subreceivers restarted due to a config change", "1")
+)
+
+var viewReceiversStarted = &view.View{
+	Name:        mReceiversStarted.Name(),
+	Description: mReceiversStarted.Description(),
+	Measure:     mReceiversStarted,
+	TagKeys:     []tag.Key{tagSubreceiverKey},
+	Aggregation: view.Sum(),
+}
+
+var viewReceiversStopped = &view.View{
+	Name:        mReceiversStopped.Name(),
+	Description: mReceiversStopped.Description(),
+	Measure:     mReceiversStopped,
+	TagKeys:     []tag.Key{tagSubreceiverKey},
+	Aggregation: view.Sum(),
+}
+
+var viewReceiversRestarted = &view.View{
+	Name:        mReceiversRestarted.Name(),
+	Description: mReceiversRestarted.Description(),
+	Measure:     mReceiversRestarted,
+	TagKeys:     []tag.Key{tagSubreceiverKey},
+	Aggregation: view.Sum(),
+}
+
+// recordReceiverStarted increments the metric that records subreceiver starts.
+func recordReceiverStarted(subreceiver string) {
+	recordWithSubreceiver(subreceiver, mReceiversStarted.M(int64(1)))
+}
+
+// recordReceiverStopped increments the metric that records subreceiver stops.
+func recordReceiverStopped(subreceiver string) {
+	recordWithSubreceiver(subreceiver, mReceiversStopped.M(int64(1)))
+}
+
+// recordReceiverRestarted increments the metric that records subreceiver restarts.
+func recordReceiverRestarted(subreceiver string) {
+	recordWithSubreceiver(subreceiver, mReceiversRestarted.M(int64(1)))
+}
+
+func recordWithSubreceiver(subreceiver string, m stats.Measurement) {
+	stats.RecordWithTags(context.Background(), []tag.Mutator{tag.Upsert(tagSubreceiverKey, subreceiver)}, m)
+}
@@ -0,0 +1,53 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package receivercreator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+func TestRecordReceiverCounters(t *testing.T) {
+	tests := []struct {
+		view   *view.View
+		record func(string)
+	}{
+		{viewReceiversStarted, recordReceiverStarted},
+		{viewReceiversStopped, recordReceiverStopped},
+		{viewReceiversRestarted, recordReceiverRestarted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.view.Name, func(t *testing.T) {
+			tt.record("counter_test/1")
+			tt.record("counter_test/1")
+
+			rows, err := view.RetrieveData(tt.view.Name)
+			require.NoError(t, err)
+
+			var found bool
+			for _, row := range rows {
+				if assert.Len(t, row.Tags, 1) && row.Tags[0] == (tag.Tag{Key: tagSubreceiverKey, Value: "counter_test/1"}) {
+					found = true
+					assert.Equal(t, float64(2), row.Data.(*view.SumData).Value)
+				}
+			}
+			assert.True(t, found)
+		})
+	}
+}
@@ -16,8 +16,12 @@ package receivercreator
 
 import (
 	"fmt"
+	"net"
+	"reflect"
+	"strconv"
 	"sync"
 
+	"github.com/open-telemetry/opentelemetry-collector/component"
 	"github.com/open-telemetry/opentelemetry-collector/component/componenterror"
 	"go.uber.org/zap"
 
@@ -34,10 +38,24 @@ type observerHandler struct {
 	receiverTemplates map[string]receiverTemplate
 	// receiversByEndpointID is a map of endpoint IDs to a receiver instance.
 	receiversByEndpointID receiverMap
+	// runtimeConfigs maps a running receiver instance to the config it was started with.
+	runtimeConfigs map[component.Receiver]*runtimeConfig
+	// endpointStatuses maps endpoint IDs to the last evaluation of the templates for the endpoint.
+	endpointStatuses map[string]*endpointStatus
 	// runner starts and stops receiver instances.
 	runner runner
 }
 
+// runtimeConfig is the configuration of a receiver template rendered for an endpoint.
+type runtimeConfig struct {
+	// templateName is the full name of the receiverTemplate.
+	templateName string
+	// config is the template config with all expressions expanded.
+	config userConfigMap
+	// discoveredConfig contains values discovered from the endpoint.
+	discoveredConfig userConfigMap
+}
+
 // Shutdown all receivers started at runtime.
 func (obs *observerHandler) Shutdown() error {
 	obs.Lock()
@@ -66,19 +84,35 @@ func (obs *observerHandler) OnAdd(added []observer.Endpoint) {
 	defer obs.Unlock()
 
 	for _, e := range added {
+		status := newEndpointStatus(e)
+		obs.endpointStatuses[e.ID()] = status
+
+		env, err := endpointToEnv(e)
+		if err != nil {
+			obs.logger.Error("unable to convert endpoint to environment map", zap.String("endpoint", e.ID()), zap.Error(err))
+			status.err = err
+			continue
+		}
+
 		for _, template := range obs.receiverTemplates {
-			if !ruleMatches(template.Rule, e) {
+			cfg, err := obs.renderTemplate(template, e, env)
+			if err != nil {
+				obs.logger.Error("unable to resolve template config",
+					zap.String("receiver", template.fullName), zap.String("endpoint", e.ID()), zap.Error(err))
+				status.setError(template.fullName, err)
 				continue
 			}
-			rcvr, err := obs.runner.start(template.receiverConfig, userConfigMap{
-				endpointConfigKey: e.Target(),
-			})
-			if err != nil {
-				obs.logger.Error("failed to start receiver", zap.String("receiver", template.fullName))
+			if cfg == nil {
+				status.setNotMatched(template.fullName)
 				continue
 			}
 
-			obs.receiversByEndpointID.Put(e.ID(), rcvr)
+			if err := obs.startReceiver(e, template, cfg); err != nil {
+				status.setError(template.fullName, err)
+				continue
+			}
+			status.setRunning(template.fullName)
+			recordReceiverStarted(template.fullName)
 		}
 	}
 }
@@ -90,18 +124,161 @@ func (obs *observerHandler) OnRemove(removed []observer.Endpoint) {
 
 	for _, e := range removed {
 		for _, rcvr := range obs.receiversByEndpointID.Get(e.ID()) {
+			templateName := ""
+			if cfg, ok := obs.runtimeConfigs[rcvr]; ok {
+				templateName = cfg.templateName
+				delete(obs.runtimeConfigs, rcvr)
+			}
 			if err := obs.runner.shutdown(rcvr); err != nil {
 				obs.logger.Error("failed to stop receiver", zap.Reflect("receiver", rcvr))
 				continue
 			}
+			recordReceiverStopped(templateName)
 		}
 		obs.receiversByEndpointID.RemoveAll(e.ID())
+		delete(obs.endpointStatuses, e.ID())
 	}
 }
 
-// OnChange responds to endpoint change notifications.
+// OnChange responds to endpoint change notifications. Each template is rendered
+// against the changed endpoint and only the receivers whose rule match or
+// effective config changed are started, stopped or restarted.
 func (obs *observerHandler) OnChange(changed []observer.Endpoint) {
-	// TODO: optimize to only restart if effective config has changed.
-	obs.OnRemove(changed)
-	obs.OnAdd(changed)
+	obs.Lock()
+	defer obs.Unlock()
+
+	for _, e := range changed {
+		status := newEndpointStatus(e)
+		obs.endpointStatuses[e.ID()] = status
+
+		env, err := endpointToEnv(e)
+		if err != nil {
+			obs.logger.Error("unable to convert endpoint to environment map", zap.String("endpoint", e.ID()), zap.Error(err))
+			status.err = err
+			continue
+		}
+
+		running := map[string]component.Receiver{}
+		for _, rcvr := range obs.receiversByEndpointID.Get(e.ID()) {
+			if cfg, ok := obs.runtimeConfigs[rcvr]; ok {
+				running[cfg.templateName] = rcvr
+			}
+		}
+
+		for _, template := range obs.receiverTemplates {
+			cfg, err := obs.renderTemplate(template, e, env)
+			if err != nil {
+				// Leave any running receiver untouched as the new config is unusable.
+				obs.logger.Error("unable to resolve template config",
+					zap.String("receiver", template.fullName), zap.String("endpoint", e.ID()), zap.Error(err))
+				status.setError(template.fullName, err)
+				continue
+			}
+
+			rcvr, isRunning := running[template.fullName]
+			switch {
+			case cfg == nil && !isRunning:
+				// Still not matching.
+				status.setNotMatched(template.fullName)
+			case cfg == nil && isRunning:
+				// No longer matching.
+				if err := obs.stopReceiver(e, rcvr); err != nil {
+					status.setError(template.fullName, err)
+					continue
+				}
+				status.setNotMatched(template.fullName)
+				recordReceiverStopped(template.fullName)
+			case !isRunning:
+				// Newly matching.
+				if err := obs.startReceiver(e, template, cfg); err != nil {
+					status.setError(template.fullName, err)
+					continue
+				}
+				status.setRunning(template.fullName)
+				recordReceiverStarted(template.fullName)
+			case !reflect.DeepEqual(obs.runtimeConfigs[rcvr], cfg):
+				// Effective config changed.
+				if err := obs.stopReceiver(e, rcvr); err != nil {
+					status.setError(template.fullName, err)
+					continue
+				}
+				if err := obs.startReceiver(e, template, cfg); err != nil {
+					status.setError(template.fullName, err)
+					continue
+				}
+				status.setRunning(template.fullName)
+				recordReceiverRestarted(template.fullName)
+			default:
+				status.setRunning(template.fullName)
+			}
+		}
+	}
+}
+
+// renderTemplate returns the config of template for an endpoint. If the rule of the
+// template does not match the endpoint nil is returned.
+func (obs *observerHandler) renderTemplate(template receiverTemplate, e observer.Endpoint, env endpointEnv) (*runtimeConfig, error) {
+	matches, err := template.rule.eval(env)
+	if err != nil {
+		return nil, fmt.Errorf("failed matching rule %q: %v", template.Rule, err)
+	}
+	if !matches {
+		return nil, nil
+	}
+
+	resolvedConfig, err := expandConfig(template.config, env)
+	if err != nil {
+		return nil, err
+	}
+
+	discoveredConfig := userConfigMap{}
+	// If user didn't set endpoint set to default value.
+	if _, ok := resolvedConfig[endpointConfigKey]; !ok {
+		discoveredConfig[endpointConfigKey] = defaultEndpoint(e)
+	}
+
+	return &runtimeConfig{
+		templateName:     template.fullName,
+		config:           resolvedConfig,
+		discoveredConfig: discoveredConfig,
+	}, nil
+}
+
+// startReceiver starts a receiver for the endpoint.
+func (obs *observerHandler) startReceiver(e observer.Endpoint, template receiverTemplate, cfg *runtimeConfig) error {
+	rcvr, err := obs.runner.start(receiverConfig{
+		fullName: template.fullName,
+		typeStr:  template.typeStr,
+		config:   cfg.config,
+	}, cfg.discoveredConfig)
+	if err != nil {
+		obs.logger.Error("failed to start receiver",
+			zap.String("receiver", template.fullName), zap.String("endpoint", e.ID()), zap.Error(err))
+		return fmt.Errorf("failed to start receiver: %v", err)
+	}
+
+	obs.receiversByEndpointID.Put(e.ID(), rcvr)
+	obs.runtimeConfigs[rcvr] = cfg
+	return nil
+}
+
+// stopReceiver stops a receiver of the endpoint.
+func (obs *observerHandler) stopReceiver(e observer.Endpoint, rcvr component.Receiver) error {
+	if err := obs.runner.shutdown(rcvr); err != nil {
+		obs.logger.Error("failed to stop receiver", zap.String("endpoint", e.ID()), zap.Reflect("receiver", rcvr), zap.Error(err))
+		return fmt.Errorf("failed to stop receiver: %v", err)
+	}
+
+	obs.receiversByEndpointID.Remove(e.ID(), rcvr)
+	delete(obs.runtimeConfigs, rcvr)
+	return nil
+}
+
+// defaultEndpoint returns the value of the endpoint config key for a discovered endpoint
+// when the template does not set one.
+func defaultEndpoint(e observer.Endpoint) string {
+	if p, ok := e.(*observer.PortEndpoint); ok {
+		return net.JoinHostPort(p.Target(), strconv.Itoa(int(p.Port)))
+	}
+	return e.Target()
 }
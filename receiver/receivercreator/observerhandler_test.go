@@ -49,9 +49,11 @@ func TestOnAdd(t *testing.T) {
 	handler := &observerHandler{
 		logger: zap.NewNop(),
 		receiverTemplates: map[string]receiverTemplate{
-			"name/1": {rcvrCfg, "enabled"},
+			"name/1": {rcvrCfg, "type.host", newRuleOrPanic("type.host")},
 		},
 		receiversByEndpointID: receiverMap{},
+		runtimeConfigs:        map[component.Receiver]*runtimeConfig{},
+		endpointStatuses:      map[string]*endpointStatus{},
 		runner:                runner,
 	}
 
@@ -71,6 +73,8 @@ func TestOnRemove(t *testing.T) {
 	handler := &observerHandler{
 		logger:                zap.NewNop(),
 		receiversByEndpointID: receiverMap{},
+		runtimeConfigs:        map[component.Receiver]*runtimeConfig{},
+		endpointStatuses:      map[string]*endpointStatus{},
 		runner:                runner,
 	}
 
@@ -88,28 +92,110 @@ func TestOnRemove(t *testing.T) {
 
 func TestOnChange(t *testing.T) {
 	runner := &mockRunner{}
-	rcvrCfg := receiverConfig{typeStr: configmodels.Type("name"), config: userConfigMap{"foo": "bar"}, fullName: "name/1"}
-	oldRcvr := &config.ExampleReceiverProducer{}
-	newRcvr := &config.ExampleReceiverProducer{}
+	redisCfg := receiverConfig{typeStr: configmodels.Type("redis"), config: userConfigMap{"password": "`labels[\"password\"]`"}, fullName: "redis/1"}
+	promCfg := receiverConfig{typeStr: configmodels.Type("prometheus"), config: userConfigMap{}, fullName: "prometheus/1"}
+	redisRule := `type.port && labels["app"] == "redis"`
+	promRule := `type.port && labels["scrape"] == "true"`
 	handler := &observerHandler{
 		logger: zap.NewNop(),
 		receiverTemplates: map[string]receiverTemplate{
-			"name/1": {rcvrCfg, "enabled"},
+			"redis/1":      {redisCfg, redisRule, newRuleOrPanic(redisRule)},
+			"prometheus/1": {promCfg, promRule, newRuleOrPanic(promRule)},
 		},
 		receiversByEndpointID: receiverMap{},
+		runtimeConfigs:        map[component.Receiver]*runtimeConfig{},
+		endpointStatuses:      map[string]*endpointStatus{},
 		runner:                runner,
 	}
 
-	handler.receiversByEndpointID.Put("id-1", oldRcvr)
+	endpoint := func(labels map[string]string) []observer.Endpoint {
+		return []observer.Endpoint{observer.NewPortEndpoint("id-1", "localhost", 6379, observer.ProtocolTCP, labels)}
+	}
+	redisStart := func(password string) receiverConfig {
+		return receiverConfig{typeStr: "redis", config: userConfigMap{"password": password}, fullName: "redis/1"}
+	}
+	discovered := userConfigMap{endpointConfigKey: "localhost:6379"}
 
-	runner.On("shutdown", oldRcvr).Return(nil)
-	runner.On("start", rcvrCfg, userConfigMap{endpointConfigKey: "localhost"}).Return(newRcvr, nil)
+	// Initial add starts redis.
+	redisRcvr := &config.ExampleReceiverProducer{}
+	runner.On("start", redisStart("secret"), discovered).Return(redisRcvr, nil).Once()
+	handler.OnAdd(endpoint(map[string]string{"app": "redis", "password": "secret"}))
+	runner.AssertExpectations(t)
+	assert.Equal(t, 1, handler.receiversByEndpointID.Size())
 
-	handler.OnChange([]observer.Endpoint{
-		observer.NewHostEndpoint("id-1", "localhost", nil),
+	// Label change that does not affect any config does nothing.
+	handler.OnChange(endpoint(map[string]string{"app": "redis", "password": "secret", "version": "2"}))
+	runner.AssertExpectations(t)
+	assert.Equal(t, []component.Receiver{redisRcvr}, handler.receiversByEndpointID.Get("id-1"))
+
+	// Config change restarts redis and newly matching prometheus is started.
+	restartedRedis := &config.ExampleReceiverProducer{}
+	promRcvr := &config.ExampleReceiverProducer{}
+	runner.On("shutdown", redisRcvr).Return(nil).Once()
+	runner.On("start", redisStart("changed"), discovered).Return(restartedRedis, nil).Once()
+	runner.On("start", promCfg, discovered).Return(promRcvr, nil).Once()
+	handler.OnChange(endpoint(map[string]string{"app": "redis", "password": "changed", "scrape": "true"}))
+	runner.AssertExpectations(t)
+	assert.Equal(t, 2, handler.receiversByEndpointID.Size())
+	assert.Contains(t, handler.receiversByEndpointID.Get("id-1"), restartedRedis)
+	assert.Contains(t, handler.receiversByEndpointID.Get("id-1"), promRcvr)
+
+	// Redis no longer matches and is stopped.
+	runner.On("shutdown", restartedRedis).Return(nil).Once()
+	handler.OnChange(endpoint(map[string]string{"password": "changed", "scrape": "true"}))
+	runner.AssertExpectations(t)
+	assert.Equal(t, []component.Receiver{promRcvr}, handler.receiversByEndpointID.Get("id-1"))
+	assert.Len(t, handler.runtimeConfigs, 1)
+
+	// Removal stops remaining receivers.
+	runner.On("shutdown", promRcvr).Return(nil).Once()
+	handler.OnRemove(endpoint(nil))
+	runner.AssertExpectations(t)
+	assert.Equal(t, 0, handler.receiversByEndpointID.Size())
+	assert.Empty(t, handler.runtimeConfigs)
+}
+
+func TestOnAddExpandsConfig(t *testing.T) {
+	runner := &mockRunner{}
+	rcvrCfg := receiverConfig{
+		typeStr:  configmodels.Type("name"),
+		config:   userConfigMap{"path": "`labels[\"path\"]`"},
+		fullName: "name/1",
+	}
+	invalidCfg := receiverConfig{
+		typeStr:  configmodels.Type("name"),
+		config:   userConfigMap{"endpoint": "`labels[\"path\"] + port`"},
+		fullName: "name/2",
+	}
+	handler := &observerHandler{
+		logger: zap.NewNop(),
+		receiverTemplates: map[string]receiverTemplate{
+			"name/1": {rcvrCfg, "type.port", newRuleOrPanic("type.port")},
+			"name/2": {invalidCfg, "type.port", newRuleOrPanic("type.port")},
+		},
+		receiversByEndpointID: receiverMap{},
+		runtimeConfigs:        map[component.Receiver]*runtimeConfig{},
+		endpointStatuses:      map[string]*endpointStatus{},
+		runner:                runner,
+	}
+
+	runner.On("start", receiverConfig{
+		typeStr:  configmodels.Type("name"),
+		config:   userConfigMap{"path": "/metrics"},
+		fullName: "name/1",
+	}, userConfigMap{endpointConfigKey: "10.0.0.1:8080"}).Return(&config.ExampleReceiverProducer{}, nil)
+
+	handler.OnAdd([]observer.Endpoint{
+		observer.NewPortEndpoint("id-1", "10.0.0.1", 8080, observer.ProtocolTCP, map[string]string{"path": "/metrics"}),
 	})
 
+	// name/2 fails to render and is not started.
 	runner.AssertExpectations(t)
 	assert.Equal(t, 1, handler.receiversByEndpointID.Size())
-	assert.Same(t, newRcvr, handler.receiversByEndpointID.Get("id-1")[0])
+}
+
+func TestDefaultEndpoint(t *testing.T) {
+	assert.Equal(t, "10.0.0.1", defaultEndpoint(observer.NewHostEndpoint("id", "10.0.0.1", nil)))
+	assert.Equal(t, "10.0.0.1:6379", defaultEndpoint(observer.NewPortEndpoint("id", "10.0.0.1", 6379, observer.ProtocolTCP, nil)))
+	assert.Equal(t, "[::1]:6379", defaultEndpoint(observer.NewPortEndpoint("id", "::1", 6379, observer.ProtocolTCP, nil)))
 }
@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 
 	"github.com/open-telemetry/opentelemetry-collector/component"
 	"github.com/open-telemetry/opentelemetry-collector/config/configmodels"
@@ -39,6 +40,7 @@ type receiverCreator struct {
 	logger          *zap.Logger
 	cfg             *Config
 	observerHandler observerHandler
+	statusServer    *http.Server
 }
 
 // newReceiverCreator creates the receiver_creator with the given parameters.
@@ -74,6 +76,8 @@ func (rc *receiverCreator) Start(ctx context.Context, host component.Host) error
 		logger:                rc.logger,
 		receiverTemplates:     rc.cfg.receiverTemplates,
 		receiversByEndpointID: receiverMap{},
+		runtimeConfigs:        map[component.Receiver]*runtimeConfig{},
+		endpointStatuses:      map[string]*endpointStatus{},
 		runner: &receiverRunner{
 			logger:       rc.logger,
 			nextConsumer: rc.nextConsumer,
@@ -113,6 +117,12 @@ func (rc *receiverCreator) Start(ctx context.Context, host component.Host) error
 			zap.String("receiver", rc.cfg.Name()))
 	}
 
+	if rc.cfg.StatusEndpoint != "" {
+		if err := rc.startStatusServer(); err != nil {
+			return err
+		}
+	}
+
 	// Start all configured watchers.
 	for _, observable := range observers {
 		observable.ListAndWatch(&rc.observerHandler)
@@ -123,5 +133,8 @@ func (rc *receiverCreator) Start(ctx context.Context, host component.Host) error
 
 // Shutdown stops the receiver_creator and all its receivers started at runtime.
 func (rc *receiverCreator) Shutdown(ctx context.Context) error {
+	if err := rc.stopStatusServer(ctx); err != nil {
+		rc.logger.Error("failed to stop status server", zap.Error(err))
+	}
 	return rc.observerHandler.Shutdown()
 }
@@ -73,7 +73,7 @@ func (m *mockObserver) Shutdown(ctx context.Context) error {
 var _ component.ServiceExtension = (*mockObserver)(nil)
 
 func (m *mockObserver) ListAndWatch(notify observer.Notify) {
-	notify.OnAdd([]observer.Endpoint{observer.NewHostEndpoint("foobar", "169.168.1.100", nil)})
+	notify.OnAdd([]observer.Endpoint{observer.NewPortEndpoint("foobar", "169.168.1.100", 6379, observer.ProtocolTCP, nil)})
 }
 
 var _ observer.Observable = (*mockObserver)(nil)
@@ -32,6 +32,22 @@ func (rm receiverMap) Get(id string) []component.Receiver {
 	return rm[id]
 }
 
+// Remove a single receiver from id. The id is removed if it has no receivers left.
+func (rm receiverMap) Remove(id string, rcvr component.Receiver) {
+	rcvrs := rm[id]
+	for i, r := range rcvrs {
+		if r == rcvr {
+			rcvrs = append(rcvrs[:i:i], rcvrs[i+1:]...)
+			break
+		}
+	}
+	if len(rcvrs) == 0 {
+		delete(rm, id)
+		return
+	}
+	rm[id] = rcvrs
+}
+
 // Remove all receivers by id.
 func (rm receiverMap) RemoveAll(id string) {
 	delete(rm, id)
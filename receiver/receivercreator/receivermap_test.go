@@ -56,3 +56,25 @@ func TestReceiverMap(t *testing.T) {
 	assert.Equal(t, 2, rm.Size())
 	assert.Equal(t, []component.Receiver{r1, r2}, rm.Values())
 }
+
+func TestReceiverMapRemove(t *testing.T) {
+	rm := receiverMap{}
+	r1 := &config.ExampleReceiverProducer{}
+	r2 := &config.ExampleReceiverProducer{}
+
+	rm.Put("a", r1)
+	rm.Put("a", r2)
+
+	rm.Remove("a", &config.ExampleReceiverProducer{})
+	assert.Equal(t, []component.Receiver{r1, r2}, rm.Get("a"))
+
+	rm.Remove("a", r1)
+	assert.Equal(t, []component.Receiver{r2}, rm.Get("a"))
+
+	rm.Remove("a", r2)
+	assert.Nil(t, rm.Get("a"))
+	assert.NotContains(t, rm, "a")
+
+	rm.Remove("missing", r1)
+	assert.Equal(t, 0, rm.Size())
+}
@@ -15,10 +15,129 @@
 package receivercreator
 
 import (
+	"errors"
+	"fmt"
+
+	"github.com/antonmedv/expr"
+	"github.com/antonmedv/expr/ast"
+	"github.com/antonmedv/expr/parser"
+	"github.com/antonmedv/expr/vm"
+
 	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
 )
 
-func ruleMatches(rule string, e observer.Endpoint) bool {
-	// TODO
-	return rule == "enabled"
+const (
+	// ruleTypeHost is the value of "type" for observer.HostEndpoint.
+	ruleTypeHost = "host"
+	// ruleTypePort is the value of "type" for observer.PortEndpoint.
+	ruleTypePort = "port"
+)
+
+// rule wraps a compiled expr program for later evaluation against endpoints.
+type rule struct {
+	program *vm.Program
+}
+
+// endpointEnv is the set of variables available to rules and config expressions.
+type endpointEnv map[string]interface{}
+
+// endpointEnvTemplate declares every variable (and its type) an expression may reference so
+// that typos and type errors are caught when the expression is compiled.
+var endpointEnvTemplate = endpointEnv{
+	"type":     map[string]bool{},
+	"endpoint": "",
+	"port":     uint16(0),
+	"labels":   map[string]string{},
+}
+
+// newRule compiles ruleStr. A rule must begin by checking the endpoint type
+// (type.host or type.port) and that check must be required for the rule to
+// match, so that it can not unintentionally match every endpoint.
+func newRule(ruleStr string) (rule, error) {
+	if ruleStr == "" {
+		return rule{}, errors.New("rule cannot be empty")
+	}
+
+	program, err := expr.Compile(ruleStr, expr.Env(endpointEnvTemplate), expr.AsBool())
+	if err != nil {
+		return rule{}, fmt.Errorf("rule %q failed to compile: %v", ruleStr, err)
+	}
+
+	tree, err := parser.Parse(ruleStr)
+	if err != nil {
+		return rule{}, fmt.Errorf("rule %q failed to compile: %v", ruleStr, err)
+	}
+	if !startsWithTypeCheck(tree.Node) {
+		return rule{}, fmt.Errorf("rule %q must start with an endpoint type check (type.%s or type.%s)",
+			ruleStr, ruleTypeHost, ruleTypePort)
+	}
+
+	return rule{program: program}, nil
+}
+
+// startsWithTypeCheck returns whether node is a type check, or a chain of
+// conjunctions whose first operand is a type check. Any other top-level
+// operator, e.g. "type.port || true", could match regardless of the type.
+func startsWithTypeCheck(node ast.Node) bool {
+	for {
+		binary, ok := node.(*ast.BinaryNode)
+		if !ok || (binary.Operator != "&&" && binary.Operator != "and") {
+			break
+		}
+		node = binary.Left
+	}
+
+	property, ok := node.(*ast.PropertyNode)
+	if !ok || (property.Property != ruleTypeHost && property.Property != ruleTypePort) {
+		return false
+	}
+	identifier, ok := property.Node.(*ast.IdentifierNode)
+	return ok && identifier.Value == "type"
+}
+
+// eval the rule against the given endpoint environment.
+func (r *rule) eval(env endpointEnv) (bool, error) {
+	if r.program == nil {
+		return false, errors.New("rule has not been compiled")
+	}
+
+	res, err := expr.Run(r.program, env)
+	if err != nil {
+		return false, err
+	}
+	matched, ok := res.(bool)
+	if !ok {
+		return false, fmt.Errorf("rule did not return a bool but %T", res)
+	}
+	return matched, nil
+}
+
+// endpointToEnv converts an endpoint into the variables available to rules.
+func endpointToEnv(e observer.Endpoint) (endpointEnv, error) {
+	labels := e.Labels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	env := endpointEnv{
+		"type": map[string]bool{
+			ruleTypeHost: false,
+			ruleTypePort: false,
+		},
+		"endpoint": e.Target(),
+		"port":     uint16(0),
+		"labels":   labels,
+	}
+
+	switch o := e.(type) {
+	case *observer.HostEndpoint:
+		env["type"].(map[string]bool)[ruleTypeHost] = true
+	case *observer.PortEndpoint:
+		env["type"].(map[string]bool)[ruleTypePort] = true
+		env["port"] = o.Port
+	default:
+		return nil, fmt.Errorf("unknown endpoint type %T", e)
+	}
+
+	return env, nil
 }
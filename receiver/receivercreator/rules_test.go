@@ -0,0 +1,108 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package receivercreator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
+)
+
+// newRuleOrPanic creates a rule for use in tests.
+func newRuleOrPanic(ruleStr string) rule {
+	r, err := newRule(ruleStr)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+func TestRuleEval(t *testing.T) {
+	redisPod := observer.NewPortEndpoint("pod-1:6379", "10.0.0.1", 6379, observer.ProtocolTCP, map[string]string{
+		"app": "redis-primary",
+	})
+	otherPort := observer.NewPortEndpoint("pod-1:8080", "10.0.0.1", 8080, observer.ProtocolTCP, map[string]string{
+		"app": "web",
+	})
+	host := observer.NewHostEndpoint("pod-1", "10.0.0.1", nil)
+
+	tests := []struct {
+		name     string
+		rule     string
+		endpoint observer.Endpoint
+		want     bool
+	}{
+		{"type port", "type.port", redisPod, true},
+		{"type host on port", "type.host", redisPod, false},
+		{"type host", "type.host", host, true},
+		{"port matches", "type.port && port == 6379", redisPod, true},
+		{"port does not match", "type.port && port == 6379", otherPort, false},
+		{"labels regex", `type.port && port == 6379 && labels["app"] matches "redis.*"`, redisPod, true},
+		{"labels regex mismatch", `type.port && labels["app"] matches "redis.*"`, otherPort, false},
+		{"missing label", `type.host && labels["app"] == "redis"`, host, false},
+		{"endpoint", `type.host && endpoint == "10.0.0.1"`, host, true},
+		{"and keyword", `type.port and port == 6379 and labels["app"] == "redis-primary"`, redisPod, true},
+		{"parenthesized or", `type.port && (port == 6379 || port == 8080)`, otherPort, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := newRule(tt.rule)
+			require.NoError(t, err)
+
+			env, err := endpointToEnv(tt.endpoint)
+			require.NoError(t, err)
+
+			got, err := r.eval(env)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewRuleErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		wantErr string
+	}{
+		{"empty", "", "rule cannot be empty"},
+		{"no type", "port == 6379", `rule "port == 6379" must start with an endpoint type check (type.host or type.port)`},
+		{"unknown type", "type.pod", `rule "type.pod" must start with an endpoint type check (type.host or type.port)`},
+		{"or", "type.port || true", `rule "type.port || true" must start with an endpoint type check (type.host or type.port)`},
+		{"or in conjunction", "(type.port || true) && port == 6379", `rule "(type.port || true) && port == 6379" must start with an endpoint type check (type.host or type.port)`},
+		{"not", "not type.port", `rule "not type.port" must start with an endpoint type check (type.host or type.port)`},
+		{"type not first", "port == 6379 && type.port", `rule "port == 6379 && type.port" must start with an endpoint type check (type.host or type.port)`},
+		{"type index", `type["port"] || true`, `rule "type[\"port\"] || true" must start with an endpoint type check (type.host or type.port)`},
+		{"unknown variable", "type.port && prot == 6379", `rule "type.port && prot == 6379" failed to compile`},
+		{"not a bool", "type.port && port + 1", `rule "type.port && port + 1" failed to compile`},
+		{"syntax error", "type.port &&", `rule "type.port &&" failed to compile`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newRule(tt.rule)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestEvalUncompiledRule(t *testing.T) {
+	r := rule{}
+	_, err := r.eval(endpointEnv{})
+	assert.EqualError(t, err, "rule has not been compiled")
+}
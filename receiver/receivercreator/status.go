@@ -0,0 +1,239 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package receivercreator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net"
+	"net/http"
+	"sort"
+
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
+)
+
+// statusPath is the path of the status page served on Config.StatusEndpoint.
+const statusPath = "/debug/receivercreatorz"
+
+// endpointStatus is the result of the last evaluation of the receiver templates
+// against an observed endpoint.
+type endpointStatus struct {
+	endpoint observer.Endpoint
+	// err is set if the endpoint could not be evaluated at all.
+	err error
+	// templates maps receiver template full names to their status.
+	templates map[string]templateStatus
+}
+
+// templateStatus is the status of a single receiver template for an endpoint.
+type templateStatus struct {
+	// matched is true when the rule matched and a receiver is running.
+	matched bool
+	// reason explains why no receiver is running for the template.
+	reason string
+}
+
+func newEndpointStatus(e observer.Endpoint) *endpointStatus {
+	return &endpointStatus{endpoint: e, templates: map[string]templateStatus{}}
+}
+
+func (s *endpointStatus) setRunning(templateName string) {
+	s.templates[templateName] = templateStatus{matched: true}
+}
+
+func (s *endpointStatus) setNotMatched(templateName string) {
+	s.templates[templateName] = templateStatus{reason: "rule did not match"}
+}
+
+func (s *endpointStatus) setError(templateName string, err error) {
+	s.templates[templateName] = templateStatus{reason: err.Error()}
+}
+
+// statusPage is the data rendered on the status page.
+type statusPage struct {
+	Name      string           `json:"name"`
+	Endpoints []endpointReport `json:"endpoints"`
+}
+
+// endpointReport describes an observed endpoint and the receivers created for it.
+type endpointReport struct {
+	ID        string            `json:"id"`
+	Endpoint  string            `json:"endpoint"`
+	Labels    map[string]string `json:"labels"`
+	Error     string            `json:"error,omitempty"`
+	Templates []templateReport  `json:"templates"`
+	Receivers []receiverReport  `json:"receivers"`
+}
+
+// templateReport describes whether a receiver template matched an endpoint.
+type templateReport struct {
+	Name    string `json:"name"`
+	Matched bool   `json:"matched"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// receiverReport describes a running subreceiver instance.
+type receiverReport struct {
+	Template string `json:"template"`
+	Config   string `json:"config"`
+}
+
+// status returns a snapshot of the observed endpoints and running receivers.
+func (obs *observerHandler) status() []endpointReport {
+	obs.Lock()
+	defer obs.Unlock()
+
+	reports := make([]endpointReport, 0, len(obs.endpointStatuses))
+	for id, s := range obs.endpointStatuses {
+		report := endpointReport{
+			ID:        id,
+			Endpoint:  s.endpoint.String(),
+			Labels:    s.endpoint.Labels(),
+			Templates: []templateReport{},
+			Receivers: []receiverReport{},
+		}
+		if s.err != nil {
+			report.Error = s.err.Error()
+		}
+
+		for name, ts := range s.templates {
+			report.Templates = append(report.Templates, templateReport{Name: name, Matched: ts.matched, Reason: ts.reason})
+		}
+		sort.Slice(report.Templates, func(i, j int) bool {
+			return report.Templates[i].Name < report.Templates[j].Name
+		})
+
+		for _, rcvr := range obs.receiversByEndpointID.Get(id) {
+			cfg, ok := obs.runtimeConfigs[rcvr]
+			if !ok {
+				continue
+			}
+			report.Receivers = append(report.Receivers, receiverReport{
+				Template: cfg.templateName,
+				Config:   formatRuntimeConfig(cfg),
+			})
+		}
+		sort.Slice(report.Receivers, func(i, j int) bool {
+			return report.Receivers[i].Template < report.Receivers[j].Template
+		})
+
+		reports = append(reports, report)
+	}
+
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].ID < reports[j].ID
+	})
+	return reports
+}
+
+// formatRuntimeConfig returns the effective config a receiver was started with.
+func formatRuntimeConfig(cfg *runtimeConfig) string {
+	merged := map[string]interface{}{}
+	for k, v := range cfg.config {
+		merged[k] = v
+	}
+	for k, v := range cfg.discoveredConfig {
+		merged[k] = v
+	}
+	out, err := json.MarshalIndent(merged, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("%v", merged)
+	}
+	return string(out)
+}
+
+var statusTemplate = template.Must(template.New("status").Parse(`<!DOCTYPE html>
+<html>
+<head><title>{{.Name}}</title></head>
+<body>
+<h1>{{.Name}}</h1>
+{{range .Endpoints}}
+<h2>{{.ID}}</h2>
+<p>{{.Endpoint}}</p>
+{{if .Error}}<p><b>Error:</b> {{.Error}}</p>{{end}}
+<h3>Labels</h3>
+<table>
+{{range $k, $v := .Labels}}<tr><td>{{$k}}</td><td>{{$v}}</td></tr>
+{{end}}</table>
+<h3>Templates</h3>
+<table>
+<tr><th>Template</th><th>Matched</th><th>Reason</th></tr>
+{{range .Templates}}<tr><td>{{.Name}}</td><td>{{.Matched}}</td><td>{{.Reason}}</td></tr>
+{{end}}</table>
+<h3>Receivers</h3>
+<table>
+<tr><th>Template</th><th>Config</th></tr>
+{{range .Receivers}}<tr><td>{{.Template}}</td><td><pre>{{.Config}}</pre></td></tr>
+{{end}}</table>
+{{else}}
+<p>No endpoints observed.</p>
+{{end}}
+</body>
+</html>
+`))
+
+// statusHandler serves the status page as HTML or as JSON when the query
+// parameter format=json is given.
+func (rc *receiverCreator) statusHandler(w http.ResponseWriter, r *http.Request) {
+	page := statusPage{
+		Name:      rc.cfg.Name(),
+		Endpoints: rc.observerHandler.status(),
+	}
+
+	if r.URL.Query().Get("format") == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(page); err != nil {
+			rc.logger.Error("failed to write status page", zap.Error(err))
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := statusTemplate.Execute(w, page); err != nil {
+		rc.logger.Error("failed to write status page", zap.Error(err))
+	}
+}
+
+// startStatusServer starts serving the status page on Config.StatusEndpoint.
+func (rc *receiverCreator) startStatusServer() error {
+	ln, err := net.Listen("tcp", rc.cfg.StatusEndpoint)
+	if err != nil {
+		return fmt.Errorf("failed to bind to status endpoint %q: %v", rc.cfg.StatusEndpoint, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(statusPath, rc.statusHandler)
+	rc.statusServer = &http.Server{Handler: mux}
+
+	go func() {
+		if err := rc.statusServer.Serve(ln); err != nil && err != http.ErrServerClosed {
+			rc.logger.Error("status server failed", zap.Error(err))
+		}
+	}()
+
+	return nil
+}
+
+// stopStatusServer stops serving the status page if it was started.
+func (rc *receiverCreator) stopStatusServer(ctx context.Context) error {
+	if rc.statusServer == nil {
+		return nil
+	}
+	return rc.statusServer.Shutdown(ctx)
+}
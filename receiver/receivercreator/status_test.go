@@ -0,0 +1,144 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package receivercreator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/open-telemetry/opentelemetry-collector/component"
+	"github.com/open-telemetry/opentelemetry-collector/config"
+	"github.com/open-telemetry/opentelemetry-collector/config/configmodels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
+)
+
+func newStatusTestCreator(t *testing.T) *receiverCreator {
+	runner := &mockRunner{}
+	redisCfg := receiverConfig{typeStr: "redis", config: userConfigMap{"password": "secret"}, fullName: "redis/1"}
+	brokenCfg := receiverConfig{typeStr: "broken", config: userConfigMap{}, fullName: "broken/1"}
+	templateCfg := receiverConfig{typeStr: "nginx", config: userConfigMap{"path": "`labels[\"path\"] + port`"}, fullName: "nginx/1"}
+	hostCfg := receiverConfig{typeStr: "host", config: userConfigMap{}, fullName: "host/1"}
+
+	rc := &receiverCreator{
+		logger: zap.NewNop(),
+		cfg:    &Config{ReceiverSettings: configmodels.ReceiverSettings{NameVal: "receiver_creator/1"}},
+		observerHandler: observerHandler{
+			logger: zap.NewNop(),
+			receiverTemplates: map[string]receiverTemplate{
+				"redis/1":  {redisCfg, "type.port", newRuleOrPanic("type.port")},
+				"broken/1": {brokenCfg, "type.port", newRuleOrPanic("type.port")},
+				"nginx/1":  {templateCfg, "type.port", newRuleOrPanic("type.port")},
+				"host/1":   {hostCfg, "type.host", newRuleOrPanic("type.host")},
+			},
+			receiversByEndpointID: receiverMap{},
+			runtimeConfigs:        map[component.Receiver]*runtimeConfig{},
+			endpointStatuses:      map[string]*endpointStatus{},
+			runner:                runner,
+		},
+	}
+
+	discovered := userConfigMap{endpointConfigKey: "10.0.0.1:6379"}
+	runner.On("start", redisCfg, discovered).Return(&config.ExampleReceiverProducer{}, nil)
+	runner.On("start", brokenCfg, discovered).Return((*config.ExampleReceiverProducer)(nil), errors.New("port in use"))
+
+	rc.observerHandler.OnAdd([]observer.Endpoint{
+		observer.NewPortEndpoint("id-1", "10.0.0.1", 6379, observer.ProtocolTCP, map[string]string{"app": "redis"}),
+	})
+	runner.AssertExpectations(t)
+
+	return rc
+}
+
+func TestStatus(t *testing.T) {
+	rc := newStatusTestCreator(t)
+
+	reports := rc.observerHandler.status()
+	require.Len(t, reports, 1)
+	report := reports[0]
+
+	assert.Equal(t, "id-1", report.ID)
+	assert.Equal(t, map[string]string{"app": "redis"}, report.Labels)
+	assert.Empty(t, report.Error)
+	require.Len(t, report.Templates, 4)
+	assert.Equal(t, templateReport{Name: "broken/1", Reason: "failed to start receiver: port in use"}, report.Templates[0])
+	assert.Equal(t, templateReport{Name: "host/1", Reason: "rule did not match"}, report.Templates[1])
+	assert.Equal(t, "nginx/1", report.Templates[2].Name)
+	assert.False(t, report.Templates[2].Matched)
+	assert.Contains(t, report.Templates[2].Reason, `failed to expand "path"`)
+	assert.Equal(t, templateReport{Name: "redis/1", Matched: true}, report.Templates[3])
+
+	assert.Equal(t, []receiverReport{
+		{Template: "redis/1", Config: "{\n  \"endpoint\": \"10.0.0.1:6379\",\n  \"password\": \"secret\"\n}"},
+	}, report.Receivers)
+}
+
+func TestStatusRemovedEndpoint(t *testing.T) {
+	rc := newStatusTestCreator(t)
+	runner := rc.observerHandler.runner.(*mockRunner)
+	runner.On("shutdown", rc.observerHandler.receiversByEndpointID.Get("id-1")[0]).Return(nil)
+
+	rc.observerHandler.OnRemove([]observer.Endpoint{observer.NewHostEndpoint("id-1", "10.0.0.1", nil)})
+	assert.Empty(t, rc.observerHandler.status())
+}
+
+func TestStatusHandler(t *testing.T) {
+	rc := newStatusTestCreator(t)
+
+	rec := httptest.NewRecorder()
+	rc.statusHandler(rec, httptest.NewRequest(http.MethodGet, statusPath, nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+	body := rec.Body.String()
+	assert.Contains(t, body, "<h1>receiver_creator/1</h1>")
+	assert.Contains(t, body, "<h2>id-1</h2>")
+	assert.Contains(t, body, "failed to start receiver: port in use")
+	assert.Contains(t, body, "rule did not match")
+
+	rec = httptest.NewRecorder()
+	rc.statusHandler(rec, httptest.NewRequest(http.MethodGet, statusPath+"?format=json", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var page statusPage
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+	assert.Equal(t, "receiver_creator/1", page.Name)
+	require.Len(t, page.Endpoints, 1)
+	assert.Len(t, page.Endpoints[0].Templates, 4)
+	assert.Len(t, page.Endpoints[0].Receivers, 1)
+}
+
+func TestStatusServer(t *testing.T) {
+	rc := &receiverCreator{
+		logger: zap.NewNop(),
+		cfg:    &Config{StatusEndpoint: "localhost:0"},
+	}
+	require.NoError(t, rc.startStatusServer())
+	assert.NoError(t, rc.stopStatusServer(context.Background()))
+
+	rc = &receiverCreator{
+		logger: zap.NewNop(),
+		cfg:    &Config{StatusEndpoint: "invalid:address:0"},
+	}
+	assert.Error(t, rc.startStatusServer())
+	assert.NoError(t, rc.stopStatusServer(context.Background()))
+}
@@ -15,6 +15,9 @@
 package redisreceiver
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/go-redis/redis/v7"
 )
 
@@ -22,11 +25,42 @@ import (
 type client interface {
 	// retrieves a string of key/value pairs of redis metadata
 	retrieveInfo() (string, error)
+	// retrieves a section of redis metadata, e.g. replication, lighter than
+	// retrieveInfo, or a section not returned by default, e.g. commandstats
+	retrieveInfoSection(section string) (string, error)
+	// retrieves the latest latency spikes, one array per event, as returned
+	// by LATENCY LATEST
+	retrieveLatencyLatest() ([]interface{}, error)
+	// retrieves the number of entries in the slow log
+	retrieveSlowlogLen() (int64, error)
+	// scans the keys matching the pattern from the cursor, returns the keys
+	// and the cursor to scan from next, 0 once done
+	scanKeys(cursor uint64, match string, count int64) ([]string, uint64, error)
+	// retrieves the type of a key, "none" if it doesn't exist
+	retrieveKeyType(key string) (string, error)
+	// retrieves the memory used by a key and its value, in bytes, fails
+	// with errKeyNotFound if it doesn't exist
+	retrieveMemoryUsage(key string) (int64, error)
+	// retrieves the length of the value of a key of one of the types in
+	// keyLengthTypes
+	retrieveKeyLength(key string, keyType string) (int64, error)
+	// retrieves the description of the nodes of the redis cluster, one per
+	// line, as returned by CLUSTER NODES
+	retrieveClusterNodes() (string, error)
 	// line delimiter
 	// redis lines are delimited by \r\n, files (for testing) by \n
 	delimiter() string
+	// closes the connections of the client
+	close() error
 }
 
+// Returned when a key doesn't exist.
+var errKeyNotFound = errors.New("key not found")
+
+// Creates clients of Redis nodes by their address. Used to connect to the
+// nodes discovered in cluster mode.
+type clientFactory func(address string) client
+
 // Wraps a real Redis client, implements `client` interface.
 type redisClient struct {
 	client *redis.Client
@@ -41,6 +75,42 @@ func newRedisClient(options *redis.Options) client {
 	}
 }
 
+// Builds the options of clients connecting to addr from the receiver config.
+func newRedisOptions(cfg *config, addr string) (*redis.Options, error) {
+	tlsCfg, err := cfg.TLS.load()
+	if err != nil {
+		return nil, err
+	}
+
+	options := &redis.Options{
+		Addr:      addr,
+		Password:  cfg.Password,
+		TLSConfig: tlsCfg,
+	}
+
+	// The Redis client only authenticates with a password, ACL users are
+	// authenticated on connection instead.
+	if cfg.Username != "" {
+		username, password := cfg.Username, cfg.Password
+		options.Password = ""
+		options.OnConnect = func(conn *redis.Conn) error {
+			return conn.Process(redis.NewStatusCmd("auth", username, password))
+		}
+	}
+
+	return options, nil
+}
+
+// Returns a factory of clients with the same options, connecting to other
+// nodes.
+func newRedisClientFactory(options *redis.Options) clientFactory {
+	return func(address string) client {
+		nodeOptions := *options
+		nodeOptions.Addr = address
+		return newRedisClient(&nodeOptions)
+	}
+}
+
 // Redis strings are CRLF delimited.
 func (c *redisClient) delimiter() string {
 	return "\r\n"
@@ -50,3 +120,73 @@ func (c *redisClient) delimiter() string {
 func (c *redisClient) retrieveInfo() (string, error) {
 	return c.client.Info().Result()
 }
+
+// Retrieve a section of Redis INFO.
+func (c *redisClient) retrieveInfoSection(section string) (string, error) {
+	return c.client.Info(section).Result()
+}
+
+// Retrieve the latest latency spikes of Redis events.
+func (c *redisClient) retrieveLatencyLatest() ([]interface{}, error) {
+	res, err := c.client.Do("latency", "latest").Result()
+	if err != nil {
+		return nil, err
+	}
+	events, ok := res.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected LATENCY LATEST reply %T", res)
+	}
+	return events, nil
+}
+
+// Retrieve the length of the Redis slow log.
+func (c *redisClient) retrieveSlowlogLen() (int64, error) {
+	return c.client.Do("slowlog", "len").Int64()
+}
+
+// Retrieve the nodes of the Redis cluster.
+func (c *redisClient) retrieveClusterNodes() (string, error) {
+	return c.client.ClusterNodes().Result()
+}
+
+// Scan the keys matching the pattern.
+func (c *redisClient) scanKeys(cursor uint64, match string, count int64) ([]string, uint64, error) {
+	return c.client.Scan(cursor, match, count).Result()
+}
+
+// Retrieve the type of a key.
+func (c *redisClient) retrieveKeyType(key string) (string, error) {
+	return c.client.Type(key).Result()
+}
+
+// Retrieve the memory usage of a key.
+func (c *redisClient) retrieveMemoryUsage(key string) (int64, error) {
+	usage, err := c.client.MemoryUsage(key).Result()
+	if err == redis.Nil {
+		return 0, errKeyNotFound
+	}
+	return usage, err
+}
+
+// Retrieve the length of the value of a key with the command of its type.
+func (c *redisClient) retrieveKeyLength(key string, keyType string) (int64, error) {
+	switch keyType {
+	case "string":
+		return c.client.StrLen(key).Result()
+	case "list":
+		return c.client.LLen(key).Result()
+	case "hash":
+		return c.client.HLen(key).Result()
+	case "set":
+		return c.client.SCard(key).Result()
+	case "zset":
+		return c.client.ZCard(key).Result()
+	case "stream":
+		return c.client.XLen(key).Result()
+	}
+	return 0, fmt.Errorf("no length of keys of type %s", keyType)
+}
+
+func (c *redisClient) close() error {
+	return c.client.Close()
+}
@@ -15,8 +15,11 @@
 package redisreceiver
 
 import (
+	"errors"
 	"io/ioutil"
 	"path"
+	"sort"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -25,7 +28,24 @@ import (
 
 var _ client = (*fakeClient)(nil)
 
-type fakeClient struct{}
+// Replies to commands with the content of files in testdata.
+type fakeClient struct {
+	// file of the reply to INFO, "info" if empty
+	infoFile string
+	// file of the reply to CLUSTER NODES, fails as with cluster support
+	// disabled if empty
+	clusterNodesFile string
+	// keys of the node by name, for key commands
+	keys map[string]fakeKey
+	// counts the key commands issued, if set
+	keyCommands *int
+}
+
+type fakeKey struct {
+	keyType string
+	memory  int64
+	length  int64
+}
 
 func newFakeClient() *fakeClient {
 	return &fakeClient{}
@@ -35,8 +55,109 @@ func (c fakeClient) delimiter() string {
 	return "\n"
 }
 
-func (fakeClient) retrieveInfo() (string, error) {
-	return readFile("info")
+func (c fakeClient) retrieveInfo() (string, error) {
+	if c.infoFile == "" {
+		return readFile("info")
+	}
+	return readFile(c.infoFile)
+}
+
+func (c fakeClient) retrieveInfoSection(section string) (string, error) {
+	if section == "replication" {
+		// The replication section is part of the complete INFO.
+		return c.retrieveInfo()
+	}
+	return readFile(section)
+}
+
+// Replies with the events of testdata/latency_latest.txt, one per line:
+// "<event> <timestamp> <latest> <max>".
+func (c fakeClient) retrieveLatencyLatest() ([]interface{}, error) {
+	str, err := readFile("latency_latest")
+	if err != nil {
+		return nil, err
+	}
+	var events []interface{}
+	for _, line := range strings.Split(strings.TrimSpace(str), "\n") {
+		fields := strings.Fields(line)
+		event := []interface{}{fields[0]}
+		for _, field := range fields[1:] {
+			i, err := strconv.ParseInt(field, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			event = append(event, i)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func (c fakeClient) retrieveSlowlogLen() (int64, error) {
+	return 3, nil
+}
+
+func (c fakeClient) retrieveClusterNodes() (string, error) {
+	if c.clusterNodesFile == "" {
+		return "", errors.New("ERR This instance has cluster support disabled")
+	}
+	return readFile(c.clusterNodesFile)
+}
+
+// Scans count keys per call in order of their names, the cursor being the
+// index of the next key.
+func (c fakeClient) scanKeys(cursor uint64, match string, count int64) ([]string, uint64, error) {
+	c.countKeyCommand()
+	var names []string
+	for name := range c.keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var keys []string
+	end := int(cursor) + int(count)
+	for i := int(cursor); i < end && i < len(names); i++ {
+		if ok, _ := path.Match(match, names[i]); ok {
+			keys = append(keys, names[i])
+		}
+	}
+	if end >= len(names) {
+		return keys, 0, nil
+	}
+	return keys, uint64(end), nil
+}
+
+func (c fakeClient) retrieveKeyType(key string) (string, error) {
+	c.countKeyCommand()
+	k, ok := c.keys[key]
+	if !ok {
+		return "none", nil
+	}
+	return k.keyType, nil
+}
+
+func (c fakeClient) retrieveMemoryUsage(key string) (int64, error) {
+	c.countKeyCommand()
+	k, ok := c.keys[key]
+	if !ok {
+		return 0, errKeyNotFound
+	}
+	return k.memory, nil
+}
+
+func (c fakeClient) retrieveKeyLength(key string, _ string) (int64, error) {
+	c.countKeyCommand()
+	return c.keys[key].length, nil
+}
+
+func (c fakeClient) countKeyCommand() {
+	if c.keyCommands != nil {
+		*c.keyCommands++
+	}
+}
+
+func (fakeClient) close() error {
+	return nil
 }
 
 func readFile(fname string) (string, error) {
@@ -53,3 +174,56 @@ func TestRetrieveInfo(t *testing.T) {
 	require.Nil(t, err)
 	require.True(t, strings.HasPrefix(res, "# Server"))
 }
+
+func TestNewRedisOptions(t *testing.T) {
+	options, err := newRedisOptions(&config{Password: "secret"}, "localhost:6379")
+	require.Nil(t, err)
+	require.Equal(t, "localhost:6379", options.Addr)
+	require.Equal(t, "secret", options.Password)
+	require.Nil(t, options.TLSConfig)
+	require.Nil(t, options.OnConnect)
+}
+
+func TestNewRedisOptionsWithUsername(t *testing.T) {
+	options, err := newRedisOptions(&config{Username: "otel", Password: "secret"}, "localhost:6379")
+	require.Nil(t, err)
+	// ACL users authenticate on connection.
+	require.Equal(t, "", options.Password)
+	require.NotNil(t, options.OnConnect)
+}
+
+func TestNewRedisOptionsWithTLS(t *testing.T) {
+	options, err := newRedisOptions(&config{
+		TLS: tlsConfig{Enabled: true, ServerName: "redis", InsecureSkipVerify: true},
+	}, "localhost:6379")
+	require.Nil(t, err)
+	require.NotNil(t, options.TLSConfig)
+	require.Equal(t, "redis", options.TLSConfig.ServerName)
+	require.True(t, options.TLSConfig.InsecureSkipVerify)
+
+	_, err = newRedisOptions(&config{
+		TLS: tlsConfig{Enabled: true, CAFile: "testdata/missing.pem"},
+	}, "localhost:6379")
+	require.NotNil(t, err)
+
+	_, err = newRedisOptions(&config{
+		TLS: tlsConfig{Enabled: true, CAFile: "testdata/info.txt"},
+	}, "localhost:6379")
+	require.EqualError(t, err, "no CA certificate found in testdata/info.txt")
+
+	_, err = newRedisOptions(&config{
+		TLS: tlsConfig{Enabled: true, CertFile: "testdata/missing.pem"},
+	}, "localhost:6379")
+	require.NotNil(t, err)
+}
+
+func TestNewRedisClientFactory(t *testing.T) {
+	options, err := newRedisOptions(&config{Password: "secret"}, "localhost:6379")
+	require.Nil(t, err)
+	c := newRedisClientFactory(options)("10.0.0.2:6379").(*redisClient)
+	defer c.close()
+	require.Equal(t, "10.0.0.2:6379", c.client.Options().Addr)
+	require.Equal(t, "secret", c.client.Options().Password)
+	// The options of the endpoint are left untouched.
+	require.Equal(t, "localhost:6379", options.Addr)
+}
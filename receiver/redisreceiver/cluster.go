@@ -0,0 +1,112 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Node roles, as labeled on metrics.
+const (
+	roleMaster  = "master"
+	roleReplica = "replica"
+)
+
+// A Redis node described by CLUSTER NODES.
+type clusterNode struct {
+	address string
+	role    string
+}
+
+// Parses the output of CLUSTER NODES, one node per line:
+// "<id> <ip:port@cport[,hostname]> <flags> <master> <ping-sent> <pong-recv> <config-epoch> <link-state> <slot>..."
+// Nodes that are failing, being added or without address are skipped since
+// they can't be scraped.
+func parseClusterNodes(str string) ([]clusterNode, error) {
+	var nodes []clusterNode
+	for _, line := range strings.Split(str, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 8 {
+			return nil, fmt.Errorf("unexpected cluster node '%s'", line)
+		}
+
+		address := fields[1]
+		if i := strings.IndexAny(address, "@,"); i >= 0 {
+			address = address[:i]
+		}
+
+		var role string
+		skip := false
+		for _, flag := range strings.Split(fields[2], ",") {
+			switch flag {
+			case "master":
+				role = roleMaster
+			case "slave":
+				role = roleReplica
+			case "fail", "fail?", "handshake", "noaddr":
+				skip = true
+			}
+		}
+		if skip || role == "" || strings.HasPrefix(address, ":") {
+			continue
+		}
+
+		nodes = append(nodes, clusterNode{address: address, role: role})
+	}
+	return nodes, nil
+}
+
+// Returns the addresses of the replicas listed in the replication section
+// of INFO of a master: e.g. "slave0:ip=10.0.0.2,port=6379,state=online,offset=1,lag=0".
+func (i info) replicaAddresses() []string {
+	var addresses []string
+	for n := 0; ; n++ {
+		str, ok := i[fmt.Sprintf("slave%d", n)]
+		if !ok {
+			break
+		}
+		var ip, port string
+		for _, pair := range strings.Split(str, ",") {
+			kv := strings.SplitN(pair, "=", 2)
+			if len(kv) != 2 {
+				continue
+			}
+			switch kv[0] {
+			case "ip":
+				ip = kv[1]
+			case "port":
+				port = kv[1]
+			}
+		}
+		if ip != "" && port != "" {
+			addresses = append(addresses, net.JoinHostPort(ip, port))
+		}
+	}
+	return addresses
+}
+
+// Returns the role of the node, master or replica.
+func (i info) role() string {
+	if i["role"] == "slave" {
+		return roleReplica
+	}
+	return roleMaster
+}
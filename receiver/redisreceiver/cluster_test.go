@@ -0,0 +1,57 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseClusterNodes(t *testing.T) {
+	str, err := readFile("cluster_nodes")
+	require.Nil(t, err)
+	nodes, err := parseClusterNodes(str)
+	require.Nil(t, err)
+	require.Equal(t, []clusterNode{
+		{address: "10.0.0.4:6379", role: roleReplica},
+		{address: "10.0.0.2:6379", role: roleMaster},
+		{address: "10.0.0.3:6379", role: roleMaster},
+		{address: "10.0.0.5:6379", role: roleReplica},
+		{address: "10.0.0.6:6379", role: roleReplica},
+		{address: "10.0.0.1:6379", role: roleMaster},
+	}, nodes)
+}
+
+func TestParseClusterNodesMalformed(t *testing.T) {
+	_, err := parseClusterNodes("e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca 10.0.0.1:6379@16379 master")
+	require.NotNil(t, err)
+}
+
+func TestReplicaAddresses(t *testing.T) {
+	svc := newRedisSvc(fakeClient{infoFile: "info_master"})
+	info, err := svc.info()
+	require.Nil(t, err)
+	require.Equal(t, []string{"10.0.0.2:6379", "10.0.0.3:6379"}, info.replicaAddresses())
+	require.Equal(t, roleMaster, info.role())
+}
+
+func TestReplicaRole(t *testing.T) {
+	svc := newRedisSvc(fakeClient{infoFile: "info_replica"})
+	info, err := svc.info()
+	require.Nil(t, err)
+	require.Empty(t, info.replicaAddresses())
+	require.Equal(t, roleReplica, info.role())
+}
@@ -0,0 +1,97 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Prefixes of the keys of the Commandstats and Errorstats sections of INFO.
+const (
+	commandStatsPrefix = "cmdstat_"
+	errorStatsPrefix   = "errorstat_"
+)
+
+// Holds fields returned for a command by the Commandstats section of the INFO
+// command: e.g. "cmdstat_get:calls=21,usec=175,usec_per_call=8.33"
+type commandStats struct {
+	command     string
+	calls       int64
+	usec        int64
+	usecPerCall float64
+}
+
+// Turns a commandstats value (the part after the colon
+// e.g. "calls=21,usec=175,usec_per_call=8.33") into a commandStats struct.
+// Fields added by later versions, e.g. "rejected_calls", are ignored.
+func parseCommandStatsString(command string, str string) (*commandStats, error) {
+	cs := commandStats{command: command}
+	err := parseStatsPairs(str, func(key string, val string) error {
+		var err error
+		switch key {
+		case "calls":
+			cs.calls, err = strconv.ParseInt(val, 10, 64)
+		case "usec":
+			cs.usec, err = strconv.ParseInt(val, 10, 64)
+		case "usec_per_call":
+			cs.usecPerCall, err = strconv.ParseFloat(val, 64)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &cs, nil
+}
+
+// Holds fields returned for an error prefix by the Errorstats section of the
+// INFO command: e.g. "errorstat_ERR:count=2"
+type errorStats struct {
+	prefix string
+	count  int64
+}
+
+// Turns an errorstats value (the part after the colon e.g. "count=2") into an
+// errorStats struct.
+func parseErrorStatsString(prefix string, str string) (*errorStats, error) {
+	es := errorStats{prefix: prefix}
+	err := parseStatsPairs(str, func(key string, val string) error {
+		var err error
+		if key == "count" {
+			es.count, err = strconv.ParseInt(val, 10, 64)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &es, nil
+}
+
+// Calls fn with each key and value of comma separated "key=value" pairs.
+func parseStatsPairs(str string, fn func(key string, val string) error) error {
+	for _, pairStr := range strings.Split(str, ",") {
+		pair := strings.Split(pairStr, "=")
+		if len(pair) != 2 {
+			return fmt.Errorf("unexpected stats pair '%s'", pairStr)
+		}
+		if err := fn(pair[0], pair[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
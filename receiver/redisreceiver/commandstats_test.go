@@ -0,0 +1,102 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCommandStats(t *testing.T) {
+	cs, err := parseCommandStatsString("set", "calls=12,usec=98,usec_per_call=8.17,rejected_calls=0,failed_calls=0")
+	require.Nil(t, err)
+	require.Equal(t, &commandStats{command: "set", calls: 12, usec: 98, usecPerCall: 8.17}, cs)
+
+	_, err = parseCommandStatsString("set", "calls=12,usec")
+	require.EqualError(t, err, "unexpected stats pair 'usec'")
+
+	_, err = parseCommandStatsString("set", "calls=x")
+	require.NotNil(t, err)
+}
+
+func TestParseErrorStats(t *testing.T) {
+	es, err := parseErrorStatsString("ERR", "count=2")
+	require.Nil(t, err)
+	require.Equal(t, &errorStats{prefix: "ERR", count: 2}, es)
+
+	_, err = parseErrorStatsString("ERR", "count=")
+	require.NotNil(t, err)
+}
+
+func TestCommandStatsMetrics(t *testing.T) {
+	svc := newRedisSvc(newFakeClient())
+	info, err := svc.infoSection("commandstats")
+	require.Nil(t, err)
+	m, warnings := info.buildCommandStatsProtoMetrics(getDefaultStartTime())
+	require.Nil(t, warnings)
+	require.Equal(t, 9, len(m))
+
+	// Commands are sorted.
+	require.Equal(t, "redis/commands/calls", m[0].MetricDescriptor.Name)
+	require.Equal(t, "command", m[0].MetricDescriptor.LabelKeys[0].Key)
+	require.Equal(t, "get", m[0].Timeseries[0].LabelValues[0].Value)
+	require.Equal(t, metricspb.MetricDescriptor_CUMULATIVE_INT64, m[0].MetricDescriptor.Type)
+	require.Equal(t, &metricspb.Point_Int64Value{Int64Value: 21}, m[0].Timeseries[0].Points[0].Value)
+
+	require.Equal(t, "redis/commands/usec", m[1].MetricDescriptor.Name)
+	require.Equal(t, "us", m[1].MetricDescriptor.Unit)
+	require.Equal(t, &metricspb.Point_Int64Value{Int64Value: 175}, m[1].Timeseries[0].Points[0].Value)
+
+	require.Equal(t, "redis/commands/usec_per_call", m[2].MetricDescriptor.Name)
+	require.Equal(t, metricspb.MetricDescriptor_GAUGE_DOUBLE, m[2].MetricDescriptor.Type)
+	require.Equal(t, &metricspb.Point_DoubleValue{DoubleValue: 8.33}, m[2].Timeseries[0].Points[0].Value)
+
+	require.Equal(t, "info", m[3].Timeseries[0].LabelValues[0].Value)
+	require.Equal(t, "set", m[6].Timeseries[0].LabelValues[0].Value)
+}
+
+func TestErrorStatsMetrics(t *testing.T) {
+	svc := newRedisSvc(newFakeClient())
+	info, err := svc.infoSection("errorstats")
+	require.Nil(t, err)
+	m, warnings := info.buildErrorStatsProtoMetrics(getDefaultStartTime())
+	require.Nil(t, warnings)
+	require.Equal(t, 2, len(m))
+
+	require.Equal(t, "redis/errors", m[0].MetricDescriptor.Name)
+	require.Equal(t, "error", m[0].MetricDescriptor.LabelKeys[0].Key)
+	require.Equal(t, "ERR", m[0].Timeseries[0].LabelValues[0].Value)
+	require.Equal(t, metricspb.MetricDescriptor_CUMULATIVE_INT64, m[0].MetricDescriptor.Type)
+	require.Equal(t, &metricspb.Point_Int64Value{Int64Value: 2}, m[0].Timeseries[0].Points[0].Value)
+	require.Equal(t, "WRONGTYPE", m[1].Timeseries[0].LabelValues[0].Value)
+}
+
+func TestCommandStatsWarnings(t *testing.T) {
+	i := info{
+		"cmdstat_get":   "calls=21,usec=175,usec_per_call=8.33",
+		"cmdstat_set":   "calls",
+		"uptime":        "1",
+		"errorstat_ERR": "count=x",
+	}
+	m, warnings := i.buildCommandStatsProtoMetrics(getDefaultStartTime())
+	require.Equal(t, 3, len(m))
+	require.Equal(t, 1, len(warnings))
+
+	m, warnings = i.buildErrorStatsProtoMetrics(getDefaultStartTime())
+	require.Empty(t, m)
+	require.Equal(t, 1, len(warnings))
+}
@@ -15,16 +15,125 @@
 package redisreceiver
 
 import (
-	"time"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
 
 	"github.com/open-telemetry/opentelemetry-collector/config/configmodels"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/scraperhelper"
 )
 
 type config struct {
 	configmodels.ReceiverSettings `mapstructure:",squash"`
 	// The duration between Redis metric fetches.
-	CollectionInterval time.Duration `mapstructure:"collection_interval"`
+	scraperhelper.ScraperSettings `mapstructure:",squash"`
 	// Optional password. Must match the password specified in the
-	// requirepass server configuration option.
+	// requirepass server configuration option, or the password of the user.
 	Password string `mapstructure:"password"`
+	// Optional ACL user, supported by Redis 6 and later. Authenticates as the
+	// default user if empty.
+	Username string `mapstructure:"username"`
+	// TLS settings of connections to Redis.
+	TLS tlsConfig `mapstructure:"tls"`
+	// Whether to discover and scrape all masters and replicas of the Redis
+	// cluster or replication group the endpoint belongs to.
+	ClusterMode bool `mapstructure:"cluster_mode"`
+	// Enables or disables groups of metrics by name, groups not set are
+	// enabled or not by default.
+	MetricGroups map[string]bool `mapstructure:"metric_groups"`
+	// Settings of the sampling of keys matching patterns, disabled unless
+	// patterns are set.
+	KeySampling keySamplingConfig `mapstructure:"key_sampling"`
+}
+
+type tlsConfig struct {
+	// Whether to connect with TLS.
+	Enabled bool `mapstructure:"enabled"`
+	// Optional CA certificate verifying the certificate of the server,
+	// system CAs are used if empty.
+	CAFile string `mapstructure:"ca_file"`
+	// Optional client certificate and key, for servers requiring them.
+	CertFile string `mapstructure:"cert_file"`
+	KeyFile  string `mapstructure:"key_file"`
+	// Optional name of the server to verify its certificate against,
+	// defaults to the host of the address connected to.
+	ServerName string `mapstructure:"server_name"`
+	// Whether to skip verification of the certificate of the server.
+	InsecureSkipVerify bool `mapstructure:"insecure_skip_verify"`
+}
+
+// load returns the TLS configuration of connections, nil if TLS is disabled.
+func (c *tlsConfig) load() (*tls.Config, error) {
+	if !c.Enabled {
+		return nil, nil
+	}
+
+	tlsCfg := &tls.Config{
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+
+	if c.CAFile != "" {
+		pem, err := ioutil.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate: %v", err)
+		}
+		tlsCfg.RootCAs = x509.NewCertPool()
+		if !tlsCfg.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no CA certificate found in %s", c.CAFile)
+		}
+	}
+
+	if c.CertFile != "" || c.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %v", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsCfg, nil
+}
+
+type keySamplingConfig struct {
+	// Patterns of the keys to sample, as matched by SCAN, e.g. "session:*".
+	Patterns []string `mapstructure:"patterns"`
+	// Number of keys to scan per SCAN command, the COUNT hint.
+	ScanCount int64 `mapstructure:"scan_count"`
+	// Max number of commands issued to a node per interval to sample keys,
+	// across patterns.
+	MaxCommandsPerInterval int `mapstructure:"max_commands_per_interval"`
+	// Max number of keys of a pattern inspected per scan of the keyspace,
+	// other matching keys are only counted.
+	MaxSampledKeys int `mapstructure:"max_sampled_keys"`
+	// Number of largest keys of a pattern to report.
+	TopKeys int `mapstructure:"top_keys"`
+}
+
+func (c *keySamplingConfig) enabled() bool {
+	return len(c.Patterns) > 0
+}
+
+func (c *keySamplingConfig) validate() error {
+	if !c.enabled() {
+		return nil
+	}
+	if c.ScanCount <= 0 {
+		return fmt.Errorf("key_sampling scan_count must be positive, got %d", c.ScanCount)
+	}
+	if c.MaxCommandsPerInterval < len(c.Patterns) {
+		return fmt.Errorf(
+			"key_sampling max_commands_per_interval must be at least the number of patterns (%d), got %d",
+			len(c.Patterns), c.MaxCommandsPerInterval,
+		)
+	}
+	if c.MaxSampledKeys < 0 {
+		return fmt.Errorf("key_sampling max_sampled_keys can't be negative, got %d", c.MaxSampledKeys)
+	}
+	if c.TopKeys < 0 {
+		return fmt.Errorf("key_sampling top_keys can't be negative, got %d", c.TopKeys)
+	}
+	return nil
 }
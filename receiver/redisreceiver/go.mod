@@ -7,6 +7,9 @@ require (
 	github.com/go-redis/redis/v7 v7.2.0
 	github.com/golang/protobuf v1.3.5
 	github.com/open-telemetry/opentelemetry-collector v0.3.1-0.20200427150635-ca4b8231de7c
+	github.com/open-telemetry/opentelemetry-collector-contrib/receiver/scraperhelper v0.0.0
 	github.com/stretchr/testify v1.5.1
 	go.uber.org/zap v1.10.0
 )
+
+replace github.com/open-telemetry/opentelemetry-collector-contrib/receiver/scraperhelper => ../scraperhelper
@@ -16,46 +16,33 @@ package redisreceiver
 
 import (
 	"errors"
-	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 
 	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/scraperhelper"
 )
 
 // A map of the INFO data returned from Redis.
 type info map[string]string
 
 // Builds protobuf metrics from the combination of a metrics map
-// (INFO from Redis) and redisMetrics (built at startup). These are the fixed,
-// non keyspace metrics. Returns a list of parsing errors, which can be treated
-// like warnings.
-func (i info) buildFixedProtoMetrics(metrics []*redisMetric, t *timeBundle) (
+// (INFO from Redis) and metric definitions (built at startup). These are the
+// fixed, non keyspace metrics. Returns a list of parsing errors, which can be
+// treated like warnings.
+func (i info) buildFixedProtoMetrics(metrics []*scraperhelper.MetricDefinition, t *scraperhelper.StartTime) (
 	protoMetrics []*metricspb.Metric,
 	warnings []error,
 ) {
-	for _, redisMetric := range metrics {
-		strVal, ok := i[redisMetric.key]
-		if !ok {
-			warnings = append(warnings, fmt.Errorf("info key not found: %v", redisMetric.key))
-			continue
-		}
-		if strVal == "" {
-			continue
-		}
-		protoMetric, parsingError := redisMetric.parseMetric(strVal, t)
-		if parsingError != nil {
-			warnings = append(warnings, parsingError)
-			continue
-		}
-		protoMetrics = append(protoMetrics, protoMetric)
-	}
-	return protoMetrics, warnings
+	return scraperhelper.BuildMetrics(i, metrics, t)
 }
 
 // Builds proto metrics from any 'keyspace' metrics in Redis INFO:
 // e.g. "db0:keys=1,expires=2, avg_ttl=3". Returns proto metrics and parsing
 // errors, to be treated as warnings, if there were any.
-func (i info) buildKeyspaceProtoMetrics(t *timeBundle) (
+func (i info) buildKeyspaceProtoMetrics(t *scraperhelper.StartTime) (
 	protoMetrics []*metricspb.Metric,
 	warnings []error,
 ) {
@@ -77,6 +64,56 @@ func (i info) buildKeyspaceProtoMetrics(t *timeBundle) (
 	return protoMetrics, warnings
 }
 
+// Builds proto metrics from the 'commandstats' section of Redis INFO, one
+// line per command called since startup: e.g.
+// "cmdstat_get:calls=21,usec=175,usec_per_call=8.33". Returns proto metrics
+// and parsing errors, to be treated as warnings, if there were any.
+func (i info) buildCommandStatsProtoMetrics(t *scraperhelper.StartTime) (
+	protoMetrics []*metricspb.Metric,
+	warnings []error,
+) {
+	for _, key := range i.keysWithPrefix(commandStatsPrefix) {
+		cs, parsingError := parseCommandStatsString(strings.TrimPrefix(key, commandStatsPrefix), i[key])
+		if parsingError != nil {
+			warnings = append(warnings, parsingError)
+			continue
+		}
+		protoMetrics = append(protoMetrics, buildCommandStatsMetrics(cs, t)...)
+	}
+	return protoMetrics, warnings
+}
+
+// Builds proto metrics from the 'errorstats' section of Redis INFO, one line
+// per error prefix replied since startup: e.g. "errorstat_ERR:count=2".
+// Returns proto metrics and parsing errors, to be treated as warnings, if
+// there were any.
+func (i info) buildErrorStatsProtoMetrics(t *scraperhelper.StartTime) (
+	protoMetrics []*metricspb.Metric,
+	warnings []error,
+) {
+	for _, key := range i.keysWithPrefix(errorStatsPrefix) {
+		es, parsingError := parseErrorStatsString(strings.TrimPrefix(key, errorStatsPrefix), i[key])
+		if parsingError != nil {
+			warnings = append(warnings, parsingError)
+			continue
+		}
+		protoMetrics = append(protoMetrics, buildErrorStatsMetric(es, t))
+	}
+	return protoMetrics, warnings
+}
+
+// Returns the sorted keys starting with prefix.
+func (i info) keysWithPrefix(prefix string) []string {
+	var keys []string
+	for key := range i {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func (i info) getUptimeInSeconds() (int, error) {
 	const uptimeKey = "uptime_in_seconds"
 	uptimeStr, ok := i[uptimeKey]
@@ -0,0 +1,235 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"math"
+	"sort"
+)
+
+// Types of keys whose length is retrieved, with STRLEN, LLEN, HLEN, SCARD,
+// ZCARD and XLEN.
+var keyLengthTypes = map[string]bool{
+	"string": true,
+	"list":   true,
+	"hash":   true,
+	"set":    true,
+	"zset":   true,
+	"stream": true,
+}
+
+// Max number of commands issued to inspect a key: TYPE, MEMORY USAGE and the
+// length command of its type.
+const commandsPerKey = 3
+
+// Percentiles of memory usage and lengths of keys reported, by label value.
+var keyPercentiles = []struct {
+	label      string
+	percentile float64
+}{
+	{"p50", 50},
+	{"p90", 90},
+	{"p99", 99},
+	{"max", 100},
+}
+
+// Samples the keys of a node matching patterns with SCAN, issuing at most a
+// max number of commands per interval. Scans of the keyspace resume where
+// they left off on the next interval when the limit is reached, stats of a
+// pattern are reported once a scan is complete.
+type keySampler struct {
+	cfg   *keySamplingConfig
+	scans []*keyScan
+}
+
+// The scan of the keys matching a pattern.
+type keyScan struct {
+	pattern string
+	// cursor to scan from next, and whether the scan started
+	cursor  uint64
+	started bool
+	// keys returned by the last SCAN not inspected yet
+	pending []string
+	// stats of the scan in progress, and of the last complete scan, nil
+	// until a scan completes
+	current *keyStats
+	last    *keyStats
+}
+
+// Stats of the keys matching a pattern.
+type keyStats struct {
+	// number of keys matched, keys modified during a scan may be counted
+	// twice or missed
+	keys int64
+	// inspected keys, up to max_sampled_keys
+	samples []keySample
+}
+
+type keySample struct {
+	key     string
+	keyType string
+	memory  int64
+	// -1 for types without length
+	length int64
+}
+
+func newKeySampler(cfg *keySamplingConfig) *keySampler {
+	s := &keySampler{cfg: cfg}
+	for _, pattern := range cfg.Patterns {
+		s.scans = append(s.scans, &keyScan{pattern: pattern, current: &keyStats{}})
+	}
+	return s
+}
+
+// Scans the keys of each pattern in turn. Commands are split evenly between
+// patterns, those not used by a pattern are left to the next ones. Returns
+// the number of commands issued and the errors of the scans, which resume
+// on the next interval.
+func (s *keySampler) sample(c client) (int, []error) {
+	var errs []error
+	remaining := s.cfg.MaxCommandsPerInterval
+	for i, scan := range s.scans {
+		budget := remaining / (len(s.scans) - i)
+		used, err := scan.run(c, budget, s.cfg)
+		remaining -= used
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return s.cfg.MaxCommandsPerInterval - remaining, errs
+}
+
+// Scans keys until the scan completes or the budget of commands is spent.
+// Returns the number of commands issued.
+func (sc *keyScan) run(c client, budget int, cfg *keySamplingConfig) (int, error) {
+	used := 0
+	for {
+		for len(sc.pending) > 0 {
+			if len(sc.current.samples) >= cfg.MaxSampledKeys {
+				sc.pending = nil
+				break
+			}
+			if budget-used < commandsPerKey {
+				return used, nil
+			}
+			key := sc.pending[0]
+			sc.pending = sc.pending[1:]
+			sample, n, err := inspectKey(c, key)
+			used += n
+			if err != nil {
+				return used, err
+			}
+			if sample != nil {
+				sc.current.samples = append(sc.current.samples, *sample)
+			}
+		}
+
+		if sc.started && sc.cursor == 0 {
+			// Complete, the next scan starts on the next interval.
+			sc.last = sc.current
+			sc.current = &keyStats{}
+			sc.started = false
+			return used, nil
+		}
+
+		if budget-used < 1 {
+			return used, nil
+		}
+		keys, cursor, err := c.scanKeys(sc.cursor, sc.pattern, cfg.ScanCount)
+		used++
+		if err != nil {
+			return used, err
+		}
+		sc.started = true
+		sc.cursor = cursor
+		sc.current.keys += int64(len(keys))
+		sc.pending = keys
+	}
+}
+
+// Retrieves the type, memory usage and length of a key. Returns nil if the
+// key no longer exists, along with the number of commands issued.
+func inspectKey(c client, key string) (*keySample, int, error) {
+	keyType, err := c.retrieveKeyType(key)
+	if err != nil {
+		return nil, 1, err
+	}
+	if keyType == "none" {
+		return nil, 1, nil
+	}
+
+	memory, err := c.retrieveMemoryUsage(key)
+	if err == errKeyNotFound {
+		return nil, 2, nil
+	}
+	if err != nil {
+		return nil, 2, err
+	}
+
+	if !keyLengthTypes[keyType] {
+		return &keySample{key: key, keyType: keyType, memory: memory, length: -1}, 2, nil
+	}
+	length, err := c.retrieveKeyLength(key, keyType)
+	if err != nil {
+		return nil, 3, err
+	}
+	return &keySample{key: key, keyType: keyType, memory: memory, length: length}, 3, nil
+}
+
+// Returns the memory usage of the samples.
+func (s *keyStats) memoryUsages() []int64 {
+	values := make([]int64, 0, len(s.samples))
+	for _, sample := range s.samples {
+		values = append(values, sample.memory)
+	}
+	return values
+}
+
+// Returns the lengths of the samples by type, for types with a length.
+func (s *keyStats) lengthsByType() map[string][]int64 {
+	lengths := map[string][]int64{}
+	for _, sample := range s.samples {
+		if sample.length >= 0 {
+			lengths[sample.keyType] = append(lengths[sample.keyType], sample.length)
+		}
+	}
+	return lengths
+}
+
+// Returns the n samples using the most memory, largest first.
+func (s *keyStats) topKeys(n int) []keySample {
+	top := make([]keySample, len(s.samples))
+	copy(top, s.samples)
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].memory != top[j].memory {
+			return top[i].memory > top[j].memory
+		}
+		return top[i].key < top[j].key
+	})
+	if len(top) > n {
+		top = top[:n]
+	}
+	return top
+}
+
+// Returns the nearest-rank percentile of values, which are sorted.
+func percentile(values []int64, p float64) int64 {
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	rank := int(math.Ceil(p / 100 * float64(len(values))))
+	if rank < 1 {
+		rank = 1
+	}
+	return values[rank-1]
+}
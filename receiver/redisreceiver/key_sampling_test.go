@@ -0,0 +1,249 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newFakeKeysClient(commands *int) fakeClient {
+	return fakeClient{
+		keys: map[string]fakeKey{
+			"queue:a":   {keyType: "list", memory: 100, length: 10},
+			"queue:b":   {keyType: "list", memory: 5000, length: 1000},
+			"queue:c":   {keyType: "ReJSON-RL", memory: 300},
+			"session:1": {keyType: "string", memory: 50, length: 10},
+			"session:2": {keyType: "hash", memory: 70, length: 3},
+			"other":     {keyType: "string", memory: 10, length: 1},
+		},
+		keyCommands: commands,
+	}
+}
+
+func newTestKeySamplingConfig(patterns ...string) *keySamplingConfig {
+	return &keySamplingConfig{
+		Patterns:               patterns,
+		ScanCount:              100,
+		MaxCommandsPerInterval: 100,
+		MaxSampledKeys:         100,
+		TopKeys:                2,
+	}
+}
+
+func TestKeySampler(t *testing.T) {
+	commands := 0
+	s := newKeySampler(newTestKeySamplingConfig("queue:*", "session:*"))
+	n, errs := s.sample(newFakeKeysClient(&commands))
+	require.Nil(t, errs)
+	require.Equal(t, commands, n)
+	// A SCAN per pattern, 3 commands per key with a length, 2 otherwise.
+	require.Equal(t, 2+3*4+2, n)
+
+	queue := s.scans[0].last
+	require.NotNil(t, queue)
+	require.Equal(t, int64(3), queue.keys)
+	require.Equal(t, []keySample{
+		{key: "queue:a", keyType: "list", memory: 100, length: 10},
+		{key: "queue:b", keyType: "list", memory: 5000, length: 1000},
+		{key: "queue:c", keyType: "ReJSON-RL", memory: 300, length: -1},
+	}, queue.samples)
+
+	session := s.scans[1].last
+	require.NotNil(t, session)
+	require.Equal(t, int64(2), session.keys)
+	require.Equal(t, map[string][]int64{"string": {10}, "hash": {3}}, session.lengthsByType())
+}
+
+func TestKeySamplerResumes(t *testing.T) {
+	client := fakeClient{keys: map[string]fakeKey{}}
+	for i := 0; i < 20; i++ {
+		client.keys[fmt.Sprintf("key:%02d", i)] = fakeKey{keyType: "string", memory: int64(i), length: int64(i)}
+	}
+	commands := 0
+	client.keyCommands = &commands
+	cfg := newTestKeySamplingConfig("key:*")
+	cfg.ScanCount = 5
+	cfg.MaxCommandsPerInterval = 10
+	s := newKeySampler(cfg)
+
+	intervals := 0
+	for s.scans[0].last == nil {
+		commands = 0
+		n, errs := s.sample(client)
+		require.Nil(t, errs)
+		require.Equal(t, commands, n)
+		require.True(t, n <= cfg.MaxCommandsPerInterval)
+		intervals++
+		require.True(t, intervals < 100, "scan never completed")
+	}
+	// 4 SCAN and 60 key commands.
+	require.Equal(t, 7, intervals)
+	require.Equal(t, int64(20), s.scans[0].last.keys)
+	require.Equal(t, 20, len(s.scans[0].last.samples))
+
+	// The next scan starts over.
+	_, errs := s.sample(client)
+	require.Nil(t, errs)
+	require.Equal(t, int64(20), s.scans[0].last.keys)
+	require.Equal(t, 3, len(s.scans[0].current.samples))
+}
+
+func TestKeySamplerMaxSampledKeys(t *testing.T) {
+	commands := 0
+	cfg := newTestKeySamplingConfig("*")
+	cfg.MaxSampledKeys = 2
+	s := newKeySampler(cfg)
+	n, errs := s.sample(newFakeKeysClient(&commands))
+	require.Nil(t, errs)
+	require.Equal(t, 1+3*2, n)
+	require.Equal(t, int64(6), s.scans[0].last.keys)
+	require.Equal(t, 2, len(s.scans[0].last.samples))
+}
+
+func TestKeySamplerBudgetSplit(t *testing.T) {
+	commands := 0
+	cfg := newTestKeySamplingConfig("queue:*", "session:*", "other")
+	cfg.MaxCommandsPerInterval = 12
+	s := newKeySampler(cfg)
+	n, errs := s.sample(newFakeKeysClient(&commands))
+	require.Nil(t, errs)
+	require.Equal(t, commands, n)
+	require.True(t, n <= 12)
+	// Each pattern gets 4 commands, the SCAN and a key.
+	for _, scan := range s.scans {
+		require.Equal(t, 1, len(scan.current.samples)+len(scanSamples(scan.last)))
+	}
+}
+
+func scanSamples(s *keyStats) []keySample {
+	if s == nil {
+		return nil
+	}
+	return s.samples
+}
+
+func TestInspectDeletedKey(t *testing.T) {
+	sample, n, err := inspectKey(fakeClient{}, "gone")
+	require.Nil(t, err)
+	require.Nil(t, sample)
+	require.Equal(t, 1, n)
+}
+
+func TestPercentile(t *testing.T) {
+	values := []int64{5, 1, 4, 2, 3, 10, 9, 8, 7, 6}
+	require.Equal(t, int64(5), percentile(values, 50))
+	require.Equal(t, int64(9), percentile(values, 90))
+	require.Equal(t, int64(10), percentile(values, 99))
+	require.Equal(t, int64(10), percentile(values, 100))
+	require.Equal(t, int64(3), percentile([]int64{3}, 50))
+}
+
+func TestKeySamplingMetrics(t *testing.T) {
+	s := newKeySampler(newTestKeySamplingConfig("queue:*"))
+	_, errs := s.sample(newFakeKeysClient(nil))
+	require.Nil(t, errs)
+
+	metrics := buildKeySamplingMetrics("queue:*", s.scans[0].last, 2, getDefaultStartTime())
+	// count, sampled, 4 memory and 4 list length percentiles, 2 top keys
+	require.Equal(t, 12, len(metrics))
+
+	values := map[string]int64{}
+	for _, m := range metrics {
+		require.Equal(t, metricspb.MetricDescriptor_GAUGE_INT64, m.MetricDescriptor.Type)
+		labels := map[string]string{}
+		for i, k := range m.MetricDescriptor.LabelKeys {
+			labels[k.Key] = m.Timeseries[0].LabelValues[i].Value
+		}
+		require.Equal(t, "queue:*", labels["pattern"])
+		id := m.MetricDescriptor.Name + " " + labels["type"] + " " + labels["percentile"] + " " + labels["key"]
+		values[id] = m.Timeseries[0].Points[0].GetInt64Value()
+	}
+	require.Equal(t, map[string]int64{
+		"redis/keys/count   ":                            3,
+		"redis/keys/sampled   ":                          3,
+		"redis/keys/memory_usage  p50 ":                  300,
+		"redis/keys/memory_usage  p90 ":                  5000,
+		"redis/keys/memory_usage  p99 ":                  5000,
+		"redis/keys/memory_usage  max ":                  5000,
+		"redis/keys/length list p50 ":                    10,
+		"redis/keys/length list p90 ":                    1000,
+		"redis/keys/length list p99 ":                    1000,
+		"redis/keys/length list max ":                    1000,
+		"redis/keys/top_memory_usage list  queue:b":      5000,
+		"redis/keys/top_memory_usage ReJSON-RL  queue:c": 300,
+	}, values)
+}
+
+func TestKeySamplingConfigValidate(t *testing.T) {
+	valid := *newTestKeySamplingConfig("queue:*", "session:*")
+	require.Nil(t, valid.validate())
+	require.Nil(t, (&keySamplingConfig{}).validate())
+
+	tests := []struct {
+		name   string
+		modify func(*keySamplingConfig)
+		err    string
+	}{
+		{"scan count", func(c *keySamplingConfig) { c.ScanCount = 0 },
+			"key_sampling scan_count must be positive, got 0"},
+		{"max commands", func(c *keySamplingConfig) { c.MaxCommandsPerInterval = 1 },
+			"key_sampling max_commands_per_interval must be at least the number of patterns (2), got 1"},
+		{"max sampled keys", func(c *keySamplingConfig) { c.MaxSampledKeys = -1 },
+			"key_sampling max_sampled_keys can't be negative, got -1"},
+		{"top keys", func(c *keySamplingConfig) { c.TopKeys = -1 },
+			"key_sampling top_keys can't be negative, got -1"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := valid
+			test.modify(&cfg)
+			require.EqualError(t, cfg.validate(), test.err)
+		})
+	}
+}
+
+func TestRedisScraperKeySampling(t *testing.T) {
+	consumer := &fakeMetricsConsumer{}
+	client := newFakeKeysClient(nil)
+	scraper := newRedisScraper("localhost:6379", client, nil,
+		defaultMetricGroups(), newTestKeySamplingConfig("queue:*"), zap.NewNop())
+	require.Nil(t, scraper.Setup(context.Background()))
+	consumer.scrape(t, scraper)
+
+	names := map[string]int{}
+	for _, m := range consumer.md.Metrics {
+		names[m.MetricDescriptor.Name]++
+	}
+	require.Equal(t, 1, names["redis/keys/sampling_commands"])
+	require.Equal(t, 1, names["redis/keys/count"])
+	require.Equal(t, 2, names["redis/keys/top_memory_usage"])
+
+	// Replicas are not sampled.
+	client.infoFile = "info_replica"
+	scraper = newRedisScraper("localhost:6379", client, nil,
+		defaultMetricGroups(), newTestKeySamplingConfig("queue:*"), zap.NewNop())
+	require.Nil(t, scraper.Setup(context.Background()))
+	consumer.scrape(t, scraper)
+	for _, m := range consumer.md.Metrics {
+		require.NotEqual(t, "redis/keys/sampling_commands", m.MetricDescriptor.Name)
+		require.NotEqual(t, "redis/keys/count", m.MetricDescriptor.Name)
+	}
+}
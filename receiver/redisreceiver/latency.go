@@ -0,0 +1,53 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"fmt"
+)
+
+// Holds the latest latency spike of an event returned by the LATENCY LATEST
+// command: the name of the event, the unix time of the spike, the latency of
+// the spike and the max latency of the event, in milliseconds.
+type latencyEvent struct {
+	event  string
+	latest int64
+	max    int64
+}
+
+// Turns the reply to LATENCY LATEST, an array of arrays, into latencyEvents.
+func parseLatencyLatest(reply []interface{}) ([]*latencyEvent, error) {
+	events := make([]*latencyEvent, 0, len(reply))
+	for _, r := range reply {
+		fields, ok := r.([]interface{})
+		if !ok || len(fields) < 4 {
+			return nil, fmt.Errorf("unexpected latency event '%v'", r)
+		}
+		event, ok := fields[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected latency event name '%v'", fields[0])
+		}
+		latest, ok := fields[2].(int64)
+		if !ok {
+			return nil, fmt.Errorf("unexpected latest latency '%v' of %s", fields[2], event)
+		}
+		max, ok := fields[3].(int64)
+		if !ok {
+			return nil, fmt.Errorf("unexpected max latency '%v' of %s", fields[3], event)
+		}
+		events = append(events, &latencyEvent{event: event, latest: latest, max: max})
+	}
+	return events, nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatencyLatest(t *testing.T) {
+	svc := newRedisSvc(newFakeClient())
+	events, err := svc.latencyLatest()
+	require.Nil(t, err)
+	require.Equal(t, []*latencyEvent{
+		{event: "command", latest: 5, max: 12},
+		{event: "fast-command", latest: 1, max: 3},
+	}, events)
+
+	m := buildLatencyMetrics(events[0], getDefaultStartTime())
+	require.Equal(t, "redis/latency/latest", m[0].MetricDescriptor.Name)
+	require.Equal(t, "event", m[0].MetricDescriptor.LabelKeys[0].Key)
+	require.Equal(t, "command", m[0].Timeseries[0].LabelValues[0].Value)
+	require.Equal(t, metricspb.MetricDescriptor_GAUGE_INT64, m[0].MetricDescriptor.Type)
+	require.Equal(t, &metricspb.Point_Int64Value{Int64Value: 5}, m[0].Timeseries[0].Points[0].Value)
+	require.Equal(t, "redis/latency/max", m[1].MetricDescriptor.Name)
+	require.Equal(t, &metricspb.Point_Int64Value{Int64Value: 12}, m[1].Timeseries[0].Points[0].Value)
+}
+
+func TestParseLatencyLatestErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply []interface{}
+	}{
+		{"not an array", []interface{}{"command"}},
+		{"too short", []interface{}{[]interface{}{"command", int64(1), int64(5)}}},
+		{"event not a string", []interface{}{[]interface{}{int64(1), int64(1), int64(5), int64(12)}}},
+		{"latest not an integer", []interface{}{[]interface{}{"command", int64(1), "5", int64(12)}}},
+		{"max not an integer", []interface{}{[]interface{}{"command", int64(1), int64(5), nil}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := parseLatencyLatest(test.reply)
+			require.NotNil(t, err)
+		})
+	}
+
+	events, err := parseLatencyLatest(nil)
+	require.Nil(t, err)
+	require.Empty(t, events)
+}
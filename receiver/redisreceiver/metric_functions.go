@@ -16,306 +16,349 @@ package redisreceiver
 
 import (
 	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/scraperhelper"
 )
 
 // Called once at startup. Returns all of the metrics (except keyspace)
 // we want to extract from Redis INFO.
-func getDefaultRedisMetrics() []*redisMetric {
-	return []*redisMetric{
-		uptimeInSeconds(),
-
-		usedCPUSys(),
-		usedCPUSysChildren(),
-		usedCPUUser(),
-
-		connectedClients(),
-
-		clientRecentMaxInputBuffer(),
-		clientRecentMaxOutputBuffer(),
-
-		blockedClients(),
-
-		expiredKeys(),
-		evictedKeys(),
-
-		rejectedConnections(),
-
-		usedMemory(),
-		usedMemoryRss(),
-		usedMemoryPeak(),
-		usedMemoryLua(),
-
-		memFragmentationRatio(),
-
-		rdbChangesSinceLastSave(),
-
-		instantaneousOpsPerSec(),
-
-		rdbBgsaveInProgress(),
-
-		totalConnectionsReceived(),
-		totalCommandsProcessed(),
-
-		totalNetInputBytes(),
-		totalNetOutputBytes(),
-
-		keyspaceHits(),
-		keyspaceMisses(),
-
-		latestForkUsec(),
+func getDefaultRedisMetrics() []*scraperhelper.MetricDefinition {
+	return getRedisMetrics(defaultMetricGroups())
+}
 
-		connectedSlaves(),
+// Called once at startup. Returns the metrics (except keyspace) we want to
+// extract from Redis INFO, of the enabled groups.
+func getRedisMetrics(groups metricGroups) []*scraperhelper.MetricDefinition {
+	var metrics []*scraperhelper.MetricDefinition
+	for _, g := range infoMetricGroups() {
+		if groups.enabled(g.name) {
+			metrics = append(metrics, g.metrics...)
+		}
+	}
+	return metrics
+}
 
-		replBacklogFirstByteOffset(),
+// A group of metrics extracted from Redis INFO.
+type infoMetricGroup struct {
+	name    string
+	metrics []*scraperhelper.MetricDefinition
+}
 
-		masterReplOffset(),
+// Returns the groups of metrics extracted from Redis INFO, mostly by section
+// of INFO.
+func infoMetricGroups() []infoMetricGroup {
+	return []infoMetricGroup{
+		{
+			name: groupServer,
+			metrics: []*scraperhelper.MetricDefinition{
+				uptimeInSeconds(),
+			},
+		},
+		{
+			name: groupCPU,
+			metrics: []*scraperhelper.MetricDefinition{
+				usedCPUSys(),
+				usedCPUSysChildren(),
+				usedCPUUser(),
+			},
+		},
+		{
+			name: groupClients,
+			metrics: []*scraperhelper.MetricDefinition{
+				connectedClients(),
+				clientRecentMaxInputBuffer(),
+				clientRecentMaxOutputBuffer(),
+				blockedClients(),
+			},
+		},
+		{
+			name: groupMemory,
+			metrics: []*scraperhelper.MetricDefinition{
+				usedMemory(),
+				usedMemoryRss(),
+				usedMemoryPeak(),
+				usedMemoryLua(),
+				memFragmentationRatio(),
+			},
+		},
+		{
+			name: groupPersistence,
+			metrics: []*scraperhelper.MetricDefinition{
+				rdbChangesSinceLastSave(),
+				rdbBgsaveInProgress(),
+			},
+		},
+		{
+			name: groupStats,
+			metrics: []*scraperhelper.MetricDefinition{
+				expiredKeys(),
+				evictedKeys(),
+				rejectedConnections(),
+				instantaneousOpsPerSec(),
+				totalConnectionsReceived(),
+				totalCommandsProcessed(),
+				totalNetInputBytes(),
+				totalNetOutputBytes(),
+				keyspaceHits(),
+				keyspaceMisses(),
+				latestForkUsec(),
+			},
+		},
+		{
+			name: groupReplication,
+			metrics: []*scraperhelper.MetricDefinition{
+				connectedSlaves(),
+				replBacklogFirstByteOffset(),
+				masterReplOffset(),
+			},
+		},
 	}
 }
 
-func uptimeInSeconds() *redisMetric {
-	return &redisMetric{
-		key:    "uptime_in_seconds",
-		name:   "redis/uptime",
-		units:  "s",
-		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+func uptimeInSeconds() *scraperhelper.MetricDefinition {
+	return &scraperhelper.MetricDefinition{
+		Key:  "uptime_in_seconds",
+		Name: "redis/uptime",
+		Unit: "s",
+		Type: metricspb.MetricDescriptor_GAUGE_INT64,
 	}
 }
 
-func usedCPUSys() *redisMetric {
-	return &redisMetric{
-		key:    "used_cpu_sys",
-		name:   "redis/cpu/time",
-		units:  "s",
-		mdType: metricspb.MetricDescriptor_GAUGE_DOUBLE,
-		labels: map[string]string{"state": "sys"},
+func usedCPUSys() *scraperhelper.MetricDefinition {
+	return &scraperhelper.MetricDefinition{
+		Key:    "used_cpu_sys",
+		Name:   "redis/cpu/time",
+		Unit:   "s",
+		Type:   metricspb.MetricDescriptor_GAUGE_DOUBLE,
+		Labels: map[string]string{"state": "sys"},
 	}
 }
 
-func usedCPUUser() *redisMetric {
-	return &redisMetric{
-		key:    "used_cpu_user",
-		name:   "redis/cpu/time",
-		units:  "s",
-		mdType: metricspb.MetricDescriptor_GAUGE_DOUBLE,
-		labels: map[string]string{"state": "user"},
+func usedCPUUser() *scraperhelper.MetricDefinition {
+	return &scraperhelper.MetricDefinition{
+		Key:    "used_cpu_user",
+		Name:   "redis/cpu/time",
+		Unit:   "s",
+		Type:   metricspb.MetricDescriptor_GAUGE_DOUBLE,
+		Labels: map[string]string{"state": "user"},
 	}
 }
 
-func usedCPUSysChildren() *redisMetric {
-	return &redisMetric{
-		key:    "used_cpu_sys_children",
-		name:   "redis/cpu/time",
-		units:  "s",
-		mdType: metricspb.MetricDescriptor_GAUGE_DOUBLE,
-		labels: map[string]string{"state": "children"},
+func usedCPUSysChildren() *scraperhelper.MetricDefinition {
+	return &scraperhelper.MetricDefinition{
+		Key:    "used_cpu_sys_children",
+		Name:   "redis/cpu/time",
+		Unit:   "s",
+		Type:   metricspb.MetricDescriptor_GAUGE_DOUBLE,
+		Labels: map[string]string{"state": "children"},
 	}
 }
 
-func connectedClients() *redisMetric {
-	return &redisMetric{
-		key:    "connected_clients",
-		name:   "redis/clients/connected",
-		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+func connectedClients() *scraperhelper.MetricDefinition {
+	return &scraperhelper.MetricDefinition{
+		Key:  "connected_clients",
+		Name: "redis/clients/connected",
+		Type: metricspb.MetricDescriptor_GAUGE_INT64,
 	}
 }
 
-func clientRecentMaxInputBuffer() *redisMetric {
-	return &redisMetric{
-		key:    "client_recent_max_input_buffer",
-		name:   "redis/clients/max_input_buffer",
-		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+func clientRecentMaxInputBuffer() *scraperhelper.MetricDefinition {
+	return &scraperhelper.MetricDefinition{
+		Key:  "client_recent_max_input_buffer",
+		Name: "redis/clients/max_input_buffer",
+		Type: metricspb.MetricDescriptor_GAUGE_INT64,
 	}
 }
 
-func clientRecentMaxOutputBuffer() *redisMetric {
-	return &redisMetric{
-		key:    "client_recent_max_output_buffer",
-		name:   "redis/clients/max_output_buffer",
-		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+func clientRecentMaxOutputBuffer() *scraperhelper.MetricDefinition {
+	return &scraperhelper.MetricDefinition{
+		Key:  "client_recent_max_output_buffer",
+		Name: "redis/clients/max_output_buffer",
+		Type: metricspb.MetricDescriptor_GAUGE_INT64,
 	}
 }
 
-func blockedClients() *redisMetric {
-	return &redisMetric{
-		key:    "blocked_clients",
-		name:   "redis/clients/blocked",
-		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+func blockedClients() *scraperhelper.MetricDefinition {
+	return &scraperhelper.MetricDefinition{
+		Key:  "blocked_clients",
+		Name: "redis/clients/blocked",
+		Type: metricspb.MetricDescriptor_GAUGE_INT64,
 	}
 }
 
-func expiredKeys() *redisMetric {
-	return &redisMetric{
-		key:    "expired_keys",
-		name:   "redis/keys/expired",
-		mdType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+func expiredKeys() *scraperhelper.MetricDefinition {
+	return &scraperhelper.MetricDefinition{
+		Key:  "expired_keys",
+		Name: "redis/keys/expired",
+		Type: metricspb.MetricDescriptor_CUMULATIVE_INT64,
 	}
 }
 
-func evictedKeys() *redisMetric {
-	return &redisMetric{
-		key:    "evicted_keys",
-		name:   "redis/keys/evicted",
-		mdType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+func evictedKeys() *scraperhelper.MetricDefinition {
+	return &scraperhelper.MetricDefinition{
+		Key:  "evicted_keys",
+		Name: "redis/keys/evicted",
+		Type: metricspb.MetricDescriptor_CUMULATIVE_INT64,
 	}
 }
 
-func totalConnectionsReceived() *redisMetric {
-	return &redisMetric{
-		key:    "total_connections_received",
-		name:   "redis/connections/received",
-		mdType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+func totalConnectionsReceived() *scraperhelper.MetricDefinition {
+	return &scraperhelper.MetricDefinition{
+		Key:  "total_connections_received",
+		Name: "redis/connections/received",
+		Type: metricspb.MetricDescriptor_CUMULATIVE_INT64,
 	}
 }
 
-func rejectedConnections() *redisMetric {
-	return &redisMetric{
-		key:    "rejected_connections",
-		name:   "redis/connections/rejected",
-		mdType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+func rejectedConnections() *scraperhelper.MetricDefinition {
+	return &scraperhelper.MetricDefinition{
+		Key:  "rejected_connections",
+		Name: "redis/connections/rejected",
+		Type: metricspb.MetricDescriptor_CUMULATIVE_INT64,
 	}
 }
 
-func usedMemory() *redisMetric {
-	return &redisMetric{
-		key:    "used_memory",
-		name:   "redis/memory/used",
-		units:  "By",
-		desc:   "memory used",
-		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+func usedMemory() *scraperhelper.MetricDefinition {
+	return &scraperhelper.MetricDefinition{
+		Key:         "used_memory",
+		Name:        "redis/memory/used",
+		Unit:        "By",
+		Description: "memory used",
+		Type:        metricspb.MetricDescriptor_GAUGE_INT64,
 	}
 }
 
-func usedMemoryPeak() *redisMetric {
-	return &redisMetric{
-		key:    "used_memory_peak",
-		name:   "redis/memory/peak",
-		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
-		units:  "By",
+func usedMemoryPeak() *scraperhelper.MetricDefinition {
+	return &scraperhelper.MetricDefinition{
+		Key:  "used_memory_peak",
+		Name: "redis/memory/peak",
+		Type: metricspb.MetricDescriptor_GAUGE_INT64,
+		Unit: "By",
 	}
 }
 
-func usedMemoryRss() *redisMetric {
-	return &redisMetric{
-		key:    "used_memory_rss",
-		name:   "redis/memory/rss",
-		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
-		units:  "By",
+func usedMemoryRss() *scraperhelper.MetricDefinition {
+	return &scraperhelper.MetricDefinition{
+		Key:  "used_memory_rss",
+		Name: "redis/memory/rss",
+		Type: metricspb.MetricDescriptor_GAUGE_INT64,
+		Unit: "By",
 	}
 }
 
-func usedMemoryLua() *redisMetric {
-	return &redisMetric{
-		key:    "used_memory_lua",
-		name:   "redis/memory/lua",
-		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
-		units:  "By",
+func usedMemoryLua() *scraperhelper.MetricDefinition {
+	return &scraperhelper.MetricDefinition{
+		Key:  "used_memory_lua",
+		Name: "redis/memory/lua",
+		Type: metricspb.MetricDescriptor_GAUGE_INT64,
+		Unit: "By",
 	}
 }
 
-func memFragmentationRatio() *redisMetric {
-	return &redisMetric{
-		key:    "mem_fragmentation_ratio",
-		name:   "redis/memory/fragmentation_ratio",
-		mdType: metricspb.MetricDescriptor_GAUGE_DOUBLE,
+func memFragmentationRatio() *scraperhelper.MetricDefinition {
+	return &scraperhelper.MetricDefinition{
+		Key:  "mem_fragmentation_ratio",
+		Name: "redis/memory/fragmentation_ratio",
+		Type: metricspb.MetricDescriptor_GAUGE_DOUBLE,
 	}
 }
 
-func rdbChangesSinceLastSave() *redisMetric {
-	return &redisMetric{
-		key:    "rdb_changes_since_last_save",
-		name:   "redis/rdb/changes_since_last_save",
-		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+func rdbChangesSinceLastSave() *scraperhelper.MetricDefinition {
+	return &scraperhelper.MetricDefinition{
+		Key:  "rdb_changes_since_last_save",
+		Name: "redis/rdb/changes_since_last_save",
+		Type: metricspb.MetricDescriptor_GAUGE_INT64,
 	}
 }
 
-func rdbBgsaveInProgress() *redisMetric {
-	return &redisMetric{
-		key:    "rdb_bgsave_in_progress",
-		name:   "redis/rdb/bgsave_in_progress",
-		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+func rdbBgsaveInProgress() *scraperhelper.MetricDefinition {
+	return &scraperhelper.MetricDefinition{
+		Key:  "rdb_bgsave_in_progress",
+		Name: "redis/rdb/bgsave_in_progress",
+		Type: metricspb.MetricDescriptor_GAUGE_INT64,
 	}
 }
 
-func instantaneousOpsPerSec() *redisMetric {
-	return &redisMetric{
-		key:    "instantaneous_ops_per_sec",
-		name:   "redis/commands",
-		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
-		units:  "{ops}/s",
+func instantaneousOpsPerSec() *scraperhelper.MetricDefinition {
+	return &scraperhelper.MetricDefinition{
+		Key:  "instantaneous_ops_per_sec",
+		Name: "redis/commands",
+		Type: metricspb.MetricDescriptor_GAUGE_INT64,
+		Unit: "{ops}/s",
 	}
 }
 
-func totalCommandsProcessed() *redisMetric {
-	return &redisMetric{
-		key:    "total_commands_processed",
-		name:   "redis/commands/processed",
-		mdType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+func totalCommandsProcessed() *scraperhelper.MetricDefinition {
+	return &scraperhelper.MetricDefinition{
+		Key:  "total_commands_processed",
+		Name: "redis/commands/processed",
+		Type: metricspb.MetricDescriptor_CUMULATIVE_INT64,
 	}
 }
 
-func totalNetInputBytes() *redisMetric {
-	return &redisMetric{
-		key:    "total_net_input_bytes",
-		name:   "redis/net/input",
-		mdType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
-		units:  "By",
+func totalNetInputBytes() *scraperhelper.MetricDefinition {
+	return &scraperhelper.MetricDefinition{
+		Key:  "total_net_input_bytes",
+		Name: "redis/net/input",
+		Type: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+		Unit: "By",
 	}
 }
 
-func totalNetOutputBytes() *redisMetric {
-	return &redisMetric{
-		key:    "total_net_output_bytes",
-		name:   "redis/net/output",
-		mdType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
-		units:  "By",
+func totalNetOutputBytes() *scraperhelper.MetricDefinition {
+	return &scraperhelper.MetricDefinition{
+		Key:  "total_net_output_bytes",
+		Name: "redis/net/output",
+		Type: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+		Unit: "By",
 	}
 }
 
-func keyspaceHits() *redisMetric {
-	return &redisMetric{
-		key:    "keyspace_hits",
-		name:   "redis/keyspace/hits",
-		mdType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+func keyspaceHits() *scraperhelper.MetricDefinition {
+	return &scraperhelper.MetricDefinition{
+		Key:  "keyspace_hits",
+		Name: "redis/keyspace/hits",
+		Type: metricspb.MetricDescriptor_CUMULATIVE_INT64,
 	}
 }
 
-func keyspaceMisses() *redisMetric {
-	return &redisMetric{
-		key:    "keyspace_misses",
-		name:   "redis/keyspace/misses",
-		mdType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+func keyspaceMisses() *scraperhelper.MetricDefinition {
+	return &scraperhelper.MetricDefinition{
+		Key:  "keyspace_misses",
+		Name: "redis/keyspace/misses",
+		Type: metricspb.MetricDescriptor_CUMULATIVE_INT64,
 	}
 }
 
-func latestForkUsec() *redisMetric {
-	return &redisMetric{
-		key:    "latest_fork_usec",
-		name:   "redis/latest_fork",
-		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
-		units:  "us",
+func latestForkUsec() *scraperhelper.MetricDefinition {
+	return &scraperhelper.MetricDefinition{
+		Key:  "latest_fork_usec",
+		Name: "redis/latest_fork",
+		Type: metricspb.MetricDescriptor_GAUGE_INT64,
+		Unit: "us",
 	}
 }
 
-func connectedSlaves() *redisMetric {
-	return &redisMetric{
-		key:    "connected_slaves",
-		name:   "redis/slaves/connected",
-		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+func connectedSlaves() *scraperhelper.MetricDefinition {
+	return &scraperhelper.MetricDefinition{
+		Key:  "connected_slaves",
+		Name: "redis/slaves/connected",
+		Type: metricspb.MetricDescriptor_GAUGE_INT64,
 	}
 }
 
-func replBacklogFirstByteOffset() *redisMetric {
-	return &redisMetric{
-		key:    "repl_backlog_first_byte_offset",
-		name:   "redis/replication/backlog_first_byte_offset",
-		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+func replBacklogFirstByteOffset() *scraperhelper.MetricDefinition {
+	return &scraperhelper.MetricDefinition{
+		Key:  "repl_backlog_first_byte_offset",
+		Name: "redis/replication/backlog_first_byte_offset",
+		Type: metricspb.MetricDescriptor_GAUGE_INT64,
 	}
 }
 
-func masterReplOffset() *redisMetric {
-	return &redisMetric{
-		key:    "master_repl_offset",
-		name:   "redis/replication/offset",
-		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+func masterReplOffset() *scraperhelper.MetricDefinition {
+	return &scraperhelper.MetricDefinition{
+		Key:  "master_repl_offset",
+		Name: "redis/replication/offset",
+		Type: metricspb.MetricDescriptor_GAUGE_INT64,
 	}
 }
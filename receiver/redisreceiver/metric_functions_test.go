@@ -24,15 +24,15 @@ import (
 
 func TestDefaultMetrics(t *testing.T) {
 	for _, metric := range getDefaultRedisMetrics() {
-		require.True(t, len(metric.key) > 0)
-		require.True(t, len(metric.name) > 0)
-		require.True(t, strings.HasPrefix(metric.name, "redis/"))
+		require.True(t, len(metric.Key) > 0)
+		require.True(t, len(metric.Name) > 0)
+		require.True(t, strings.HasPrefix(metric.Name, "redis/"))
 		require.True(
 			t,
-			metric.mdType == v1.MetricDescriptor_GAUGE_INT64 ||
-				metric.mdType == v1.MetricDescriptor_GAUGE_DOUBLE ||
-				metric.mdType == v1.MetricDescriptor_CUMULATIVE_INT64 ||
-				metric.mdType == v1.MetricDescriptor_CUMULATIVE_DOUBLE,
+			metric.Type == v1.MetricDescriptor_GAUGE_INT64 ||
+				metric.Type == v1.MetricDescriptor_GAUGE_DOUBLE ||
+				metric.Type == v1.MetricDescriptor_CUMULATIVE_INT64 ||
+				metric.Type == v1.MetricDescriptor_CUMULATIVE_DOUBLE,
 		)
 	}
 }
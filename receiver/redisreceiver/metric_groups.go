@@ -0,0 +1,95 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Groups of metrics, enabled or disabled by name in config.
+const (
+	groupServer       = "server"
+	groupCPU          = "cpu"
+	groupClients      = "clients"
+	groupMemory       = "memory"
+	groupPersistence  = "persistence"
+	groupStats        = "stats"
+	groupReplication  = "replication"
+	groupKeyspace     = "keyspace"
+	groupCommandstats = "commandstats"
+	groupErrorstats   = "errorstats"
+	groupLatency      = "latency"
+	groupSlowlog      = "slowlog"
+)
+
+// Whether groups are enabled unless set in config. Groups requiring other
+// commands than INFO, or reporting a series per command or error, are
+// disabled by default.
+var metricGroupDefaults = map[string]bool{
+	groupServer:       true,
+	groupCPU:          true,
+	groupClients:      true,
+	groupMemory:       true,
+	groupPersistence:  true,
+	groupStats:        true,
+	groupReplication:  true,
+	groupKeyspace:     true,
+	groupCommandstats: false,
+	groupErrorstats:   false,
+	groupLatency:      false,
+	groupSlowlog:      false,
+}
+
+// The enabled groups of metrics by name.
+type metricGroups map[string]bool
+
+func (g metricGroups) enabled(group string) bool {
+	return g[group]
+}
+
+func defaultMetricGroups() metricGroups {
+	groups := metricGroups{}
+	for group, enabled := range metricGroupDefaults {
+		groups[group] = enabled
+	}
+	return groups
+}
+
+// Returns the default groups, enabled or disabled by the passed-in config.
+// Fails on unknown groups.
+func newMetricGroups(cfg map[string]bool) (metricGroups, error) {
+	groups := defaultMetricGroups()
+	for group, enabled := range cfg {
+		if _, ok := metricGroupDefaults[group]; !ok {
+			return nil, fmt.Errorf(
+				"unknown metric group %q, must be one of %s",
+				group, strings.Join(metricGroupNames(), ", "),
+			)
+		}
+		groups[group] = enabled
+	}
+	return groups, nil
+}
+
+func metricGroupNames() []string {
+	names := make([]string, 0, len(metricGroupDefaults))
+	for group := range metricGroupDefaults {
+		names = append(names, group)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,56 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMetricGroups(t *testing.T) {
+	groups, err := newMetricGroups(nil)
+	require.Nil(t, err)
+	require.Equal(t, defaultMetricGroups(), groups)
+	require.True(t, groups.enabled(groupMemory))
+	require.False(t, groups.enabled(groupCommandstats))
+
+	groups, err = newMetricGroups(map[string]bool{groupMemory: false, groupCommandstats: true})
+	require.Nil(t, err)
+	require.False(t, groups.enabled(groupMemory))
+	require.True(t, groups.enabled(groupCommandstats))
+	require.True(t, groups.enabled(groupCPU))
+
+	_, err = newMetricGroups(map[string]bool{"foo": true})
+	require.EqualError(t, err, `unknown metric group "foo", must be one of clients, commandstats, `+
+		`cpu, errorstats, keyspace, latency, memory, persistence, replication, server, slowlog, stats`)
+}
+
+func TestGetRedisMetrics(t *testing.T) {
+	groups := defaultMetricGroups()
+	groups[groupMemory] = false
+	groups[groupCPU] = false
+	metrics := getRedisMetrics(groups)
+	require.Equal(t, len(getDefaultRedisMetrics())-8, len(metrics))
+	for _, m := range metrics {
+		require.NotContains(t, m.Name, "redis/memory/")
+		require.NotContains(t, m.Name, "redis/cpu/")
+	}
+
+	// All groups of INFO metrics are known.
+	for _, g := range infoMetricGroups() {
+		require.Contains(t, metricGroupDefaults, g.name)
+	}
+}
@@ -15,26 +15,39 @@
 package redisreceiver
 
 import (
+	"sort"
+
 	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
 	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
-	"github.com/golang/protobuf/ptypes"
-	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/open-telemetry/opentelemetry-collector/consumer/consumerdata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/scraperhelper"
 )
 
 // Helper functions that produce protobuf
 
-func newMetricsData(protoMetrics []*metricspb.Metric) *consumerdata.MetricsData {
+// Resource labels identifying the scraped node. The replication offset is a
+// metric rather than a label, since it changes with every write.
+const (
+	nodeAddressLabel = "redis.node.address"
+	nodeRoleLabel    = "redis.node.role"
+)
+
+func newMetricsData(protoMetrics []*metricspb.Metric, address string, role string) *consumerdata.MetricsData {
 	return &consumerdata.MetricsData{
 		Resource: &resourcepb.Resource{
-			Type:   typeStr,
-			Labels: map[string]string{"type": typeStr},
+			Type: typeStr,
+			Labels: map[string]string{
+				"type":           typeStr,
+				nodeAddressLabel: address,
+				nodeRoleLabel:    role,
+			},
 		},
 		Metrics: protoMetrics,
 	}
 }
 
-func buildKeyspaceTriplet(k *keyspace, t *timeBundle) []*metricspb.Metric {
+func buildKeyspaceTriplet(k *keyspace, t *scraperhelper.StartTime) []*metricspb.Metric {
 	return []*metricspb.Metric{
 		buildKeyspaceKeysMetric(k, t),
 		buildKeyspaceExpiresMetric(k, t),
@@ -42,88 +55,159 @@ func buildKeyspaceTriplet(k *keyspace, t *timeBundle) []*metricspb.Metric {
 	}
 }
 
-func buildKeyspaceKeysMetric(k *keyspace, t *timeBundle) *metricspb.Metric {
-	m := &redisMetric{
-		name:   "redis/db/keys",
-		labels: map[string]string{"db": k.db},
-		mdType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+func buildKeyspaceKeysMetric(k *keyspace, t *scraperhelper.StartTime) *metricspb.Metric {
+	m := &scraperhelper.MetricDefinition{
+		Name:   "redis/db/keys",
+		Labels: map[string]string{"db": k.db},
+		Type:   metricspb.MetricDescriptor_CUMULATIVE_INT64,
 	}
-	pt := &metricspb.Point{Value: &metricspb.Point_Int64Value{Int64Value: int64(k.keys)}}
-	return newProtoMetric(m, pt, t)
+	pt := scraperhelper.Int64Point(int64(k.keys))
+	return scraperhelper.NewMetric(m, pt, t)
 }
 
-func buildKeyspaceExpiresMetric(k *keyspace, t *timeBundle) *metricspb.Metric {
-	m := &redisMetric{
-		name:   "redis/db/expires",
-		labels: map[string]string{"db": k.db},
-		mdType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+func buildKeyspaceExpiresMetric(k *keyspace, t *scraperhelper.StartTime) *metricspb.Metric {
+	m := &scraperhelper.MetricDefinition{
+		Name:   "redis/db/expires",
+		Labels: map[string]string{"db": k.db},
+		Type:   metricspb.MetricDescriptor_CUMULATIVE_INT64,
 	}
-	pt := &metricspb.Point{Value: &metricspb.Point_Int64Value{Int64Value: int64(k.expires)}}
-	return newProtoMetric(m, pt, t)
+	pt := scraperhelper.Int64Point(int64(k.expires))
+	return scraperhelper.NewMetric(m, pt, t)
 }
 
-func buildKeyspaceTTLMetric(k *keyspace, t *timeBundle) *metricspb.Metric {
-	m := &redisMetric{
-		name:   "redis/db/avg_ttl",
-		units:  "ms",
-		labels: map[string]string{"db": k.db},
-		mdType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+func buildKeyspaceTTLMetric(k *keyspace, t *scraperhelper.StartTime) *metricspb.Metric {
+	m := &scraperhelper.MetricDefinition{
+		Name:   "redis/db/avg_ttl",
+		Unit:   "ms",
+		Labels: map[string]string{"db": k.db},
+		Type:   metricspb.MetricDescriptor_GAUGE_INT64,
 	}
-	pt := &metricspb.Point{Value: &metricspb.Point_Int64Value{Int64Value: int64(k.avgTTL)}}
-	return newProtoMetric(m, pt, t)
+	pt := scraperhelper.Int64Point(int64(k.avgTTL))
+	return scraperhelper.NewMetric(m, pt, t)
 }
 
-// Create new protobuf Metric.
-// Arguments:
-//   * redisMetric -- the fixed metadata to build the protobuf metric
-//   * pt -- the protobuf Point to be wrapped
-//   * currTime -- the timestamp to be put on the Point (not on the timeseries)
-func newProtoMetric(m *redisMetric, pt *metricspb.Point, t *timeBundle) *metricspb.Metric {
-	var startTime *timestamp.Timestamp
-
-	if m.mdType == metricspb.MetricDescriptor_CUMULATIVE_INT64 ||
-		m.mdType == metricspb.MetricDescriptor_CUMULATIVE_DOUBLE {
-		ts, _ := ptypes.TimestampProto(t.serverStart)
-		startTime = ts
-	}
-
-	ts, _ := ptypes.TimestampProto(t.current)
-	pt.Timestamp = ts
-	labelKeys, labelVals := buildLabels(m.labels, m.labelDescriptions)
-	pbMetric := &metricspb.Metric{
-		MetricDescriptor: &metricspb.MetricDescriptor{
-			Name:        m.name,
-			Description: m.desc,
-			Unit:        m.units,
-			Type:        m.mdType,
-			LabelKeys:   labelKeys,
-		},
-		Timeseries: []*metricspb.TimeSeries{{
-			LabelValues:    labelVals,
-			Points:         []*metricspb.Point{pt},
-			StartTimestamp: startTime,
-		}},
+func buildCommandStatsMetrics(cs *commandStats, t *scraperhelper.StartTime) []*metricspb.Metric {
+	labels := map[string]string{"command": cs.command}
+	return []*metricspb.Metric{
+		scraperhelper.NewMetric(&scraperhelper.MetricDefinition{
+			Name:   "redis/commands/calls",
+			Labels: labels,
+			Type:   metricspb.MetricDescriptor_CUMULATIVE_INT64,
+		}, scraperhelper.Int64Point(cs.calls), t),
+		scraperhelper.NewMetric(&scraperhelper.MetricDefinition{
+			Name:   "redis/commands/usec",
+			Unit:   "us",
+			Labels: labels,
+			Type:   metricspb.MetricDescriptor_CUMULATIVE_INT64,
+		}, scraperhelper.Int64Point(cs.usec), t),
+		scraperhelper.NewMetric(&scraperhelper.MetricDefinition{
+			Name:   "redis/commands/usec_per_call",
+			Unit:   "us",
+			Labels: labels,
+			Type:   metricspb.MetricDescriptor_GAUGE_DOUBLE,
+		}, scraperhelper.DoublePoint(cs.usecPerCall), t),
+	}
+}
+
+func buildErrorStatsMetric(es *errorStats, t *scraperhelper.StartTime) *metricspb.Metric {
+	m := &scraperhelper.MetricDefinition{
+		Name:   "redis/errors",
+		Labels: map[string]string{"error": es.prefix},
+		Type:   metricspb.MetricDescriptor_CUMULATIVE_INT64,
+	}
+	pt := scraperhelper.Int64Point(es.count)
+	return scraperhelper.NewMetric(m, pt, t)
+}
+
+func buildLatencyMetrics(e *latencyEvent, t *scraperhelper.StartTime) []*metricspb.Metric {
+	labels := map[string]string{"event": e.event}
+	return []*metricspb.Metric{
+		scraperhelper.NewMetric(&scraperhelper.MetricDefinition{
+			Name:   "redis/latency/latest",
+			Unit:   "ms",
+			Labels: labels,
+			Type:   metricspb.MetricDescriptor_GAUGE_INT64,
+		}, scraperhelper.Int64Point(e.latest), t),
+		scraperhelper.NewMetric(&scraperhelper.MetricDefinition{
+			Name:   "redis/latency/max",
+			Unit:   "ms",
+			Labels: labels,
+			Type:   metricspb.MetricDescriptor_GAUGE_INT64,
+		}, scraperhelper.Int64Point(e.max), t),
+	}
+}
+
+func buildSlowlogLenMetric(length int64, t *scraperhelper.StartTime) *metricspb.Metric {
+	m := &scraperhelper.MetricDefinition{
+		Name: "redis/slowlog/length",
+		Type: metricspb.MetricDescriptor_GAUGE_INT64,
 	}
-	return pbMetric
+	pt := scraperhelper.Int64Point(length)
+	return scraperhelper.NewMetric(m, pt, t)
 }
 
-func buildLabels(
-	labels map[string]string,
-	descriptions map[string]string,
-) ([]*metricspb.LabelKey, []*metricspb.LabelValue) {
-	var keys []*metricspb.LabelKey
-	var values []*metricspb.LabelValue
-	for key, val := range labels {
-		labelKey := &metricspb.LabelKey{Key: key}
-		desc, hasDesc := descriptions[key]
-		if hasDesc {
-			labelKey.Description = desc
+// Builds the metrics of the last complete scan of the keys matching a
+// pattern.
+func buildKeySamplingMetrics(pattern string, s *keyStats, topKeys int, t *scraperhelper.StartTime) []*metricspb.Metric {
+	metrics := []*metricspb.Metric{
+		scraperhelper.NewMetric(&scraperhelper.MetricDefinition{
+			Name:   "redis/keys/count",
+			Labels: map[string]string{"pattern": pattern},
+			Type:   metricspb.MetricDescriptor_GAUGE_INT64,
+		}, scraperhelper.Int64Point(s.keys), t),
+		scraperhelper.NewMetric(&scraperhelper.MetricDefinition{
+			Name:   "redis/keys/sampled",
+			Labels: map[string]string{"pattern": pattern},
+			Type:   metricspb.MetricDescriptor_GAUGE_INT64,
+		}, scraperhelper.Int64Point(int64(len(s.samples))), t),
+	}
+	if len(s.samples) == 0 {
+		return metrics
+	}
+
+	memoryUsages := s.memoryUsages()
+	for _, p := range keyPercentiles {
+		metrics = append(metrics, scraperhelper.NewMetric(&scraperhelper.MetricDefinition{
+			Name:   "redis/keys/memory_usage",
+			Unit:   "By",
+			Labels: map[string]string{"pattern": pattern, "percentile": p.label},
+			Type:   metricspb.MetricDescriptor_GAUGE_INT64,
+		}, scraperhelper.Int64Point(percentile(memoryUsages, p.percentile)), t))
+	}
+
+	lengths := s.lengthsByType()
+	keyTypes := make([]string, 0, len(lengths))
+	for keyType := range lengths {
+		keyTypes = append(keyTypes, keyType)
+	}
+	sort.Strings(keyTypes)
+	for _, keyType := range keyTypes {
+		for _, p := range keyPercentiles {
+			metrics = append(metrics, scraperhelper.NewMetric(&scraperhelper.MetricDefinition{
+				Name:   "redis/keys/length",
+				Labels: map[string]string{"pattern": pattern, "type": keyType, "percentile": p.label},
+				Type:   metricspb.MetricDescriptor_GAUGE_INT64,
+			}, scraperhelper.Int64Point(percentile(lengths[keyType], p.percentile)), t))
 		}
-		keys = append(keys, labelKey)
-		values = append(values, &metricspb.LabelValue{
-			Value:    val,
-			HasValue: true,
-		})
 	}
-	return keys, values
+
+	for _, sample := range s.topKeys(topKeys) {
+		metrics = append(metrics, scraperhelper.NewMetric(&scraperhelper.MetricDefinition{
+			Name:   "redis/keys/top_memory_usage",
+			Unit:   "By",
+			Labels: map[string]string{"pattern": pattern, "key": sample.key, "type": sample.keyType},
+			Type:   metricspb.MetricDescriptor_GAUGE_INT64,
+		}, scraperhelper.Int64Point(sample.memory), t))
+	}
+
+	return metrics
+}
+
+func buildKeySamplingCommandsMetric(commands int, t *scraperhelper.StartTime) *metricspb.Metric {
+	m := &scraperhelper.MetricDefinition{
+		Name: "redis/keys/sampling_commands",
+		Type: metricspb.MetricDescriptor_GAUGE_INT64,
+	}
+	pt := scraperhelper.Int64Point(int64(commands))
+	return scraperhelper.NewMetric(m, pt, t)
 }
@@ -19,10 +19,10 @@ import (
 	"time"
 
 	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
-	"github.com/golang/protobuf/ptypes"
-	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/open-telemetry/opentelemetry-collector/consumer/consumerdata"
 	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/scraperhelper"
 )
 
 func TestMemoryMetric(t *testing.T) {
@@ -52,7 +52,7 @@ func TestUsedCpuSys(t *testing.T) {
 }
 
 func TestMissingMetricValue(t *testing.T) {
-	redisMetrics := []*redisMetric{{key: "config_file"}}
+	redisMetrics := []*scraperhelper.MetricDefinition{{Key: "config_file"}}
 	_, warnings, err := fetchMetrics(redisMetrics)
 	require.Nil(t, err)
 	// treat a missing value as not worthy of a warning
@@ -60,7 +60,7 @@ func TestMissingMetricValue(t *testing.T) {
 }
 
 func TestMissingMetric(t *testing.T) {
-	redisMetrics := []*redisMetric{{key: "foo"}}
+	redisMetrics := []*scraperhelper.MetricDefinition{{Key: "foo"}}
 	_, warnings, err := fetchMetrics(redisMetrics)
 	require.Nil(t, err)
 	require.Equal(t, 1, len(warnings))
@@ -77,7 +77,7 @@ func TestAllMetrics(t *testing.T) {
 func TestKeyspaceMetrics(t *testing.T) {
 	svc := newRedisSvc(newFakeClient())
 	info, _ := svc.info()
-	m, err := info.buildKeyspaceProtoMetrics(getDefaultTimeBundle())
+	m, err := info.buildKeyspaceProtoMetrics(getDefaultStartTime())
 	require.Nil(t, err)
 
 	metric := m[0]
@@ -98,69 +98,47 @@ func TestKeyspaceMetrics(t *testing.T) {
 	require.Equal(t, "redis/db/avg_ttl", metric.MetricDescriptor.Name)
 	require.Equal(t, "db", metric.MetricDescriptor.LabelKeys[0].Key)
 	require.Equal(t, "0", metric.Timeseries[0].LabelValues[0].Value)
-	require.Equal(t, metricspb.MetricDescriptor_CUMULATIVE_INT64, metric.MetricDescriptor.Type)
+	require.Equal(t, metricspb.MetricDescriptor_GAUGE_INT64, metric.MetricDescriptor.Type)
 	require.Equal(t, &metricspb.Point_Int64Value{Int64Value: 3}, metric.Timeseries[0].Points[0].Value)
 }
 
-func TestNewProtoMetric(t *testing.T) {
-	serverStartTime, _ := ptypes.TimestampProto(time.Unix(900, 0))
-	tests := []struct {
-		name     string
-		mdType   metricspb.MetricDescriptor_Type
-		expected *timestamp.Timestamp
-	}{
-		{"cumulative int", metricspb.MetricDescriptor_CUMULATIVE_INT64, serverStartTime},
-		{"cumulative double", metricspb.MetricDescriptor_CUMULATIVE_DOUBLE, serverStartTime},
-		{"gauge int", metricspb.MetricDescriptor_GAUGE_INT64, nil},
-		{"gauge double", metricspb.MetricDescriptor_GAUGE_DOUBLE, nil},
-	}
-	bundle := getDefaultTimeBundle()
-	for _, test := range tests {
-		t.Run(test.name, func(t *testing.T) {
-			m := &redisMetric{mdType: test.mdType}
-			proto := newProtoMetric(m, &metricspb.Point{}, bundle)
-			require.Equal(t, test.expected, proto.Timeseries[0].StartTimestamp)
-		})
-	}
-}
-
-func fetchMetrics(redisMetrics []*redisMetric) ([]*metricspb.Metric, []error, error) {
+func fetchMetrics(redisMetrics []*scraperhelper.MetricDefinition) ([]*metricspb.Metric, []error, error) {
 	svc := newRedisSvc(newFakeClient())
 	info, err := svc.info()
 	if err != nil {
 		return nil, nil, err
 	}
-	protoMetrics, warnings := info.buildFixedProtoMetrics(redisMetrics, getDefaultTimeBundle())
+	protoMetrics, warnings := info.buildFixedProtoMetrics(redisMetrics, getDefaultStartTime())
 	return protoMetrics, warnings, nil
 }
 
-func getProtoMetric(t *testing.T, redisMetric *redisMetric) *metricspb.Metric {
+func getProtoMetric(t *testing.T, redisMetric *scraperhelper.MetricDefinition) *metricspb.Metric {
 	md := getMetricData(t, redisMetric)
 	metric := md.Metrics[0]
 	return metric
 }
 
-func getMetricData(t *testing.T, metric *redisMetric) *consumerdata.MetricsData {
+func getMetricData(t *testing.T, metric *scraperhelper.MetricDefinition) *consumerdata.MetricsData {
 	md, warnings, err := getMetricDataErr(metric)
 	require.Nil(t, err)
 	require.Nil(t, warnings)
 	return md
 }
 
-func getMetricDataErr(metric *redisMetric) (*consumerdata.MetricsData, []error, error) {
-	redisMetrics := []*redisMetric{metric}
+func getMetricDataErr(metric *scraperhelper.MetricDefinition) (*consumerdata.MetricsData, []error, error) {
+	redisMetrics := []*scraperhelper.MetricDefinition{metric}
 	svc := newRedisSvc(newFakeClient())
 	info, err := svc.info()
 	if err != nil {
 		return nil, nil, err
 	}
-	protoMetrics, warnings := info.buildFixedProtoMetrics(redisMetrics, getDefaultTimeBundle())
-	md := newMetricsData(protoMetrics)
+	protoMetrics, warnings := info.buildFixedProtoMetrics(redisMetrics, getDefaultStartTime())
+	md := newMetricsData(protoMetrics, "localhost:6379", info.role())
 	return md, warnings, nil
 }
 
-func getDefaultTimeBundle() *timeBundle {
-	return newTimeBundle(time.Unix(1000, 0), 100)
+func getDefaultStartTime() *scraperhelper.StartTime {
+	return scraperhelper.NewStartTime(time.Unix(1000, 0), 100*time.Second)
 }
 
 func requireIntPtEqual(t *testing.T, i int64, metric *metricspb.Metric) {
@@ -15,54 +15,53 @@
 package redisreceiver
 
 import (
-	"context"
-
-	"github.com/go-redis/redis/v7"
 	"github.com/open-telemetry/opentelemetry-collector/component"
 	"github.com/open-telemetry/opentelemetry-collector/consumer"
 	"go.uber.org/zap"
 
-	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/redisreceiver/interval"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/scraperhelper"
 )
 
-type redisReceiver struct {
-	logger         *zap.Logger
-	config         *config
-	consumer       consumer.MetricsConsumerOld
-	intervalRunner *interval.Runner
-}
-
+// Creates a receiver scraping Redis every collection interval.
 func newRedisReceiver(
 	logger *zap.Logger,
 	config *config,
 	consumer consumer.MetricsConsumerOld,
-) *redisReceiver {
-	return &redisReceiver{
-		logger:   logger,
-		config:   config,
-		consumer: consumer,
+) (component.MetricsReceiver, error) {
+	groups, err := newMetricGroups(config.MetricGroups)
+	if err != nil {
+		return nil, err
+	}
+	if err := config.KeySampling.validate(); err != nil {
+		return nil, err
+	}
+	var keySampling *keySamplingConfig
+	if config.KeySampling.enabled() {
+		keySampling = &config.KeySampling
+	}
+	options, err := newRedisOptions(config, config.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	var newClient clientFactory
+	if config.ClusterMode {
+		newClient = newRedisClientFactory(options)
 	}
-}
-
-// Set up and kick off the interval runner.
-func (r redisReceiver) Start(ctx context.Context, host component.Host) error {
-	client := newRedisClient(&redis.Options{
-		Addr:     r.config.Endpoint,
-		Password: r.config.Password,
-	})
-	redisRunnable := newRedisRunnable(ctx, client, r.consumer, r.logger)
-	r.intervalRunner = interval.NewRunner(r.config.CollectionInterval, redisRunnable)
-
-	go func() {
-		if err := r.intervalRunner.Start(); err != nil {
-			host.ReportFatalError(err)
-		}
-	}()
-
-	return nil
-}
 
-func (r redisReceiver) Shutdown(ctx context.Context) error {
-	r.intervalRunner.Stop()
-	return nil
+	scraper := newRedisScraper(
+		config.Endpoint,
+		newRedisClient(options),
+		newClient,
+		groups,
+		keySampling,
+		logger,
+	)
+	return scraperhelper.NewMetricsReceiver(
+		config,
+		config.ScraperSettings,
+		scraper,
+		consumer,
+		logger,
+		scraperhelper.WithTransport("tcp"),
+	)
 }
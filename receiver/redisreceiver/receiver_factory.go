@@ -45,7 +45,14 @@ func (f Factory) Type() configmodels.Type {
 
 // CreateDefaultConfig creates a default config.
 func (f Factory) CreateDefaultConfig() configmodels.Receiver {
-	return &config{}
+	return &config{
+		KeySampling: keySamplingConfig{
+			ScanCount:              100,
+			MaxCommandsPerInterval: 500,
+			MaxSampledKeys:         1000,
+			TopKeys:                10,
+		},
+	}
 }
 
 // CreateTraceReceiver creates a trace Receiver. Not supported for now.
@@ -65,5 +72,5 @@ func (f Factory) CreateMetricsReceiver(
 	cfg configmodels.Receiver,
 	consumer consumer.MetricsConsumerOld,
 ) (component.MetricsReceiver, error) {
-	return newRedisReceiver(logger, cfg.(*config), consumer), nil
+	return newRedisReceiver(logger, cfg.(*config), consumer)
 }
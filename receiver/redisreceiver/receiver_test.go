@@ -0,0 +1,70 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector/component/componenttest"
+	"github.com/open-telemetry/opentelemetry-collector/exporter/exportertest"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestConfig() *config {
+	cfg := Factory{}.CreateDefaultConfig().(*config)
+	cfg.Endpoint = "localhost:6379"
+	cfg.CollectionInterval = 10 * time.Second
+	return cfg
+}
+
+func TestNewRedisReceiver(t *testing.T) {
+	r, err := newRedisReceiver(zap.NewNop(), newTestConfig(), &exportertest.SinkMetricsExporterOld{})
+	require.Nil(t, err)
+	require.Nil(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	require.Nil(t, r.Shutdown(context.Background()))
+}
+
+func TestNewRedisReceiverErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*config)
+		err    string
+	}{
+		{"collection interval", func(c *config) { c.CollectionInterval = 0 },
+			"collection_interval must be positive, got 0s"},
+		{"metric group", func(c *config) { c.MetricGroups = map[string]bool{"foo": true} },
+			`unknown metric group "foo", must be one of clients, commandstats, cpu, errorstats, ` +
+				`keyspace, latency, memory, persistence, replication, server, slowlog, stats`},
+		{"key sampling", func(c *config) {
+			c.KeySampling.Patterns = []string{"queue:*"}
+			c.KeySampling.ScanCount = 0
+		}, "key_sampling scan_count must be positive, got 0"},
+		{"tls", func(c *config) {
+			c.TLS.Enabled = true
+			c.TLS.CAFile = "testdata/info.txt"
+		}, "no CA certificate found in testdata/info.txt"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := newTestConfig()
+			test.modify(cfg)
+			_, err := newRedisReceiver(zap.NewNop(), cfg, &exportertest.SinkMetricsExporterOld{})
+			require.EqualError(t, err, test.err)
+		})
+	}
+}
@@ -0,0 +1,350 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/open-telemetry/opentelemetry-collector/component/componenterror"
+	"github.com/open-telemetry/opentelemetry-collector/consumer/consumerdata"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/scraperhelper"
+)
+
+var _ scraperhelper.Scraper = (*redisScraper)(nil)
+
+// Scrapes Redis every collection interval, fetching info from Redis and
+// creating metrics/datapoints. In cluster mode, the masters and replicas of
+// the cluster are discovered on every scrape and each of them is scraped.
+type redisScraper struct {
+	endpoint string
+	redisSvc *redisSvc
+	// Creates clients of discovered nodes, nil unless in cluster mode.
+	newClient clientFactory
+	// The enabled groups of metrics.
+	groups metricGroups
+	// Settings of the sampling of keys, nil if disabled.
+	keySampling  *keySamplingConfig
+	redisMetrics []*scraperhelper.MetricDefinition
+	logger       *zap.Logger
+
+	// mu guards nodes, the scraped nodes by address, and the metrics built by
+	// Setup.
+	mu    sync.Mutex
+	nodes map[string]*redisNode
+}
+
+// A scraped Redis node, with its own start time.
+type redisNode struct {
+	address   string
+	redisSvc  *redisSvc
+	startTime *scraperhelper.StartTime
+	// Samples keys of the node if it's a master and key sampling is enabled,
+	// created on the first scrape.
+	keySampler *keySampler
+}
+
+func newRedisScraper(
+	endpoint string,
+	client client,
+	newClient clientFactory,
+	groups metricGroups,
+	keySampling *keySamplingConfig,
+	logger *zap.Logger,
+) *redisScraper {
+	return &redisScraper{
+		endpoint:    endpoint,
+		redisSvc:    newRedisSvc(client),
+		newClient:   newClient,
+		groups:      groups,
+		keySampling: keySampling,
+		logger:      logger,
+		nodes:       map[string]*redisNode{},
+	}
+}
+
+// Builds a data structure of all of the keys, types, converters and such to
+// later extract data from Redis.
+func (r *redisScraper) Setup(context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.redisMetrics = getRedisMetrics(r.groups)
+	if r.newClient == nil {
+		r.nodes[r.endpoint] = &redisNode{address: r.endpoint, redisSvc: r.redisSvc}
+	}
+	return nil
+}
+
+// Scrape is called periodically, querying Redis and building Metrics to send
+// to the next consumer. In cluster mode, nodes are discovered first. Each node
+// is then scraped in turn, the metrics of each node being in their own
+// MetricsData. Nodes failing to be scraped are skipped.
+func (r *redisScraper) Scrape(context.Context) ([]consumerdata.MetricsData, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.newClient != nil {
+		r.discoverNodes()
+	}
+
+	addresses := make([]string, 0, len(r.nodes))
+	for address := range r.nodes {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+
+	var mds []consumerdata.MetricsData
+	var errs []error
+	for _, address := range addresses {
+		md, err := r.scrapeNode(r.nodes[address])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to scrape redis node %s: %v", address, err))
+			continue
+		}
+		mds = append(mds, *md)
+	}
+
+	return mds, componenterror.CombineErrors(errs)
+}
+
+// Discovers the masters and replicas of the cluster the endpoint belongs to
+// with CLUSTER NODES, and the replicas of each master with INFO replication.
+// If the endpoint is not in cluster mode, it is considered the master of a
+// replication group. Nodes no longer found are forgotten. Nodes are kept if
+// the endpoint can't be reached.
+func (r *redisScraper) discoverNodes() {
+	seen := map[string]bool{}
+	var masters []*redisNode
+
+	nodes, err := r.redisSvc.clusterNodes()
+	switch {
+	case err == nil:
+		for _, n := range nodes {
+			node := r.getOrAddNode(n.address)
+			seen[n.address] = true
+			if n.role == roleMaster {
+				masters = append(masters, node)
+			}
+		}
+	case isClusterDisabled(err):
+		masters = append(masters, r.getOrAddNode(r.endpoint))
+		seen[r.endpoint] = true
+	default:
+		r.logger.Warn("failed to discover redis nodes", zap.Error(err))
+		return
+	}
+
+	for _, master := range masters {
+		info, err := master.redisSvc.replicationInfo()
+		if err != nil {
+			r.logger.Warn(
+				"failed to discover redis replicas",
+				zap.String("address", master.address),
+				zap.Error(err),
+			)
+			continue
+		}
+		for _, address := range info.replicaAddresses() {
+			r.getOrAddNode(address)
+			seen[address] = true
+		}
+	}
+
+	for address, node := range r.nodes {
+		if !seen[address] {
+			r.logger.Info("redis node left", zap.String("address", address))
+			if node.redisSvc != r.redisSvc {
+				node.redisSvc.client.close()
+			}
+			delete(r.nodes, address)
+		}
+	}
+}
+
+// Returns the node with the address, connecting to it if it's new. The
+// client of the endpoint is reused for the endpoint node.
+func (r *redisScraper) getOrAddNode(address string) *redisNode {
+	node, ok := r.nodes[address]
+	if !ok {
+		r.logger.Info("redis node discovered", zap.String("address", address))
+		svc := r.redisSvc
+		if address != r.endpoint {
+			svc = newRedisSvc(r.newClient(address))
+		}
+		node = &redisNode{address: address, redisSvc: svc}
+		r.nodes[address] = node
+	}
+	return node
+}
+
+// Nodes not in cluster mode reply to CLUSTER commands with an error.
+func isClusterDisabled(err error) bool {
+	return strings.Contains(err.Error(), "cluster support disabled")
+}
+
+// Scrapes a node. First builds 'fixed' metrics (non-keyspace metrics)
+// defined at startup time. Then builds 'keyspace' metrics if there are any
+// keyspace lines returned by Redis. There should be one keyspace line per
+// active Redis database, of which there can be 16.
+func (r *redisScraper) scrapeNode(node *redisNode) (*consumerdata.MetricsData, error) {
+	info, err := node.redisSvc.info()
+	if err != nil {
+		return nil, err
+	}
+
+	uptime, err := info.getUptimeInSeconds()
+	if err != nil {
+		return nil, err
+	}
+
+	uptimeDuration := time.Duration(uptime) * time.Second
+	if node.startTime == nil {
+		node.startTime = scraperhelper.NewStartTime(time.Now(), uptimeDuration)
+	} else {
+		node.startTime.Update(time.Now(), uptimeDuration)
+	}
+
+	metrics, warnings := info.buildFixedProtoMetrics(r.redisMetrics, node.startTime)
+	if warnings != nil {
+		r.logger.Warn(
+			"errors parsing redis string",
+			zap.Errors("parsing errors", warnings),
+		)
+	}
+
+	if r.groups.enabled(groupKeyspace) {
+		keyspaceMetrics, warnings := info.buildKeyspaceProtoMetrics(node.startTime)
+		metrics = append(metrics, keyspaceMetrics...)
+		if warnings != nil {
+			r.logger.Warn(
+				"errors parsing keyspace string",
+				zap.Errors("parsing errors", warnings),
+			)
+		}
+	}
+
+	metrics = append(metrics, r.buildCommandProtoMetrics(node)...)
+
+	role := info.role()
+	if r.keySampling != nil && role == roleMaster {
+		metrics = append(metrics, r.sampleKeys(node)...)
+	}
+
+	return newMetricsData(metrics, node.address, role), nil
+}
+
+// Builds the metrics of the enabled groups requiring other commands than
+// INFO. Failing commands, e.g. not allowed to the user, are logged and the
+// metrics of the group skipped.
+func (r *redisScraper) buildCommandProtoMetrics(node *redisNode) []*metricspb.Metric {
+	var metrics []*metricspb.Metric
+	logWarnings := func(group string, warnings []error) {
+		if warnings != nil {
+			r.logger.Warn(
+				"errors retrieving redis "+group,
+				zap.String("address", node.address),
+				zap.Errors("errors", warnings),
+			)
+		}
+	}
+
+	for _, section := range []struct {
+		group string
+		build func(info, *scraperhelper.StartTime) ([]*metricspb.Metric, []error)
+	}{
+		{groupCommandstats, info.buildCommandStatsProtoMetrics},
+		{groupErrorstats, info.buildErrorStatsProtoMetrics},
+	} {
+		if !r.groups.enabled(section.group) {
+			continue
+		}
+		sectionInfo, err := node.redisSvc.infoSection(section.group)
+		if err != nil {
+			logWarnings(section.group, []error{err})
+			continue
+		}
+		sectionMetrics, warnings := section.build(sectionInfo, node.startTime)
+		metrics = append(metrics, sectionMetrics...)
+		logWarnings(section.group, warnings)
+	}
+
+	if r.groups.enabled(groupLatency) {
+		events, err := node.redisSvc.latencyLatest()
+		if err != nil {
+			logWarnings(groupLatency, []error{err})
+		}
+		for _, e := range events {
+			metrics = append(metrics, buildLatencyMetrics(e, node.startTime)...)
+		}
+	}
+
+	if r.groups.enabled(groupSlowlog) {
+		length, err := node.redisSvc.slowlogLen()
+		if err != nil {
+			logWarnings(groupSlowlog, []error{err})
+		} else {
+			metrics = append(metrics, buildSlowlogLenMetric(length, node.startTime))
+		}
+	}
+
+	return metrics
+}
+
+// Samples the keys of a master node, replicas having the same keys. Reports
+// the stats of the patterns whose scan completed, now or on a previous run.
+func (r *redisScraper) sampleKeys(node *redisNode) []*metricspb.Metric {
+	if node.keySampler == nil {
+		node.keySampler = newKeySampler(r.keySampling)
+	}
+
+	commands, errs := node.keySampler.sample(node.redisSvc.client)
+	if errs != nil {
+		r.logger.Warn(
+			"errors sampling redis keys",
+			zap.String("address", node.address),
+			zap.Errors("errors", errs),
+		)
+	}
+
+	metrics := []*metricspb.Metric{buildKeySamplingCommandsMetric(commands, node.startTime)}
+	for _, scan := range node.keySampler.scans {
+		if scan.last != nil {
+			metrics = append(metrics, buildKeySamplingMetrics(scan.pattern, scan.last, r.keySampling.TopKeys, node.startTime)...)
+		}
+	}
+	return metrics
+}
+
+// Close closes the connections to all nodes.
+func (r *redisScraper) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for address, node := range r.nodes {
+		if node.redisSvc != r.redisSvc {
+			node.redisSvc.client.close()
+		}
+		delete(r.nodes, address)
+	}
+	return r.redisSvc.client.close()
+}
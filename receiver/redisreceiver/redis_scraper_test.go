@@ -0,0 +1,241 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/open-telemetry/opentelemetry-collector/consumer/consumerdata"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRedisScraper(t *testing.T) {
+	consumer := &fakeMetricsConsumer{}
+	logger, _ := zap.NewDevelopment()
+	scraper := newRedisScraper("localhost:6379", newFakeClient(), nil, defaultMetricGroups(), nil, logger)
+	require.Nil(t, scraper.Setup(context.Background()))
+	consumer.scrape(t, scraper)
+	// + 6 because there are two keyspace entries each of which has three metrics
+	require.Equal(t, len(getDefaultRedisMetrics())+6, len(consumer.md.Metrics))
+	require.Equal(t, map[string]string{
+		"type":               "redis",
+		"redis.node.address": "localhost:6379",
+		"redis.node.role":    "master",
+	}, consumer.md.Resource.Labels)
+}
+
+func TestRedisScraperMetricGroups(t *testing.T) {
+	consumer := &fakeMetricsConsumer{}
+	groups := defaultMetricGroups()
+	groups[groupKeyspace] = false
+	groups[groupCommandstats] = true
+	groups[groupErrorstats] = true
+	groups[groupLatency] = true
+	groups[groupSlowlog] = true
+	scraper := newRedisScraper("localhost:6379", newFakeClient(), nil, groups, nil, zap.NewNop())
+	require.Nil(t, scraper.Setup(context.Background()))
+	consumer.scrape(t, scraper)
+
+	names := map[string]int{}
+	for _, m := range consumer.md.Metrics {
+		names[m.MetricDescriptor.Name]++
+	}
+	require.Equal(t, 3, names["redis/commands/calls"])
+	require.Equal(t, 3, names["redis/commands/usec"])
+	require.Equal(t, 3, names["redis/commands/usec_per_call"])
+	require.Equal(t, 2, names["redis/errors"])
+	require.Equal(t, 2, names["redis/latency/latest"])
+	require.Equal(t, 2, names["redis/latency/max"])
+	require.Equal(t, 1, names["redis/slowlog/length"])
+	require.Equal(t, 0, names["redis/db/keys"])
+	require.Equal(t, len(getDefaultRedisMetrics())+16, len(consumer.md.Metrics))
+}
+
+func TestRedisScraperClusterMode(t *testing.T) {
+	consumer := &fakeMetricsConsumer{}
+	factory := newFakeClientFactory(map[string]*fakeClient{
+		"10.0.0.4:6379": {infoFile: "info_replica"},
+		"10.0.0.5:6379": {infoFile: "info_replica"},
+		"10.0.0.6:6379": {infoFile: "info_replica"},
+	})
+	scraper := newRedisScraper("redis:6379",
+		&fakeClient{clusterNodesFile: "cluster_nodes"}, factory.newClient, defaultMetricGroups(), nil, zap.NewNop())
+	require.Nil(t, scraper.Setup(context.Background()))
+	consumer.scrape(t, scraper)
+
+	// Failed nodes and nodes without address are not scraped.
+	require.Equal(t, map[string]string{
+		"10.0.0.1:6379": "master",
+		"10.0.0.2:6379": "master",
+		"10.0.0.3:6379": "master",
+		"10.0.0.4:6379": "replica",
+		"10.0.0.5:6379": "replica",
+		"10.0.0.6:6379": "replica",
+	}, consumer.roleByAddress())
+	require.Equal(t, 6, len(consumer.mds))
+	for _, md := range consumer.mds {
+		require.Equal(t, len(getDefaultRedisMetrics())+6, len(md.Metrics))
+	}
+
+	// Clients are reused across runs.
+	consumer.mds = nil
+	consumer.scrape(t, scraper)
+	require.Equal(t, 6, len(consumer.mds))
+	require.Equal(t, 6, len(factory.created))
+
+	require.Nil(t, scraper.Close())
+	require.Empty(t, scraper.nodes)
+}
+
+func TestRedisScraperReplicationGroup(t *testing.T) {
+	consumer := &fakeMetricsConsumer{}
+	factory := newFakeClientFactory(map[string]*fakeClient{
+		"10.0.0.2:6379": {infoFile: "info_replica"},
+		"10.0.0.3:6379": {infoFile: "info_replica"},
+	})
+	// The endpoint is not in cluster mode, it is the master of its replicas.
+	scraper := newRedisScraper("redis:6379",
+		&fakeClient{infoFile: "info_master"}, factory.newClient, defaultMetricGroups(), nil, zap.NewNop())
+	require.Nil(t, scraper.Setup(context.Background()))
+	consumer.scrape(t, scraper)
+
+	require.Equal(t, map[string]string{
+		"redis:6379":    "master",
+		"10.0.0.2:6379": "replica",
+		"10.0.0.3:6379": "replica",
+	}, consumer.roleByAddress())
+	// The client of the endpoint is reused.
+	require.Equal(t, []string{"10.0.0.2:6379", "10.0.0.3:6379"}, factory.created)
+}
+
+func TestRedisScraperForgetsNodes(t *testing.T) {
+	consumer := &fakeMetricsConsumer{}
+	factory := newFakeClientFactory(nil)
+	seed := &fakeClient{infoFile: "info_master"}
+	scraper := newRedisScraper("redis:6379", seed, factory.newClient, defaultMetricGroups(), nil, zap.NewNop())
+	require.Nil(t, scraper.Setup(context.Background()))
+	consumer.scrape(t, scraper)
+	require.Equal(t, 3, len(scraper.nodes))
+
+	// Replicas left, the master doesn't list them anymore.
+	seed.infoFile = ""
+	consumer.mds = nil
+	consumer.scrape(t, scraper)
+	require.Equal(t, 1, len(scraper.nodes))
+	require.Equal(t, map[string]string{"redis:6379": "master"}, consumer.roleByAddress())
+}
+
+type fakeMetricsConsumer struct {
+	md  consumerdata.MetricsData
+	mds []consumerdata.MetricsData
+}
+
+// Scrapes and records the scraped metrics, as the receiver sends them.
+func (c *fakeMetricsConsumer) scrape(t *testing.T, s *redisScraper) {
+	mds, err := s.Scrape(context.Background())
+	require.Nil(t, err)
+	for _, md := range mds {
+		c.md = md
+		c.mds = append(c.mds, md)
+	}
+}
+
+// Returns the role of the scraped nodes by address.
+func (c *fakeMetricsConsumer) roleByAddress() map[string]string {
+	out := map[string]string{}
+	for _, md := range c.mds {
+		out[md.Resource.Labels[nodeAddressLabel]] = md.Resource.Labels[nodeRoleLabel]
+	}
+	return out
+}
+
+// Creates fake clients of nodes, replying as a master with the default INFO
+// unless configured.
+type fakeClientFactory struct {
+	clients map[string]*fakeClient
+	created []string
+}
+
+func newFakeClientFactory(clients map[string]*fakeClient) *fakeClientFactory {
+	if clients == nil {
+		clients = map[string]*fakeClient{}
+	}
+	return &fakeClientFactory{clients: clients}
+}
+
+func (f *fakeClientFactory) newClient(address string) client {
+	f.created = append(f.created, address)
+	return &delegatingClient{factory: f, address: address}
+}
+
+// Delegates to the fake client configured for the address when called, so
+// that the replies of nodes can change across runs.
+type delegatingClient struct {
+	factory *fakeClientFactory
+	address string
+}
+
+func (c *delegatingClient) get() *fakeClient {
+	if fc, ok := c.factory.clients[c.address]; ok {
+		return fc
+	}
+	return newFakeClient()
+}
+
+func (c *delegatingClient) retrieveInfo() (string, error) {
+	return c.get().retrieveInfo()
+}
+
+func (c *delegatingClient) retrieveInfoSection(section string) (string, error) {
+	return c.get().retrieveInfoSection(section)
+}
+
+func (c *delegatingClient) retrieveLatencyLatest() ([]interface{}, error) {
+	return c.get().retrieveLatencyLatest()
+}
+
+func (c *delegatingClient) retrieveSlowlogLen() (int64, error) {
+	return c.get().retrieveSlowlogLen()
+}
+
+func (c *delegatingClient) retrieveClusterNodes() (string, error) {
+	return c.get().retrieveClusterNodes()
+}
+
+func (c *delegatingClient) scanKeys(cursor uint64, match string, count int64) ([]string, uint64, error) {
+	return c.get().scanKeys(cursor, match, count)
+}
+
+func (c *delegatingClient) retrieveKeyType(key string) (string, error) {
+	return c.get().retrieveKeyType(key)
+}
+
+func (c *delegatingClient) retrieveMemoryUsage(key string) (int64, error) {
+	return c.get().retrieveMemoryUsage(key)
+}
+
+func (c *delegatingClient) retrieveKeyLength(key string, keyType string) (int64, error) {
+	return c.get().retrieveKeyLength(key, keyType)
+}
+
+func (c *delegatingClient) delimiter() string {
+	return "\n"
+}
+
+func (c *delegatingClient) close() error {
+	return nil
+}
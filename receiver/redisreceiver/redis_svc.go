@@ -39,6 +39,51 @@ func (p redisSvc) info() (info, error) {
 	if err != nil {
 		return nil, err
 	}
+	return p.parseInfo(str), nil
+}
+
+// Calls the Redis INFO replication command on the client and returns an
+// `info` map of the replication section.
+func (p redisSvc) replicationInfo() (info, error) {
+	return p.infoSection("replication")
+}
+
+// Calls the Redis INFO command with a section on the client and returns an
+// `info` map of the section.
+func (p redisSvc) infoSection(section string) (info, error) {
+	str, err := p.client.retrieveInfoSection(section)
+	if err != nil {
+		return nil, err
+	}
+	return p.parseInfo(str), nil
+}
+
+// Calls the Redis LATENCY LATEST command on the client and returns the
+// latest latency spike of each event.
+func (p redisSvc) latencyLatest() ([]*latencyEvent, error) {
+	reply, err := p.client.retrieveLatencyLatest()
+	if err != nil {
+		return nil, err
+	}
+	return parseLatencyLatest(reply)
+}
+
+// Calls the Redis SLOWLOG LEN command on the client.
+func (p redisSvc) slowlogLen() (int64, error) {
+	return p.client.retrieveSlowlogLen()
+}
+
+// Calls the Redis CLUSTER NODES command on the client and returns the nodes
+// of the cluster.
+func (p redisSvc) clusterNodes() ([]clusterNode, error) {
+	str, err := p.client.retrieveClusterNodes()
+	if err != nil {
+		return nil, err
+	}
+	return parseClusterNodes(str)
+}
+
+func (p redisSvc) parseInfo(str string) info {
 	lines := strings.Split(str, p.delimiter)
 	attrs := make(map[string]string)
 	for _, line := range lines {
@@ -50,5 +95,5 @@ func (p redisSvc) info() (info, error) {
 			attrs[pair[0]] = pair[1]
 		}
 	}
-	return attrs, nil
+	return attrs
 }
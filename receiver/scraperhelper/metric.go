@@ -0,0 +1,167 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scraperhelper
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+)
+
+// MetricDefinition declares a metric built from a value scraped as a string,
+// e.g. a field of the INFO of Redis or of a status page, and how to convert it.
+type MetricDefinition struct {
+	// Key of the value in the scraped values.
+	Key               string
+	Name              string
+	Unit              string
+	Description       string
+	Labels            map[string]string
+	LabelDescriptions map[string]string
+	Type              metricspb.MetricDescriptor_Type
+}
+
+// BuildMetrics builds metrics from scraped values by key, e.g. the fields of
+// a status page, and the definitions of metrics to extract. Returns a list of
+// parsing errors, which can be treated like warnings. Values missing are
+// reported as errors, empty values are skipped.
+func BuildMetrics(values map[string]string, defs []*MetricDefinition, t *StartTime) (
+	metrics []*metricspb.Metric,
+	warnings []error,
+) {
+	for _, def := range defs {
+		strVal, ok := values[def.Key]
+		if !ok {
+			warnings = append(warnings, fmt.Errorf("key not found: %v", def.Key))
+			continue
+		}
+		if strVal == "" {
+			continue
+		}
+		metric, parsingError := def.Parse(strVal, t)
+		if parsingError != nil {
+			warnings = append(warnings, parsingError)
+			continue
+		}
+		metrics = append(metrics, metric)
+	}
+	return metrics, warnings
+}
+
+// Parse parses a numeric string to build a proto Metric based on this
+// definition. The current time of t is applied to the Point.
+func (d *MetricDefinition) Parse(strVal string, t *StartTime) (*metricspb.Metric, error) {
+	pt, err := d.ParsePoint(strVal)
+	if err != nil {
+		return nil, err
+	}
+	return NewMetric(d, pt, t), nil
+}
+
+// ParsePoint parses a numeric string to build a Point of the type of the
+// metric.
+func (d *MetricDefinition) ParsePoint(strVal string) (*metricspb.Point, error) {
+	switch d.Type {
+	case metricspb.MetricDescriptor_CUMULATIVE_INT64, metricspb.MetricDescriptor_GAUGE_INT64:
+		i, err := strconv.ParseInt(strVal, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return Int64Point(i), nil
+	case metricspb.MetricDescriptor_CUMULATIVE_DOUBLE, metricspb.MetricDescriptor_GAUGE_DOUBLE:
+		f, err := strconv.ParseFloat(strVal, 64)
+		if err != nil {
+			return nil, err
+		}
+		return DoublePoint(f), nil
+	}
+	return nil, fmt.Errorf("unexpected point type %v", d.Type)
+}
+
+// Int64Point returns a Point of an int64 value, its timestamp set by NewMetric.
+func Int64Point(i int64) *metricspb.Point {
+	return &metricspb.Point{Value: &metricspb.Point_Int64Value{Int64Value: i}}
+}
+
+// DoublePoint returns a Point of a float64 value, its timestamp set by NewMetric.
+func DoublePoint(f float64) *metricspb.Point {
+	return &metricspb.Point{Value: &metricspb.Point_DoubleValue{DoubleValue: f}}
+}
+
+// NewMetric creates a proto Metric of a single time series.
+// Arguments:
+//   * d -- the definition of the metric
+//   * pt -- the protobuf Point to be wrapped
+//   * t -- the current time is put on the Point, the server start time is the
+//     start timestamp of cumulative metrics
+func NewMetric(d *MetricDefinition, pt *metricspb.Point, t *StartTime) *metricspb.Metric {
+	var startTime *timestamp.Timestamp
+
+	if d.Type == metricspb.MetricDescriptor_CUMULATIVE_INT64 ||
+		d.Type == metricspb.MetricDescriptor_CUMULATIVE_DOUBLE {
+		ts, _ := ptypes.TimestampProto(t.ServerStart())
+		startTime = ts
+	}
+
+	ts, _ := ptypes.TimestampProto(t.Current())
+	pt.Timestamp = ts
+	labelKeys, labelVals := buildLabels(d.Labels, d.LabelDescriptions)
+	return &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{
+			Name:        d.Name,
+			Description: d.Description,
+			Unit:        d.Unit,
+			Type:        d.Type,
+			LabelKeys:   labelKeys,
+		},
+		Timeseries: []*metricspb.TimeSeries{{
+			LabelValues:    labelVals,
+			Points:         []*metricspb.Point{pt},
+			StartTimestamp: startTime,
+		}},
+	}
+}
+
+// Returns the keys and values of labels, sorted by key.
+func buildLabels(
+	labels map[string]string,
+	descriptions map[string]string,
+) ([]*metricspb.LabelKey, []*metricspb.LabelValue) {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var keys []*metricspb.LabelKey
+	var values []*metricspb.LabelValue
+	for _, name := range names {
+		labelKey := &metricspb.LabelKey{Key: name}
+		desc, hasDesc := descriptions[name]
+		if hasDesc {
+			labelKey.Description = desc
+		}
+		keys = append(keys, labelKey)
+		values = append(values, &metricspb.LabelValue{
+			Value:    labels[name],
+			HasValue: true,
+		})
+	}
+	return keys, values
+}
@@ -0,0 +1,134 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scraperhelper
+
+import (
+	"testing"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/stretchr/testify/require"
+)
+
+func uptimeDefinition() *MetricDefinition {
+	return &MetricDefinition{
+		Key:  "uptime",
+		Name: "test/uptime",
+		Unit: "s",
+		Type: metricspb.MetricDescriptor_GAUGE_INT64,
+	}
+}
+
+func cpuDefinition() *MetricDefinition {
+	return &MetricDefinition{
+		Key:    "cpu_sys",
+		Name:   "test/cpu/time",
+		Unit:   "s",
+		Type:   metricspb.MetricDescriptor_CUMULATIVE_DOUBLE,
+		Labels: map[string]string{"state": "sys"},
+	}
+}
+
+func TestBuildSingleProtoMetric_PointTimestamp(t *testing.T) {
+	now := time.Now()
+	metric, err := uptimeDefinition().Parse("42", NewStartTime(now, 100*time.Second))
+	require.Nil(t, err)
+	ts := metric.Timeseries[0]
+	pt := ts.Points[0]
+	require.Equal(t, now.Unix(), pt.Timestamp.GetSeconds())
+	nanosecond := now.Nanosecond()
+	require.Equal(t, int32(nanosecond), pt.Timestamp.GetNanos())
+	require.Equal(t, &metricspb.Point_Int64Value{Int64Value: 42}, pt.Value)
+}
+
+func TestBuildSingleProtoMetric_Labels(t *testing.T) {
+	metric, err := cpuDefinition().Parse("42", NewStartTime(time.Now(), 100*time.Second))
+	require.Nil(t, err)
+	require.Equal(t, 1, len(metric.MetricDescriptor.LabelKeys))
+	key := metric.MetricDescriptor.LabelKeys[0].Key
+	require.Equal(t, "state", key)
+	require.Equal(t, 1, len(metric.Timeseries[0].LabelValues))
+	require.Equal(t, "sys", metric.Timeseries[0].LabelValues[0].Value)
+	require.Equal(t, &metricspb.Point_DoubleValue{DoubleValue: 42}, metric.Timeseries[0].Points[0].Value)
+}
+
+func TestLabelsSorted(t *testing.T) {
+	d := &MetricDefinition{
+		Type:              metricspb.MetricDescriptor_GAUGE_INT64,
+		Labels:            map[string]string{"b": "2", "a": "1", "c": "3"},
+		LabelDescriptions: map[string]string{"a": "first"},
+	}
+	metric := NewMetric(d, Int64Point(1), NewStartTime(time.Now(), 0))
+	require.Equal(t, []*metricspb.LabelKey{{Key: "a", Description: "first"}, {Key: "b"}, {Key: "c"}},
+		metric.MetricDescriptor.LabelKeys)
+	require.Equal(t, []*metricspb.LabelValue{
+		{Value: "1", HasValue: true},
+		{Value: "2", HasValue: true},
+		{Value: "3", HasValue: true},
+	}, metric.Timeseries[0].LabelValues)
+}
+
+func TestBuildMetrics(t *testing.T) {
+	values := map[string]string{
+		"uptime":  "104946",
+		"cpu_sys": "185.649184",
+		"empty":   "",
+		"bad":     "x",
+	}
+	defs := []*MetricDefinition{
+		uptimeDefinition(),
+		cpuDefinition(),
+		{Key: "empty", Type: metricspb.MetricDescriptor_GAUGE_INT64},
+		{Key: "bad", Type: metricspb.MetricDescriptor_GAUGE_INT64},
+		{Key: "missing", Type: metricspb.MetricDescriptor_GAUGE_INT64},
+	}
+	metrics, warnings := BuildMetrics(values, defs, NewStartTime(time.Now(), 0))
+	require.Equal(t, 2, len(metrics))
+	require.Equal(t, "test/uptime", metrics[0].MetricDescriptor.Name)
+	require.Equal(t, "test/cpu/time", metrics[1].MetricDescriptor.Name)
+	// An empty value is not worthy of a warning.
+	require.Equal(t, 2, len(warnings))
+	require.EqualError(t, warnings[1], "key not found: missing")
+}
+
+func TestParsePointUnexpectedType(t *testing.T) {
+	d := &MetricDefinition{Type: metricspb.MetricDescriptor_SUMMARY}
+	_, err := d.ParsePoint("1")
+	require.NotNil(t, err)
+}
+
+func TestNewMetricStartTimestamp(t *testing.T) {
+	serverStartTime, _ := ptypes.TimestampProto(time.Unix(900, 0))
+	tests := []struct {
+		name     string
+		mdType   metricspb.MetricDescriptor_Type
+		expected *timestamp.Timestamp
+	}{
+		{"cumulative int", metricspb.MetricDescriptor_CUMULATIVE_INT64, serverStartTime},
+		{"cumulative double", metricspb.MetricDescriptor_CUMULATIVE_DOUBLE, serverStartTime},
+		{"gauge int", metricspb.MetricDescriptor_GAUGE_INT64, nil},
+		{"gauge double", metricspb.MetricDescriptor_GAUGE_DOUBLE, nil},
+	}
+	startTime := NewStartTime(time.Unix(1000, 0), 100*time.Second)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d := &MetricDefinition{Type: test.mdType}
+			metric := NewMetric(d, &metricspb.Point{}, startTime)
+			require.Equal(t, test.expected, metric.Timeseries[0].StartTimestamp)
+		})
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scraperhelper
+
+import (
+	"context"
+
+	"github.com/open-telemetry/opentelemetry-collector/component"
+	"github.com/open-telemetry/opentelemetry-collector/component/componenterror"
+	"github.com/open-telemetry/opentelemetry-collector/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-collector/consumer"
+	"github.com/open-telemetry/opentelemetry-collector/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-collector/obsreport"
+	"go.uber.org/zap"
+)
+
+// Scraper scrapes the metrics of a receiver created by NewMetricsReceiver.
+type Scraper interface {
+	// Setup is called once when the receiver starts, before the first scrape.
+	Setup(ctx context.Context) error
+	// Scrape is called every collection interval. Returns the metrics
+	// scraped, and an error if scraping failed, entirely or in part, e.g. for
+	// one of several servers. Metrics returned along with an error are still
+	// sent.
+	Scrape(ctx context.Context) ([]consumerdata.MetricsData, error)
+	// Close is called once when the receiver shuts down.
+	Close() error
+}
+
+// ReceiverOption customizes receivers created by NewMetricsReceiver.
+type ReceiverOption func(*metricsReceiver)
+
+// WithTransport sets the transport used to scrape metrics, reported by the
+// observability metrics of the receiver, e.g. "tcp" or "http".
+func WithTransport(transport string) ReceiverOption {
+	return func(r *metricsReceiver) {
+		r.transport = transport
+	}
+}
+
+// WithFormat sets the format of the scraped data reported by the
+// observability metrics of the receiver, the type of the receiver by default.
+func WithFormat(format string) ReceiverOption {
+	return func(r *metricsReceiver) {
+		r.format = format
+	}
+}
+
+type metricsReceiver struct {
+	name      string
+	transport string
+	format    string
+	settings  ScraperSettings
+	scraper   Scraper
+	consumer  consumer.MetricsConsumerOld
+	logger    *zap.Logger
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	runner *Runner
+	// done is closed once the runner returned, after which the scraper is
+	// neither set up nor scraped anymore.
+	done chan struct{}
+}
+
+var _ component.MetricsReceiver = (*metricsReceiver)(nil)
+
+// NewMetricsReceiver creates a receiver calling the scraper every collection
+// interval and sending the scraped metrics to the consumer. Each scrape is
+// reported by the observability metrics of the receiver, scraping errors are
+// logged.
+func NewMetricsReceiver(
+	cfg configmodels.Receiver,
+	settings ScraperSettings,
+	scraper Scraper,
+	consumer consumer.MetricsConsumerOld,
+	logger *zap.Logger,
+	options ...ReceiverOption,
+) (component.MetricsReceiver, error) {
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+	r := &metricsReceiver{
+		name:     cfg.Name(),
+		format:   string(cfg.Type()),
+		settings: settings,
+		scraper:  scraper,
+		consumer: consumer,
+		logger:   logger,
+	}
+	for _, option := range options {
+		option(r)
+	}
+	return r, nil
+}
+
+// Start sets up the scraper and starts scraping every collection interval.
+// The first scrape happens after an interval.
+func (r *metricsReceiver) Start(_ context.Context, host component.Host) error {
+	r.ctx, r.cancel = context.WithCancel(context.Background())
+	r.ctx = obsreport.ReceiverContext(r.ctx, r.name, r.transport, r.name)
+	r.runner = NewRunner(r.settings.CollectionInterval, r)
+	r.done = make(chan struct{})
+
+	go func() {
+		defer close(r.done)
+		if err := r.runner.Start(); err != nil {
+			host.ReportFatalError(err)
+		}
+	}()
+
+	return nil
+}
+
+// Shutdown stops scraping and closes the scraper, once the scraper is set up
+// and the last scrape, if any, is done.
+func (r *metricsReceiver) Shutdown(context.Context) error {
+	r.runner.Stop()
+	<-r.done
+	r.cancel()
+	return r.scraper.Close()
+}
+
+// Setup implements Runnable.
+func (r *metricsReceiver) Setup() error {
+	return r.scraper.Setup(r.ctx)
+}
+
+// Run implements Runnable, scraping metrics and sending them to the consumer.
+// Errors are logged and reported, they don't stop the receiver.
+func (r *metricsReceiver) Run() error {
+	ctx := obsreport.StartMetricsReceiveOp(r.ctx, r.name, r.transport, obsreport.WithLongLivedCtx())
+
+	var errs []error
+	mds, err := r.scraper.Scrape(ctx)
+	if err != nil {
+		r.logger.Warn("failed to scrape metrics", zap.Error(err))
+		errs = append(errs, err)
+	}
+
+	var numTimeSeries, numPoints int
+	for _, md := range mds {
+		ts, pts := obsreport.CountMetricPoints(md)
+		numTimeSeries += ts
+		numPoints += pts
+		if err := r.consumer.ConsumeMetricsData(ctx, md); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	obsreport.EndMetricsReceiveOp(ctx, r.format, numPoints, numTimeSeries, componenterror.CombineErrors(errs))
+	return nil
+}
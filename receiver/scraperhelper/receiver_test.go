@@ -0,0 +1,175 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scraperhelper
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/open-telemetry/opentelemetry-collector/component"
+	"github.com/open-telemetry/opentelemetry-collector/component/componenttest"
+	"github.com/open-telemetry/opentelemetry-collector/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-collector/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-collector/exporter/exportertest"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeScraper struct {
+	sync.Mutex
+	setupErr   error
+	setupDelay time.Duration
+	setup      bool
+	scrapes    int
+	closed     bool
+	// misordered is set if scraped before Setup or after Close, or closed
+	// before Setup.
+	misordered bool
+}
+
+func (s *fakeScraper) Setup(context.Context) error {
+	time.Sleep(s.setupDelay)
+	s.Lock()
+	defer s.Unlock()
+	s.setup = true
+	return s.setupErr
+}
+
+// Scrapes a metric and a failing server every other scrape.
+func (s *fakeScraper) Scrape(context.Context) ([]consumerdata.MetricsData, error) {
+	s.Lock()
+	defer s.Unlock()
+	if !s.setup || s.closed {
+		s.misordered = true
+	}
+	s.scrapes++
+	md := consumerdata.MetricsData{Metrics: []*metricspb.Metric{
+		NewMetric(uptimeDefinition(), Int64Point(int64(s.scrapes)), NewStartTime(time.Now(), 0)),
+	}}
+	if s.scrapes%2 == 0 {
+		return []consumerdata.MetricsData{md}, errors.New("server unavailable")
+	}
+	return []consumerdata.MetricsData{md}, nil
+}
+
+func (s *fakeScraper) Close() error {
+	s.Lock()
+	defer s.Unlock()
+	if !s.setup {
+		s.misordered = true
+	}
+	s.closed = true
+	return nil
+}
+
+func newTestConfig() configmodels.Receiver {
+	return &configmodels.ReceiverSettings{TypeVal: "test", NameVal: "test/1"}
+}
+
+func TestMetricsReceiver(t *testing.T) {
+	scraper := &fakeScraper{}
+	sink := &exportertest.SinkMetricsExporterOld{}
+	r, err := NewMetricsReceiver(
+		newTestConfig(),
+		ScraperSettings{CollectionInterval: time.Millisecond},
+		scraper,
+		sink,
+		zap.NewNop(),
+		WithTransport("tcp"),
+		WithFormat("test"),
+	)
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+
+	// Metrics scraped along with an error are still sent.
+	require.Eventually(t, func() bool {
+		return len(sink.AllMetrics()) >= 3
+	}, 10*time.Second, time.Millisecond)
+
+	require.NoError(t, r.Shutdown(context.Background()))
+	scraper.Lock()
+	defer scraper.Unlock()
+	require.True(t, scraper.closed)
+	require.False(t, scraper.misordered)
+}
+
+func TestMetricsReceiverShutdownAfterStart(t *testing.T) {
+	for _, setupDelay := range []time.Duration{0, 10 * time.Millisecond} {
+		scraper := &fakeScraper{setupDelay: setupDelay}
+		sink := &exportertest.SinkMetricsExporterOld{}
+		r, err := NewMetricsReceiver(
+			newTestConfig(),
+			ScraperSettings{CollectionInterval: time.Microsecond},
+			scraper,
+			sink,
+			zap.NewNop(),
+		)
+		require.NoError(t, err)
+		require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+		require.NoError(t, r.Shutdown(context.Background()))
+
+		// Nothing is scraped or sent once shut down.
+		numMetrics := len(sink.AllMetrics())
+		time.Sleep(10 * time.Millisecond)
+		require.Equal(t, numMetrics, len(sink.AllMetrics()))
+
+		scraper.Lock()
+		require.True(t, scraper.closed)
+		require.False(t, scraper.misordered)
+		scraper.Unlock()
+	}
+}
+
+func TestMetricsReceiverSetupError(t *testing.T) {
+	scraper := &fakeScraper{setupErr: errors.New("setup failed")}
+	r, err := NewMetricsReceiver(
+		newTestConfig(),
+		ScraperSettings{CollectionInterval: time.Millisecond},
+		scraper,
+		&exportertest.SinkMetricsExporterOld{},
+		zap.NewNop(),
+	)
+	require.NoError(t, err)
+
+	errs := make(chan error, 1)
+	host := &reportingHost{Host: componenttest.NewNopHost(), errs: errs}
+	require.NoError(t, r.Start(context.Background(), host))
+	require.EqualError(t, <-errs, "setup failed")
+	require.NoError(t, r.Shutdown(context.Background()))
+}
+
+func TestNewMetricsReceiverInvalidSettings(t *testing.T) {
+	_, err := NewMetricsReceiver(
+		newTestConfig(),
+		ScraperSettings{},
+		&fakeScraper{},
+		&exportertest.SinkMetricsExporterOld{},
+		zap.NewNop(),
+	)
+	require.EqualError(t, err, "collection_interval must be positive, got 0s")
+}
+
+type reportingHost struct {
+	component.Host
+	errs chan error
+}
+
+func (h *reportingHost) ReportFatalError(err error) {
+	h.errs <- err
+}
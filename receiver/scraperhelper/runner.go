@@ -12,9 +12,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package interval is a work in progress. Later versions may support parallel
-// execution.
-package interval
+package scraperhelper
 
 import "time"
 
@@ -24,6 +22,7 @@ import "time"
 type Runner struct {
 	runnables []Runnable
 	ticker    *time.Ticker
+	done      chan struct{}
 }
 
 // NewRunner creates a new interval runner. Pass in a duration (time between
@@ -32,6 +31,7 @@ func NewRunner(interval time.Duration, runnables ...Runnable) *Runner {
 	return &Runner{
 		runnables: runnables,
 		ticker:    time.NewTicker(interval),
+		done:      make(chan struct{}),
 	}
 }
 
@@ -45,7 +45,7 @@ type Runnable interface {
 }
 
 // Start kicks off this Runner. Calls Setup() and Run() on the passed-in
-// Runnables.
+// Runnables. Returns once stopped, or on the first error.
 func (r *Runner) Start() error {
 	err := r.setup()
 	if err != nil {
@@ -69,18 +69,30 @@ func (r *Runner) setup() error {
 }
 
 func (r *Runner) run() error {
-	for range r.ticker.C {
-		for _, runnable := range r.runnables {
-			err := runnable.Run()
-			if err != nil {
-				return err
+	for {
+		select {
+		case <-r.ticker.C:
+			// A tick may be pending when stopped, select picks either.
+			select {
+			case <-r.done:
+				return nil
+			default:
 			}
+			for _, runnable := range r.runnables {
+				err := runnable.Run()
+				if err != nil {
+					return err
+				}
+			}
+		case <-r.done:
+			return nil
 		}
 	}
-	return nil
 }
 
-// Stop turns off this Runner's ticker.
+// Stop turns off this Runner's ticker and returns from Start(). Must be
+// called once.
 func (r *Runner) Stop() {
 	r.ticker.Stop()
+	close(r.done)
 }
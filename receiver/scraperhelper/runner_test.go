@@ -0,0 +1,75 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scraperhelper
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler(t *testing.T) {
+	f := &fakeRunnable{}
+	s := NewRunner(time.Second, f)
+	go func() {
+		_ = s.Start()
+	}()
+	s.Stop()
+	// getting here is success
+}
+
+func TestRunnerRunsUntilStopped(t *testing.T) {
+	f := &fakeRunnable{}
+	s := NewRunner(time.Millisecond, f)
+	done := make(chan error)
+	go func() {
+		done <- s.Start()
+	}()
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&f.runs) >= 2
+	}, 10*time.Second, time.Millisecond)
+	require.Equal(t, int32(1), atomic.LoadInt32(&f.setups))
+
+	s.Stop()
+	require.Nil(t, <-done)
+}
+
+func TestRunnerErrors(t *testing.T) {
+	s := NewRunner(time.Millisecond, &fakeRunnable{setupErr: errors.New("setup")})
+	require.EqualError(t, s.Start(), "setup")
+
+	s = NewRunner(time.Millisecond, &fakeRunnable{runErr: errors.New("run")})
+	require.EqualError(t, s.Start(), "run")
+}
+
+type fakeRunnable struct {
+	setups   int32
+	runs     int32
+	setupErr error
+	runErr   error
+}
+
+func (f *fakeRunnable) Setup() error {
+	atomic.AddInt32(&f.setups, 1)
+	return f.setupErr
+}
+
+func (f *fakeRunnable) Run() error {
+	atomic.AddInt32(&f.runs, 1)
+	return f.runErr
+}
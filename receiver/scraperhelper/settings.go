@@ -0,0 +1,41 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scraperhelper
+
+import (
+	"fmt"
+	"time"
+)
+
+// ScraperSettings are the settings of receivers scraping metrics on an
+// interval. Squash them into the config of the receiver:
+//
+//     type Config struct {
+//         configmodels.ReceiverSettings `mapstructure:",squash"`
+//         scraperhelper.ScraperSettings `mapstructure:",squash"`
+//         ...
+//     }
+type ScraperSettings struct {
+	// The duration between scrapes.
+	CollectionInterval time.Duration `mapstructure:"collection_interval"`
+}
+
+// Validate checks the settings are valid.
+func (s *ScraperSettings) Validate() error {
+	if s.CollectionInterval <= 0 {
+		return fmt.Errorf("collection_interval must be positive, got %v", s.CollectionInterval)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scraperhelper
+
+import "time"
+
+// StartTime provides the start time of a scraped server, the start timestamp
+// of cumulative metrics, and the current time for all metrics. The server start
+// time is calculated by subtracting the uptime reported by the server from the
+// current time. This is done during construction or if a server restart is
+// detected. Server restarts are detected by testing for a lower uptime value
+// than the most recent uptime. Provides the current (server) time by adding
+// the uptime value to the server start time.
+type StartTime struct {
+	serverStart time.Time
+	current     time.Time
+	lastUptime  time.Duration
+}
+
+// NewStartTime creates a StartTime from the uptime of the server at the time
+// of the first scrape.
+func NewStartTime(now time.Time, uptime time.Duration) *StartTime {
+	return &StartTime{
+		serverStart: now.Add(-uptime),
+		current:     now,
+		lastUptime:  uptime,
+	}
+}
+
+// Update calculates the current time by adding the server uptime to the server
+// start time. If a server restart is detected, updates the server start time.
+// Call on each scrape.
+func (t *StartTime) Update(now time.Time, uptime time.Duration) {
+	if uptime < t.lastUptime {
+		// assume a server restart
+		t.serverStart = now.Add(-uptime)
+	}
+	t.lastUptime = uptime
+	t.current = t.serverStart.Add(uptime)
+}
+
+// ServerStart returns the start time of the server.
+func (t *StartTime) ServerStart() time.Time {
+	return t.serverStart
+}
+
+// Current returns the time of the last scrape.
+func (t *StartTime) Current() time.Time {
+	return t.current
+}
@@ -18,13 +18,10 @@ import (
 	"context"
 
 	"github.com/open-telemetry/opentelemetry-collector/component"
-	"github.com/open-telemetry/opentelemetry-collector/config/configerror"
 	"github.com/open-telemetry/opentelemetry-collector/config/configmodels"
 	"github.com/open-telemetry/opentelemetry-collector/consumer"
 	"go.uber.org/zap"
 
-	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver"
-	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/protocol"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/transport"
 )
 
@@ -72,7 +69,13 @@ func (f *Factory) CreateTraceReceiver(
 	consumer consumer.TraceConsumerOld,
 ) (component.TraceReceiver, error) {
 
-	return nil, configerror.ErrDataTypeIsNotSupported
+	r, err := f.createReceiver(logger, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	r.nextConsumers.traceConsumer = consumer
+	return r, nil
 }
 
 // CreateMetricsReceiver creates a metrics receiver based on provided config.
@@ -82,24 +85,35 @@ func (f *Factory) CreateMetricsReceiver(
 	consumer consumer.MetricsConsumerOld,
 ) (component.MetricsReceiver, error) {
 
+	r, err := f.createReceiver(logger, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	r.nextConsumers.metricsConsumer = consumer
+	return r, nil
+}
+
+func (f *Factory) createReceiver(logger *zap.Logger, cfg configmodels.Receiver) (*wavefrontReceiver, error) {
 	rCfg := cfg.(*Config)
 
-	// Wavefront is very similar to Carbon: it is TCP based in which each received
-	// text line represents a single metric data point. They differ on the format
-	// of their textual representation.
-	//
-	// The Wavefront receiver leverages the Carbon receiver code by implementing
-	// a dedicated parser for its format.
-	carbonCfg := carbonreceiver.Config{
-		ReceiverSettings: rCfg.ReceiverSettings,
-		Transport:        "tcp",
-		TCPIdleTimeout:   rCfg.TCPIdleTimeout,
-		Parser: &protocol.Config{
-			Type: "plaintext", // TODO: update after other parsers are implemented for Carbon receiver.
-			Config: &WavefrontParser{
-				ExtractCollectdTags: rCfg.ExtractCollectdTags,
-			},
-		},
+	// There must be one receiver for both metrics and traces, since they are
+	// received on the same endpoint. We maintain a map of receivers per config.
+	r, ok := receivers[rCfg]
+	if !ok {
+		var err error
+		r, err = newReceiver(logger, rCfg)
+		if err != nil {
+			return nil, err
+		}
+		receivers[rCfg] = r
 	}
-	return carbonreceiver.New(logger, carbonCfg, consumer)
+	return r, nil
 }
+
+// This is the map of already created Wavefront receivers for particular
+// configurations. We maintain this map because the Factory is asked trace and
+// metric receivers separately when it gets CreateTraceReceiver() and
+// CreateMetricsReceiver() but they must not create separate objects, they must
+// use one receiver object per configuration.
+var receivers = map[*Config]*wavefrontReceiver{}
@@ -19,7 +19,6 @@ import (
 	"testing"
 
 	"github.com/open-telemetry/opentelemetry-collector/config/configcheck"
-	"github.com/open-telemetry/opentelemetry-collector/config/configerror"
 	"github.com/open-telemetry/opentelemetry-collector/exporter/exportertest"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
@@ -37,15 +36,29 @@ func TestCreateReceiver(t *testing.T) {
 	cfg := factory.CreateDefaultConfig().(*Config)
 	cfg.Endpoint = "localhost:0" // Endpoint is required, not going to be used here.
 
-	tReceiver, err := factory.CreateMetricsReceiver(zap.NewNop(), cfg, new(exportertest.SinkMetricsExporterOld))
+	mReceiver, err := factory.CreateMetricsReceiver(zap.NewNop(), cfg, new(exportertest.SinkMetricsExporterOld))
 	assert.Nil(t, err, "receiver creation failed")
-	assert.NotNil(t, tReceiver, "receiver creation failed")
+	assert.NotNil(t, mReceiver, "receiver creation failed")
+
+	mReceiver, err = factory.CreateMetricsReceiver(zap.NewNop(), cfg, new(exportertest.SinkMetricsExporterOld))
+	assert.Nil(t, err, "receiver creation failed")
+	assert.NotNil(t, mReceiver, "receiver creation failed")
 
-	tReceiver, err = factory.CreateMetricsReceiver(zap.NewNop(), cfg, new(exportertest.SinkMetricsExporterOld))
+	tReceiver, err := factory.CreateTraceReceiver(context.Background(), zap.NewNop(), cfg, new(exportertest.SinkTraceExporterOld))
 	assert.Nil(t, err, "receiver creation failed")
 	assert.NotNil(t, tReceiver, "receiver creation failed")
 
-	mReceiver, err := factory.CreateTraceReceiver(context.Background(), zap.NewNop(), cfg, new(exportertest.SinkTraceExporterOld))
-	assert.Equal(t, err, configerror.ErrDataTypeIsNotSupported)
-	assert.Nil(t, mReceiver)
+	// The same receiver receives both metrics and traces.
+	assert.Equal(t, mReceiver, tReceiver)
+	assert.NoError(t, tReceiver.Shutdown(context.Background()))
+}
+
+func TestCreateReceiverEmptyEndpoint(t *testing.T) {
+	factory := &Factory{}
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Endpoint = ""
+
+	tReceiver, err := factory.CreateTraceReceiver(context.Background(), zap.NewNop(), cfg, new(exportertest.SinkTraceExporterOld))
+	assert.Error(t, err)
+	assert.Nil(t, tReceiver)
 }
@@ -0,0 +1,181 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefrontreceiver
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/golang/protobuf/ptypes/timestamp"
+)
+
+// histogramGranularitySuffixes maps the granularity of a histogram to the
+// suffix added to its name, as done by Wavefront to distinguish the histograms
+// of each granularity, see https://docs.wavefront.com/proxies_histograms.html.
+var histogramGranularitySuffixes = map[string]string{
+	"!M": ".m",
+	"!H": ".h",
+	"!D": ".d",
+}
+
+type centroid struct {
+	mean  float64
+	count int64
+}
+
+func isHistogram(line string) bool {
+	return strings.HasPrefix(line, "!")
+}
+
+// parseHistogram converts a Wavefront histogram, see
+// https://docs.wavefront.com/proxies_histograms.html, in the following format:
+//
+// 	"{!M | !H | !D} [<timestamp>] #<count> <mean> [... #<count> <mean>] <metricName> source=<source> [pointTags]"
+//
+// The histogram is converted to a gauge distribution whose buckets preserve
+// the centroids: the explicit bounds are the sorted means of the centroids,
+// each bucket holding the count of the centroid of its lower bound, while the
+// first bucket, below the lowest mean, is empty. The name of the metric has the
+// suffix of its granularity, i.e. ".m", ".h" or ".d".
+func (wp *WavefrontParser) parseHistogram(line string) (*metricspb.Metric, error) {
+	parts := strings.SplitN(line, " ", 2)
+	suffix, ok := histogramGranularitySuffixes[parts[0]]
+	if !ok || len(parts) < 2 {
+		return nil, fmt.Errorf("invalid wavefront histogram [%s]", line)
+	}
+	rest := parts[1]
+
+	var ts timestamp.Timestamp
+	if !strings.HasPrefix(rest, "#") {
+		parts = strings.SplitN(rest, " ", 2)
+		unixTime, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil || len(parts) < 2 {
+			return nil, fmt.Errorf("invalid timestamp for wavefront histogram [%s]", line)
+		}
+		ts.Seconds = unixTime
+		rest = parts[1]
+	} else {
+		// Timestamp omitted, use the current time.
+		ts.Seconds = time.Now().Unix()
+	}
+
+	var centroids []centroid
+	for strings.HasPrefix(rest, "#") {
+		parts = strings.SplitN(rest[1:], " ", 3)
+		if len(parts) < 3 {
+			return nil, fmt.Errorf("invalid centroid for wavefront histogram [%s]", line)
+		}
+		count, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil || count < 0 {
+			return nil, fmt.Errorf("invalid centroid count for wavefront histogram [%s]", line)
+		}
+		mean, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid centroid mean for wavefront histogram [%s]: %v", line, err)
+		}
+		centroids = append(centroids, centroid{mean: mean, count: count})
+		rest = parts[2]
+	}
+	if len(centroids) == 0 {
+		return nil, fmt.Errorf("no centroid for wavefront histogram [%s]", line)
+	}
+
+	parts = strings.SplitN(rest, " ", 2)
+	metricName := unDoubleQuote(parts[0])
+	if metricName == "" {
+		return nil, fmt.Errorf("empty name for wavefront histogram [%s]", line)
+	}
+	var tags string
+	if len(parts) == 2 {
+		tags = parts[1]
+	}
+
+	var labelKeys []*metricspb.LabelKey
+	var labelValues []*metricspb.LabelValue
+	if tags != "" {
+		var err error
+		labelKeys, labelValues, err = buildLabels(tags)
+		if err != nil {
+			return nil, fmt.Errorf("invalid wavefront histogram [%s]: %v", line, err)
+		}
+	}
+
+	if wp.ExtractCollectdTags {
+		metricName, labelKeys, labelValues = wp.injectCollectDLabels(metricName, labelKeys, labelValues)
+	}
+
+	metric := &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{
+			Name:      metricName + suffix,
+			Type:      metricspb.MetricDescriptor_GAUGE_DISTRIBUTION,
+			LabelKeys: labelKeys,
+		},
+		Timeseries: []*metricspb.TimeSeries{
+			{
+				LabelValues: labelValues,
+				Points: []*metricspb.Point{
+					{
+						Timestamp: &ts,
+						Value: &metricspb.Point_DistributionValue{
+							DistributionValue: buildDistribution(centroids),
+						},
+					},
+				},
+			},
+		},
+	}
+	return metric, nil
+}
+
+// buildDistribution builds a distribution whose buckets preserve the
+// centroids, see parseHistogram, merging the centroids of the same mean.
+func buildDistribution(centroids []centroid) *metricspb.DistributionValue {
+	sort.SliceStable(centroids, func(i, j int) bool {
+		return centroids[i].mean < centroids[j].mean
+	})
+
+	bounds := make([]float64, 0, len(centroids))
+	buckets := make([]*metricspb.DistributionValue_Bucket, 1, len(centroids)+1)
+	buckets[0] = &metricspb.DistributionValue_Bucket{}
+	var count int64
+	var sum float64
+	for i, c := range centroids {
+		count += c.count
+		sum += c.mean * float64(c.count)
+		if i > 0 && c.mean == centroids[i-1].mean {
+			buckets[len(buckets)-1].Count += c.count
+			continue
+		}
+		bounds = append(bounds, c.mean)
+		buckets = append(buckets, &metricspb.DistributionValue_Bucket{Count: c.count})
+	}
+
+	return &metricspb.DistributionValue{
+		Count: count,
+		Sum:   sum,
+		BucketOptions: &metricspb.DistributionValue_BucketOptions{
+			Type: &metricspb.DistributionValue_BucketOptions_Explicit_{
+				Explicit: &metricspb.DistributionValue_BucketOptions_Explicit{
+					Bounds: bounds,
+				},
+			},
+		},
+		Buckets: buckets,
+	}
+}
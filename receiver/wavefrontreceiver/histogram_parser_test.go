@@ -0,0 +1,143 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefrontreceiver
+
+import (
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_wavefrontParser_parseHistogram(t *testing.T) {
+	tests := []struct {
+		line                string
+		extractCollectDTags bool
+		want                *metricspb.Metric
+		wantErr             bool
+	}{
+		{
+			line: "!M 1582230020 #2 1.5 #1 0.5 #3 1.5 request.latency source=tst k0=v0",
+			want: buildMetric(
+				metricspb.MetricDescriptor_GAUGE_DISTRIBUTION,
+				"request.latency.m",
+				[]string{"source", "k0"},
+				[]string{"tst", "v0"},
+				&metricspb.Point{
+					Timestamp: &timestamp.Timestamp{Seconds: 1582230020},
+					Value: &metricspb.Point_DistributionValue{
+						DistributionValue: &metricspb.DistributionValue{
+							Count: 6,
+							Sum:   8,
+							BucketOptions: &metricspb.DistributionValue_BucketOptions{
+								Type: &metricspb.DistributionValue_BucketOptions_Explicit_{
+									Explicit: &metricspb.DistributionValue_BucketOptions_Explicit{
+										Bounds: []float64{0.5, 1.5},
+									},
+								},
+							},
+							Buckets: []*metricspb.DistributionValue_Bucket{
+								{Count: 0},
+								{Count: 1},
+								{Count: 5},
+							},
+						},
+					},
+				},
+			),
+		},
+		{
+			line:                "!D 1582230020 #10 2 \"collectd.[cdk=cdv].latency\"",
+			extractCollectDTags: true,
+			want: buildMetric(
+				metricspb.MetricDescriptor_GAUGE_DISTRIBUTION,
+				"collectd.latency.d",
+				[]string{"cdk"},
+				[]string{"cdv"},
+				&metricspb.Point{
+					Timestamp: &timestamp.Timestamp{Seconds: 1582230020},
+					Value: &metricspb.Point_DistributionValue{
+						DistributionValue: &metricspb.DistributionValue{
+							Count: 10,
+							Sum:   20,
+							BucketOptions: &metricspb.DistributionValue_BucketOptions{
+								Type: &metricspb.DistributionValue_BucketOptions_Explicit_{
+									Explicit: &metricspb.DistributionValue_BucketOptions_Explicit{
+										Bounds: []float64{2},
+									},
+								},
+							},
+							Buckets: []*metricspb.DistributionValue_Bucket{
+								{Count: 0},
+								{Count: 10},
+							},
+						},
+					},
+				},
+			),
+		},
+		{
+			line:    "!W 1582230020 #1 1 invalid.granularity source=tst",
+			wantErr: true,
+		},
+		{
+			line:    "!M xyz #1 1 invalid.timestamp source=tst",
+			wantErr: true,
+		},
+		{
+			line:    "!M 1582230020 no.centroids source=tst",
+			wantErr: true,
+		},
+		{
+			line:    "!M 1582230020 #x 1 invalid.count source=tst",
+			wantErr: true,
+		},
+		{
+			line:    "!M 1582230020 #-1 1 negative.count source=tst",
+			wantErr: true,
+		},
+		{
+			line:    "!M 1582230020 #1 x invalid.mean source=tst",
+			wantErr: true,
+		},
+		{
+			line:    "!M 1582230020 #1 1",
+			wantErr: true,
+		},
+		{
+			line:    "!M 1582230020 #1 1 invalid.tags source",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			p := WavefrontParser{ExtractCollectdTags: tt.extractCollectDTags}
+			got, err := p.Parse(tt.line)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
+
+func Test_wavefrontParser_parseHistogramMissingTimestamp(t *testing.T) {
+	p := WavefrontParser{}
+	got, err := p.Parse("!H #1 1 no.timestamp source=tst")
+	require.NoError(t, err)
+	assert.Equal(t, "no.timestamp.h", got.MetricDescriptor.Name)
+	assert.NotZero(t, got.Timeseries[0].Points[0].Timestamp.Seconds)
+}
@@ -0,0 +1,101 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefrontreceiver
+
+import (
+	"context"
+	"errors"
+
+	"github.com/open-telemetry/opentelemetry-collector/component"
+	"github.com/open-telemetry/opentelemetry-collector/consumer"
+	"github.com/open-telemetry/opentelemetry-collector/consumer/consumerdata"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/protocol"
+)
+
+var (
+	errNoMetricsPipeline = errors.New("the receiver is not part of a metrics pipeline")
+	errNoTracesPipeline  = errors.New("the receiver is not part of a traces pipeline")
+)
+
+// wavefrontReceiver receives both the metrics and the spans sent to the same
+// endpoint, so there is a single receiver for both.
+type wavefrontReceiver struct {
+	// The Carbon receiver receiving the lines, see newReceiver.
+	component.MetricsReceiver
+
+	nextConsumers *nextConsumers
+}
+
+var _ component.MetricsReceiver = (*wavefrontReceiver)(nil)
+var _ component.TraceReceiver = (*wavefrontReceiver)(nil)
+
+func newReceiver(logger *zap.Logger, config *Config) (*wavefrontReceiver, error) {
+	r := &wavefrontReceiver{
+		nextConsumers: &nextConsumers{},
+	}
+
+	// Wavefront is very similar to Carbon: it is TCP based in which each received
+	// text line represents a single metric data point. They differ on the format
+	// of their textual representation.
+	//
+	// The Wavefront receiver leverages the Carbon receiver code by implementing
+	// a dedicated parser for its format. The parser also returns the spans, which
+	// the Carbon receiver batches and passes to the next trace consumer.
+	carbonCfg := carbonreceiver.Config{
+		ReceiverSettings: config.ReceiverSettings,
+		Transport:        "tcp",
+		TCPIdleTimeout:   config.TCPIdleTimeout,
+		Parser: &protocol.Config{
+			Type: "plaintext", // TODO: update after other parsers are implemented for Carbon receiver.
+			Config: &WavefrontParser{
+				ExtractCollectdTags: config.ExtractCollectdTags,
+			},
+		},
+	}
+	carbonReceiver, err := carbonreceiver.New(logger, carbonCfg, r.nextConsumers)
+	if err != nil {
+		return nil, err
+	}
+	r.MetricsReceiver = carbonReceiver
+	return r, nil
+}
+
+// nextConsumers passes the metrics and the spans, batched by the Carbon
+// receiver, to the next consumers of the metrics and traces pipelines, if
+// any, set before the receiver is started.
+type nextConsumers struct {
+	metricsConsumer consumer.MetricsConsumerOld
+	traceConsumer   consumer.TraceConsumerOld
+}
+
+var _ consumer.MetricsConsumerOld = (*nextConsumers)(nil)
+var _ consumer.TraceConsumerOld = (*nextConsumers)(nil)
+
+func (nc *nextConsumers) ConsumeMetricsData(ctx context.Context, md consumerdata.MetricsData) error {
+	if nc.metricsConsumer == nil {
+		return errNoMetricsPipeline
+	}
+	return nc.metricsConsumer.ConsumeMetricsData(ctx, md)
+}
+
+func (nc *nextConsumers) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	if nc.traceConsumer == nil {
+		return errNoTracesPipeline
+	}
+	return nc.traceConsumer.ConsumeTraceData(ctx, td)
+}
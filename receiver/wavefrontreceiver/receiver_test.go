@@ -28,6 +28,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector/component/componenttest"
 	"github.com/open-telemetry/opentelemetry-collector/consumer"
 	"github.com/open-telemetry/opentelemetry-collector/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-collector/exporter/exportertest"
 	"github.com/open-telemetry/opentelemetry-collector/testutils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -144,6 +145,76 @@ func Test_wavefrontreceiver_EndToEnd(t *testing.T) {
 	}
 }
 
+func Test_wavefrontreceiver_EndToEnd_MetricsAndTraces(t *testing.T) {
+	factory := &Factory{}
+	rCfg := factory.CreateDefaultConfig().(*Config)
+	rCfg.TCPIdleTimeout = time.Second
+
+	addr := testutils.GetAvailableLocalAddress(t)
+	rCfg.Endpoint = addr
+	metricsSink := new(exportertest.SinkMetricsExporterOld)
+	tracesSink := new(exportertest.SinkTraceExporterOld)
+	mRcvr, err := factory.CreateMetricsReceiver(zap.NewNop(), rCfg, metricsSink)
+	require.NoError(t, err)
+	tRcvr, err := factory.CreateTraceReceiver(context.Background(), zap.NewNop(), rCfg, tracesSink)
+	require.NoError(t, err)
+	require.Equal(t, mRcvr, tRcvr)
+
+	require.NoError(t, mRcvr.Start(context.Background(), componenttest.NewNopHost()))
+	defer mRcvr.Shutdown(context.Background())
+
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	lines := []string{
+		"single.metric 1 1582231120 source=e2e",
+		"!M 1582231120 #2 1.5 #1 0.5 request.latency source=e2e",
+		"getAllUsers source=e2e traceId=" + testTraceID + " spanId=" + testParent + " service=auth 1582231120000 343",
+		"withLogs source=e2e traceId=" + testTraceID + " spanId=" + testSpanID + " parent=" + testParent + " _spanLogs=true 1582231120001 1",
+		`{"traceId":"` + testTraceID + `","spanId":"` + testSpanID + `","logs":[{"timestamp":1582231120001000,"fields":{"event":"error"}}],` +
+			`"span":"withLogs source=e2e traceId=` + testTraceID + ` spanId=` + testSpanID + ` parent=` + testParent + ` _spanLogs=true 1582231120001 1"}`,
+	}
+	for _, line := range lines {
+		_, err = fmt.Fprintln(conn, line)
+		require.NoError(t, err)
+	}
+	require.NoError(t, conn.Close())
+
+	var metricNames, spanNames []string
+	require.Eventually(t, func() bool {
+		metricNames = nil
+		for _, md := range metricsSink.AllMetrics() {
+			for _, metric := range md.Metrics {
+				metricNames = append(metricNames, metric.MetricDescriptor.Name)
+			}
+		}
+		spanNames = nil
+		for _, td := range tracesSink.AllTraces() {
+			for _, span := range td.Spans {
+				spanNames = append(spanNames, span.Name.Value)
+			}
+		}
+		return len(spanNames) == 2 && len(metricNames) == 2
+	}, 10*time.Second, 10*time.Millisecond)
+	assert.ElementsMatch(t, []string{"single.metric", "request.latency.m"}, metricNames)
+	assert.ElementsMatch(t, []string{"getAllUsers", "withLogs"}, spanNames)
+}
+
+func Test_nextConsumers(t *testing.T) {
+	nc := &nextConsumers{}
+	ctx := context.Background()
+	assert.Equal(t, errNoMetricsPipeline, nc.ConsumeMetricsData(ctx, consumerdata.MetricsData{}))
+	assert.Equal(t, errNoTracesPipeline, nc.ConsumeTraceData(ctx, consumerdata.TraceData{}))
+
+	metricsSink := new(exportertest.SinkMetricsExporterOld)
+	tracesSink := new(exportertest.SinkTraceExporterOld)
+	nc.metricsConsumer = metricsSink
+	nc.traceConsumer = tracesSink
+	assert.NoError(t, nc.ConsumeMetricsData(ctx, consumerdata.MetricsData{}))
+	assert.NoError(t, nc.ConsumeTraceData(ctx, consumerdata.TraceData{}))
+	assert.Equal(t, 1, len(metricsSink.AllMetrics()))
+	assert.Equal(t, 1, len(tracesSink.AllTraces()))
+}
+
 type waitableMetricsConsumer struct {
 	sync.WaitGroup
 	mtx     sync.Mutex
@@ -156,7 +227,10 @@ func (w *waitableMetricsConsumer) ConsumeMetricsData(ctx context.Context, md con
 	w.mtx.Lock()
 	w.metrics = append(w.metrics, md.Metrics...)
 	w.mtx.Unlock()
-	w.Done()
+	// The metrics of multiple lines can be passed together.
+	for range md.Metrics {
+		w.Done()
+	}
 	return nil
 }
 
@@ -0,0 +1,326 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefrontreceiver
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/open-telemetry/opentelemetry-collector/consumer/consumerdata"
+)
+
+// Tags of the Wavefront spans with a special meaning, see
+// https://docs.wavefront.com/trace_data_details.html#span-tags.
+const (
+	traceIDTag     = "traceId"
+	spanIDTag      = "spanId"
+	parentTag      = "parent"
+	followsFromTag = "followsFrom"
+	spanLogsTag    = "_spanLogs"
+	serviceTag     = "service"
+	spanKindTag    = "span.kind"
+	errorTag       = "error"
+
+	// spanLogEventField is the field of the span logs used as the description
+	// of the corresponding time events.
+	spanLogEventField = "event"
+)
+
+var errNoSpanInSpanLogs = errors.New("no span in the span logs")
+
+func isSpan(line string) bool {
+	_, rest := splitName(line)
+	if i := strings.IndexByte(rest, ' '); i >= 0 {
+		rest = rest[:i]
+	}
+	// The name of a metric is followed by its value, while the name of a span
+	// is followed by its tags.
+	return strings.IndexByte(rest, '=') > 0
+}
+
+func isSpanLogs(line string) bool {
+	return strings.HasPrefix(line, "{")
+}
+
+// parseSpan converts a Wavefront span, see
+// https://docs.wavefront.com/trace_data_details.html, into trace data. The
+// span is expected in the following format:
+//
+// 	"<operationName> source=<source> <spanTags> <start_milliseconds> <duration_milliseconds>"
+//
+// The traceId and spanId tags are required, the spans with the _spanLogs tag
+// are skipped, returning no trace data, since they are received again with
+// their span logs, see parseSpanLogs.
+func parseSpan(line string) (*consumerdata.TraceData, error) {
+	span, serviceName, hasSpanLogs, err := buildSpan(line)
+	if err != nil {
+		return nil, fmt.Errorf("invalid wavefront span [%s]: %v", line, err)
+	}
+	if hasSpanLogs {
+		return nil, nil
+	}
+
+	td := buildTraceData(span, serviceName)
+	return &td, nil
+}
+
+// spanLogs are the span logs of a Wavefront span, see
+// https://docs.wavefront.com/trace_data_details.html#span-logs, received as
+// JSON, along with the span itself.
+type spanLogs struct {
+	TraceID string    `json:"traceId"`
+	SpanID  string    `json:"spanId"`
+	Logs    []spanLog `json:"logs"`
+	Span    string    `json:"span"`
+}
+
+type spanLog struct {
+	// Timestamp is in microseconds.
+	Timestamp int64             `json:"timestamp"`
+	Fields    map[string]string `json:"fields"`
+}
+
+// parseSpanLogs converts the span of Wavefront span logs into trace data, the
+// logs becoming annotations of the span.
+func parseSpanLogs(line string) (*consumerdata.TraceData, error) {
+	var sl spanLogs
+	if err := json.Unmarshal([]byte(line), &sl); err != nil {
+		return nil, fmt.Errorf("invalid wavefront span logs [%s]: %v", line, err)
+	}
+	if sl.Span == "" {
+		return nil, fmt.Errorf("invalid wavefront span logs [%s]: %v", line, errNoSpanInSpanLogs)
+	}
+
+	span, serviceName, _, err := buildSpan(sl.Span)
+	if err != nil {
+		return nil, fmt.Errorf("invalid wavefront span logs [%s]: %v", line, err)
+	}
+
+	if len(sl.Logs) > 0 {
+		span.TimeEvents = &tracepb.Span_TimeEvents{
+			TimeEvent: make([]*tracepb.Span_TimeEvent, 0, len(sl.Logs)),
+		}
+		for _, log := range sl.Logs {
+			annotation := &tracepb.Span_TimeEvent_Annotation{
+				Description: &tracepb.TruncatableString{Value: log.Fields[spanLogEventField]},
+			}
+			if len(log.Fields) > 0 {
+				annotation.Attributes = &tracepb.Span_Attributes{
+					AttributeMap: make(map[string]*tracepb.AttributeValue, len(log.Fields)),
+				}
+				for k, v := range log.Fields {
+					annotation.Attributes.AttributeMap[k] = stringAttributeValue(v)
+				}
+			}
+			span.TimeEvents.TimeEvent = append(span.TimeEvents.TimeEvent, &tracepb.Span_TimeEvent{
+				Time: microsToTimestamp(log.Timestamp),
+				Value: &tracepb.Span_TimeEvent_Annotation_{
+					Annotation: annotation,
+				},
+			})
+		}
+	}
+
+	td := buildTraceData(span, serviceName)
+	return &td, nil
+}
+
+// buildSpan builds the span of a line in the Wavefront span format, returning
+// also its service name and if it has span logs.
+func buildSpan(line string) (*tracepb.Span, string, bool, error) {
+	name, rest := splitName(line)
+	if name == "" {
+		return nil, "", false, errors.New("empty operation name")
+	}
+
+	// The start and duration are the last two fields, after the tags.
+	rest = strings.TrimRight(rest, " ")
+	i := strings.LastIndexByte(rest, ' ')
+	if i < 0 {
+		return nil, "", false, errors.New("missing start or duration")
+	}
+	durationMillis, err := strconv.ParseInt(rest[i+1:], 10, 64)
+	if err != nil {
+		return nil, "", false, fmt.Errorf("invalid duration: %v", err)
+	}
+	rest = strings.TrimRight(rest[:i], " ")
+	i = strings.LastIndexByte(rest, ' ')
+	if i < 0 {
+		return nil, "", false, errors.New("missing start or tags")
+	}
+	startMillis, err := strconv.ParseInt(rest[i+1:], 10, 64)
+	if err != nil {
+		return nil, "", false, fmt.Errorf("invalid start: %v", err)
+	}
+
+	keys, values, err := buildLabels(rest[:i])
+	if err != nil {
+		return nil, "", false, err
+	}
+
+	span := &tracepb.Span{
+		Name:      &tracepb.TruncatableString{Value: name},
+		StartTime: millisToTimestamp(startMillis),
+		EndTime:   millisToTimestamp(startMillis + durationMillis),
+		Attributes: &tracepb.Span_Attributes{
+			AttributeMap: make(map[string]*tracepb.AttributeValue, len(keys)),
+		},
+	}
+	var serviceName string
+	var hasSpanLogs bool
+	for i, key := range keys {
+		value := values[i].Value
+		switch key.Key {
+		case traceIDTag:
+			if span.TraceId, err = uuidToBytes(value); err != nil {
+				return nil, "", false, err
+			}
+		case spanIDTag:
+			if span.SpanId, err = uuidToSpanID(value); err != nil {
+				return nil, "", false, err
+			}
+		case parentTag, followsFromTag:
+			spanID, err := uuidToSpanID(value)
+			if err != nil {
+				return nil, "", false, err
+			}
+			// The first parent is the parent of the span, any other parent,
+			// or span it follows from, is linked.
+			if key.Key == parentTag && span.ParentSpanId == nil {
+				span.ParentSpanId = spanID
+				continue
+			}
+			if span.Links == nil {
+				span.Links = &tracepb.Span_Links{}
+			}
+			span.Links.Link = append(span.Links.Link, &tracepb.Span_Link{
+				SpanId: spanID,
+				Type:   tracepb.Span_Link_PARENT_LINKED_SPAN,
+			})
+		case spanLogsTag:
+			hasSpanLogs = value == "true"
+		default:
+			switch key.Key {
+			case serviceTag:
+				serviceName = value
+			case spanKindTag:
+				span.Kind = toSpanKind(value)
+			case errorTag:
+				if value == "true" {
+					// Unknown, as per the gRPC status codes.
+					span.Status = &tracepb.Status{Code: 2}
+				}
+			}
+			span.Attributes.AttributeMap[key.Key] = stringAttributeValue(value)
+		}
+	}
+	if span.TraceId == nil || span.SpanId == nil {
+		return nil, "", false, fmt.Errorf("missing %s or %s tag", traceIDTag, spanIDTag)
+	}
+	if span.Links != nil {
+		// The links are in the same trace.
+		for _, link := range span.Links.Link {
+			link.TraceId = span.TraceId
+		}
+	}
+
+	return span, serviceName, hasSpanLogs, nil
+}
+
+func buildTraceData(span *tracepb.Span, serviceName string) consumerdata.TraceData {
+	td := consumerdata.TraceData{
+		Spans:        []*tracepb.Span{span},
+		SourceFormat: "wavefront",
+	}
+	if serviceName != "" {
+		td.Node = &commonpb.Node{
+			ServiceInfo: &commonpb.ServiceInfo{Name: serviceName},
+		}
+	}
+	return td
+}
+
+// splitName splits the name, optionally double-quoted, from the rest of the
+// line.
+func splitName(line string) (string, string) {
+	if strings.HasPrefix(line, `"`) {
+		if i := strings.IndexByte(line[1:], '"'); i >= 0 {
+			return line[1 : i+1], strings.TrimLeft(line[i+2:], " ")
+		}
+	}
+	parts := strings.SplitN(line, " ", 2)
+	if len(parts) < 2 {
+		return parts[0], ""
+	}
+	return parts[0], strings.TrimLeft(parts[1], " ")
+}
+
+// uuidToBytes converts the UUIDs used as trace IDs by Wavefront to bytes.
+func uuidToBytes(uuid string) ([]byte, error) {
+	b, err := hex.DecodeString(strings.Replace(uuid, "-", "", -1))
+	if err != nil || len(b) != 16 {
+		return nil, fmt.Errorf("invalid UUID %q", uuid)
+	}
+	return b, nil
+}
+
+// uuidToSpanID converts the UUIDs used as span IDs by Wavefront to 8-byte
+// span IDs, keeping their lower 8 bytes, where Wavefront puts 8-byte span IDs.
+func uuidToSpanID(uuid string) ([]byte, error) {
+	b, err := uuidToBytes(uuid)
+	if err != nil {
+		return nil, err
+	}
+	return b[8:], nil
+}
+
+func toSpanKind(kind string) tracepb.Span_SpanKind {
+	switch kind {
+	case "server":
+		return tracepb.Span_SERVER
+	case "client":
+		return tracepb.Span_CLIENT
+	}
+	return tracepb.Span_SPAN_KIND_UNSPECIFIED
+}
+
+func stringAttributeValue(value string) *tracepb.AttributeValue {
+	return &tracepb.AttributeValue{
+		Value: &tracepb.AttributeValue_StringValue{
+			StringValue: &tracepb.TruncatableString{Value: value},
+		},
+	}
+}
+
+func millisToTimestamp(millis int64) *timestamp.Timestamp {
+	return &timestamp.Timestamp{
+		Seconds: millis / 1e3,
+		Nanos:   int32(millis%1e3) * 1e6,
+	}
+}
+
+func microsToTimestamp(micros int64) *timestamp.Timestamp {
+	return &timestamp.Timestamp{
+		Seconds: micros / 1e6,
+		Nanos:   int32(micros%1e6) * 1e3,
+	}
+}
@@ -0,0 +1,240 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefrontreceiver
+
+import (
+	"testing"
+
+	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/open-telemetry/opentelemetry-collector/consumer/consumerdata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/protocol"
+)
+
+const (
+	testTraceID = "7b3bf470-9456-11e8-9eb6-529269fb1459"
+	testSpanID  = "0313bafe-9457-11e8-9eb6-529269fb1459"
+	testParent  = "2f64e538-9457-11e8-9eb6-529269fb1459"
+	testFollows = "5f64e538-9457-11e8-9eb6-529269fb1459"
+)
+
+func Test_wavefrontParser_parseSpan(t *testing.T) {
+	tests := []struct {
+		line    string
+		want    *consumerdata.TraceData
+		wantErr bool
+	}{
+		{
+			line: "getAllUsers source=localhost traceId=" + testTraceID + " spanId=" + testSpanID +
+				" parent=" + testParent + " followsFrom=" + testFollows +
+				" application=Wavefront service=auth http.method=GET span.kind=server error=true 1552949776000 343",
+			want: &consumerdata.TraceData{
+				Node: &commonpb.Node{
+					ServiceInfo: &commonpb.ServiceInfo{Name: "auth"},
+				},
+				Spans: []*tracepb.Span{
+					{
+						TraceId:      []byte{0x7b, 0x3b, 0xf4, 0x70, 0x94, 0x56, 0x11, 0xe8, 0x9e, 0xb6, 0x52, 0x92, 0x69, 0xfb, 0x14, 0x59},
+						SpanId:       []byte{0x9e, 0xb6, 0x52, 0x92, 0x69, 0xfb, 0x14, 0x59},
+						ParentSpanId: []byte{0x9e, 0xb6, 0x52, 0x92, 0x69, 0xfb, 0x14, 0x59},
+						Name:         &tracepb.TruncatableString{Value: "getAllUsers"},
+						Kind:         tracepb.Span_SERVER,
+						StartTime:    &timestamp.Timestamp{Seconds: 1552949776},
+						EndTime:      &timestamp.Timestamp{Seconds: 1552949776, Nanos: 343e6},
+						Attributes: &tracepb.Span_Attributes{
+							AttributeMap: map[string]*tracepb.AttributeValue{
+								"source":      stringAttributeValue("localhost"),
+								"application": stringAttributeValue("Wavefront"),
+								"service":     stringAttributeValue("auth"),
+								"http.method": stringAttributeValue("GET"),
+								"span.kind":   stringAttributeValue("server"),
+								"error":       stringAttributeValue("true"),
+							},
+						},
+						Links: &tracepb.Span_Links{
+							Link: []*tracepb.Span_Link{
+								{
+									TraceId: []byte{0x7b, 0x3b, 0xf4, 0x70, 0x94, 0x56, 0x11, 0xe8, 0x9e, 0xb6, 0x52, 0x92, 0x69, 0xfb, 0x14, 0x59},
+									SpanId:  []byte{0x9e, 0xb6, 0x52, 0x92, 0x69, 0xfb, 0x14, 0x59},
+									Type:    tracepb.Span_Link_PARENT_LINKED_SPAN,
+								},
+							},
+						},
+						Status: &tracepb.Status{Code: 2},
+					},
+				},
+				SourceFormat: "wavefront",
+			},
+		},
+		{
+			line: "\"GET /users\" source=localhost traceId=" + testTraceID + " spanId=" + testSpanID + " 1552949776001 1",
+			want: &consumerdata.TraceData{
+				Spans: []*tracepb.Span{
+					{
+						TraceId:   []byte{0x7b, 0x3b, 0xf4, 0x70, 0x94, 0x56, 0x11, 0xe8, 0x9e, 0xb6, 0x52, 0x92, 0x69, 0xfb, 0x14, 0x59},
+						SpanId:    []byte{0x9e, 0xb6, 0x52, 0x92, 0x69, 0xfb, 0x14, 0x59},
+						Name:      &tracepb.TruncatableString{Value: "GET /users"},
+						StartTime: &timestamp.Timestamp{Seconds: 1552949776, Nanos: 1e6},
+						EndTime:   &timestamp.Timestamp{Seconds: 1552949776, Nanos: 2e6},
+						Attributes: &tracepb.Span_Attributes{
+							AttributeMap: map[string]*tracepb.AttributeValue{
+								"source": stringAttributeValue("localhost"),
+							},
+						},
+					},
+				},
+				SourceFormat: "wavefront",
+			},
+		},
+		{
+			// Received again with its span logs.
+			line: "withLogs source=localhost traceId=" + testTraceID + " spanId=" + testSpanID + " _spanLogs=true 1552949776000 343",
+		},
+		{
+			line:    "missing.span.id source=localhost traceId=" + testTraceID + " 1552949776000 343",
+			wantErr: true,
+		},
+		{
+			line:    "invalid.trace.id source=localhost traceId=xyz spanId=" + testSpanID + " 1552949776000 343",
+			wantErr: true,
+		},
+		{
+			line:    "invalid.parent source=localhost traceId=" + testTraceID + " spanId=" + testSpanID + " parent=1234 1552949776000 343",
+			wantErr: true,
+		},
+		{
+			line:    "invalid.start source=localhost traceId=" + testTraceID + " spanId=" + testSpanID + " xyz 343",
+			wantErr: true,
+		},
+		{
+			line:    "invalid.duration source=localhost traceId=" + testTraceID + " spanId=" + testSpanID + " 1552949776000 xyz",
+			wantErr: true,
+		},
+		{
+			line:    "missing.start source=localhost 343",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			var p WavefrontParser
+			metric, err := p.Parse(tt.line)
+			assert.Nil(t, metric)
+			assert.Equal(t, protocol.ErrNotMetric, err)
+
+			metric, got, err := p.ParseLine(tt.line)
+			assert.Nil(t, metric)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, got)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_wavefrontParser_parseSpanLogs(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		want    *tracepb.Span_TimeEvents
+		wantErr bool
+	}{
+		{
+			name: "logs",
+			line: `{"traceId":"` + testTraceID + `","spanId":"` + testSpanID + `",` +
+				`"logs":[{"timestamp":1552949776000100,"fields":{"event":"error","error.kind":"exception"}},{"timestamp":1552949776200000,"fields":{"message":"retry"}}],` +
+				`"span":"getAllUsers source=localhost traceId=` + testTraceID + ` spanId=` + testSpanID + ` _spanLogs=true 1552949776000 343"}`,
+			want: &tracepb.Span_TimeEvents{
+				TimeEvent: []*tracepb.Span_TimeEvent{
+					{
+						Time: &timestamp.Timestamp{Seconds: 1552949776, Nanos: 100e3},
+						Value: &tracepb.Span_TimeEvent_Annotation_{
+							Annotation: &tracepb.Span_TimeEvent_Annotation{
+								Description: &tracepb.TruncatableString{Value: "error"},
+								Attributes: &tracepb.Span_Attributes{
+									AttributeMap: map[string]*tracepb.AttributeValue{
+										"event":      stringAttributeValue("error"),
+										"error.kind": stringAttributeValue("exception"),
+									},
+								},
+							},
+						},
+					},
+					{
+						Time: &timestamp.Timestamp{Seconds: 1552949776, Nanos: 200e6},
+						Value: &tracepb.Span_TimeEvent_Annotation_{
+							Annotation: &tracepb.Span_TimeEvent_Annotation{
+								Description: &tracepb.TruncatableString{},
+								Attributes: &tracepb.Span_Attributes{
+									AttributeMap: map[string]*tracepb.AttributeValue{
+										"message": stringAttributeValue("retry"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "invalid_json",
+			line:    `{"traceId":`,
+			wantErr: true,
+		},
+		{
+			name:    "no_span",
+			line:    `{"traceId":"` + testTraceID + `","spanId":"` + testSpanID + `","logs":[]}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid_span",
+			line:    `{"logs":[],"span":"invalid.span source=localhost 1552949776000 343"}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p WavefrontParser
+			metric, err := p.Parse(tt.line)
+			assert.Nil(t, metric)
+			assert.Equal(t, protocol.ErrNotMetric, err)
+
+			metric, got, err := p.ParseLine(tt.line)
+			assert.Nil(t, metric)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, got)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, got)
+			spans := got.Spans
+			require.Equal(t, 1, len(spans))
+			assert.Equal(t, "getAllUsers", spans[0].Name.Value)
+			assert.Equal(t, tt.want, spans[0].TimeEvents)
+		})
+	}
+}
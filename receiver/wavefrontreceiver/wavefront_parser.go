@@ -22,6 +22,7 @@ import (
 
 	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
 	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/open-telemetry/opentelemetry-collector/consumer/consumerdata"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/protocol"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/collectdreceiver"
@@ -29,12 +30,14 @@ import (
 
 // WavefrontParser converts metrics in the Wavefront format, see
 // https://docs.wavefront.com/wavefront_data_format.html#metrics-data-format-syntax,
-// into the internal format of the Collector
+// into the internal format of the Collector. It also converts histograms, see
+// https://docs.wavefront.com/proxies_histograms.html, and spans, see
+// https://docs.wavefront.com/trace_data_details.html.
 type WavefrontParser struct {
 	ExtractCollectdTags bool `mapstructure:"extract_collectd_tags"`
 }
 
-var _ (protocol.Parser) = (*WavefrontParser)(nil)
+var _ (protocol.SpanParser) = (*WavefrontParser)(nil)
 var _ (protocol.ParserConfig) = (*WavefrontParser)(nil)
 
 // Only two chars can be espcaped per Wavafront SDK, see
@@ -58,7 +61,39 @@ func (wp *WavefrontParser) BuildParser() (protocol.Parser, error) {
 // 	"<metricName> <metricValue> [<timestamp>] source=<source> [pointTags]"
 //
 // Detailed description of each element is available on the link above.
+//
+// Histograms are also received, see parseHistogram. Parse returns
+// protocol.ErrNotMetric for the spans and span logs, see ParseLine.
 func (wp *WavefrontParser) Parse(line string) (*metricspb.Metric, error) {
+	switch {
+	case isHistogram(line):
+		return wp.parseHistogram(line)
+	case isSpanLogs(line), isSpan(line):
+		return nil, protocol.ErrNotMetric
+	}
+	return wp.parseMetric(line)
+}
+
+// ParseLine transforms a line that is either a metric or histogram, as by
+// Parse, or a span or span logs, see parseSpan and parseSpanLogs.
+func (wp *WavefrontParser) ParseLine(line string) (*metricspb.Metric, *consumerdata.TraceData, error) {
+	switch {
+	case isHistogram(line):
+		metric, err := wp.parseHistogram(line)
+		return metric, nil, err
+	case isSpanLogs(line):
+		td, err := parseSpanLogs(line)
+		return nil, td, err
+	case isSpan(line):
+		td, err := parseSpan(line)
+		return nil, td, err
+	}
+	metric, err := wp.parseMetric(line)
+	return metric, nil, err
+}
+
+// parseMetric transforms a line in the Wavefront metric format.
+func (wp *WavefrontParser) parseMetric(line string) (*metricspb.Metric, error) {
 	parts := strings.SplitN(line, " ", 3)
 	if len(parts) < 3 {
 		return nil, fmt.Errorf("invalid wavefront metric [%s]", line)